@@ -24,7 +24,6 @@ export function useEmployees(useCache = true) {
     setLoading(true);
     setError(null);
     try {
-      await apiClient.refreshEmployees();
       const newData = await apiClient.getAllEmployees(false);
       setData(newData);
     } catch (err) {
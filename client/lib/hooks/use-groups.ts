@@ -24,7 +24,6 @@ export function useGroups(useCache = true) {
     setLoading(true);
     setError(null);
     try {
-      await apiClient.refreshGroups();
       const newData = await apiClient.getAllGroups(false);
       setData(newData);
     } catch (err) {
@@ -30,7 +30,6 @@ export function useGroupSchedule(groupNumber: string | null, useCache = true) {
     setLoading(true);
     setError(null);
     try {
-      await apiClient.refreshGroupSchedule(groupNumber);
       const newData = await apiClient.getGroupSchedule(groupNumber, false);
       setData(newData);
     } catch (err) {
@@ -69,7 +68,6 @@ export function useEmployeeSchedule(urlId: string | null, useCache = true) {
     setLoading(true);
     setError(null);
     try {
-      await apiClient.refreshEmployeeSchedule(urlId);
       const newData = await apiClient.getEmployeeSchedule(urlId, false);
       setData(newData);
     } catch (err) {
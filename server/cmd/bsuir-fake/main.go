@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/pkg/bsuir/bsuirtest"
+)
+
+// Фейковый API БГУИРа для локальной разработки без сети:
+//
+//	go run ./cmd/bsuir-fake -addr localhost:8090
+//	BSUIR_API_BASE_URL=http://localhost:8090/api/v1 make run
+func main() {
+	addr := flag.String("addr", "localhost:8090", "listen address")
+	dir := flag.String("fixtures", "", "fixtures directory (default: built-in fixtures)")
+	latency := flag.Duration("latency", 0, "delay before every response")
+	failEvery := flag.Int("fail-every", 0, "answer every Nth request with 503 (0 disables)")
+	flag.Parse()
+
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+
+	fixtures := bsuirtest.DefaultFixtures()
+	if *dir != "" {
+		var err error
+		fixtures, err = bsuirtest.LoadFixtures(os.DirFS(*dir))
+		if err != nil {
+			logrus.Fatalf("Failed to load fixtures: %v", err)
+		}
+	}
+
+	fake := bsuirtest.New(fixtures)
+	fake.SetLatency(*latency)
+
+	handler := http.Handler(fake)
+	if *failEvery > 0 {
+		handler = failEveryNth(handler, *failEvery)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"addr":     *addr,
+		"base_url": "http://" + *addr + bsuirtest.BasePath,
+	}).Info("Starting fake BSUIR API")
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           logRequests(handler),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		logrus.Fatalf("Failed to start server: %v", err)
+	}
+}
+
+// failEveryNth отвечает 503 на каждый n-й запрос
+func failEveryNth(next http.Handler, n int) http.Handler {
+	var count atomic.Int64
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1)%int64(n) == 0 {
+			http.Error(w, `{"error":"injected fault"}`, http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		logrus.WithFields(logrus.Fields{
+			"path":       r.URL.RequestURI(),
+			"latency_ms": time.Since(start).Milliseconds(),
+		}).Info("Request")
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/config"
+	"schedluer/internal/container"
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/service"
+)
+
+const usage = `Usage: schedluer-admin <command> [arguments]
+
+Commands:
+  apikey create -name NAME [-role admin]           mint a new API key and print it once
+  apikey create -name NAME -role user [-user ID]   mint a key acting as user ID or a new user (/me endpoints)
+  apikey list                                      list issued API keys
+  apikey revoke PREFIX                             revoke the API key with the given prefix
+  migrate                                          apply pending storage migrations and print their status
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "migrate":
+	case len(args) >= 2 && args[0] == "apikey":
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return errors.New("unknown command")
+	}
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logrus.SetLevel(logrus.WarnLevel)
+
+	if cfg.Storage.Driver == config.StorageMemory {
+		return errors.New("nothing is persisted with the memory storage driver")
+	}
+
+	// Миграции применяются при открытии хранилища, migrate только показывает результат
+	store, err := container.OpenStore(cfg, logrus.StandardLogger())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	defer func() {
+		_ = store.Close(ctx)
+	}()
+
+	if args[0] == "migrate" {
+		return printMigrations(ctx, store)
+	}
+
+	authService := service.NewAuthService(store.APIKeys(), logrus.StandardLogger())
+
+	switch args[1] {
+	case "create":
+		return createAPIKey(ctx, authService, args[2:])
+	case "list":
+		return listAPIKeys(ctx, authService)
+	case "revoke":
+		if len(args) != 3 {
+			return errors.New("usage: apikey revoke PREFIX")
+		}
+		if err := authService.RevokeAPIKey(ctx, args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("API key %s revoked\n", args[2])
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown apikey command: %s", args[1])
+	}
+}
+
+func createAPIKey(ctx context.Context, authService service.AuthService, args []string) error {
+	fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	name := fs.String("name", "", "human readable key name")
+	role := fs.String("role", models.RoleAdmin, "key role: admin or user")
+	userID := fs.String("user", "", "user id for a user key, a new random id if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rawKey, key, err := authService.CreateAPIKey(ctx, *name, *role, *userID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s key %q (prefix %s)\n", key.Role, key.Name, key.Prefix)
+	if key.UserID != "" {
+		fmt.Printf("User: %s\n", key.UserID)
+	}
+	fmt.Println("Store it now, it will not be shown again:")
+	fmt.Println(rawKey)
+	return nil
+}
+
+func listAPIKeys(ctx context.Context, authService service.AuthService) error {
+	keys, err := authService.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PREFIX\tNAME\tROLE\tUSER\tCREATED\tSTATUS")
+	for _, key := range keys {
+		status := "active"
+		if key.IsRevoked() {
+			status = "revoked " + key.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", key.Prefix, key.Name, key.Role, key.UserID, key.CreatedAt.Format(time.RFC3339), status)
+	}
+	return w.Flush()
+}
+
+func printMigrations(ctx context.Context, store repository.Store) error {
+	migrator, ok := store.(repository.Migrator)
+	if !ok {
+		fmt.Printf("The %s storage driver has no versioned migrations\n", store.Driver())
+		return nil
+	}
+
+	migrations, err := migrator.Migrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, migration := range migrations {
+		applied := "pending"
+		if migration.AppliedAt != nil {
+			applied = migration.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", migration.Version, migration.Name, applied)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+)
+
+func TestEvents(t *testing.T) {
+	app := newTestApp(t)
+	user, stranger := app.newUser(), app.newUser()
+	app.addTimetableFavorites(user.APIKey)
+
+	for _, body := range []string{
+		`{"title":"Кружок","start":"2026-09-08T18:00:00+03:00","end":"2026-09-08T17:00:00+03:00"}`,
+		`{"title":"Кружок","start":"2026-09-08T18:00:00+03:00","end":"2026-09-08T19:30:00+03:00","recurrence":"FREQ=YEARLY"}`,
+	} {
+		if resp := app.send(user.APIKey, http.MethodPost, "/api/v1/me/events", body); resp.Code != http.StatusBadRequest {
+			t.Errorf("create event %s = %d, want 400", body, resp.Code)
+		}
+	}
+
+	// Повторяющийся кружок попадает в ленту рядом с занятиями
+	var event models.Event
+	app.decode(app.send(user.APIKey, http.MethodPost, "/api/v1/me/events",
+		`{"title":"Кружок","location":"Актовый зал","start":"2026-09-08T18:00:00+03:00","end":"2026-09-08T19:30:00+03:00","recurrence":"freq=weekly;count=5"}`),
+		http.StatusCreated, &event, "create event")
+	if event.Recurrence != "FREQ=WEEKLY;COUNT=5" {
+		t.Errorf("recurrence must be normalized, got %q", event.Recurrence)
+	}
+
+	eventEntries := func() []models.TimetableEntry {
+		t.Helper()
+		var events []models.TimetableEntry
+		for _, entry := range app.timetable(user.APIKey, "?from=2026-09-07&to=2026-09-20").Entries {
+			if entry.Event != nil {
+				events = append(events, entry)
+			}
+		}
+		return events
+	}
+	entries := eventEntries()
+	if len(entries) != 2 || entries[0].Date != "2026-09-08" || entries[1].Date != "2026-09-15" ||
+		entries[0].Lesson != nil || entries[0].End.Sub(entries[0].Start) != 90*time.Minute ||
+		entries[0].Sources[0] != (models.TimetableSource{Type: models.TimetableSourceEvent, Key: event.ID.Hex()}) {
+		t.Errorf("timetable must contain two weekly event entries, got %+v", entries)
+	}
+
+	eventPath := "/api/v1/me/events/" + event.ID.Hex()
+	if resp := app.get(stranger.APIKey, eventPath); resp.Code != http.StatusNotFound {
+		t.Errorf("event of another user = %d, want 404", resp.Code)
+	}
+	if resp := app.send(user.APIKey, http.MethodPut, eventPath,
+		`{"title":"Кружок","start":"2026-09-08T18:00:00+03:00","end":"2026-09-08T19:30:00+03:00"}`); resp.Code != http.StatusOK {
+		t.Errorf("update event = %d: %s", resp.Code, resp.Body)
+	}
+	if entries := eventEntries(); len(entries) != 1 {
+		t.Errorf("one-off event must appear once, got %+v", entries)
+	}
+	if resp := app.do(user.APIKey, http.MethodDelete, eventPath); resp.Code != http.StatusOK {
+		t.Errorf("delete event = %d: %s", resp.Code, resp.Body)
+	}
+	if resp := app.get(user.APIKey, "/api/v1/me/events"); resp.Code != http.StatusOK || strings.TrimSpace(resp.Body.String()) != "[]" {
+		t.Errorf("events after delete = %d: %s", resp.Code, resp.Body)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"schedluer/internal/models"
+)
+
+func TestFavorites(t *testing.T) {
+	app := newTestApp(t)
+	owner, stranger := app.newUser(), app.newUser()
+
+	// Подробности избранного берутся из закэшированных списков: запрашиваем их и ждем фоновую запись
+	for _, path := range []string{"/api/v1/groups", "/api/v1/employees/m-petrova"} {
+		if resp := app.get("", path); resp.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, resp.Code, resp.Body)
+		}
+	}
+	app.waitTasks()
+
+	app.addFavorites(owner.APIKey,
+		"/api/v1/favorites/221701",
+		"/api/v1/favorites/items/employee/m-petrova",
+		"/api/v1/favorites/items/auditory/101-5%20%D0%BA.",
+	)
+	for path, want := range map[string]int{
+		"/api/v1/favorites/items/building/1":      http.StatusBadRequest,
+		"/api/v1/favorites/items/group/999999":    http.StatusNotFound,
+		"/api/v1/favorites/items/employee/nobody": http.StatusNotFound,
+		"/api/v1/favorites/999999":                http.StatusNotFound,
+	} {
+		if resp := app.do(owner.APIKey, http.MethodPost, path); resp.Code != want {
+			t.Errorf("POST %s = %d, want %d", path, resp.Code, want)
+		}
+	}
+
+	var items []models.FavoriteItem
+	app.decode(app.get(owner.APIKey, "/api/v1/favorites/items"), http.StatusOK, &items, "favorite items")
+	if len(items) != 3 {
+		t.Fatalf("favorite items = %+v, want 3", items)
+	}
+	if items[0].Group == nil || items[0].Group.Course == 0 {
+		t.Errorf("group favorite has no metadata: %+v", items[0])
+	}
+	if items[1].Employee == nil || !strings.Contains(items[1].Title, "Петрова") {
+		t.Errorf("employee favorite has no metadata: %+v", items[1])
+	}
+	if items[2].Auditory == nil || items[2].Key != "101-5 к." {
+		t.Errorf("auditory favorite has no metadata: %+v", items[2])
+	}
+
+	if resp := app.get(owner.APIKey, "/api/v1/favorites"); !strings.Contains(resp.Body.String(), `"group_number":"221701"`) || strings.Contains(resp.Body.String(), "m-petrova") {
+		t.Errorf("legacy favorites must list groups only: %s", resp.Body)
+	}
+	// user_id из запроса не читается: без ключа видно только общее избранное default
+	if resp := app.get("", "/api/v1/favorites?user_id="+owner.UserID); strings.TrimSpace(resp.Body.String()) != "[]" {
+		t.Errorf("anonymous favorites by user_id = %d: %s", resp.Code, resp.Body)
+	}
+	if resp := app.do("", http.MethodPost, "/api/v1/favorites/items/group/221701?user_id="+owner.UserID); resp.Code != http.StatusOK {
+		t.Fatalf("anonymous add = %d: %s", resp.Code, resp.Body)
+	}
+	app.do("", http.MethodDelete, "/api/v1/favorites/items/employee/m-petrova?user_id="+owner.UserID)
+	if resp := app.get(owner.APIKey, "/api/v1/favorites/items/employee/m-petrova/check"); !strings.Contains(resp.Body.String(), "true") {
+		t.Errorf("anonymous request removed another user's favorite: %s", resp.Body)
+	}
+	if resp := app.get("", "/api/v1/favorites?user_id=someone"); !strings.Contains(resp.Body.String(), "221701") {
+		t.Errorf("anonymous favorites must be the shared default list: %s", resp.Body)
+	}
+	if resp := app.get(owner.APIKey, "/api/v1/favorites?user_id="+stranger.UserID); !strings.Contains(resp.Body.String(), "221701") {
+		t.Errorf("favorites with a key must belong to the key's user: %s", resp.Body)
+	}
+	if resp := app.get(stranger.APIKey, "/api/v1/favorites/items"); strings.TrimSpace(resp.Body.String()) != "[]" {
+		t.Errorf("favorites of another user leaked: %s", resp.Body)
+	}
+}
+
+// TestFavoriteCollections — коллекции, подписи и пользовательский порядок избранного
+func TestFavoriteCollections(t *testing.T) {
+	app := newTestApp(t)
+	owner, stranger := app.newUser(), app.newUser()
+	app.addFavorites(owner.APIKey,
+		"/api/v1/favorites/items/group/221701",
+		"/api/v1/favorites/items/employee/m-petrova",
+		"/api/v1/favorites/items/auditory/101-5%20%D0%BA.",
+	)
+	var items []models.FavoriteItem
+	app.decode(app.get(owner.APIKey, "/api/v1/favorites/items"), http.StatusOK, &items, "favorite items")
+	if len(items) != 3 {
+		t.Fatalf("favorite items = %+v, want 3", items)
+	}
+
+	// Аудитория переезжает в коллекцию, группа и преподаватель меняются местами
+	var collection models.FavoriteCollection
+	app.decode(app.send(owner.APIKey, http.MethodPost, "/api/v1/favorites/collections", `{"name":"Корпус 5","color":"#3366ff"}`),
+		http.StatusCreated, &collection, "create collection")
+	if collection.Color != "#3366FF" {
+		t.Errorf("collection color must be normalized, got %q", collection.Color)
+	}
+	if resp := app.send(owner.APIKey, http.MethodPost, "/api/v1/favorites/collections", `{"name":"Корпус 5"}`); resp.Code != http.StatusConflict {
+		t.Errorf("duplicate collection name = %d, want 409", resp.Code)
+	}
+	if resp := app.send(owner.APIKey, http.MethodPatch, "/api/v1/favorites/items/auditory/101-5%20%D0%BA.",
+		`{"collection_id":"`+collection.ID.Hex()+`","label":"Лекционная"}`); resp.Code != http.StatusOK {
+		t.Fatalf("move to collection = %d: %s", resp.Code, resp.Body)
+	}
+	if resp := app.send(owner.APIKey, http.MethodPatch, "/api/v1/favorites/items/group/221701", `{"color":"red"}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("invalid color = %d, want 400", resp.Code)
+	}
+	if resp := app.send(owner.APIKey, http.MethodPut, "/api/v1/favorites/order", `{"ids":["`+items[0].ID.Hex()+`"]}`); resp.Code != http.StatusConflict {
+		t.Errorf("incomplete reorder = %d, want 409", resp.Code)
+	}
+	if resp := app.send(owner.APIKey, http.MethodPut, "/api/v1/favorites/order", `{"ids":["`+items[1].ID.Hex()+`","`+items[0].ID.Hex()+`"]}`); resp.Code != http.StatusOK {
+		t.Fatalf("reorder = %d: %s", resp.Code, resp.Body)
+	}
+
+	items = nil
+	app.decode(app.get(owner.APIKey, "/api/v1/favorites/items"), http.StatusOK, &items, "favorite items")
+	// Позиции после перестановки — 0 и 1, у перенесенной аудитории — время переноса
+	if len(items) != 3 || items[0].Key != "m-petrova" || items[1].Key != "221701" ||
+		items[2].CollectionID != collection.ID.Hex() || items[2].Label != "Лекционная" {
+		t.Errorf("favorites are not in the user's order: %+v", items)
+	}
+
+	// Удаление коллекции оставляет ее элементы в избранном вне коллекций
+	if resp := app.do(stranger.APIKey, http.MethodDelete, "/api/v1/favorites/collections/"+collection.ID.Hex()); resp.Code != http.StatusNotFound {
+		t.Errorf("delete collection of another user = %d, want 404", resp.Code)
+	}
+	if resp := app.do(owner.APIKey, http.MethodDelete, "/api/v1/favorites/collections/"+collection.ID.Hex()); resp.Code != http.StatusOK {
+		t.Fatalf("delete collection = %d: %s", resp.Code, resp.Body)
+	}
+	items = nil
+	app.decode(app.get(owner.APIKey, "/api/v1/favorites/items"), http.StatusOK, &items, "favorite items")
+	if len(items) != 3 || items[2].Key != "101-5 к." || items[2].CollectionID != "" {
+		t.Errorf("items of a deleted collection must stay unfiled: %+v", items)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"schedluer/internal/config"
+	"schedluer/internal/container"
+	"schedluer/internal/handler"
+	"schedluer/internal/logging"
+	"schedluer/internal/metrics"
+	"schedluer/internal/tracing"
+
+	_ "schedluer/docs"
+)
+
+// @title           Schedluer API
+// @version         1.0
+// @description     API для работы с расписанием БГУИР
+// @host      localhost:8080
+// @BasePath  /api/v1
+// @schemes   http https
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		logrus.Fatalf("Failed to load config: %v", err)
+	}
+
+	setupLogger(cfg.Logger.Level)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logrus.Fatalf("Failed to setup tracing: %v", err)
+	}
+
+	ctn, err := container.NewContainer(cfg)
+	if err != nil {
+		logrus.Fatalf("Failed to create container: %v", err)
+	}
+	defer func() {
+		if err := ctn.Close(); err != nil {
+			logrus.Errorf("Failed to close container: %v", err)
+		}
+	}()
+
+	router := setupRouter(ctn, cfg)
+
+	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
+	logrus.Infof("Starting server on %s", addr)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	// По SIGHUP перечитываем конфиг; при ошибке продолжаем работать со старым
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			newCfg, err := config.Load(os.Args[1:])
+			if err != nil {
+				logrus.WithError(err).Error("Failed to reload config, keeping the current one")
+				continue
+			}
+			ctn.Reload(newCfg)
+		}
+	}()
+
+	// Напоминания идут в отдельной горутине до остановки сервера
+	remindersCtx, stopReminders := context.WithCancel(context.Background())
+	defer stopReminders()
+	remindersDone := make(chan struct{})
+	if ctn.Reminders != nil {
+		go func() {
+			defer close(remindersDone)
+			ctn.Reminders.Run(remindersCtx)
+		}()
+	} else {
+		close(remindersDone)
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-quit
+	logrus.Info("Shutting down server...")
+
+	// Один дедлайн на все: сначала дожидаемся текущих запросов, затем фоновых записей в БД
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		logrus.Errorf("Failed to shutdown server gracefully: %v", err)
+	}
+
+	stopReminders()
+	select {
+	case <-remindersDone:
+	case <-ctx.Done():
+		logrus.Error("Reminder scheduler did not stop in time")
+	}
+
+	if err := ctn.Tasks.Shutdown(ctx); err != nil {
+		logrus.Errorf("Background tasks did not finish in time: %v", err)
+		// Задачам отменен контекст; хранилище закрывается следом (defer ctn.Close), поэтому даем им выйти
+		graceCtx, cancelGrace := context.WithTimeout(context.Background(), taskCancelGrace)
+		if err := ctn.Tasks.Wait(graceCtx); err != nil {
+			logrus.Error("Background tasks ignored cancellation, closing storage under them")
+		}
+		cancelGrace()
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		logrus.Errorf("Failed to flush traces: %v", err)
+	}
+
+	logrus.Info("Server stopped")
+}
+
+// taskCancelGrace — сколько ждать фоновые задачи после отмены их контекста при остановке
+const taskCancelGrace = 5 * time.Second
+
+func setupLogger(level string) {
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	logrus.AddHook(logging.RedactHook{})
+
+	// Уровень уже проверен config.Validate
+	if parsed, err := logrus.ParseLevel(level); err == nil {
+		logrus.SetLevel(parsed)
+	}
+}
+
+func setupRouter(ctn *container.Container, cfg *config.Config) *gin.Engine {
+	// gin.Default() пишет свой текстовый access-лог, вместо него — RequestLogger
+	router := gin.New()
+	router.Use(gin.Recovery())
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logrus.Warnf("Invalid trusted proxies %v: %v", cfg.Server.TrustedProxies, err)
+	}
+
+	router.Use(ctn.CORS.Middleware())
+
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		switch r.URL.Path {
+		case "/metrics", "/health", "/livez", "/readyz":
+			return false
+		}
+		return true
+	})))
+	router.Use(handler.RequestLogger(ctn.Logger))
+	router.Use(metrics.Middleware())
+	router.Use(ctn.RateLimiter.Middleware())
+
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	ctn.Router.SetupRoutes(router)
+
+	return router
+}
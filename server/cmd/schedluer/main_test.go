@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"schedluer/internal/config"
+	"schedluer/internal/container"
+	"schedluer/internal/models"
+	"schedluer/pkg/bsuir/bsuirtest"
+	"schedluer/pkg/notify/notifytest"
+)
+
+// testApp — весь сервис с хранилищем в памяти поверх фейкового API БГУИРа и локального SMTP, без сети.
+// Каждый тест поднимает свой экземпляр, поэтому порядок тестов и их данные не влияют друг на друга.
+type testApp struct {
+	t      *testing.T
+	fake   *bsuirtest.Server
+	mail   *notifytest.SMTPServer
+	ctn    *container.Container
+	router *gin.Engine
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	fake := bsuirtest.NewServer(bsuirtest.DefaultFixtures())
+	t.Cleanup(fake.Close)
+
+	cfg, err := config.Load([]string{"-storage", config.StorageMemory, "-bsuir-base-url", fake.BaseURL()})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	cfg.RateLimit.Enabled = false
+
+	mail, err := notifytest.NewSMTPServer()
+	if err != nil {
+		t.Fatalf("failed to start SMTP server: %v", err)
+	}
+	t.Cleanup(func() { mail.Close() })
+	cfg.Notifications.Enabled = true
+	cfg.Notifications.SMTP.Host, cfg.Notifications.SMTP.Port = mail.Host(), mail.Port()
+	cfg.Notifications.SMTP.From = "schedluer@example.com"
+	// Заглушка webhook слушает на loopback по http
+	cfg.Notifications.WebhookAllowPrivate = true
+
+	ctn, err := container.NewContainer(cfg)
+	if err != nil {
+		t.Fatalf("failed to create container: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = ctn.Tasks.Shutdown(ctx)
+		_ = ctn.Close()
+	})
+
+	return &testApp{t: t, fake: fake, mail: mail, ctn: ctn, router: setupRouter(ctn, cfg)}
+}
+
+// send выполняет запрос с JSON-телом; key — API-ключ пользователя, "" — анонимно
+func (a *testApp) send(key, method, path, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		request.Header.Set("X-API-Key", key)
+	}
+	a.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (a *testApp) do(key, method, path string) *httptest.ResponseRecorder {
+	return a.send(key, method, path, "")
+}
+
+func (a *testApp) get(key, path string) *httptest.ResponseRecorder {
+	return a.do(key, http.MethodGet, path)
+}
+
+// decode разбирает ответ с ожидаемым кодом в target, иначе роняет тест
+func (a *testApp) decode(resp *httptest.ResponseRecorder, code int, target any, what string) {
+	a.t.Helper()
+	if err := json.Unmarshal(resp.Body.Bytes(), target); err != nil || resp.Code != code {
+		a.t.Fatalf("%s = %d: %s", what, resp.Code, resp.Body)
+	}
+}
+
+// testUser — пользователь с ключом, выпущенным как в schedluer-admin
+type testUser struct {
+	UserID string
+	APIKey string
+}
+
+// newUser выпускает ключ новому пользователю
+func (a *testApp) newUser() testUser {
+	a.t.Helper()
+	rawKey, key, err := a.ctn.AuthService.CreateAPIKey(context.Background(), "test", models.RoleUser, "")
+	if err != nil {
+		a.t.Fatalf("failed to create user key: %v", err)
+	}
+	return testUser{UserID: key.UserID, APIKey: rawKey}
+}
+
+// waitTasks ждет фоновые записи в хранилище (списки групп и преподавателей, расписания)
+func (a *testApp) waitTasks() {
+	for deadline := time.Now().Add(5 * time.Second); a.ctn.Tasks.Stats().Running > 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (a *testApp) addFavorites(key string, paths ...string) {
+	a.t.Helper()
+	for _, path := range paths {
+		if resp := a.do(key, http.MethodPost, path); resp.Code != http.StatusOK {
+			a.t.Fatalf("POST %s = %d: %s", path, resp.Code, resp.Body)
+		}
+	}
+}
+
+// addTimetableFavorites — группа и два ее преподавателя: источники для ленты с объединением и накладками
+func (a *testApp) addTimetableFavorites(key string) {
+	a.t.Helper()
+	a.addFavorites(key,
+		"/api/v1/favorites/items/group/221701",
+		"/api/v1/favorites/items/employee/i-ivanov",
+		"/api/v1/favorites/items/employee/m-petrova",
+	)
+}
+
+func (a *testApp) timetable(key, query string) models.Timetable {
+	a.t.Helper()
+	var timetable models.Timetable
+	a.decode(a.get(key, "/api/v1/me/timetable"+query), http.StatusOK, &timetable, "timetable"+query)
+	return timetable
+}
+
+func (a *testApp) groupSchedule(key, query string) models.ScheduleResponse {
+	a.t.Helper()
+	var schedule models.ScheduleResponse
+	a.decode(a.get(key, "/api/v1/schedule/group/221701"+query), http.StatusOK, &schedule, "group schedule"+query)
+	return schedule
+}
+
+func TestHealthAndAuth(t *testing.T) {
+	app := newTestApp(t)
+
+	for _, path := range []string{"/livez", "/readyz"} {
+		if resp := app.get("", path); resp.Code != http.StatusOK {
+			t.Errorf("%s = %d: %s", path, resp.Code, resp.Body)
+		}
+	}
+
+	// Пользователи ходят в /me с ключом, выпущенным администратором
+	user := app.newUser()
+	if resp := app.get("", "/api/v1/me/timetable"); resp.Code != http.StatusUnauthorized {
+		t.Errorf("/me without a key = %d, want 401", resp.Code)
+	}
+	if resp := app.get("sch_unknown", "/api/v1/me/timetable"); resp.Code != http.StatusUnauthorized {
+		t.Errorf("/me with an unknown key = %d, want 401", resp.Code)
+	}
+	if resp := app.get(user.APIKey, "/api/v1/me/timetable"); resp.Code != http.StatusOK {
+		t.Errorf("/me with the user's key = %d: %s", resp.Code, resp.Body)
+	}
+	if resp := app.do("", http.MethodPost, "/api/v1/auth/register"); resp.Code != http.StatusNotFound {
+		t.Errorf("anonymous registration = %d, want 404", resp.Code)
+	}
+	if resp := app.get("", "/api/v1/schedule/group/221701?personalized=true&user_id="+user.UserID); resp.Code != http.StatusUnauthorized {
+		t.Errorf("personalized schedule without a key = %d, want 401", resp.Code)
+	}
+}
+
+func TestScheduleCache(t *testing.T) {
+	app := newTestApp(t)
+
+	upstreamCalls := app.fake.Calls("/schedule")
+	schedule := app.groupSchedule("", "")
+	if schedule.StudentGroupDto == nil || schedule.StudentGroupDto.Name != "221701" {
+		t.Fatalf("unexpected group schedule %+v", schedule.StudentGroupDto)
+	}
+
+	// Второй запрос отдается из кэша и до API не доходит
+	app.groupSchedule("", "")
+	if calls := app.fake.Calls("/schedule") - upstreamCalls; calls != 1 {
+		t.Errorf("upstream /schedule called %d times, want 1", calls)
+	}
+
+	// Ошибка API без кэша доходит до клиента, с кэшем — нет
+	app.fake.FailNext("/schedule", http.StatusServiceUnavailable, 1)
+	if resp := app.get("", "/api/v1/schedule/group/221701?useCache=false"); resp.Code != http.StatusInternalServerError {
+		t.Errorf("upstream failure = %d, want 500", resp.Code)
+	}
+	if resp := app.get("", "/api/v1/schedule/employee/i-ivanov"); resp.Code != http.StatusOK {
+		t.Errorf("employee schedule = %d: %s", resp.Code, resp.Body)
+	}
+
+	if resp := app.get("", "/api/v1/groups"); resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), "153501") {
+		t.Errorf("groups = %d: %s", resp.Code, resp.Body)
+	}
+	if resp := app.get("", "/api/v1/employees/m-petrova"); resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), "Петрова") {
+		t.Errorf("employee = %d: %s", resp.Code, resp.Body)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+)
+
+// TestNotes — заметки и домашние задания к занятиям: занятие сверяется с расписанием группы
+func TestNotes(t *testing.T) {
+	app := newTestApp(t)
+	user, stranger := app.newUser(), app.newUser()
+	app.addTimetableFavorites(user.APIKey)
+
+	var lesson *models.TimetableEntry
+	timetable := app.timetable(user.APIKey, "?from=2026-09-07&to=2026-09-14")
+	for i, entry := range timetable.Entries {
+		if entry.Lesson != nil && entry.Lesson.Subject == "ООП" && entry.Lesson.LessonTypeAbbrev == "ЛК" {
+			lesson = &timetable.Entries[i]
+			break
+		}
+	}
+	if lesson == nil {
+		t.Fatalf("no lecture in the timetable: %+v", timetable.Entries)
+	}
+	lessonRef := fmt.Sprintf(`{"group_number":"221701","subject":"ООП","lesson_type":"ЛК","date":%q,"start":%q}`, lesson.Date, lesson.Lesson.StartLessonTime)
+	otherDay := fmt.Sprintf(`{"group_number":"221701","subject":"ООП","lesson_type":"ЛК","date":%q,"start":%q}`,
+		lesson.Start.AddDate(0, 0, 1).Format(time.DateOnly), lesson.Lesson.StartLessonTime)
+
+	for _, tt := range []struct {
+		name, body string
+		want       int
+	}{
+		{"missing lesson", `{"kind":"note","text":"Нет такой пары","lesson":` + otherDay + `}`, http.StatusNotFound},
+		{"homework without due_at", `{"kind":"homework","text":"Без срока","lesson":` + lessonRef + `}`, http.StatusBadRequest},
+		{"attachment with ftp URL", `{"kind":"note","text":"Слайды","lesson":` + lessonRef + `,"attachments":[{"name":"slides","url":"ftp://example.com/s.pdf"}]}`, http.StatusBadRequest},
+	} {
+		if resp := app.send(user.APIKey, http.MethodPost, "/api/v1/me/notes", tt.body); resp.Code != tt.want {
+			t.Errorf("note with %s = %d, want %d: %s", tt.name, resp.Code, tt.want, resp.Body)
+		}
+	}
+
+	var note, homework models.LessonNote
+	app.decode(app.send(user.APIKey, http.MethodPost, "/api/v1/me/notes",
+		`{"kind":"note","text":"Слайды","lesson":`+lessonRef+`,"attachments":[{"name":"slides.pdf","url":"https://example.com/slides.pdf","size":1024}]}`),
+		http.StatusCreated, &note, "create note")
+	if len(note.Attachments) != 1 {
+		t.Errorf("note attachments = %+v", note.Attachments)
+	}
+	app.decode(app.send(user.APIKey, http.MethodPost, "/api/v1/me/notes",
+		`{"kind":"homework","text":"Задачи 1-5","lesson":`+lessonRef+`,"due_at":"2026-09-20T23:59:00+03:00"}`),
+		http.StatusCreated, &homework, "create homework")
+	if homework.DueAt == nil {
+		t.Errorf("homework has no due date: %+v", homework)
+	}
+
+	var notes []models.LessonNote
+	app.decode(app.get(user.APIKey, "/api/v1/me/notes?group=221701&date="+lesson.Date), http.StatusOK, &notes, "notes for the lesson")
+	if len(notes) != 2 {
+		t.Errorf("notes for the lesson = %+v, want 2", notes)
+	}
+	app.decode(app.get(stranger.APIKey, "/api/v1/me/notes?group=221701&date="+lesson.Date), http.StatusOK, &notes, "notes of another user")
+	if len(notes) != 0 {
+		t.Errorf("notes of another user leaked: %+v", notes)
+	}
+
+	// Домашние задания фильтруются по сроку (due_before — дата, срок строго раньше ее начала) и по выполнению
+	if resp := app.get(user.APIKey, "/api/v1/me/homework?due_before=2026-09"); resp.Code != http.StatusBadRequest {
+		t.Errorf("bad due_before = %d, want 400", resp.Code)
+	}
+	homeworkCount := func(query string) int {
+		t.Helper()
+		var list []models.LessonNote
+		app.decode(app.get(user.APIKey, "/api/v1/me/homework"+query), http.StatusOK, &list, "homework"+query)
+		return len(list)
+	}
+	for query, want := range map[string]int{"": 1, "?due_before=2026-09-21": 1, "?due_before=2026-09-20": 0} {
+		if got := homeworkCount(query); got != want {
+			t.Errorf("homework%s = %d, want %d", query, got, want)
+		}
+	}
+
+	notePath := "/api/v1/me/notes/" + note.ID.Hex()
+	if resp := app.send(user.APIKey, http.MethodPatch, notePath, `{"done":true}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("marking a plain note done = %d, want 400", resp.Code)
+	}
+	homeworkPath := "/api/v1/me/notes/" + homework.ID.Hex()
+	homework = models.LessonNote{}
+	app.decode(app.send(user.APIKey, http.MethodPatch, homeworkPath, `{"done":true}`), http.StatusOK, &homework, "mark homework done")
+	if !homework.Done || homework.DoneAt == nil || homework.Text != "Задачи 1-5" {
+		t.Errorf("mark homework done = %+v", homework)
+	}
+	if got := homeworkCount(""); got != 0 {
+		t.Errorf("done homework must be hidden by default, got %d", got)
+	}
+	if got := homeworkCount("?include_done=true"); got != 1 {
+		t.Errorf("homework with include_done = %d, want 1", got)
+	}
+
+	if resp := app.do(stranger.APIKey, http.MethodDelete, notePath); resp.Code != http.StatusNotFound {
+		t.Errorf("note of another user = %d, want 404", resp.Code)
+	}
+	if resp := app.do(user.APIKey, http.MethodDelete, notePath); resp.Code != http.StatusOK {
+		t.Errorf("delete note = %d: %s", resp.Code, resp.Body)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"schedluer/internal/models"
+)
+
+func TestOverrides(t *testing.T) {
+	app := newTestApp(t)
+	user, stranger := app.newUser(), app.newUser()
+
+	for _, body := range []string{
+		`{"schedule_type":"group","key":"221701","series":{"subject":"ВМ","lesson_type":"ПЗ"},"hidden":true,"auditories":["202-5 к."]}`,
+		`{"schedule_type":"group","key":"221701","series":{"subject":"ООП","weekday":"Funday"},"auditories":["202-5 к."]}`,
+	} {
+		if resp := app.send(user.APIKey, http.MethodPost, "/api/v1/me/overrides", body); resp.Code != http.StatusBadRequest {
+			t.Errorf("create override %s = %d, want 400", body, resp.Code)
+		}
+	}
+
+	// Практика скрыта, лекция перенесена; видно только владельцу и только с personalized=true
+	var hide, move models.LessonOverride
+	app.decode(app.send(user.APIKey, http.MethodPost, "/api/v1/me/overrides",
+		`{"schedule_type":"group","key":"221701","series":{"subject":"ВМ","lesson_type":"ПЗ"},"hidden":true}`),
+		http.StatusCreated, &hide, "create hiding override")
+	app.decode(app.send(user.APIKey, http.MethodPost, "/api/v1/me/overrides",
+		`{"schedule_type":"group","key":"221701","series":{"subject":"ООП","lesson_type":"ЛК","weekday":"Понедельник","start":"9:00"},"auditories":["202-5 к."],"start_time":"13:50","end_time":"15:10"}`),
+		http.StatusCreated, &move, "create moving override")
+	if move.Series.Start != "09:00" {
+		t.Errorf("series start must be normalized, got %q", move.Series.Start)
+	}
+
+	monday := app.groupSchedule(user.APIKey, "?personalized=true").Schedules["Понедельник"]
+	if len(monday) != 2 || monday[0].LessonTypeAbbrev != "ЛР" || monday[1].Subject != "ООП" || monday[1].StartLessonTime != "13:50" ||
+		monday[1].EndLessonTime != "15:10" || len(monday[1].Auditories) != 1 || monday[1].Auditories[0] != "202-5 к." {
+		t.Errorf("personalized Monday must hide the practice and move the lecture, got %+v", monday)
+	}
+	if wednesday := app.groupSchedule(user.APIKey, "?personalized=true").Schedules["Среда"]; len(wednesday) != 1 {
+		t.Errorf("overrides must not touch other lesson types, got %+v", wednesday)
+	}
+	for _, request := range []struct{ key, query string }{{"", ""}, {user.APIKey, ""}, {stranger.APIKey, "?personalized=true"}} {
+		if monday := app.groupSchedule(request.key, request.query).Schedules["Понедельник"]; len(monday) != 3 || monday[0].Auditories[0] != "101-5 к." {
+			t.Errorf("schedule%s must stay as published, got %+v", request.query, monday)
+		}
+	}
+
+	overridePath := "/api/v1/me/overrides/" + hide.ID.Hex()
+	if resp := app.do(stranger.APIKey, http.MethodDelete, overridePath); resp.Code != http.StatusNotFound {
+		t.Errorf("override of another user = %d, want 404", resp.Code)
+	}
+	if resp := app.do(user.APIKey, http.MethodDelete, overridePath); resp.Code != http.StatusOK {
+		t.Errorf("delete override = %d: %s", resp.Code, resp.Body)
+	}
+	if monday := app.groupSchedule(user.APIKey, "?personalized=true").Schedules["Понедельник"]; len(monday) != 3 {
+		t.Errorf("deleted override must no longer hide the practice, got %+v", monday)
+	}
+}
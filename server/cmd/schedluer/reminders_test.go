@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+	"schedluer/pkg/converter"
+	"schedluer/pkg/notify"
+)
+
+// TestReminders — webhook и почта уходят на локальные заглушки, часы планировщика подменены
+func TestReminders(t *testing.T) {
+	app := newTestApp(t)
+	user := app.newUser()
+	app.addTimetableFavorites(user.APIKey)
+
+	var (
+		hooksMu sync.Mutex
+		hooks   []map[string]any
+	)
+	hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var hook map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&hook)
+		hooksMu.Lock()
+		hooks = append(hooks, hook)
+		hooksMu.Unlock()
+	}))
+	defer hookServer.Close()
+	sentHooks := func() []map[string]any {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		return append([]map[string]any(nil), hooks...)
+	}
+
+	if app.ctn.Reminders == nil {
+		t.Error("reminder scheduler must be created when notifications are enabled")
+	}
+	for _, body := range []string{
+		`{"channel":"telegram","target":"123","lead_minutes":15}`,
+		`{"channel":"email","target":"not an address","lead_minutes":15}`,
+	} {
+		if resp := app.send(user.APIKey, http.MethodPut, "/api/v1/me/reminders", body); resp.Code != http.StatusBadRequest {
+			t.Errorf("subscribe %s = %d, want 400", body, resp.Code)
+		}
+	}
+	if resp := app.get(user.APIKey, "/api/v1/me/reminders"); resp.Code != http.StatusNotFound {
+		t.Errorf("reminder before subscribing = %d, want 404", resp.Code)
+	}
+	if resp := app.send(user.APIKey, http.MethodPut, "/api/v1/me/reminders",
+		fmt.Sprintf(`{"channel":"webhook","target":%q,"lead_minutes":15}`, hookServer.URL)); resp.Code != http.StatusOK {
+		t.Fatalf("subscribe to webhook reminders = %d: %s", resp.Code, resp.Body)
+	}
+
+	now := time.Date(2026, 9, 7, 8, 50, 0, 0, converter.Minsk)
+	scheduler := service.NewReminderScheduler(app.ctn.ReminderRepo, app.ctn.TimetableService, map[string]notify.Sender{
+		models.ReminderChannelWebhook: notify.NewWebhookSender(notify.WebhookConfig{Timeout: 5 * time.Second, AllowPrivate: true}),
+		models.ReminderChannelEmail: notify.NewSMTPSender(notify.SMTPConfig{
+			Host: app.mail.Host(), Port: app.mail.Port(), From: "schedluer@example.com", Timeout: 5 * time.Second,
+		}),
+	}, time.Minute, func() time.Time { return now }, app.ctn.Logger)
+	tick := func() {
+		t.Helper()
+		if err := scheduler.Tick(context.Background()); err != nil {
+			t.Fatalf("reminder tick at %s: %v", now, err)
+		}
+	}
+
+	// Лекция в 09:00 попадает в окно 15 минут; повторные проходы ее не дублируют
+	tick()
+	tick()
+	now = now.Add(5 * time.Minute)
+	tick()
+	if hooks := sentHooks(); len(hooks) != 1 || hooks[0]["subject"] != "Через 10 мин: ООП (ЛК)" || !strings.Contains(fmt.Sprint(hooks[0]["text"]), "101-5 к.") {
+		t.Fatalf("webhook reminders = %+v, want one for the 09:00 lecture", hooks)
+	}
+
+	// В тихие часы напоминание откладывается, после них уходит, пока занятие не началось
+	var reminder models.ReminderSubscription
+	app.decode(app.send(user.APIKey, http.MethodPut, "/api/v1/me/reminders",
+		`{"channel":"email","target":"Student <student@example.com>","lead_minutes":15,"quiet_hours":{"from":"12:00","to":"12:20"}}`),
+		http.StatusOK, &reminder, "switch reminders to email")
+	if reminder.Target != "student@example.com" {
+		t.Errorf("email target must be normalized, got %q", reminder.Target)
+	}
+	now = time.Date(2026, 9, 7, 12, 15, 0, 0, converter.Minsk)
+	tick()
+	if mails := app.mail.Mails(); len(mails) != 0 {
+		t.Errorf("reminder sent during quiet hours: %+v", mails)
+	}
+	now = now.Add(5 * time.Minute)
+	tick()
+	mails := app.mail.Mails()
+	if len(mails) != 1 || len(mails[0].To) != 1 || mails[0].To[0] != "student@example.com" || !strings.Contains(mails[0].Data, "12:25") {
+		t.Errorf("email reminders = %+v, want one for the 12:25 practice", mails)
+	}
+	if hooks := sentHooks(); len(hooks) != 1 {
+		t.Errorf("webhook must not be used after switching to email, got %d hooks", len(hooks))
+	}
+
+	if resp := app.do(user.APIKey, http.MethodDelete, "/api/v1/me/reminders"); resp.Code != http.StatusOK {
+		t.Errorf("unsubscribe = %d: %s", resp.Code, resp.Body)
+	}
+	if resp := app.do(user.APIKey, http.MethodDelete, "/api/v1/me/reminders"); resp.Code != http.StatusNotFound {
+		t.Errorf("second unsubscribe = %d, want 404", resp.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+)
+
+func TestShares(t *testing.T) {
+	app := newTestApp(t)
+	user, stranger := app.newUser(), app.newUser()
+	app.addTimetableFavorites(user.APIKey)
+	timetable := app.timetable(user.APIKey, "?from=2026-09-07&to=2026-09-14")
+
+	for _, body := range []string{`{"kind":"calendar"}`, `{"kind":"timetable","expires_at":"2020-01-01T00:00:00Z"}`} {
+		if resp := app.send(user.APIKey, http.MethodPost, "/api/v1/me/shares", body); resp.Code != http.StatusBadRequest {
+			t.Errorf("create share %s = %d, want 400", body, resp.Code)
+		}
+	}
+
+	// Лента целиком: JSON и календарь
+	var timetableShare models.CreatedShare
+	app.decode(app.send(user.APIKey, http.MethodPost, "/api/v1/me/shares", `{"kind":"timetable","title":"221701"}`),
+		http.StatusCreated, &timetableShare, "create timetable share")
+	var shared models.SharedView
+	app.decode(app.get("", "/api/v1/shared/"+timetableShare.Token+"?from=2026-09-07&to=2026-09-14"), http.StatusOK, &shared, "shared timetable")
+	if shared.Timetable == nil || len(shared.Timetable.Entries) != len(timetable.Entries) {
+		t.Errorf("shared timetable differs from the owner's: %+v", shared.Timetable)
+	}
+	resp := app.get("", "/api/v1/shared/"+timetableShare.Token+".ics?from=2026-09-07")
+	if resp.Code != http.StatusOK || !strings.HasPrefix(resp.Header().Get("Content-Type"), "text/calendar") ||
+		!strings.Contains(resp.Body.String(), "SUMMARY:ООП (ЛК)") || !strings.Contains(resp.Body.String(), "X-WR-CALNAME:221701") {
+		t.Errorf("shared calendar = %d: %s", resp.Code, resp.Body)
+	}
+
+	// Личные события в публичную ленту не попадают
+	if resp := app.send(user.APIKey, http.MethodPost, "/api/v1/me/events",
+		`{"title":"Кружок","start":"2026-09-08T18:00:00+03:00","end":"2026-09-08T19:30:00+03:00"}`); resp.Code != http.StatusCreated {
+		t.Fatalf("create event = %d: %s", resp.Code, resp.Body)
+	}
+	shared = models.SharedView{}
+	app.decode(app.get("", "/api/v1/shared/"+timetableShare.Token+"?from=2026-09-07&to=2026-09-14"), http.StatusOK, &shared, "shared timetable")
+	if shared.Timetable == nil || len(shared.Timetable.Entries) != len(timetable.Entries) {
+		t.Errorf("personal events must not leak into shared timetables: %+v", shared.Timetable)
+	}
+
+	// Избранное одной коллекции, без идентификатора владельца
+	app.addFavorites(stranger.APIKey, "/api/v1/favorites/221701", "/api/v1/favorites/items/auditory/101-5%20%D0%BA.")
+	var collection models.FavoriteCollection
+	app.decode(app.send(stranger.APIKey, http.MethodPost, "/api/v1/favorites/collections", `{"name":"Корпус 5"}`),
+		http.StatusCreated, &collection, "create collection")
+	if resp := app.send(stranger.APIKey, http.MethodPatch, "/api/v1/favorites/items/auditory/101-5%20%D0%BA.",
+		`{"collection_id":"`+collection.ID.Hex()+`"}`); resp.Code != http.StatusOK {
+		t.Fatalf("move to collection = %d: %s", resp.Code, resp.Body)
+	}
+	var favoritesShare models.CreatedShare
+	app.decode(app.send(stranger.APIKey, http.MethodPost, "/api/v1/me/shares", `{"kind":"favorites","collection_id":"`+collection.ID.Hex()+`"}`),
+		http.StatusCreated, &favoritesShare, "create favorites share")
+	shared = models.SharedView{}
+	app.decode(app.get("", "/api/v1/shared/"+favoritesShare.Token), http.StatusOK, &shared, "shared favorites")
+	if len(shared.Favorites) != 1 || shared.Favorites[0].Key != "101-5 к." || shared.Favorites[0].UserID != "" {
+		t.Errorf("shared favorites = %+v", shared.Favorites)
+	}
+
+	// Отозвать ссылку может только владелец, отозванная и неизвестная ссылки — 404
+	if resp := app.do(user.APIKey, http.MethodDelete, "/api/v1/me/shares/"+favoritesShare.ID.Hex()); resp.Code != http.StatusNotFound {
+		t.Errorf("revoke by another user = %d, want 404", resp.Code)
+	}
+	if resp := app.do(stranger.APIKey, http.MethodDelete, "/api/v1/me/shares/"+favoritesShare.ID.Hex()); resp.Code != http.StatusOK {
+		t.Errorf("revoke = %d: %s", resp.Code, resp.Body)
+	}
+	for _, path := range []string{"/api/v1/shared/" + favoritesShare.Token, "/api/v1/shared/shr_unknown", "/api/v1/shared/unknown.ics"} {
+		if resp := app.get("", path); resp.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", path, resp.Code)
+		}
+	}
+}
+
+func TestShareExpiry(t *testing.T) {
+	app := newTestApp(t)
+	user := app.newUser()
+	app.addTimetableFavorites(user.APIKey)
+
+	expiresAt := time.Now().Add(time.Second)
+	var share models.CreatedShare
+	app.decode(app.send(user.APIKey, http.MethodPost, "/api/v1/me/shares", `{"kind":"timetable","expires_at":"`+expiresAt.UTC().Format(time.RFC3339Nano)+`"}`),
+		http.StatusCreated, &share, "create expiring share")
+	if resp := app.get("", "/api/v1/shared/"+share.Token+"?from=2026-09-07"); resp.Code != http.StatusOK {
+		t.Fatalf("share before expiry = %d: %s", resp.Code, resp.Body)
+	}
+
+	if share.ExpiresAt == nil || !share.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("share expires at %v, want %s", share.ExpiresAt, expiresAt)
+	}
+	time.Sleep(time.Until(expiresAt.Add(10 * time.Millisecond)))
+	for _, path := range []string{"/api/v1/shared/" + share.Token + "?from=2026-09-07", "/api/v1/shared/" + share.Token + ".ics"} {
+		if resp := app.get("", path); resp.Code != http.StatusNotFound {
+			t.Errorf("GET %s after expiry = %d, want 404", path, resp.Code)
+		}
+	}
+}
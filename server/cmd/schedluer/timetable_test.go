@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTimetable(t *testing.T) {
+	app := newTestApp(t)
+	user := app.newUser()
+	app.addTimetableFavorites(user.APIKey)
+
+	for _, query := range []string{"?from=2026-09-07&to=2026-09-01", "?from=2026-09", "?from=2026-01-01&to=2027-01-01"} {
+		if resp := app.get(user.APIKey, "/api/v1/me/timetable"+query); resp.Code != http.StatusBadRequest {
+			t.Errorf("timetable%s = %d, want 400", query, resp.Code)
+		}
+	}
+
+	// Две соседние недели — одна из них нечетная, с лабораторными
+	timetable := app.timetable(user.APIKey, "?from=2026-09-07&to=2026-09-14")
+	var lecture, practice, labs int
+	for _, entry := range timetable.Entries {
+		switch {
+		case entry.Lesson.Subject == "ООП" && entry.Lesson.LessonTypeAbbrev == "ЛК":
+			lecture++
+			if len(entry.Sources) != 2 {
+				t.Errorf("lecture must be merged from the group and the lecturer: %+v", entry.Sources)
+			}
+		case entry.Lesson.Subject == "ВМ" && entry.Lesson.LessonTypeAbbrev == "ПЗ":
+			practice++
+			if len(entry.Sources) != 2 {
+				t.Errorf("practice must be merged from the group and the teacher: %+v", entry.Sources)
+			}
+		case entry.Lesson.LessonTypeAbbrev == "ЛР":
+			labs++
+			if len(entry.Overlaps) != 1 {
+				t.Errorf("labs of two subgroups must overlap: %+v", entry)
+			}
+		}
+	}
+	if lecture != 2 || practice != 2 || labs != 2 {
+		t.Errorf("timetable has %d lectures, %d practices, %d labs; want 2, 2, 2: %+v", lecture, practice, labs, timetable.Entries)
+	}
+	for i := 1; i < len(timetable.Entries); i++ {
+		if timetable.Entries[i].Start.Before(timetable.Entries[i-1].Start) {
+			t.Errorf("timetable is not sorted by start: %s after %s", timetable.Entries[i].Start, timetable.Entries[i-1].Start)
+		}
+	}
+
+	// Пустое избранное — пустая лента, а не ошибка
+	if other := app.timetable(app.newUser().APIKey, "?from=2026-09-07&to=2026-09-14"); len(other.Entries) != 0 {
+		t.Errorf("timetable without favorites = %+v", other.Entries)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"schedluer/internal/config"
+	"schedluer/internal/container"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/bsuir/bsuirtest"
+)
+
+// TestTracingSpansShareTrace — span запроса от otelgin, span сервиса и span запроса к API БГУИРа
+// попадают в один трейс, продолжают входящий traceparent и передают его дальше в API
+func TestTracingSpansShareTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+		_ = provider.Shutdown(context.Background())
+	})
+	// Выключенная трассировка только ставит W3C-пропагатор, провайдер остается тестовым
+	if _, err := tracing.Setup(context.Background(), config.TracingConfig{}); err != nil {
+		t.Fatalf("tracing.Setup: %v", err)
+	}
+
+	// Фейковый API запоминает заголовок traceparent, с которым к нему пришли
+	var (
+		upstreamMu     sync.Mutex
+		upstreamParent string
+	)
+	fake := bsuirtest.New(bsuirtest.DefaultFixtures())
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamMu.Lock()
+		if upstreamParent == "" {
+			upstreamParent = r.Header.Get("traceparent")
+		}
+		upstreamMu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	defer upstream.Close()
+
+	cfg, err := config.Load([]string{"-storage", config.StorageMemory, "-bsuir-base-url", upstream.URL + bsuirtest.BasePath})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	cfg.RateLimit.Enabled = false
+	ctn, err := container.NewContainer(cfg)
+	if err != nil {
+		t.Fatalf("failed to create container: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = ctn.Tasks.Shutdown(ctx)
+		_ = ctn.Close()
+	}()
+	router := setupRouter(ctn, cfg)
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/schedule/group/221701", nil)
+	request.Header.Set("traceparent", traceparent)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("GET schedule = %d: %s", response.Code, response.Body)
+	}
+
+	var server, upstreamSpan sdktrace.ReadOnlySpan
+	byID := map[trace.SpanID]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		byID[span.SpanContext().SpanID()] = span
+		switch {
+		case span.SpanKind() == trace.SpanKindServer:
+			server = span
+		case span.Name() == "bsuir.GetGroupSchedule":
+			upstreamSpan = span
+		}
+	}
+	if server == nil || upstreamSpan == nil {
+		names := []string{}
+		for _, span := range recorder.Ended() {
+			names = append(names, span.Name())
+		}
+		t.Fatalf("expected a server span and bsuir.GetGroupSchedule, got %v", names)
+	}
+
+	wantTrace := "4bf92f3577b34da6a3ce929d0e0e4736"
+	if got := server.SpanContext().TraceID().String(); got != wantTrace {
+		t.Errorf("server span trace = %s, want the incoming %s", got, wantTrace)
+	}
+	if server.SpanContext().TraceID() != upstreamSpan.SpanContext().TraceID() {
+		t.Errorf("bsuir span trace %s differs from the request trace %s", upstreamSpan.SpanContext().TraceID(), server.SpanContext().TraceID())
+	}
+
+	// Span API — потомок span'а запроса, а не отдельный корень с тем же трейсом
+	descends := false
+	for span := upstreamSpan; span != nil; span = byID[span.Parent().SpanID()] {
+		if span.SpanContext().SpanID() == server.SpanContext().SpanID() {
+			descends = true
+			break
+		}
+	}
+	if !descends {
+		t.Errorf("bsuir span is not a descendant of the request span %s", server.Name())
+	}
+
+	upstreamMu.Lock()
+	defer upstreamMu.Unlock()
+	carrier := propagation.HeaderCarrier(http.Header{"Traceparent": []string{upstreamParent}})
+	propagated := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+	if propagated.TraceID().String() != wantTrace {
+		t.Errorf("upstream request traceparent = %q, want trace %s", upstreamParent, wantTrace)
+	}
+}
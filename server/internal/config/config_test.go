@@ -0,0 +1,261 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolate убирает влияние окружения и .env разработчика: тест видит только то, что задал сам
+func isolate(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	for _, key := range []string{"CONFIG_FILE", "SERVER_PORT", "LOG_LEVEL", "STORAGE_DRIVER", "MONGODB_URI", "BSUIR_API_TIMEOUT"} {
+		t.Setenv(key, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	const file = "server:\n  port: \"8082\"\nlogger:\n  level: warn\n"
+	tests := []struct {
+		name      string
+		env       map[string]string
+		file      string
+		args      []string
+		wantPort  string
+		wantLevel string
+	}{
+		{"defaults", nil, "", nil, "8080", "info"},
+		{"env over defaults", map[string]string{"SERVER_PORT": "8081", "LOG_LEVEL": "debug"}, "", nil, "8081", "debug"},
+		{"file over env", map[string]string{"SERVER_PORT": "8081", "LOG_LEVEL": "debug"}, file, nil, "8082", "warn"},
+		{"file keeps env values it does not set", map[string]string{"SERVER_PORT": "8081"}, "logger:\n  level: error\n", nil, "8081", "error"},
+		{"flags over file", map[string]string{"SERVER_PORT": "8081"}, file, []string{"-port", "8083"}, "8083", "warn"},
+		{"flags over env", map[string]string{"LOG_LEVEL": "debug"}, "", []string{"-log-level", "error"}, "8080", "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			t.Setenv("STORAGE_DRIVER", StorageMemory)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "config.yaml", tt.file)}, args...)
+			}
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Server.Port != tt.wantPort || cfg.Logger.Level != tt.wantLevel {
+				t.Errorf("port %q, level %q; want %q, %q", cfg.Server.Port, cfg.Logger.Level, tt.wantPort, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileLocation(t *testing.T) {
+	isolate(t)
+	t.Setenv("STORAGE_DRIVER", StorageMemory)
+	t.Setenv("CONFIG_FILE", writeFile(t, "env.yaml", "server:\n  port: \"9001\"\n"))
+
+	cfg, err := Load(nil)
+	if err != nil || cfg.Server.Port != "9001" {
+		t.Fatalf("Load with CONFIG_FILE = %+v, %v", cfg, err)
+	}
+
+	// -config важнее CONFIG_FILE
+	flagFile := writeFile(t, "flag.yaml", "server:\n  port: \"9002\"\n")
+	cfg, err = Load([]string{"-config", flagFile})
+	if err != nil || cfg.Server.Port != "9002" || cfg.File != flagFile {
+		t.Fatalf("Load with -config = %+v, %v", cfg, err)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want []string
+	}{
+		{"mongodb without uri", nil, nil, []string{"invalid configuration", "mongodb.uri"}},
+		{"invalid flag value", map[string]string{"STORAGE_DRIVER": StorageMemory}, []string{"-port", "http"}, []string{"server.port"}},
+		{"positional argument", map[string]string{"STORAGE_DRIVER": StorageMemory}, []string{"serve"}, []string{"unexpected arguments"}},
+		{"env parse error", map[string]string{"STORAGE_DRIVER": StorageMemory, "BSUIR_API_TIMEOUT": "30"}, nil, []string{"BSUIR_API_TIMEOUT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			_, err := Load(tt.args)
+			if err == nil {
+				t.Fatal("Load succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	const yamlConfig = `server:
+  port: "9000"
+  read_timeout: 5s
+cors:
+  allowed_origins: ["https://a.example", "https://b.example"]
+rate_limit:
+  cached:
+    rps: 2.5
+    burst: 3
+`
+	const tomlConfig = `[server]
+port = "9000"
+read_timeout = "5s"
+
+[cors]
+allowed_origins = ["https://a.example", "https://b.example"]
+
+[rate_limit.cached]
+rps = 2.5
+burst = 3
+`
+	for _, tt := range []struct{ name, content string }{
+		{"config.yaml", yamlConfig},
+		{"config.yml", yamlConfig},
+		{"config.toml", tomlConfig},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaults()
+			if err := loadFile(writeFile(t, tt.name, tt.content), cfg); err != nil {
+				t.Fatalf("loadFile: %v", err)
+			}
+			if cfg.Server.Port != "9000" || cfg.Server.ReadTimeout != 5*time.Second {
+				t.Errorf("server = %+v", cfg.Server)
+			}
+			if !slices.Equal(cfg.CORS.AllowedOrigins, []string{"https://a.example", "https://b.example"}) {
+				t.Errorf("cors.allowed_origins = %v", cfg.CORS.AllowedOrigins)
+			}
+			if cfg.RateLimit.Cached != (RateLimitRule{RPS: 2.5, Burst: 3}) {
+				t.Errorf("rate_limit.cached = %+v", cfg.RateLimit.Cached)
+			}
+			// Ключи, которых нет в файле, не сбрасываются
+			if cfg.Server.WriteTimeout != 90*time.Second || cfg.RateLimit.Refresh.Burst != 2 {
+				t.Errorf("defaults were lost: write_timeout %s, rate_limit.refresh %+v", cfg.Server.WriteTimeout, cfg.RateLimit.Refresh)
+			}
+		})
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		want                []string
+		notWant             string
+	}{
+		{"unknown yaml key", "config.yaml", "server:\n  prot: \"9000\"\n", []string{"config.yaml", "prot"}, ""},
+		{"unknown yaml section", "config.yaml", "sever:\n  port: \"9000\"\n", []string{"sever"}, ""},
+		// Номер строки относится к промежуточному YAML, а не к TOML-файлу, поэтому его в ошибке нет
+		{"unknown toml key", "config.toml", "[server]\nprot = \"9000\"\n", []string{"config.toml", "prot"}, "line"},
+		{"yaml type mismatch", "config.yaml", "server:\n  read_timeout: soon\n", []string{"soon"}, ""},
+		{"toml syntax", "config.toml", "[server\nport = 1\n", []string{"config.toml"}, ""},
+		{"unsupported format", "config.json", "{}", []string{"unsupported format", ".json"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadFile(writeFile(t, tt.file, tt.content), defaults())
+			if err == nil {
+				t.Fatal("loadFile succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+			if tt.notWant != "" && strings.Contains(err.Error(), tt.notWant) {
+				t.Errorf("error %q mentions %q", err, tt.notWant)
+			}
+		})
+	}
+
+	if err := loadFile(filepath.Join(t.TempDir(), "missing.yaml"), defaults()); err == nil || !strings.Contains(err.Error(), "failed to open config file") {
+		t.Errorf("missing file: %v", err)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("SERVER_TRUSTED_PROXIES", " 10.0.0.1, ,10.0.0.2 ")
+	t.Setenv("MONGODB_MAX_POOL_SIZE", "50")
+	t.Setenv("BSUIR_API_RPS", "2.5")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	t.Setenv("NOTIFICATIONS_INTERVAL", "90s")
+	// Пустое и пробельное значение — переменная не задана
+	t.Setenv("SERVER_HOST", "  ")
+	t.Setenv("CORS_ALLOWED_ORIGINS", " , ")
+
+	cfg := defaults()
+	if err := applyEnv(cfg); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if !slices.Equal(cfg.Server.TrustedProxies, []string{"10.0.0.1", "10.0.0.2"}) {
+		t.Errorf("trusted proxies = %q", cfg.Server.TrustedProxies)
+	}
+	if cfg.MongoDB.MaxPoolSize != 50 || cfg.BSUIRAPI.RequestsPerSecond != 2.5 || cfg.CORS.AllowCredentials || cfg.Notifications.Interval != 90*time.Second {
+		t.Errorf("parsed values: pool %d, rps %v, credentials %v, interval %s",
+			cfg.MongoDB.MaxPoolSize, cfg.BSUIRAPI.RequestsPerSecond, cfg.CORS.AllowCredentials, cfg.Notifications.Interval)
+	}
+	if cfg.Server.Host != "localhost" || len(cfg.CORS.AllowedOrigins) != 2 {
+		t.Errorf("blank variables overrode defaults: host %q, origins %q", cfg.Server.Host, cfg.CORS.AllowedOrigins)
+	}
+}
+
+func TestApplyEnvErrors(t *testing.T) {
+	tests := []struct {
+		key, value, kind string
+	}{
+		{"SERVER_READ_TIMEOUT", "30", "duration"},
+		{"POSTGRES_MAX_CONNS", "many", "integer"},
+		{"MONGODB_MAX_POOL_SIZE", "-1", "non-negative integer"},
+		{"BSUIR_API_RPS", "fast", "number"},
+		{"CORS_ALLOW_CREDENTIALS", "maybe", "boolean"},
+	}
+	for _, tt := range tests {
+		t.Setenv(tt.key, tt.value)
+	}
+
+	cfg := defaults()
+	err := applyEnv(cfg)
+	if err == nil {
+		t.Fatal("applyEnv succeeded, want an error")
+	}
+	// Обо всех неверных переменных сообщается сразу, а поле сохраняет прежнее значение
+	for _, tt := range tests {
+		if want := tt.key + `: "` + tt.value + `" is not a valid ` + tt.kind; !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	if lines := strings.Count(err.Error(), "\n") + 1; lines != len(tests) {
+		t.Errorf("error has %d lines, want %d:\n%v", lines, len(tests), err)
+	}
+	if cfg.Server.ReadTimeout != 15*time.Second || cfg.Postgres.MaxConns != 10 {
+		t.Errorf("invalid values changed the config: read_timeout %s, max_conns %d", cfg.Server.ReadTimeout, cfg.Postgres.MaxConns)
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv переопределяет конфиг заданными переменными окружения.
+// Нераспознанное значение — ошибка, а не молчаливый откат к значению по умолчанию.
+func applyEnv(config *Config) error {
+	env := &envReader{}
+
+	env.string("SERVER_PORT", &config.Server.Port)
+	env.string("SERVER_HOST", &config.Server.Host)
+	env.list("SERVER_TRUSTED_PROXIES", &config.Server.TrustedProxies)
+	env.duration("SERVER_READ_TIMEOUT", &config.Server.ReadTimeout)
+	env.duration("SERVER_WRITE_TIMEOUT", &config.Server.WriteTimeout)
+	env.duration("SERVER_IDLE_TIMEOUT", &config.Server.IdleTimeout)
+	env.duration("SERVER_SHUTDOWN_TIMEOUT", &config.Server.ShutdownTimeout)
+
+	env.string("STORAGE_DRIVER", &config.Storage.Driver)
+
+	env.string("MONGODB_URI", &config.MongoDB.URI)
+	env.string("MONGODB_DATABASE", &config.MongoDB.Database)
+	env.duration("MONGODB_CONNECT_TIMEOUT", &config.MongoDB.ConnectTimeout)
+	env.uint("MONGODB_MAX_POOL_SIZE", &config.MongoDB.MaxPoolSize)
+	env.uint("MONGODB_MIN_POOL_SIZE", &config.MongoDB.MinPoolSize)
+	env.duration("MONGODB_MAX_CONN_IDLE_TIME", &config.MongoDB.MaxConnIdleTime)
+
+	env.string("BOLT_PATH", &config.Bolt.Path)
+
+	env.string("POSTGRES_DSN", &config.Postgres.DSN)
+	env.duration("POSTGRES_CONNECT_TIMEOUT", &config.Postgres.ConnectTimeout)
+	env.int("POSTGRES_MAX_CONNS", &config.Postgres.MaxConns)
+
+	env.string("BSUIR_API_BASE_URL", &config.BSUIRAPI.BaseURL)
+	env.duration("BSUIR_API_TIMEOUT", &config.BSUIRAPI.Timeout)
+	env.string("BSUIR_API_MODE", &config.BSUIRAPI.Mode)
+	env.string("BSUIR_API_FIXTURES_DIR", &config.BSUIRAPI.FixturesDir)
+	env.string("BSUIR_API_FALLBACK_DIR", &config.BSUIRAPI.FallbackDir)
+	env.duration("BSUIR_API_REFERENCE_CACHE_TTL", &config.BSUIRAPI.ReferenceCacheTTL)
+	env.float("BSUIR_API_RPS", &config.BSUIRAPI.RequestsPerSecond)
+	env.int("BSUIR_API_BURST", &config.BSUIRAPI.Burst)
+	env.int("BSUIR_API_MAX_CONCURRENT", &config.BSUIRAPI.MaxConcurrent)
+
+	env.string("LOG_LEVEL", &config.Logger.Level)
+
+	env.list("CORS_ALLOWED_ORIGINS", &config.CORS.AllowedOrigins)
+	env.bool("CORS_ALLOW_CREDENTIALS", &config.CORS.AllowCredentials)
+
+	env.bool("RATE_LIMIT_ENABLED", &config.RateLimit.Enabled)
+	env.float("RATE_LIMIT_REFRESH_RPS", &config.RateLimit.Refresh.RPS)
+	env.int("RATE_LIMIT_REFRESH_BURST", &config.RateLimit.Refresh.Burst)
+	env.float("RATE_LIMIT_UNCACHED_RPS", &config.RateLimit.Uncached.RPS)
+	env.int("RATE_LIMIT_UNCACHED_BURST", &config.RateLimit.Uncached.Burst)
+	env.float("RATE_LIMIT_CACHED_RPS", &config.RateLimit.Cached.RPS)
+	env.int("RATE_LIMIT_CACHED_BURST", &config.RateLimit.Cached.Burst)
+
+	env.bool("TRACING_ENABLED", &config.Tracing.Enabled)
+	env.string("OTEL_EXPORTER_OTLP_ENDPOINT", &config.Tracing.Endpoint)
+	env.string("OTEL_SERVICE_NAME", &config.Tracing.ServiceName)
+	env.float("TRACING_SAMPLE_RATIO", &config.Tracing.SampleRatio)
+
+	env.bool("NOTIFICATIONS_ENABLED", &config.Notifications.Enabled)
+	env.duration("NOTIFICATIONS_INTERVAL", &config.Notifications.Interval)
+	env.duration("NOTIFICATIONS_TIMEOUT", &config.Notifications.Timeout)
+	env.bool("NOTIFICATIONS_WEBHOOK_ALLOW_PRIVATE", &config.Notifications.WebhookAllowPrivate)
+	env.string("SMTP_HOST", &config.Notifications.SMTP.Host)
+	env.int("SMTP_PORT", &config.Notifications.SMTP.Port)
+	env.string("SMTP_USERNAME", &config.Notifications.SMTP.Username)
+	env.string("SMTP_PASSWORD", &config.Notifications.SMTP.Password)
+	env.string("SMTP_FROM", &config.Notifications.SMTP.From)
+	env.string("TELEGRAM_BOT_TOKEN", &config.Notifications.Telegram.BotToken)
+	env.string("TELEGRAM_API_URL", &config.Notifications.Telegram.APIURL)
+
+	return errors.Join(env.errs...)
+}
+
+// envReader читает переменные окружения в поля конфига, накапливая ошибки разбора,
+// чтобы сообщить обо всех неверных переменных сразу
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) lookup(key string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(key))
+	return value, value != ""
+}
+
+func (e *envReader) fail(key, value, kind string) {
+	e.errs = append(e.errs, fmt.Errorf("%s: %q is not a valid %s", key, value, kind))
+}
+
+func (e *envReader) string(key string, target *string) {
+	if value, ok := e.lookup(key); ok {
+		*target = value
+	}
+}
+
+func (e *envReader) list(key string, target *[]string) {
+	if value, ok := e.lookup(key); ok {
+		if items := splitList(value); len(items) > 0 {
+			*target = items
+		}
+	}
+}
+
+func (e *envReader) int(key string, target *int) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			e.fail(key, value, "integer")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (e *envReader) uint(key string, target *uint64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			e.fail(key, value, "non-negative integer")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (e *envReader) float(key string, target *float64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.fail(key, value, "number")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (e *envReader) bool(key string, target *bool) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			e.fail(key, value, "boolean")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (e *envReader) duration(key string, target *time.Duration) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			e.fail(key, value, "duration (e.g. 30s, 5m)")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
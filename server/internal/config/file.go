@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// yamlLine — номер строки в ошибке YAML; у TOML-файла он указывал бы на промежуточный документ
+var yamlLine = regexp.MustCompile(`line \d+: `)
+
+// loadFile накладывает на конфиг значения из YAML- или TOML-файла. Ключи, отсутствующие в файле,
+// сохраняют текущие значения; неизвестные ключи — ошибка, чтобы опечатка не терялась молча.
+func loadFile(path string, config *Config) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml", ".toml":
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q, expected .yaml, .yml or .toml", path, ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+
+	if ext == ".toml" {
+		// TOML переводим в YAML, чтобы у обоих форматов были одни ключи, длительности ("30s") и проверки
+		var doc map[string]any
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(doc); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		if ext == ".toml" {
+			return fmt.Errorf("config file %s: %s", path, yamlLine.ReplaceAllString(err.Error(), ""))
+		}
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
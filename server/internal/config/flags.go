@@ -0,0 +1,86 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+)
+
+// commandLine — флаги, переопределяющие окружение и файл. Учитываются только явно переданные.
+type commandLine struct {
+	configFile string
+	set        map[string]bool
+
+	port           string
+	host           string
+	logLevel       string
+	storageDriver  string
+	mongoURI       string
+	mongoDatabase  string
+	bsuirBaseURL   string
+	bsuirMode      string
+	bsuirFixtures  string
+	tracingEnabled bool
+}
+
+func parseFlags(args []string) (*commandLine, error) {
+	cl := &commandLine{set: make(map[string]bool)}
+
+	fs := flag.NewFlagSet("schedluer", flag.ContinueOnError)
+	fs.StringVar(&cl.configFile, "config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	fs.StringVar(&cl.port, "port", "", "HTTP port (env SERVER_PORT)")
+	fs.StringVar(&cl.host, "host", "", "HTTP host (env SERVER_HOST)")
+	fs.StringVar(&cl.logLevel, "log-level", "", "log level: debug, info, warn, error (env LOG_LEVEL)")
+	fs.StringVar(&cl.storageDriver, "storage", "", "storage driver: mongodb, bolt, postgres, memory (env STORAGE_DRIVER)")
+	fs.StringVar(&cl.mongoURI, "mongodb-uri", "", "MongoDB connection string (env MONGODB_URI)")
+	fs.StringVar(&cl.mongoDatabase, "mongodb-database", "", "MongoDB database name (env MONGODB_DATABASE)")
+	fs.StringVar(&cl.bsuirBaseURL, "bsuir-base-url", "", "BSUIR API base URL (env BSUIR_API_BASE_URL)")
+	fs.StringVar(&cl.bsuirMode, "bsuir-mode", "", "BSUIR API mode: live, record, replay (env BSUIR_API_MODE)")
+	fs.StringVar(&cl.bsuirFixtures, "bsuir-fixtures", "", "directory for recorded BSUIR API responses (env BSUIR_API_FIXTURES_DIR)")
+	fs.BoolVar(&cl.tracingEnabled, "tracing", false, "enable OpenTelemetry tracing (env TRACING_ENABLED)")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		cl.set[f.Name] = true
+	})
+
+	return cl, nil
+}
+
+func (cl *commandLine) apply(config *Config) {
+	if cl.set["port"] {
+		config.Server.Port = cl.port
+	}
+	if cl.set["host"] {
+		config.Server.Host = cl.host
+	}
+	if cl.set["log-level"] {
+		config.Logger.Level = cl.logLevel
+	}
+	if cl.set["storage"] {
+		config.Storage.Driver = cl.storageDriver
+	}
+	if cl.set["mongodb-uri"] {
+		config.MongoDB.URI = cl.mongoURI
+	}
+	if cl.set["mongodb-database"] {
+		config.MongoDB.Database = cl.mongoDatabase
+	}
+	if cl.set["bsuir-base-url"] {
+		config.BSUIRAPI.BaseURL = cl.bsuirBaseURL
+	}
+	if cl.set["bsuir-mode"] {
+		config.BSUIRAPI.Mode = cl.bsuirMode
+	}
+	if cl.set["bsuir-fixtures"] {
+		config.BSUIRAPI.FixturesDir = cl.bsuirFixtures
+	}
+	if cl.set["tracing"] {
+		config.Tracing.Enabled = cl.tracingEnabled
+	}
+}
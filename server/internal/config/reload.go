@@ -0,0 +1,41 @@
+package config
+
+import "reflect"
+
+// RestartRequired возвращает секции, изменения в которых не применяются на лету.
+// По SIGHUP перечитываются logger, cors, rate_limit, bsuir_api.reference_cache_ttl и notifications.interval;
+// остальное вступит в силу после перезапуска.
+func (c *Config) RestartRequired(next *Config) []string {
+	// Поля, которые применяются на лету, в сравнении секций не участвуют
+	bsuirAPI, nextBSUIRAPI := c.BSUIRAPI, next.BSUIRAPI
+	bsuirAPI.ReferenceCacheTTL, nextBSUIRAPI.ReferenceCacheTTL = 0, 0
+	notifications, nextNotifications := c.Notifications, next.Notifications
+	notifications.Interval, nextNotifications.Interval = 0, 0
+
+	var sections []string
+	if !reflect.DeepEqual(c.Server, next.Server) {
+		sections = append(sections, "server")
+	}
+	if !reflect.DeepEqual(c.Storage, next.Storage) {
+		sections = append(sections, "storage")
+	}
+	if !reflect.DeepEqual(c.MongoDB, next.MongoDB) {
+		sections = append(sections, "mongodb")
+	}
+	if !reflect.DeepEqual(c.Bolt, next.Bolt) {
+		sections = append(sections, "bolt")
+	}
+	if !reflect.DeepEqual(c.Postgres, next.Postgres) {
+		sections = append(sections, "postgres")
+	}
+	if !reflect.DeepEqual(bsuirAPI, nextBSUIRAPI) {
+		sections = append(sections, "bsuir_api")
+	}
+	if !reflect.DeepEqual(c.Tracing, next.Tracing) {
+		sections = append(sections, "tracing")
+	}
+	if !reflect.DeepEqual(notifications, nextNotifications) {
+		sections = append(sections, "notifications")
+	}
+	return sections
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Validate проверяет конфиг целиком и возвращает все найденные ошибки сразу.
+// Ошибки ссылаются на ключи файла конфигурации (server.port, rate_limit.cached.rps и т.д.).
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, field, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port <= 65535, "server.port", "%q is not a valid port", c.Server.Port)
+	check(c.Server.ReadTimeout > 0, "server.read_timeout", "must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout", "must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout", "must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+
+	switch c.Storage.Driver {
+	case StorageMongoDB:
+		check(c.MongoDB.URI != "", "mongodb.uri", "is required (MONGODB_URI)")
+		check(c.MongoDB.Database != "", "mongodb.database", "is required")
+		check(c.MongoDB.ConnectTimeout > 0, "mongodb.connect_timeout", "must be positive")
+		check(c.MongoDB.MaxPoolSize == 0 || c.MongoDB.MinPoolSize <= c.MongoDB.MaxPoolSize,
+			"mongodb.min_pool_size", "must not exceed max_pool_size (%d)", c.MongoDB.MaxPoolSize)
+	case StorageBolt:
+		check(c.Bolt.Path != "", "bolt.path", "is required (BOLT_PATH)")
+	case StoragePostgres:
+		check(c.Postgres.DSN != "", "postgres.dsn", "is required (POSTGRES_DSN)")
+		check(c.Postgres.ConnectTimeout > 0, "postgres.connect_timeout", "must be positive")
+		check(c.Postgres.MaxConns >= 1 && c.Postgres.MaxConns <= math.MaxInt32, "postgres.max_conns", "must be at least 1")
+	case StorageMemory:
+	default:
+		check(false, "storage.driver", "%q is not one of %s, %s, %s, %s",
+			c.Storage.Driver, StorageMongoDB, StorageBolt, StoragePostgres, StorageMemory)
+	}
+
+	check(validHTTPURL(c.BSUIRAPI.BaseURL), "bsuir_api.base_url", "%q is not an http(s) URL", c.BSUIRAPI.BaseURL)
+	check(c.BSUIRAPI.Timeout > 0, "bsuir_api.timeout", "must be positive")
+	switch c.BSUIRAPI.Mode {
+	case BSUIRModeLive:
+	case BSUIRModeRecord, BSUIRModeReplay:
+		check(c.BSUIRAPI.FixturesDir != "", "bsuir_api.fixtures_dir", "is required in %s mode (BSUIR_API_FIXTURES_DIR)", c.BSUIRAPI.Mode)
+	default:
+		check(false, "bsuir_api.mode", "%q is not one of %s, %s, %s", c.BSUIRAPI.Mode, BSUIRModeLive, BSUIRModeRecord, BSUIRModeReplay)
+	}
+	check(c.BSUIRAPI.ReferenceCacheTTL >= 0, "bsuir_api.reference_cache_ttl", "must not be negative (0 disables the cache)")
+	check(c.BSUIRAPI.RequestsPerSecond >= 0, "bsuir_api.requests_per_second", "must not be negative (0 disables the limit)")
+	check(c.BSUIRAPI.RequestsPerSecond == 0 || c.BSUIRAPI.Burst >= 1, "bsuir_api.burst", "must be at least 1")
+	check(c.BSUIRAPI.MaxConcurrent >= 0, "bsuir_api.max_concurrent", "must not be negative (0 disables the limit)")
+
+	_, err = logrus.ParseLevel(c.Logger.Level)
+	check(err == nil, "logger.level", "%q is not one of debug, info, warn, error", c.Logger.Level)
+
+	check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins", "must not be empty")
+	// Любой сайт с учетными данными пользователя — это уже не CORS, а отключенная защита
+	check(!c.CORS.AllowCredentials || !slices.Contains(c.CORS.AllowedOrigins, "*"),
+		"cors.allowed_origins", `"*" is not allowed with cors.allow_credentials: list the origins or disable credentials`)
+
+	if c.RateLimit.Enabled {
+		rules := []struct {
+			name string
+			rule RateLimitRule
+		}{
+			{"refresh", c.RateLimit.Refresh},
+			{"uncached", c.RateLimit.Uncached},
+			{"cached", c.RateLimit.Cached},
+		}
+		for _, r := range rules {
+			check(r.rule.RPS > 0, "rate_limit."+r.name+".rps", "must be positive")
+			check(r.rule.Burst >= 1, "rate_limit."+r.name+".burst", "must be at least 1")
+		}
+	}
+
+	if c.Tracing.Enabled {
+		check(validHTTPURL(c.Tracing.Endpoint), "tracing.endpoint", "%q is not an http(s) URL", c.Tracing.Endpoint)
+		check(c.Tracing.ServiceName != "", "tracing.service_name", "is required")
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	if c.Notifications.Enabled {
+		check(c.Notifications.Interval >= time.Second, "notifications.interval", "must be at least 1s")
+		check(c.Notifications.Timeout > 0, "notifications.timeout", "must be positive")
+		if smtp := c.Notifications.SMTP; smtp.Host != "" {
+			check(smtp.Port > 0 && smtp.Port <= 65535, "notifications.smtp.port", "%d is not a valid port", smtp.Port)
+			_, err := mail.ParseAddress(smtp.From)
+			check(err == nil, "notifications.smtp.from", "%q is not an email address (SMTP_FROM)", smtp.From)
+		}
+		if c.Notifications.Telegram.BotToken != "" {
+			check(validHTTPURL(c.Notifications.Telegram.APIURL), "notifications.telegram.api_url", "%q is not an http(s) URL", c.Notifications.Telegram.APIURL)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
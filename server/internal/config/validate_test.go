@@ -0,0 +1,139 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string
+	}{
+		{"memory defaults", func(c *Config) {}, nil},
+		{"mongodb with uri", func(c *Config) {
+			c.Storage.Driver = StorageMongoDB
+			c.MongoDB.URI = "mongodb://localhost:27017"
+		}, nil},
+		{"mongodb without uri", func(c *Config) { c.Storage.Driver = StorageMongoDB }, []string{"mongodb.uri"}},
+		{"mongodb pool sizes", func(c *Config) {
+			c.Storage.Driver = StorageMongoDB
+			c.MongoDB.URI = "mongodb://localhost:27017"
+			c.MongoDB.MinPoolSize = 200
+		}, []string{"mongodb.min_pool_size"}},
+		{"postgres without dsn", func(c *Config) { c.Storage.Driver = StoragePostgres }, []string{"postgres.dsn"}},
+		{"bolt without path", func(c *Config) {
+			c.Storage.Driver = StorageBolt
+			c.Bolt.Path = ""
+		}, []string{"bolt.path"}},
+		{"unknown driver", func(c *Config) { c.Storage.Driver = "sqlite" }, []string{`storage.driver: "sqlite"`}},
+		{"port out of range", func(c *Config) { c.Server.Port = "70000" }, []string{"server.port"}},
+		{"zero timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, []string{"server.shutdown_timeout"}},
+		{"base url without scheme", func(c *Config) { c.BSUIRAPI.BaseURL = "iis.bsuir.by/api/v1" }, []string{"bsuir_api.base_url"}},
+		{"replay without fixtures", func(c *Config) {
+			c.BSUIRAPI.Mode = BSUIRModeReplay
+			c.BSUIRAPI.FixturesDir = ""
+		}, []string{"bsuir_api.fixtures_dir"}},
+		{"unknown mode", func(c *Config) { c.BSUIRAPI.Mode = "offline" }, []string{"bsuir_api.mode"}},
+		{"negative cache ttl", func(c *Config) { c.BSUIRAPI.ReferenceCacheTTL = -time.Second }, []string{"bsuir_api.reference_cache_ttl"}},
+		{"unknown log level", func(c *Config) { c.Logger.Level = "verbose" }, []string{"logger.level"}},
+		{"any origin with credentials", func(c *Config) { c.CORS.AllowedOrigins = []string{"*"} }, []string{"cors.allowed_origins"}},
+		{"any origin without credentials", func(c *Config) {
+			c.CORS.AllowedOrigins = []string{"*"}
+			c.CORS.AllowCredentials = false
+		}, nil},
+		{"no origins", func(c *Config) { c.CORS.AllowedOrigins = nil }, []string{"cors.allowed_origins"}},
+		{"zero rate limit", func(c *Config) { c.RateLimit.Cached.RPS = 0 }, []string{"rate_limit.cached.rps"}},
+		{"zero rate limit when disabled", func(c *Config) {
+			c.RateLimit.Enabled = false
+			c.RateLimit.Cached.RPS = 0
+		}, nil},
+		{"tracing without endpoint", func(c *Config) {
+			c.Tracing.Enabled = true
+			c.Tracing.Endpoint = ""
+		}, []string{"tracing.endpoint"}},
+		{"sample ratio above 1", func(c *Config) { c.Tracing.SampleRatio = 1.5 }, []string{"tracing.sample_ratio"}},
+		{"short reminder interval", func(c *Config) {
+			c.Notifications.Enabled = true
+			c.Notifications.Interval = 500 * time.Millisecond
+		}, []string{"notifications.interval"}},
+		{"short reminder interval when disabled", func(c *Config) { c.Notifications.Interval = 0 }, nil},
+		{"smtp without sender", func(c *Config) {
+			c.Notifications.Enabled = true
+			c.Notifications.SMTP.Host = "smtp.example.com"
+		}, []string{"notifications.smtp.from"}},
+		{"all errors at once", func(c *Config) {
+			c.Server.Port = "http"
+			c.Logger.Level = "verbose"
+			c.BSUIRAPI.Timeout = 0
+		}, []string{"server.port", "logger.level", "bsuir_api.timeout"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaults()
+			cfg.Storage.Driver = StorageMemory
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+			if lines := strings.Count(err.Error(), "\n") + 1; lines != len(tt.want) {
+				t.Errorf("error has %d lines, want %d:\n%v", lines, len(tt.want), err)
+			}
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string
+	}{
+		{"unchanged", func(c *Config) {}, nil},
+		{"reloadable settings", func(c *Config) {
+			c.Logger.Level = "debug"
+			c.CORS.AllowedOrigins = []string{"https://schedluer.example"}
+			c.RateLimit.Cached = RateLimitRule{RPS: 1, Burst: 1}
+			c.BSUIRAPI.ReferenceCacheTTL = time.Hour
+			c.Notifications.Interval = 5 * time.Minute
+			c.File = "other.yaml"
+		}, nil},
+		{"server", func(c *Config) { c.Server.Port = "9090" }, []string{"server"}},
+		{"bsuir api timeout", func(c *Config) { c.BSUIRAPI.Timeout = time.Minute }, []string{"bsuir_api"}},
+		{"notifications switched on", func(c *Config) {
+			c.Notifications.Enabled = true
+			c.Notifications.Interval = 5 * time.Minute
+		}, []string{"notifications"}},
+		{"several sections", func(c *Config) {
+			c.Storage.Driver = StorageBolt
+			c.Bolt.Path = "other.db"
+			c.Tracing.Enabled = true
+		}, []string{"storage", "bolt", "tracing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := defaults()
+			next := defaults()
+			tt.modify(next)
+			if sections := current.RestartRequired(next); !slices.Equal(sections, tt.want) {
+				t.Errorf("RestartRequired = %v, want %v", sections, tt.want)
+			}
+		})
+	}
+}
@@ -2,95 +2,256 @@ package container
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"go.mongodb.org/mongo-driver/v2/mongo"
 
 	"schedluer/internal/config"
 	"schedluer/internal/handler"
+	"schedluer/internal/models"
 	"schedluer/internal/repository"
 	"schedluer/internal/service"
 	"schedluer/pkg/bsuir"
-	"schedluer/pkg/database"
+	"schedluer/pkg/notify"
+	"schedluer/pkg/worker"
 )
 
 type Container struct {
-	Config *config.Config
-
-	MongoDB *database.MongoDB
+	Store repository.Store
 
 	BSUIRClient *bsuir.Client
+	// Source — то, откуда сервисы берут данные БГУИРа: клиент с кэшем и запасным дампом поверх
+	Source bsuir.ScheduleSource
+	// ReferenceCache — кэш справочников внутри Source; его срок меняется по SIGHUP
+	ReferenceCache *bsuir.CachingSource
+
+	Tasks *worker.Group
 
 	ScheduleRepo repository.ScheduleRepository
 	GroupRepo    repository.GroupRepository
 	EmployeeRepo repository.EmployeeRepository
 	FavoriteRepo repository.FavoriteRepository
+	APIKeyRepo   repository.APIKeyRepository
+	ShareRepo    repository.ShareRepository
+	EventRepo    repository.EventRepository
+	NoteRepo     repository.NoteRepository
+	OverrideRepo repository.OverrideRepository
+	ReminderRepo repository.ReminderRepository
+
+	ScheduleService  service.ScheduleService
+	GroupService     service.GroupService
+	EmployeeService  service.EmployeeService
+	FavoriteService  service.FavoriteService
+	TimetableService service.TimetableService
+	ShareService     service.ShareService
+	EventService     service.EventService
+	NoteService      service.NoteService
+	OverrideService  service.OverrideService
+	ReminderService  service.ReminderService
+	AuthService      service.AuthService
+	HealthService    service.HealthService
 
-	ScheduleService service.ScheduleService
-	GroupService    service.GroupService
-	EmployeeService service.EmployeeService
-	FavoriteService service.FavoriteService
+	// Reminders — планировщик напоминаний; nil, если notifications.enabled выключен
+	Reminders *service.ReminderScheduler
 
-	Router *handler.Router
+	Router      *handler.Router
+	RateLimiter *handler.RateLimiter
+	CORS        *handler.CORS
 
 	Logger *logrus.Logger
+
+	// config — текущий конфиг; Reload подменяет его целиком, поэтому снимок из Config() не меняется под читателем
+	config   atomic.Pointer[config.Config]
+	reloadMu sync.Mutex
 }
 
-func NewContainer(cfg *config.Config) (*Container, error) {
-	logger := logrus.StandardLogger()
+// newScheduleSource оборачивает клиент кэшем справочников и запасным дампом, если он задан.
+// Кэш есть всегда, чтобы reference_cache_ttl можно было включить по SIGHUP; при нулевом сроке он пропускает запросы.
+// Проверка готовности по-прежнему смотрит на сам клиент: ответ из дампа не значит, что API жив.
+func newScheduleSource(cfg config.BSUIRAPIConfig, client *bsuir.Client, logger *logrus.Logger) (bsuir.ScheduleSource, *bsuir.CachingSource) {
+	cache := bsuir.NewCachingSource(client, cfg.ReferenceCacheTTL)
+	var source bsuir.ScheduleSource = cache
+	if cfg.FallbackDir != "" {
+		logger.WithField("fallback_dir", cfg.FallbackDir).Info("BSUIR API fallback dump enabled")
+		source = bsuir.NewFallbackSource(source, bsuir.NewDirSource(cfg.FallbackDir), logger)
+	}
+	return source, cache
+}
 
-	mongoConfig := database.Config{
-		URI:      cfg.MongoDB.URI,
-		Database: cfg.MongoDB.Database,
-		Timeout:  30 * time.Second, // Увеличиваем таймаут для Atlas
+// newReminderSenders возвращает отправителей настроенных каналов: webhook есть всегда,
+// email и telegram — только если заданы SMTP-сервер и токен бота
+func newReminderSenders(cfg config.NotificationsConfig) map[string]notify.Sender {
+	senders := map[string]notify.Sender{
+		models.ReminderChannelWebhook: notify.NewWebhookSender(notify.WebhookConfig{Timeout: cfg.Timeout, AllowPrivate: cfg.WebhookAllowPrivate}),
+	}
+	if cfg.SMTP.Host != "" {
+		senders[models.ReminderChannelEmail] = notify.NewSMTPSender(notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			Timeout:  cfg.Timeout,
+		})
 	}
+	if cfg.Telegram.BotToken != "" {
+		senders[models.ReminderChannelTelegram] = notify.NewTelegramSender(cfg.Telegram.APIURL, cfg.Telegram.BotToken, cfg.Timeout)
+	}
+	return senders
+}
+
+func NewContainer(cfg *config.Config) (*Container, error) {
+	logger := logrus.StandardLogger()
 
-	mongoDB, err := database.NewMongoDB(mongoConfig)
+	store, err := OpenStore(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	bsuirClient := bsuir.NewClient(&cfg.BSUIRAPI)
+	if cfg.BSUIRAPI.Mode != config.BSUIRModeLive {
+		logger.WithFields(logrus.Fields{
+			"mode":         cfg.BSUIRAPI.Mode,
+			"fixtures_dir": cfg.BSUIRAPI.FixturesDir,
+		}).Warn("BSUIR API is not in live mode")
+	}
+	source, referenceCache := newScheduleSource(cfg.BSUIRAPI, bsuirClient, logger)
+	tasks := worker.NewGroup(logger)
+
+	scheduleRepo := store.Schedules()
+	groupRepo := store.Groups()
+	employeeRepo := store.Employees()
+	favoriteRepo := store.Favorites()
+	apiKeyRepo := store.APIKeys()
+	shareRepo := store.Shares()
+	eventRepo := store.Events()
+	noteRepo := store.Notes()
+	overrideRepo := store.Overrides()
+	reminderRepo := store.Reminders()
+
+	scheduleService := service.NewScheduleService(source, scheduleRepo, logger)
+	groupService := service.NewGroupService(source, groupRepo, tasks, logger)
+	employeeService := service.NewEmployeeService(source, employeeRepo, tasks, logger)
+	favoriteService := service.NewFavoriteService(favoriteRepo, groupRepo, employeeRepo, source, logger)
+	timetableService := service.NewTimetableService(favoriteRepo, eventRepo, scheduleService, source, logger)
+	shareService := service.NewShareService(shareRepo, favoriteRepo, favoriteService, timetableService, logger)
+	eventService := service.NewEventService(eventRepo, logger)
+	noteService := service.NewNoteService(noteRepo, scheduleService, source, logger)
+	overrideService := service.NewOverrideService(overrideRepo, logger)
 
-	scheduleRepo := repository.NewScheduleRepository(mongoDB.Database)
-	groupRepo := repository.NewGroupRepository(mongoDB.Database)
-	employeeRepo := repository.NewEmployeeRepository(mongoDB.Database)
-	favoriteRepo := repository.NewFavoriteRepository(mongoDB.Database, logger)
-
-	scheduleService := service.NewScheduleService(bsuirClient, scheduleRepo, logger)
-	groupService := service.NewGroupService(bsuirClient, groupRepo, logger)
-	employeeService := service.NewEmployeeService(bsuirClient, employeeRepo, logger)
-	favoriteService := service.NewFavoriteService(favoriteRepo, logger)
-
-	apiRouter := handler.NewRouter(scheduleService, groupService, employeeService, favoriteService, logger)
-
-	return &Container{
-		Config:          cfg,
-		MongoDB:         mongoDB,
-		BSUIRClient:     bsuirClient,
-		ScheduleRepo:    scheduleRepo,
-		GroupRepo:       groupRepo,
-		EmployeeRepo:    employeeRepo,
-		FavoriteRepo:    favoriteRepo,
-		ScheduleService: scheduleService,
-		GroupService:    groupService,
-		EmployeeService: employeeService,
-		FavoriteService: favoriteService,
-		Router:          apiRouter,
-		Logger:          logger,
-	}, nil
+	// Без планировщика подписаться не на что: при выключенных уведомлениях каналов нет
+	var (
+		reminderSenders map[string]notify.Sender
+		reminders       *service.ReminderScheduler
+	)
+	if cfg.Notifications.Enabled {
+		reminderSenders = newReminderSenders(cfg.Notifications)
+		reminders = service.NewReminderScheduler(reminderRepo, timetableService, reminderSenders, cfg.Notifications.Interval, nil, logger)
+		logger.WithFields(logrus.Fields{
+			"channels":              slices.Sorted(maps.Keys(reminderSenders)),
+			"webhook_allow_private": cfg.Notifications.WebhookAllowPrivate,
+		}).Info("Lesson reminders enabled")
+	}
+	reminderService := service.NewReminderService(reminderRepo, reminderSenders, logger)
+	authService := service.NewAuthService(apiKeyRepo, logger)
+	healthService := service.NewHealthService(store, bsuirClient, groupRepo, employeeRepo, tasks, logger)
+
+	apiRouter := handler.NewRouter(scheduleService, groupService, employeeService, favoriteService, timetableService, shareService, eventService, noteService, overrideService, reminderService, authService, healthService, logger)
+	rateLimiter := handler.NewRateLimiter(cfg.RateLimit, authService, logger)
+	corsMiddleware := handler.NewCORS(cfg.CORS)
+
+	ctn := &Container{
+		Store:            store,
+		BSUIRClient:      bsuirClient,
+		Source:           source,
+		ReferenceCache:   referenceCache,
+		Tasks:            tasks,
+		ScheduleRepo:     scheduleRepo,
+		GroupRepo:        groupRepo,
+		EmployeeRepo:     employeeRepo,
+		FavoriteRepo:     favoriteRepo,
+		APIKeyRepo:       apiKeyRepo,
+		ShareRepo:        shareRepo,
+		EventRepo:        eventRepo,
+		NoteRepo:         noteRepo,
+		OverrideRepo:     overrideRepo,
+		ReminderRepo:     reminderRepo,
+		ScheduleService:  scheduleService,
+		GroupService:     groupService,
+		EmployeeService:  employeeService,
+		FavoriteService:  favoriteService,
+		TimetableService: timetableService,
+		ShareService:     shareService,
+		EventService:     eventService,
+		NoteService:      noteService,
+		OverrideService:  overrideService,
+		ReminderService:  reminderService,
+		Reminders:        reminders,
+		AuthService:      authService,
+		HealthService:    healthService,
+		Router:           apiRouter,
+		RateLimiter:      rateLimiter,
+		CORS:             corsMiddleware,
+		Logger:           logger,
+	}
+	ctn.config.Store(cfg)
+	return ctn, nil
+}
+
+// Config возвращает текущий конфиг. Снимок нельзя менять: его читают параллельно.
+func (c *Container) Config() *config.Config {
+	return c.config.Load()
+}
+
+// Reload применяет перечитанный конфиг: уровень логирования, CORS, лимиты запросов, срок кэша
+// справочников и интервал напоминаний меняются на лету, об изменениях остальных настроек только предупреждаем.
+func (c *Container) Reload(cfg *config.Config) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	if level, err := logrus.ParseLevel(cfg.Logger.Level); err == nil {
+		c.Logger.SetLevel(level)
+	}
+	c.CORS.Update(cfg.CORS)
+	c.RateLimiter.Update(cfg.RateLimit)
+	c.ReferenceCache.SetTTL(cfg.BSUIRAPI.ReferenceCacheTTL)
+
+	current := c.config.Load()
+	if sections := current.RestartRequired(cfg); len(sections) > 0 {
+		c.Logger.WithField("sections", sections).Warn("Config changes require a restart to take effect")
+	}
+
+	// Остальные настройки остаются прежними до перезапуска: в снимке они должны совпадать с тем, что работает
+	next := *current
+	next.Logger = cfg.Logger
+	next.CORS = cfg.CORS
+	next.RateLimit = cfg.RateLimit
+	next.BSUIRAPI.ReferenceCacheTTL = cfg.BSUIRAPI.ReferenceCacheTTL
+	// Интервал берем, только если планировщик запущен и уведомления остались включены: у выключенных он не проверяется
+	if c.Reminders != nil && cfg.Notifications.Enabled {
+		c.Reminders.SetInterval(cfg.Notifications.Interval)
+		next.Notifications.Interval = cfg.Notifications.Interval
+	}
+	next.File = cfg.File
+	c.config.Store(&next)
+
+	c.Logger.WithFields(logrus.Fields{
+		"file":                cfg.File,
+		"log_level":           cfg.Logger.Level,
+		"reference_cache_ttl": next.BSUIRAPI.ReferenceCacheTTL.String(),
+		"reminder_interval":   next.Notifications.Interval.String(),
+	}).Info("Config reloaded")
 }
 
 func (c *Container) Close() error {
-	if c.MongoDB != nil {
+	if c.Store != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		return c.MongoDB.Close(ctx)
+		return c.Store.Close(ctx)
 	}
 	return nil
 }
-
-func (c *Container) GetDatabase() *mongo.Database {
-	return c.MongoDB.Database
-}
@@ -0,0 +1,57 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/config"
+	"schedluer/internal/repository"
+	"schedluer/internal/repository/bolt"
+	"schedluer/internal/repository/memory"
+	"schedluer/internal/repository/postgres"
+	"schedluer/pkg/database"
+)
+
+// OpenStore подключает хранилище, выбранное в storage.driver
+func OpenStore(cfg *config.Config, logger *logrus.Logger) (repository.Store, error) {
+	switch cfg.Storage.Driver {
+	case config.StorageMongoDB:
+		mongoDB, err := database.NewMongoDB(database.Config{
+			URI:             cfg.MongoDB.URI,
+			Database:        cfg.MongoDB.Database,
+			Timeout:         cfg.MongoDB.ConnectTimeout,
+			MaxPoolSize:     cfg.MongoDB.MaxPoolSize,
+			MinPoolSize:     cfg.MongoDB.MinPoolSize,
+			MaxConnIdleTime: cfg.MongoDB.MaxConnIdleTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+		store, err := repository.NewMongoStore(mongoDB, logger)
+		if err != nil {
+			_ = mongoDB.Close(context.Background())
+			return nil, err
+		}
+		return store, nil
+
+	case config.StorageBolt:
+		logger.WithField("path", cfg.Bolt.Path).Info("Opening embedded storage")
+		return bolt.Open(cfg.Bolt.Path)
+
+	case config.StoragePostgres:
+		return postgres.Open(postgres.Config{
+			DSN:      cfg.Postgres.DSN,
+			Timeout:  cfg.Postgres.ConnectTimeout,
+			MaxConns: int32(cfg.Postgres.MaxConns),
+		}, logger)
+
+	case config.StorageMemory:
+		logger.Warn("Using in-memory storage: cache, favorites and API keys are lost on restart")
+		return memory.NewStore(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
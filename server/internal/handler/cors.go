@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+
+	"schedluer/internal/config"
+)
+
+// CORS — CORS-middleware, настройки которого можно менять на лету
+type CORS struct {
+	handler atomic.Pointer[gin.HandlerFunc]
+}
+
+func NewCORS(cfg config.CORSConfig) *CORS {
+	c := &CORS{}
+	c.Update(cfg)
+	return c
+}
+
+// Update пересобирает middleware с новыми источниками и учетными данными (по SIGHUP).
+// "*" вместе с учетными данными отсекает config.Validate.
+func (c *CORS) Update(cfg config.CORSConfig) {
+	origins := slices.Clone(cfg.AllowedOrigins)
+	handler := cors.New(cors.Config{
+		AllowOriginFunc: func(origin string) bool {
+			return slices.Contains(origins, "*") || slices.Contains(origins, origin)
+		},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Retry-After", "X-Request-ID"},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           12 * 60 * 60,
+	})
+	c.handler.Store(&handler)
+}
+
+func (c *CORS) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		(*c.handler.Load())(ctx)
+	}
+}
@@ -38,7 +38,7 @@ func (h *EmployeeHandler) GetAllEmployees(c *gin.Context) {
 
 	employees, err := h.employeeService.GetAllEmployees(c.Request.Context(), useCache)
 	if err != nil {
-		h.logger.Errorf("Failed to get employees: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to get employees")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -67,7 +67,7 @@ func (h *EmployeeHandler) GetEmployeeByURLID(c *gin.Context) {
 
 	employee, err := h.employeeService.GetEmployeeByURLID(c.Request.Context(), urlID)
 	if err != nil {
-		h.logger.Errorf("Failed to get employee: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to get employee")
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -81,12 +81,15 @@ func (h *EmployeeHandler) GetEmployeeByURLID(c *gin.Context) {
 // @Tags employees
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/employees/refresh [post]
 func (h *EmployeeHandler) RefreshEmployees(c *gin.Context) {
 	if err := h.employeeService.RefreshEmployees(c.Request.Context()); err != nil {
-		h.logger.Errorf("Failed to refresh employees: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to refresh employees")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
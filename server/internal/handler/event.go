@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+)
+
+type EventHandler struct {
+	eventService service.EventService
+	logger       *logrus.Logger
+}
+
+func NewEventHandler(eventService service.EventService, logger *logrus.Logger) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+		logger:       logger,
+	}
+}
+
+// ListEvents получает личные события пользователя
+// @Summary      Мои события
+// @Description  Возвращает личные события пользователя по времени первого повторения. Повторения разворачиваются в /me/timetable.
+// @Tags         events
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Success      200      {array}   models.Event
+// @Failure      500      {object}  map[string]string
+// @Router       /me/events [get]
+func (h *EventHandler) ListEvents(c *gin.Context) {
+	userID := currentUser(c)
+
+	events, err := h.eventService.ListEvents(c.Request.Context(), userID)
+	if err != nil {
+		h.fail(c, err, "Failed to get events")
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// GetEvent получает личное событие
+// @Summary      Мое событие
+// @Tags         events
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id       path      string  true  "ID события"
+// @Success      200      {object}  models.Event
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/events/{id} [get]
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	userID := currentUser(c)
+
+	event, err := h.eventService.GetEvent(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		h.fail(c, err, "Failed to get event")
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// CreateEvent создает личное событие
+// @Summary      Создать событие
+// @Description  Создает событие с началом и концом первого повторения (не дольше суток). recurrence — правило RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY для недельных, COUNT или UNTIL=YYYYMMDD.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        event    body      models.EventRequest  true  "Событие"
+// @Success      201      {object}  models.Event
+// @Failure      400      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/events [post]
+func (h *EventHandler) CreateEvent(c *gin.Context) {
+	userID := currentUser(c)
+	var request models.EventRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	event, err := h.eventService.CreateEvent(c.Request.Context(), userID, request)
+	if err != nil {
+		h.fail(c, err, "Failed to create event")
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// UpdateEvent заменяет личное событие
+// @Summary      Изменить событие
+// @Description  Заменяет событие целиком, поля те же, что при создании
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id       path      string               true  "ID события"
+// @Param        event    body      models.EventRequest  true  "Событие"
+// @Success      200      {object}  models.Event
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/events/{id} [put]
+func (h *EventHandler) UpdateEvent(c *gin.Context) {
+	userID := currentUser(c)
+	var request models.EventRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	event, err := h.eventService.UpdateEvent(c.Request.Context(), userID, c.Param("id"), request)
+	if err != nil {
+		h.fail(c, err, "Failed to update event")
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// DeleteEvent удаляет личное событие
+// @Summary      Удалить событие
+// @Description  Удаляет событие со всеми повторениями
+// @Tags         events
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id       path      string  true  "ID события"
+// @Success      200      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/events/{id} [delete]
+func (h *EventHandler) DeleteEvent(c *gin.Context) {
+	userID := currentUser(c)
+	id := c.Param("id")
+
+	if err := h.eventService.DeleteEvent(c.Request.Context(), userID, id); err != nil {
+		h.fail(c, err, "Failed to delete event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event deleted", "id": id})
+}
+
+func (h *EventHandler) fail(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidEvent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		requestLog(c, h.logger).WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
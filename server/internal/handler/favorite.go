@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
 	"schedluer/internal/models"
+	"schedluer/internal/repository"
 	"schedluer/internal/service"
 )
 
@@ -28,17 +31,17 @@ func NewFavoriteHandler(favoriteService service.FavoriteService, logger *logrus.
 // @Tags         favorites
 // @Accept       json
 // @Produce      json
-// @Param        user_id  query     string  true  "ID пользователя (пока используем 'default')"
+// @Security     ApiKeyAuth
 // @Success      200      {array}   models.FavoriteGroup
 // @Failure      400      {object}  map[string]string
 // @Failure      500      {object}  map[string]string
 // @Router       /favorites [get]
 func (h *FavoriteHandler) GetAllFavorites(c *gin.Context) {
-	userID := c.DefaultQuery("user_id", "default")
+	userID := favoritesUser(c)
 
 	favorites, err := h.favoriteService.GetAllFavorites(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.Errorf("Failed to get favorites: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to get favorites")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get favorites"})
 		return
 	}
@@ -48,7 +51,7 @@ func (h *FavoriteHandler) GetAllFavorites(c *gin.Context) {
 		favorites = []models.FavoriteGroup{}
 	}
 
-	h.logger.Debugf("Returning %d favorites for user %s", len(favorites), userID)
+	requestLog(c, h.logger).WithFields(logrus.Fields{"user_id": userID, "count": len(favorites)}).Debug("Returning favorites")
 	c.JSON(http.StatusOK, favorites)
 }
 
@@ -58,14 +61,15 @@ func (h *FavoriteHandler) GetAllFavorites(c *gin.Context) {
 // @Tags         favorites
 // @Accept       json
 // @Produce      json
-// @Param        user_id       query     string  true  "ID пользователя (пока используем 'default')"
+// @Security     ApiKeyAuth
 // @Param        group_number  path      string  true  "Номер группы"
 // @Success      200           {object}  map[string]string
 // @Failure      400           {object}  map[string]string
+// @Failure      404           {object}  map[string]string
 // @Failure      500           {object}  map[string]string
 // @Router       /favorites/{groupNumber} [post]
 func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
-	userID := c.DefaultQuery("user_id", "default")
+	userID := favoritesUser(c)
 	groupNumber := c.Param("groupNumber")
 
 	if groupNumber == "" {
@@ -75,8 +79,7 @@ func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
 
 	err := h.favoriteService.AddFavorite(c.Request.Context(), userID, groupNumber)
 	if err != nil {
-		h.logger.Errorf("Failed to add favorite: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add favorite"})
+		h.fail(c, err, "Failed to add favorite")
 		return
 	}
 
@@ -89,14 +92,14 @@ func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
 // @Tags         favorites
 // @Accept       json
 // @Produce      json
-// @Param        user_id       query     string  true  "ID пользователя (пока используем 'default')"
+// @Security     ApiKeyAuth
 // @Param        group_number  path      string  true  "Номер группы"
 // @Success      200           {object}  map[string]string
 // @Failure      400           {object}  map[string]string
 // @Failure      500           {object}  map[string]string
 // @Router       /favorites/{groupNumber} [delete]
 func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
-	userID := c.DefaultQuery("user_id", "default")
+	userID := favoritesUser(c)
 	groupNumber := c.Param("groupNumber")
 
 	if groupNumber == "" {
@@ -106,7 +109,7 @@ func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
 
 	err := h.favoriteService.RemoveFavorite(c.Request.Context(), userID, groupNumber)
 	if err != nil {
-		h.logger.Errorf("Failed to remove favorite: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to remove favorite")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove favorite"})
 		return
 	}
@@ -120,14 +123,14 @@ func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
 // @Tags         favorites
 // @Accept       json
 // @Produce      json
-// @Param        user_id       query     string  true  "ID пользователя (пока используем 'default')"
+// @Security     ApiKeyAuth
 // @Param        group_number  path      string  true  "Номер группы"
 // @Success      200           {object}  map[string]bool
 // @Failure      400           {object}  map[string]string
 // @Failure      500           {object}  map[string]string
 // @Router       /favorites/{groupNumber}/check [get]
 func (h *FavoriteHandler) IsFavorite(c *gin.Context) {
-	userID := c.DefaultQuery("user_id", "default")
+	userID := favoritesUser(c)
 	groupNumber := c.Param("groupNumber")
 
 	if groupNumber == "" {
@@ -137,7 +140,7 @@ func (h *FavoriteHandler) IsFavorite(c *gin.Context) {
 
 	isFav, err := h.favoriteService.IsFavorite(c.Request.Context(), userID, groupNumber)
 	if err != nil {
-		h.logger.Errorf("Failed to check favorite: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to check favorite")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check favorite"})
 		return
 	}
@@ -151,14 +154,14 @@ func (h *FavoriteHandler) IsFavorite(c *gin.Context) {
 // @Tags         favorites
 // @Accept       json
 // @Produce      json
-// @Param        user_id  query     string  true  "ID пользователя (пока используем 'default')"
+// @Security     ApiKeyAuth
 // @Param        query    query     string  true  "Поисковый запрос"
 // @Success      200      {array}   models.FavoriteGroup
 // @Failure      400      {object}  map[string]string
 // @Failure      500      {object}  map[string]string
 // @Router       /favorites/search [get]
 func (h *FavoriteHandler) SearchFavorites(c *gin.Context) {
-	userID := c.DefaultQuery("user_id", "default")
+	userID := favoritesUser(c)
 	query := c.Query("query")
 
 	if query == "" {
@@ -168,7 +171,7 @@ func (h *FavoriteHandler) SearchFavorites(c *gin.Context) {
 
 	favorites, err := h.favoriteService.SearchFavorites(c.Request.Context(), userID, query)
 	if err != nil {
-		h.logger.Errorf("Failed to search favorites: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to search favorites")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search favorites"})
 		return
 	}
@@ -177,6 +180,360 @@ func (h *FavoriteHandler) SearchFavorites(c *gin.Context) {
 		favorites = []models.FavoriteGroup{}
 	}
 
-	h.logger.Debugf("Found %d favorites for user %s with query %s", len(favorites), userID, query)
+	requestLog(c, h.logger).WithFields(logrus.Fields{"user_id": userID, "query": query, "count": len(favorites)}).Debug("Found favorites")
 	c.JSON(http.StatusOK, favorites)
 }
+
+// ListFavoriteItems получает избранное всех типов с данными для отображения
+// @Summary      Получить избранное с подробностями
+// @Description  Возвращает избранные группы, преподавателей и аудитории в порядке пользователя: закрепленные первыми, затем по позиции. Для групп добавляются факультет и курс, для преподавателей — ФИО и фото, для аудиторий — корпус и тип, если они есть в кэше.
+// @Tags         favorites
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        type     query     string  false  "Тип избранного: group, employee, auditory"
+// @Success      200      {array}   models.FavoriteItem
+// @Failure      400      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/items [get]
+func (h *FavoriteHandler) ListFavoriteItems(c *gin.Context) {
+	userID := favoritesUser(c)
+	favoriteType := c.Query("type")
+
+	items, err := h.favoriteService.ListFavoriteItems(c.Request.Context(), userID, favoriteType)
+	if errors.Is(err, service.ErrUnknownFavoriteType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		requestLog(c, h.logger).WithError(err).Error("Failed to list favorite items")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get favorites"})
+		return
+	}
+
+	requestLog(c, h.logger).WithFields(logrus.Fields{"user_id": userID, "type": favoriteType, "count": len(items)}).Debug("Returning favorite items")
+	c.JSON(http.StatusOK, items)
+}
+
+// AddFavoriteItem добавляет группу, преподавателя или аудиторию в избранное
+// @Summary      Добавить в избранное
+// @Description  Добавляет элемент в избранное. Ключ — номер группы, URL ID преподавателя или аудитория с корпусом ("505-5 к.").
+// @Tags         favorites
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        type     path      string  true  "Тип избранного: group, employee, auditory"
+// @Param        key      path      string  true  "Ключ элемента"
+// @Success      200      {object}  models.Favorite
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/items/{type}/{key} [post]
+func (h *FavoriteHandler) AddFavoriteItem(c *gin.Context) {
+	userID := favoritesUser(c)
+	favoriteType, key, ok := favoriteItemParams(c)
+	if !ok {
+		return
+	}
+
+	favorite, err := h.favoriteService.AddFavoriteItem(c.Request.Context(), userID, favoriteType, key)
+	if err != nil {
+		h.fail(c, err, "Failed to add favorite")
+		return
+	}
+
+	c.JSON(http.StatusOK, favorite)
+}
+
+// RemoveFavoriteItem удаляет элемент из избранного
+// @Summary      Удалить из избранного
+// @Description  Удаляет группу, преподавателя или аудиторию из избранного
+// @Tags         favorites
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        type     path      string  true  "Тип избранного: group, employee, auditory"
+// @Param        key      path      string  true  "Ключ элемента"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/items/{type}/{key} [delete]
+func (h *FavoriteHandler) RemoveFavoriteItem(c *gin.Context) {
+	userID := favoritesUser(c)
+	favoriteType, key, ok := favoriteItemParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.favoriteService.RemoveFavoriteItem(c.Request.Context(), userID, favoriteType, key)
+	if errors.Is(err, service.ErrUnknownFavoriteType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		requestLog(c, h.logger).WithError(err).Error("Failed to remove favorite item")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove favorite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Removed from favorites", "type": favoriteType, "key": key})
+}
+
+// IsFavoriteItem проверяет, есть ли элемент в избранном
+// @Summary      Проверить избранное
+// @Description  Проверяет, добавлены ли группа, преподаватель или аудитория в избранное
+// @Tags         favorites
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        type     path      string  true  "Тип избранного: group, employee, auditory"
+// @Param        key      path      string  true  "Ключ элемента"
+// @Success      200      {object}  map[string]bool
+// @Failure      400      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/items/{type}/{key}/check [get]
+func (h *FavoriteHandler) IsFavoriteItem(c *gin.Context) {
+	userID := favoritesUser(c)
+	favoriteType, key, ok := favoriteItemParams(c)
+	if !ok {
+		return
+	}
+
+	isFav, err := h.favoriteService.IsFavoriteItem(c.Request.Context(), userID, favoriteType, key)
+	if errors.Is(err, service.ErrUnknownFavoriteType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		requestLog(c, h.logger).WithError(err).Error("Failed to check favorite item")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check favorite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"is_favorite": isFav})
+}
+
+// UpdateFavoriteItem меняет оформление элемента избранного
+// @Summary      Изменить элемент избранного
+// @Description  Переносит элемент в коллекцию (пустая строка — вне коллекций), задает подпись, цвет #RRGGBB и закрепление. Не переданные поля не меняются; при переносе элемент встает в конец коллекции.
+// @Tags         favorites
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        type     path      string                  true  "Тип избранного: group, employee, auditory"
+// @Param        key      path      string                  true  "Ключ элемента"
+// @Param        details  body      models.FavoriteDetails  true  "Изменения"
+// @Success      200      {object}  models.Favorite
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/items/{type}/{key} [patch]
+func (h *FavoriteHandler) UpdateFavoriteItem(c *gin.Context) {
+	userID := favoritesUser(c)
+	favoriteType, key, ok := favoriteItemParams(c)
+	if !ok {
+		return
+	}
+	var details models.FavoriteDetails
+	if err := c.ShouldBindJSON(&details); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	favorite, err := h.favoriteService.UpdateFavoriteItem(c.Request.Context(), userID, favoriteType, key, details)
+	if err != nil {
+		h.fail(c, err, "Failed to update favorite")
+		return
+	}
+
+	c.JSON(http.StatusOK, favorite)
+}
+
+// ReorderFavoriteItems задает порядок элементов коллекции
+// @Summary      Переставить избранное
+// @Description  Задает порядок элементов коллекции (collection_id пустой — элементы вне коллекций). В ids должны быть все элементы коллекции ровно по одному разу, иначе 409 и порядок не меняется. Закрепленные элементы все равно выводятся первыми.
+// @Tags         favorites
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        order    body      models.FavoriteOrder  true  "Новый порядок"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      409      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/order [put]
+func (h *FavoriteHandler) ReorderFavoriteItems(c *gin.Context) {
+	userID := favoritesUser(c)
+	var order models.FavoriteOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.favoriteService.ReorderFavoriteItems(c.Request.Context(), userID, order); err != nil {
+		h.fail(c, err, "Failed to reorder favorites")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Favorites reordered"})
+}
+
+// ListCollections получает коллекции избранного
+// @Summary      Получить коллекции избранного
+// @Description  Возвращает коллекции пользователя в его порядке
+// @Tags         favorites
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200      {array}   models.FavoriteCollection
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/collections [get]
+func (h *FavoriteHandler) ListCollections(c *gin.Context) {
+	userID := favoritesUser(c)
+
+	collections, err := h.favoriteService.ListCollections(c.Request.Context(), userID)
+	if err != nil {
+		h.fail(c, err, "Failed to get collections")
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// CreateCollection создает коллекцию избранного
+// @Summary      Создать коллекцию избранного
+// @Description  Создает именованную коллекцию в конце списка. Имена коллекций одного пользователя не повторяются.
+// @Tags         favorites
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        collection  body      models.FavoriteCollectionDetails  true  "Имя и цвет"
+// @Success      201         {object}  models.FavoriteCollection
+// @Failure      400         {object}  map[string]string
+// @Failure      409         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Router       /favorites/collections [post]
+func (h *FavoriteHandler) CreateCollection(c *gin.Context) {
+	userID := favoritesUser(c)
+	var details models.FavoriteCollectionDetails
+	if err := c.ShouldBindJSON(&details); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	collection, err := h.favoriteService.CreateCollection(c.Request.Context(), userID, details)
+	if err != nil {
+		h.fail(c, err, "Failed to create collection")
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+// UpdateCollection переименовывает коллекцию или меняет ее цвет
+// @Summary      Изменить коллекцию избранного
+// @Description  Меняет имя и цвет коллекции; не переданные поля не меняются
+// @Tags         favorites
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path      string                            true  "ID коллекции"
+// @Param        collection  body      models.FavoriteCollectionDetails  true  "Имя и цвет"
+// @Success      200         {object}  models.FavoriteCollection
+// @Failure      400         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      409         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Router       /favorites/collections/{id} [patch]
+func (h *FavoriteHandler) UpdateCollection(c *gin.Context) {
+	userID := favoritesUser(c)
+	var details models.FavoriteCollectionDetails
+	if err := c.ShouldBindJSON(&details); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	collection, err := h.favoriteService.UpdateCollection(c.Request.Context(), userID, c.Param("id"), details)
+	if err != nil {
+		h.fail(c, err, "Failed to update collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// DeleteCollection удаляет коллекцию избранного
+// @Summary      Удалить коллекцию избранного
+// @Description  Удаляет коллекцию; ее элементы остаются в избранном вне коллекций
+// @Tags         favorites
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string  true  "ID коллекции"
+// @Success      200      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/collections/{id} [delete]
+func (h *FavoriteHandler) DeleteCollection(c *gin.Context) {
+	userID := favoritesUser(c)
+	id := c.Param("id")
+
+	if err := h.favoriteService.DeleteCollection(c.Request.Context(), userID, id); err != nil {
+		h.fail(c, err, "Failed to delete collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted", "id": id})
+}
+
+// ReorderCollections задает порядок коллекций
+// @Summary      Переставить коллекции избранного
+// @Description  Задает порядок коллекций. В ids должны быть все коллекции пользователя ровно по одному разу, иначе 409 и порядок не меняется.
+// @Tags         favorites
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        order    body      models.FavoriteOrder  true  "Новый порядок, collection_id не используется"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      409      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /favorites/collections/order [put]
+func (h *FavoriteHandler) ReorderCollections(c *gin.Context) {
+	userID := favoritesUser(c)
+	var order models.FavoriteOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.favoriteService.ReorderCollections(c.Request.Context(), userID, order.IDs); err != nil {
+		h.fail(c, err, "Failed to reorder collections")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collections reordered"})
+}
+
+// fail отвечает на ошибку коллекций и оформления избранного: ошибки клиента — с их текстом,
+// остальное логируется и отдается как 500 с message
+func (h *FavoriteHandler) fail(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrUnknownFavoriteType), errors.Is(err, service.ErrInvalidFavorite):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFavoriteNotFound), errors.Is(err, service.ErrCollectionNotFound), errors.Is(err, service.ErrUnknownFavoriteKey):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDuplicate):
+		c.JSON(http.StatusConflict, gin.H{"error": "collection with this name already exists"})
+	case errors.Is(err, repository.ErrReorderMismatch):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		requestLog(c, h.logger).WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+func favoriteItemParams(c *gin.Context) (favoriteType, key string, ok bool) {
+	favoriteType = c.Param("type")
+	key = strings.TrimSpace(c.Param("key"))
+	if key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key is required"})
+		return "", "", false
+	}
+	return favoriteType, key, true
+}
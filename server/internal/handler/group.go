@@ -38,7 +38,7 @@ func (h *GroupHandler) GetAllGroups(c *gin.Context) {
 
 	groups, err := h.groupService.GetAllGroups(c.Request.Context(), useCache)
 	if err != nil {
-		h.logger.Errorf("Failed to get groups: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to get groups")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -67,7 +67,7 @@ func (h *GroupHandler) GetGroupByNumber(c *gin.Context) {
 
 	group, err := h.groupService.GetGroupByNumber(c.Request.Context(), groupNumber)
 	if err != nil {
-		h.logger.Errorf("Failed to get group: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to get group")
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -81,12 +81,15 @@ func (h *GroupHandler) GetGroupByNumber(c *gin.Context) {
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/groups/refresh [post]
 func (h *GroupHandler) RefreshGroups(c *gin.Context) {
 	if err := h.groupService.RefreshGroups(c.Request.Context()); err != nil {
-		h.logger.Errorf("Failed to refresh groups: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to refresh groups")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
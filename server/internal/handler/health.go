@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+)
+
+type HealthHandler struct {
+	healthService service.HealthService
+	logger        *logrus.Logger
+}
+
+func NewHealthHandler(healthService service.HealthService, logger *logrus.Logger) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+		logger:        logger,
+	}
+}
+
+// Livez сообщает, что процесс жив и обрабатывает запросы
+// @Summary Проверка жизнеспособности
+// @Description Не обращается к зависимостям; 200, пока процесс отвечает
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": models.HealthOK})
+}
+
+// Readyz сообщает, готов ли сервис принимать трафик
+// @Summary Проверка готовности
+// @Description Проверяет MongoDB (критично), доступность API БГУИРа, прогрев кэша и отставание фоновых задач
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthReport
+// @Failure 503 {object} models.HealthReport
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == models.HealthUnavailable {
+		requestLog(c, h.logger).WithField("checks", report.Checks).Warn("Readiness check failed")
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
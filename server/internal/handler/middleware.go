@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+)
+
+const (
+	apiKeyContextKey = "api_key"
+	userIDContextKey = "user_id"
+
+	// anonymousFavoritesUser — общее избранное запросов без ключа пользователя, как у прежнего веб-клиента
+	anonymousFavoritesUser = "default"
+)
+
+// RequireRole пропускает запрос только с действующим API-ключом нужной роли.
+// Ключ передается в заголовке X-API-Key или как Authorization: Bearer <key>.
+func RequireRole(authService service.AuthService, role string, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, err := authService.Authenticate(c.Request.Context(), apiKeyFromRequest(c))
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "valid api key is required"})
+			return
+		}
+		if err != nil {
+			requestLog(c, logger).WithError(err).Error("Failed to authenticate api key")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to authenticate"})
+			return
+		}
+
+		if key.Role != role {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// RequireUser пропускает запрос только с действующим ключом роли user и кладет его пользователя
+// в контекст gin. Личные данные (/me/...) берут ID пользователя только отсюда, а не из запроса.
+func RequireUser(authService service.AuthService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, err := authService.Authenticate(c.Request.Context(), apiKeyFromRequest(c))
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "valid api key is required"})
+			return
+		}
+		if err != nil {
+			requestLog(c, logger).WithError(err).Error("Failed to authenticate api key")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to authenticate"})
+			return
+		}
+
+		if key.Role != models.RoleUser || key.UserID == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key is not bound to a user"})
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Set(userIDContextKey, key.UserID)
+		c.Next()
+	}
+}
+
+// IdentifyUser — необязательная аутентификация для общих эндпоинтов: с действующим ключом роли user
+// его пользователь попадает в контекст, без ключа запрос проходит анонимно. Неверный ключ — 401,
+// чтобы опечатка в ключе не превращалась молча в анонимный запрос.
+func IdentifyUser(authService service.AuthService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := apiKeyFromRequest(c)
+		if rawKey == "" {
+			c.Next()
+			return
+		}
+
+		key, err := authService.Authenticate(c.Request.Context(), rawKey)
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+			return
+		}
+		if err != nil {
+			requestLog(c, logger).WithError(err).Error("Failed to authenticate api key")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to authenticate"})
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		if key.Role == models.RoleUser && key.UserID != "" {
+			c.Set(userIDContextKey, key.UserID)
+		}
+		c.Next()
+	}
+}
+
+// currentUser — пользователь, подтвержденный RequireUser или IdentifyUser; пусто для анонимного запроса
+func currentUser(c *gin.Context) string {
+	return c.GetString(userIDContextKey)
+}
+
+// favoritesUser — владелец избранного: пользователь ключа, а без ключа — общее избранное default.
+// Параметр user_id не читается, иначе любой мог бы читать и менять чужое избранное.
+func favoritesUser(c *gin.Context) string {
+	if userID := currentUser(c); userID != "" {
+		return userID
+	}
+	return anonymousFavoritesUser
+}
+
+func apiKeyFromRequest(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+
+	auth := c.GetHeader("Authorization")
+	if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/service"
+	"schedluer/pkg/converter"
+)
+
+type NoteHandler struct {
+	noteService service.NoteService
+	logger      *logrus.Logger
+}
+
+func NewNoteHandler(noteService service.NoteService, logger *logrus.Logger) *NoteHandler {
+	return &NoteHandler{
+		noteService: noteService,
+		logger:      logger,
+	}
+}
+
+// ListNotes получает заметки и домашние задания пользователя
+// @Summary      Мои заметки к занятиям
+// @Description  Возвращает заметки и домашние задания по дате и времени занятия. Можно сузить по группе и дате занятия.
+// @Tags         notes
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        group    query     string  false  "Номер группы"
+// @Param        date     query     string  false  "Дата занятия, YYYY-MM-DD"
+// @Success      200      {array}   models.LessonNote
+// @Failure      400      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/notes [get]
+func (h *NoteHandler) ListNotes(c *gin.Context) {
+	userID := currentUser(c)
+	filter := repository.NoteFilter{GroupNumber: c.Query("group"), Date: c.Query("date")}
+
+	notes, err := h.noteService.ListNotes(c.Request.Context(), userID, filter)
+	if err != nil {
+		h.fail(c, err, "Failed to get notes")
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// CreateNote создает заметку или домашнее задание к занятию
+// @Summary      Создать заметку
+// @Description  kind — note или homework; у домашнего задания обязателен due_at. Занятие (группа, предмет, тип, дата и время начала) сверяется с расписанием группы. Вложения — только ссылки http(s), не больше 10.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        note     body      models.NoteRequest  true  "Заметка"
+// @Success      201      {object}  models.LessonNote
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/notes [post]
+func (h *NoteHandler) CreateNote(c *gin.Context) {
+	userID := currentUser(c)
+	var request models.NoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	note, err := h.noteService.CreateNote(c.Request.Context(), userID, request)
+	if err != nil {
+		h.fail(c, err, "Failed to create note")
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// UpdateNote изменяет заметку
+// @Summary      Изменить заметку
+// @Description  Меняет только переданные поля: text, attachments (список целиком), due_at и done. Срок и отметка о выполнении есть только у домашних заданий.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id       path      string              true  "ID заметки"
+// @Param        note     body      models.NoteDetails  true  "Изменения"
+// @Success      200      {object}  models.LessonNote
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/notes/{id} [patch]
+func (h *NoteHandler) UpdateNote(c *gin.Context) {
+	userID := currentUser(c)
+	var details models.NoteDetails
+	if err := c.ShouldBindJSON(&details); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	note, err := h.noteService.UpdateNote(c.Request.Context(), userID, c.Param("id"), details)
+	if err != nil {
+		h.fail(c, err, "Failed to update note")
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// DeleteNote удаляет заметку
+// @Summary      Удалить заметку
+// @Tags         notes
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id       path      string  true  "ID заметки"
+// @Success      200      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/notes/{id} [delete]
+func (h *NoteHandler) DeleteNote(c *gin.Context) {
+	userID := currentUser(c)
+	id := c.Param("id")
+
+	if err := h.noteService.DeleteNote(c.Request.Context(), userID, id); err != nil {
+		h.fail(c, err, "Failed to delete note")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Note deleted", "id": id})
+}
+
+// ListHomework получает домашние задания по сроку сдачи
+// @Summary      Мои домашние задания
+// @Description  Возвращает домашние задания по сроку сдачи, ближайшие первыми. Выполненные скрыты, если не передан include_done=true.
+// @Tags         notes
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        due_before    query     string  false  "Только со сроком раньше этого дня, YYYY-MM-DD"
+// @Param        include_done  query     bool    false  "Показывать выполненные"
+// @Success      200           {array}   models.LessonNote
+// @Failure      400           {object}  map[string]string
+// @Failure      500           {object}  map[string]string
+// @Router       /me/homework [get]
+func (h *NoteHandler) ListHomework(c *gin.Context) {
+	userID := currentUser(c)
+	filter := repository.HomeworkFilter{IncludeDone: c.Query("include_done") == "true"}
+
+	if value := c.Query("due_before"); value != "" {
+		dueBefore, err := time.ParseInLocation(time.DateOnly, value, converter.Minsk)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "due_before must be a date in YYYY-MM-DD format"})
+			return
+		}
+		filter.DueBefore = dueBefore
+	}
+
+	homework, err := h.noteService.ListHomework(c.Request.Context(), userID, filter)
+	if err != nil {
+		h.fail(c, err, "Failed to get homework")
+		return
+	}
+
+	c.JSON(http.StatusOK, homework)
+}
+
+func (h *NoteHandler) fail(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidNote):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNoteNotFound), errors.Is(err, service.ErrLessonNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		requestLog(c, h.logger).WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+)
+
+type OverrideHandler struct {
+	overrideService service.OverrideService
+	logger          *logrus.Logger
+}
+
+func NewOverrideHandler(overrideService service.OverrideService, logger *logrus.Logger) *OverrideHandler {
+	return &OverrideHandler{
+		overrideService: overrideService,
+		logger:          logger,
+	}
+}
+
+// ListOverrides получает личные правки расписания
+// @Summary      Мои правки расписания
+// @Description  Возвращает правки пользователя в порядке создания. Применяются к /schedule/... с personalized=true.
+// @Tags         overrides
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Success      200      {array}   models.LessonOverride
+// @Failure      500      {object}  map[string]string
+// @Router       /me/overrides [get]
+func (h *OverrideHandler) ListOverrides(c *gin.Context) {
+	userID := currentUser(c)
+
+	overrides, err := h.overrideService.ListOverrides(c.Request.Context(), userID)
+	if err != nil {
+		h.fail(c, err, "Failed to get overrides")
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+// CreateOverride создает правку серии занятий
+// @Summary      Создать правку
+// @Description  Скрывает серию занятий (hidden) или меняет ей аудитории и время. Серия — предмет и, по желанию, тип занятия, день недели, время начала и подгруппа; незаданные поля подходят к любому занятию предмета.
+// @Tags         overrides
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        override  body      models.OverrideRequest  true  "Правка"
+// @Success      201       {object}  models.LessonOverride
+// @Failure      400       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Router       /me/overrides [post]
+func (h *OverrideHandler) CreateOverride(c *gin.Context) {
+	userID := currentUser(c)
+	var request models.OverrideRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	override, err := h.overrideService.CreateOverride(c.Request.Context(), userID, request)
+	if err != nil {
+		h.fail(c, err, "Failed to create override")
+		return
+	}
+
+	c.JSON(http.StatusCreated, override)
+}
+
+// UpdateOverride заменяет правку
+// @Summary      Изменить правку
+// @Description  Заменяет правку целиком, поля те же, что при создании
+// @Tags         overrides
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id        path      string                  true  "ID правки"
+// @Param        override  body      models.OverrideRequest  true  "Правка"
+// @Success      200       {object}  models.LessonOverride
+// @Failure      400       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Router       /me/overrides/{id} [put]
+func (h *OverrideHandler) UpdateOverride(c *gin.Context) {
+	userID := currentUser(c)
+	var request models.OverrideRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	override, err := h.overrideService.UpdateOverride(c.Request.Context(), userID, c.Param("id"), request)
+	if err != nil {
+		h.fail(c, err, "Failed to update override")
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// DeleteOverride удаляет правку
+// @Summary      Удалить правку
+// @Description  Удаляет правку, серия снова показывается как в расписании
+// @Tags         overrides
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id       path      string  true  "ID правки"
+// @Success      200      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/overrides/{id} [delete]
+func (h *OverrideHandler) DeleteOverride(c *gin.Context) {
+	userID := currentUser(c)
+	id := c.Param("id")
+
+	if err := h.overrideService.DeleteOverride(c.Request.Context(), userID, id); err != nil {
+		h.fail(c, err, "Failed to delete override")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Override deleted", "id": id})
+}
+
+func (h *OverrideHandler) fail(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidOverride):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOverrideNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		requestLog(c, h.logger).WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
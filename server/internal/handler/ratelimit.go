@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/config"
+	"schedluer/internal/service"
+	"schedluer/pkg/ratelimit"
+)
+
+const (
+	// clientKeyTTL — сколько помнить, чей бюджет расходует API-ключ: без кэша каждый запрос с ключом
+	// ходил бы в хранилище еще до авторизации. Отозванный ключ еще столько же считается своим бюджетом.
+	clientKeyTTL = time.Minute
+	// clientKeyCacheSize ограничивает кэш, чтобы перебор выдуманных ключей не раздувал память
+	clientKeyCacheSize = 10000
+)
+
+// unlimitedPaths — служебные адреса мониторинга; их опрашивают часто и с одного адреса
+var unlimitedPaths = []string{"/metrics", "/healthz", "/readyz", "/livez", "/health"}
+
+// RateLimiter ограничивает входящие запросы отдельными бюджетами для трех классов маршрутов:
+// принудительное обновление, чтение в обход кэша и обычное чтение из кэша.
+type RateLimiter struct {
+	enabled  atomic.Bool
+	refresh  *ratelimit.Limiter
+	uncached *ratelimit.Limiter
+	cached   *ratelimit.Limiter
+
+	authService service.AuthService
+	logger      *logrus.Logger
+
+	keysMu sync.Mutex
+	keys   map[[sha256.Size]byte]cachedClientKey
+}
+
+type cachedClientKey struct {
+	client  string
+	expires time.Time
+}
+
+func NewRateLimiter(cfg config.RateLimitConfig, authService service.AuthService, logger *logrus.Logger) *RateLimiter {
+	l := &RateLimiter{
+		refresh:     ratelimit.New(ratelimit.Rule(cfg.Refresh)),
+		uncached:    ratelimit.New(ratelimit.Rule(cfg.Uncached)),
+		cached:      ratelimit.New(ratelimit.Rule(cfg.Cached)),
+		authService: authService,
+		logger:      logger,
+		keys:        make(map[[sha256.Size]byte]cachedClientKey),
+	}
+	l.enabled.Store(cfg.Enabled)
+	return l
+}
+
+// Update применяет новые лимиты без перезапуска (по SIGHUP)
+func (l *RateLimiter) Update(cfg config.RateLimitConfig) {
+	l.refresh.SetRule(ratelimit.Rule(cfg.Refresh))
+	l.uncached.SetRule(ratelimit.Rule(cfg.Uncached))
+	l.cached.SetRule(ratelimit.Rule(cfg.Cached))
+	l.enabled.Store(cfg.Enabled)
+}
+
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.enabled.Load() || slices.Contains(unlimitedPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		limiter, class := l.limiterFor(c)
+		key := l.clientKey(c)
+
+		allowed, retryAfter := limiter.Allow(key)
+		if !allowed {
+			seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+			requestLog(c, l.logger).WithFields(logrus.Fields{
+				"client":      key,
+				"limit_class": class,
+				"retry_after": seconds,
+			}).Debug("Rate limit exceeded")
+
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "rate limit exceeded",
+				"retry_after": seconds,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (l *RateLimiter) limiterFor(c *gin.Context) (*ratelimit.Limiter, string) {
+	if c.Request.Method == http.MethodPost && strings.HasSuffix(c.Request.URL.Path, "/refresh") {
+		return l.refresh, "refresh"
+	}
+	if c.Query("useCache") == "false" {
+		return l.uncached, "uncached"
+	}
+	return l.cached, "cached"
+}
+
+// clientKey определяет, чей бюджет расходует запрос. Заголовок с API-ключом учитывается
+// только для действующего ключа, иначе перебором выдуманных ключей лимит легко обойти.
+// Результат проверки ключа кэшируется на clientKeyTTL.
+func (l *RateLimiter) clientKey(c *gin.Context) string {
+	ip := "ip:" + c.ClientIP()
+	rawKey := apiKeyFromRequest(c)
+	if rawKey == "" {
+		return ip
+	}
+
+	hash := sha256.Sum256([]byte(rawKey))
+	now := time.Now()
+	l.keysMu.Lock()
+	cached, ok := l.keys[hash]
+	l.keysMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		if cached.client == "" {
+			return ip
+		}
+		return cached.client
+	}
+
+	key, err := l.authService.Authenticate(c.Request.Context(), rawKey)
+	client := ""
+	switch {
+	case err == nil:
+		client = "key:" + key.Prefix
+	case !errors.Is(err, service.ErrInvalidAPIKey):
+		// Хранилище недоступно: не запоминаем, следующий запрос проверит ключ снова
+		return ip
+	}
+
+	l.keysMu.Lock()
+	if len(l.keys) >= clientKeyCacheSize {
+		for hash, cached := range l.keys {
+			if !now.Before(cached.expires) {
+				delete(l.keys, hash)
+			}
+		}
+		if len(l.keys) >= clientKeyCacheSize {
+			clear(l.keys)
+		}
+	}
+	l.keys[hash] = cachedClientKey{client: client, expires: now.Add(clientKeyTTL)}
+	l.keysMu.Unlock()
+
+	if client == "" {
+		return ip
+	}
+	return client
+}
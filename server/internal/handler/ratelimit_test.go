@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/config"
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+)
+
+// fakeAuth знает один ключ и считает обращения к хранилищу ключей
+type fakeAuth struct {
+	service.AuthService
+	calls int
+}
+
+func (a *fakeAuth) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	a.calls++
+	if rawKey == "sch_valid_secret" {
+		return &models.APIKey{Prefix: "sch_valid"}, nil
+	}
+	return nil, service.ErrInvalidAPIKey
+}
+
+func newRateLimitedEngine(auth service.AuthService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	// Каждому классу — один запрос, чтобы по второму было видно, чей бюджет он расходует
+	rule := config.RateLimitRule{RPS: 0.001, Burst: 1}
+	limiter := NewRateLimiter(config.RateLimitConfig{Enabled: true, Refresh: rule, Uncached: rule, Cached: rule}, auth, logger)
+
+	engine := gin.New()
+	engine.Use(limiter.Middleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	engine.GET("/api/v1/groups", ok)
+	engine.POST("/api/v1/groups/refresh", ok)
+	for _, path := range unlimitedPaths {
+		engine.GET(path, ok)
+	}
+	return engine
+}
+
+type rateLimitRequest struct {
+	method, path, ip, key string
+}
+
+func (r rateLimitRequest) do(engine *gin.Engine) int {
+	req := httptest.NewRequest(r.method, r.path, nil)
+	req.RemoteAddr = r.ip + ":40000"
+	if r.key != "" {
+		req.Header.Set("X-API-Key", r.key)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimiterClasses(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  rateLimitRequest
+		second rateLimitRequest
+		want   int
+	}{
+		{"same class, same client",
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", ""},
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", ""}, http.StatusTooManyRequests},
+		{"cached and uncached",
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", ""},
+			rateLimitRequest{"GET", "/api/v1/groups?useCache=false", "192.0.2.1", ""}, http.StatusOK},
+		{"cached and refresh",
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", ""},
+			rateLimitRequest{"POST", "/api/v1/groups/refresh", "192.0.2.1", ""}, http.StatusOK},
+		{"different addresses",
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", ""},
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.2", ""}, http.StatusOK},
+		{"valid key gets its own budget",
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", ""},
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", "sch_valid_secret"}, http.StatusOK},
+		{"valid key follows the client across addresses",
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", "sch_valid_secret"},
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.2", "sch_valid_secret"}, http.StatusTooManyRequests},
+		{"invalid key counts against the address",
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", ""},
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", "sch_made_up"}, http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		engine := newRateLimitedEngine(&fakeAuth{})
+		if code := tt.first.do(engine); code != http.StatusOK {
+			t.Fatalf("%s: first request = %d", tt.name, code)
+		}
+		if code := tt.second.do(engine); code != tt.want {
+			t.Errorf("%s: second request = %d, want %d", tt.name, code, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterSkipsMonitoring(t *testing.T) {
+	engine := newRateLimitedEngine(&fakeAuth{})
+	for _, path := range unlimitedPaths {
+		for range 5 {
+			if code := (rateLimitRequest{"GET", path, "192.0.2.1", ""}).do(engine); code != http.StatusOK {
+				t.Errorf("%s = %d, monitoring must not be rate limited", path, code)
+				break
+			}
+		}
+	}
+}
+
+func TestRateLimiterCachesKeyLookups(t *testing.T) {
+	auth := &fakeAuth{}
+	engine := newRateLimitedEngine(auth)
+
+	for _, key := range []string{"sch_valid_secret", "sch_made_up"} {
+		for range 3 {
+			rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", key}.do(engine)
+		}
+	}
+	if auth.calls != 2 {
+		t.Errorf("Authenticate called %d times for two keys, want 2", auth.calls)
+	}
+}
+
+// failingAuth — хранилище ключей недоступно
+type failingAuth struct {
+	service.AuthService
+	calls int
+}
+
+func (a *failingAuth) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	a.calls++
+	return nil, errors.New("connection refused")
+}
+
+func TestRateLimiterDoesNotCacheStorageErrors(t *testing.T) {
+	auth := &failingAuth{}
+	engine := newRateLimitedEngine(auth)
+	for range 2 {
+		rateLimitRequest{"GET", "/api/v1/groups", "192.0.2.1", "sch_valid_secret"}.do(engine)
+	}
+	if auth.calls != 2 {
+		t.Errorf("Authenticate called %d times, storage errors must not be cached", auth.calls)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+)
+
+type ReminderHandler struct {
+	reminderService service.ReminderService
+	logger          *logrus.Logger
+}
+
+func NewReminderHandler(reminderService service.ReminderService, logger *logrus.Logger) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+		logger:          logger,
+	}
+}
+
+// GetReminder получает подписку на напоминания
+// @Summary      Моя подписка на напоминания
+// @Description  Возвращает канал, получателя, время упреждения и тихие часы напоминаний о занятиях
+// @Tags         reminders
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Success      200      {object}  models.ReminderSubscription
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/reminders [get]
+func (h *ReminderHandler) GetReminder(c *gin.Context) {
+	userID := currentUser(c)
+
+	subscription, err := h.reminderService.GetReminder(c.Request.Context(), userID)
+	if err != nil {
+		h.fail(c, err, "Failed to get reminder")
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// SaveReminder создает или заменяет подписку на напоминания
+// @Summary      Подписаться на напоминания
+// @Description  Напоминает за lead_minutes минут до каждого занятия из /me/timetable. Каналы: webhook (target — https URL с публичным адресом, на него приходит POST с JSON), email (target — адрес), telegram (target — chat_id или @username). Доступны только каналы, настроенные на сервере. В тихие часы (по Минску, могут переходить через полночь) напоминания не отправляются.
+// @Tags         reminders
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        reminder  body      models.ReminderRequest  true  "Подписка"
+// @Success      200       {object}  models.ReminderSubscription
+// @Failure      400       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Router       /me/reminders [put]
+func (h *ReminderHandler) SaveReminder(c *gin.Context) {
+	userID := currentUser(c)
+	var request models.ReminderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	subscription, err := h.reminderService.SaveReminder(c.Request.Context(), userID, request)
+	if err != nil {
+		h.fail(c, err, "Failed to save reminder")
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteReminder отписывает от напоминаний
+// @Summary      Отписаться от напоминаний
+// @Tags         reminders
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Success      200      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/reminders [delete]
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	userID := currentUser(c)
+
+	if err := h.reminderService.DeleteReminder(c.Request.Context(), userID); err != nil {
+		h.fail(c, err, "Failed to delete reminder")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder deleted"})
+}
+
+func (h *ReminderHandler) fail(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReminder):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrReminderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		requestLog(c, h.logger).WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
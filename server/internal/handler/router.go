@@ -4,56 +4,129 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"schedluer/internal/models"
 	"schedluer/internal/service"
 )
 
 type Router struct {
-	scheduleHandler *ScheduleHandler
-	groupHandler    *GroupHandler
-	employeeHandler *EmployeeHandler
-	favoriteHandler *FavoriteHandler
+	scheduleHandler  *ScheduleHandler
+	groupHandler     *GroupHandler
+	employeeHandler  *EmployeeHandler
+	favoriteHandler  *FavoriteHandler
+	timetableHandler *TimetableHandler
+	shareHandler     *ShareHandler
+	eventHandler     *EventHandler
+	noteHandler      *NoteHandler
+	overrideHandler  *OverrideHandler
+	reminderHandler  *ReminderHandler
+	healthHandler    *HealthHandler
+
+	requireAdmin gin.HandlerFunc
+	requireUser  gin.HandlerFunc
+	identifyUser gin.HandlerFunc
 }
 
-func NewRouter(scheduleService service.ScheduleService, groupService service.GroupService, employeeService service.EmployeeService, favoriteService service.FavoriteService, logger *logrus.Logger) *Router {
+func NewRouter(scheduleService service.ScheduleService, groupService service.GroupService, employeeService service.EmployeeService, favoriteService service.FavoriteService, timetableService service.TimetableService, shareService service.ShareService, eventService service.EventService, noteService service.NoteService, overrideService service.OverrideService, reminderService service.ReminderService, authService service.AuthService, healthService service.HealthService, logger *logrus.Logger) *Router {
 	return &Router{
-		scheduleHandler: NewScheduleHandler(scheduleService, logger),
-		groupHandler:    NewGroupHandler(groupService, logger),
-		employeeHandler: NewEmployeeHandler(employeeService, logger),
-		favoriteHandler: NewFavoriteHandler(favoriteService, logger),
+		scheduleHandler:  NewScheduleHandler(scheduleService, overrideService, logger),
+		groupHandler:     NewGroupHandler(groupService, logger),
+		employeeHandler:  NewEmployeeHandler(employeeService, logger),
+		favoriteHandler:  NewFavoriteHandler(favoriteService, logger),
+		timetableHandler: NewTimetableHandler(timetableService, logger),
+		shareHandler:     NewShareHandler(shareService, logger),
+		eventHandler:     NewEventHandler(eventService, logger),
+		noteHandler:      NewNoteHandler(noteService, logger),
+		overrideHandler:  NewOverrideHandler(overrideService, logger),
+		reminderHandler:  NewReminderHandler(reminderService, logger),
+		healthHandler:    NewHealthHandler(healthService, logger),
+		requireAdmin:     RequireRole(authService, models.RoleAdmin, logger),
+		requireUser:      RequireUser(authService, logger),
+		identifyUser:     IdentifyUser(authService, logger),
 	}
 }
 
 func (r *Router) SetupRoutes(engine *gin.Engine) {
+	engine.GET("/livez", r.healthHandler.Livez)
+	engine.GET("/readyz", r.healthHandler.Readyz)
+	// Старый адрес проверки, оставлен для существующих мониторингов
+	engine.GET("/health", r.healthHandler.Readyz)
+
 	api := engine.Group("/api/v1")
 
-	schedule := api.Group("/schedule")
+	// Эндпоинты /refresh запускают полный обход API БГУИРа, поэтому доступны только администраторам;
+	// personalized=true накладывает правки пользователя ключа
+	schedule := api.Group("/schedule", r.identifyUser)
 	{
 		schedule.GET("/group/:groupNumber", r.scheduleHandler.GetGroupSchedule)
-		schedule.POST("/group/:groupNumber/refresh", r.scheduleHandler.RefreshGroupSchedule)
+		schedule.POST("/group/:groupNumber/refresh", r.requireAdmin, r.scheduleHandler.RefreshGroupSchedule)
 		schedule.GET("/employee/:urlId", r.scheduleHandler.GetEmployeeSchedule)
-		schedule.POST("/employee/:urlId/refresh", r.scheduleHandler.RefreshEmployeeSchedule)
+		schedule.POST("/employee/:urlId/refresh", r.requireAdmin, r.scheduleHandler.RefreshEmployeeSchedule)
 	}
 
 	groups := api.Group("/groups")
 	{
 		groups.GET("", r.groupHandler.GetAllGroups)
 		groups.GET("/:groupNumber", r.groupHandler.GetGroupByNumber)
-		groups.POST("/refresh", r.groupHandler.RefreshGroups)
+		groups.POST("/refresh", r.requireAdmin, r.groupHandler.RefreshGroups)
 	}
 
 	employees := api.Group("/employees")
 	{
 		employees.GET("", r.employeeHandler.GetAllEmployees)
 		employees.GET("/:urlId", r.employeeHandler.GetEmployeeByURLID)
-		employees.POST("/refresh", r.employeeHandler.RefreshEmployees)
+		employees.POST("/refresh", r.requireAdmin, r.employeeHandler.RefreshEmployees)
 	}
 
-	favorites := api.Group("/favorites")
+	// С ключом пользователя избранное принадлежит ему, без ключа — общее избранное default
+	favorites := api.Group("/favorites", r.identifyUser)
 	{
 		favorites.GET("", r.favoriteHandler.GetAllFavorites)
 		favorites.GET("/search", r.favoriteHandler.SearchFavorites)
 		favorites.POST("/:groupNumber", r.favoriteHandler.AddFavorite)
 		favorites.DELETE("/:groupNumber", r.favoriteHandler.RemoveFavorite)
 		favorites.GET("/:groupNumber/check", r.favoriteHandler.IsFavorite)
+
+		// Типизированное избранное; /favorites/:groupNumber выше — прежние эндпоинты только для групп
+		favorites.GET("/items", r.favoriteHandler.ListFavoriteItems)
+		favorites.POST("/items/:type/:key", r.favoriteHandler.AddFavoriteItem)
+		favorites.DELETE("/items/:type/:key", r.favoriteHandler.RemoveFavoriteItem)
+		favorites.PATCH("/items/:type/:key", r.favoriteHandler.UpdateFavoriteItem)
+		favorites.GET("/items/:type/:key/check", r.favoriteHandler.IsFavoriteItem)
+		favorites.PUT("/order", r.favoriteHandler.ReorderFavoriteItems)
+
+		favorites.GET("/collections", r.favoriteHandler.ListCollections)
+		favorites.POST("/collections", r.favoriteHandler.CreateCollection)
+		favorites.PUT("/collections/order", r.favoriteHandler.ReorderCollections)
+		favorites.PATCH("/collections/:id", r.favoriteHandler.UpdateCollection)
+		favorites.DELETE("/collections/:id", r.favoriteHandler.DeleteCollection)
 	}
+
+	// Личные данные — только пользователю действующего ключа; user_id из запроса здесь не читается
+	me := api.Group("/me", r.requireUser)
+	{
+		me.GET("/timetable", r.timetableHandler.GetTimetable)
+		me.GET("/shares", r.shareHandler.ListShares)
+		me.POST("/shares", r.shareHandler.CreateShare)
+		me.DELETE("/shares/:id", r.shareHandler.RevokeShare)
+		me.GET("/events", r.eventHandler.ListEvents)
+		me.POST("/events", r.eventHandler.CreateEvent)
+		me.GET("/events/:id", r.eventHandler.GetEvent)
+		me.PUT("/events/:id", r.eventHandler.UpdateEvent)
+		me.DELETE("/events/:id", r.eventHandler.DeleteEvent)
+		me.GET("/notes", r.noteHandler.ListNotes)
+		me.POST("/notes", r.noteHandler.CreateNote)
+		me.PATCH("/notes/:id", r.noteHandler.UpdateNote)
+		me.DELETE("/notes/:id", r.noteHandler.DeleteNote)
+		me.GET("/homework", r.noteHandler.ListHomework)
+		me.GET("/overrides", r.overrideHandler.ListOverrides)
+		me.POST("/overrides", r.overrideHandler.CreateOverride)
+		me.PUT("/overrides/:id", r.overrideHandler.UpdateOverride)
+		me.DELETE("/overrides/:id", r.overrideHandler.DeleteOverride)
+		me.GET("/reminders", r.reminderHandler.GetReminder)
+		me.PUT("/reminders", r.reminderHandler.SaveReminder)
+		me.DELETE("/reminders", r.reminderHandler.DeleteReminder)
+	}
+
+	// Публичные ссылки открываются без user_id: доступ дает только токен
+	api.GET("/shared/:token", r.shareHandler.GetShared)
 }
@@ -6,19 +6,20 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"schedluer/internal/models"
 	"schedluer/internal/service"
-
-	_ "schedluer/internal/models" // для Swagger документации
 )
 
 type ScheduleHandler struct {
 	scheduleService service.ScheduleService
+	overrideService service.OverrideService
 	logger          *logrus.Logger
 }
 
-func NewScheduleHandler(scheduleService service.ScheduleService, logger *logrus.Logger) *ScheduleHandler {
+func NewScheduleHandler(scheduleService service.ScheduleService, overrideService service.OverrideService, logger *logrus.Logger) *ScheduleHandler {
 	return &ScheduleHandler{
 		scheduleService: scheduleService,
+		overrideService: overrideService,
 		logger:          logger,
 	}
 }
@@ -31,6 +32,9 @@ func NewScheduleHandler(scheduleService service.ScheduleService, logger *logrus.
 // @Produce json
 // @Param groupNumber path string true "Номер группы"
 // @Param useCache query bool false "Использовать кэш" default(true)
+// @Param personalized query bool false "Применить личные правки пользователя; нужен API-ключ пользователя"
+// @Security ApiKeyAuth
+// @Failure 401 {object} map[string]string
 // @Success 200 {object} models.ScheduleResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -46,12 +50,12 @@ func (h *ScheduleHandler) GetGroupSchedule(c *gin.Context) {
 
 	schedule, err := h.scheduleService.GetGroupSchedule(c.Request.Context(), groupNumber, useCache)
 	if err != nil {
-		h.logger.Errorf("Failed to get group schedule: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to get group schedule")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, schedule)
+	h.respond(c, models.FavoriteTypeGroup, groupNumber, schedule)
 }
 
 // GetEmployeeSchedule получает расписание преподавателя
@@ -62,6 +66,9 @@ func (h *ScheduleHandler) GetGroupSchedule(c *gin.Context) {
 // @Produce json
 // @Param urlId path string true "URL ID преподавателя"
 // @Param useCache query bool false "Использовать кэш" default(true)
+// @Param personalized query bool false "Применить личные правки пользователя; нужен API-ключ пользователя"
+// @Security ApiKeyAuth
+// @Failure 401 {object} map[string]string
 // @Success 200 {object} models.ScheduleResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -77,12 +84,12 @@ func (h *ScheduleHandler) GetEmployeeSchedule(c *gin.Context) {
 
 	schedule, err := h.scheduleService.GetEmployeeSchedule(c.Request.Context(), urlID, useCache)
 	if err != nil {
-		h.logger.Errorf("Failed to get employee schedule: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to get employee schedule")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, schedule)
+	h.respond(c, models.FavoriteTypeEmployee, urlID, schedule)
 }
 
 // RefreshGroupSchedule обновляет расписание группы
@@ -92,8 +99,11 @@ func (h *ScheduleHandler) GetEmployeeSchedule(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param groupNumber path string true "Номер группы"
+// @Security ApiKeyAuth
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/schedule/group/{groupNumber}/refresh [post]
 func (h *ScheduleHandler) RefreshGroupSchedule(c *gin.Context) {
@@ -104,7 +114,7 @@ func (h *ScheduleHandler) RefreshGroupSchedule(c *gin.Context) {
 	}
 
 	if err := h.scheduleService.RefreshGroupSchedule(c.Request.Context(), groupNumber); err != nil {
-		h.logger.Errorf("Failed to refresh group schedule: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to refresh group schedule")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -119,8 +129,11 @@ func (h *ScheduleHandler) RefreshGroupSchedule(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param urlId path string true "URL ID преподавателя"
+// @Security ApiKeyAuth
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/schedule/employee/{urlId}/refresh [post]
 func (h *ScheduleHandler) RefreshEmployeeSchedule(c *gin.Context) {
@@ -131,10 +144,30 @@ func (h *ScheduleHandler) RefreshEmployeeSchedule(c *gin.Context) {
 	}
 
 	if err := h.scheduleService.RefreshEmployeeSchedule(c.Request.Context(), urlID); err != nil {
-		h.logger.Errorf("Failed to refresh employee schedule: %v", err)
+		requestLog(c, h.logger).WithError(err).Error("Failed to refresh employee schedule")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "schedule refreshed successfully"})
 }
+
+// respond отдает расписание; с personalized=true на него накладываются правки пользователя из /me/overrides
+func (h *ScheduleHandler) respond(c *gin.Context, scheduleType, key string, schedule *models.ScheduleResponse) {
+	if c.Query("personalized") == "true" {
+		userID := currentUser(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "personalized schedule requires a user api key"})
+			return
+		}
+		personalized, err := h.overrideService.Personalize(c.Request.Context(), userID, scheduleType, key, schedule)
+		if err != nil {
+			requestLog(c, h.logger).WithError(err).Error("Failed to personalize schedule")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to personalize schedule"})
+			return
+		}
+		schedule = personalized
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
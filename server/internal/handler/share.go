@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/service"
+	"schedluer/pkg/ical"
+)
+
+// calendarSuffix — окончание токена, по которому ссылка отдается календарем iCalendar
+const calendarSuffix = ".ics"
+
+type ShareHandler struct {
+	shareService service.ShareService
+	logger       *logrus.Logger
+}
+
+func NewShareHandler(shareService service.ShareService, logger *logrus.Logger) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+		logger:       logger,
+	}
+}
+
+// ListShares получает ссылки пользователя
+// @Summary      Мои публичные ссылки
+// @Description  Возвращает ссылки пользователя, включая отозванные и истекшие. Токены не возвращаются — только их начало в prefix.
+// @Tags         shares
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Success      200      {array}   models.Share
+// @Failure      500      {object}  map[string]string
+// @Router       /me/shares [get]
+func (h *ShareHandler) ListShares(c *gin.Context) {
+	userID := currentUser(c)
+
+	shares, err := h.shareService.ListShares(c.Request.Context(), userID)
+	if err != nil {
+		requestLog(c, h.logger).WithError(err).Error("Failed to list shares")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shares"})
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+// CreateShare создает публичную ссылку
+// @Summary      Создать публичную ссылку
+// @Description  Публикует избранное (kind=favorites) или ленту (kind=timetable) пользователя, целиком или одну коллекцию. Токен возвращается только в этом ответе; ссылка — /api/v1/shared/{token}, календарь — /api/v1/shared/{token}.ics.
+// @Tags         shares
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        share    body      models.ShareRequest  true  "Параметры ссылки"
+// @Success      201      {object}  models.CreatedShare
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/shares [post]
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	userID := currentUser(c)
+	var request models.ShareRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	share, err := h.shareService.CreateShare(c.Request.Context(), userID, request)
+	switch {
+	case errors.Is(err, service.ErrInvalidShare):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCollectionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		requestLog(c, h.logger).WithError(err).Error("Failed to create share")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share"})
+	default:
+		c.JSON(http.StatusCreated, share)
+	}
+}
+
+// RevokeShare отзывает публичную ссылку
+// @Summary      Отозвать публичную ссылку
+// @Description  После отзыва ссылка отвечает 404
+// @Tags         shares
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        id       path      string  true  "ID ссылки"
+// @Success      200      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/shares/{id} [delete]
+func (h *ShareHandler) RevokeShare(c *gin.Context) {
+	userID := currentUser(c)
+	id := c.Param("id")
+
+	err := h.shareService.RevokeShare(c.Request.Context(), userID, id)
+	if errors.Is(err, service.ErrShareNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		requestLog(c, h.logger).WithError(err).Error("Failed to revoke share")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke share"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share revoked", "id": id})
+}
+
+// GetShared отдает содержимое публичной ссылки
+// @Summary      Открыть публичную ссылку
+// @Description  Без авторизации. Для kind=favorites — избранное владельца, для kind=timetable — его лента за период. С окончанием .ics отдается календарь iCalendar с занятиями на 92 дня от from для подписки в календаре.
+// @Tags         shares
+// @Produce      json
+// @Produce      text/calendar
+// @Param        token  path      string  true   "Токен ссылки, с .ics — календарь"
+// @Param        from   query     string  false  "Первый день, YYYY-MM-DD (по умолчанию сегодня)"
+// @Param        to     query     string  false  "Последний день включительно, YYYY-MM-DD"
+// @Success      200    {object}  models.SharedView
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /shared/{token} [get]
+func (h *ShareHandler) GetShared(c *gin.Context) {
+	token, calendar := strings.CutSuffix(c.Param("token"), calendarSuffix)
+
+	days := defaultTimetableDays
+	if calendar {
+		days = service.MaxTimetableDays
+	}
+	from, to, ok := dateRange(c, days)
+	if !ok {
+		return
+	}
+
+	var (
+		view *models.SharedView
+		err  error
+	)
+	if calendar {
+		view, err = h.shareService.GetSharedTimetable(c.Request.Context(), token, from, to)
+	} else {
+		view, err = h.shareService.GetShared(c.Request.Context(), token, from, to)
+	}
+	switch {
+	case errors.Is(err, service.ErrShareNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "share not found"})
+		return
+	case errors.Is(err, service.ErrInvalidRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		requestLog(c, h.logger).WithError(err).Error("Failed to get shared content")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shared content"})
+		return
+	}
+
+	if !calendar {
+		c.JSON(http.StatusOK, view)
+		return
+	}
+	c.Header("Content-Type", ical.ContentType)
+	c.Header("Content-Disposition", `attachment; filename="schedule.ics"`)
+	c.Status(http.StatusOK)
+	if err := ical.Write(c.Writer, timetableCalendar(view, time.Now())); err != nil {
+		requestLog(c, h.logger).WithError(err).Warn("Failed to write calendar")
+	}
+}
+
+// timetableCalendar превращает ленту в календарь. UID события зависит только от самого занятия,
+// поэтому при повторной загрузке календарь обновляет события, а не дублирует их.
+func timetableCalendar(view *models.SharedView, stamp time.Time) ical.Calendar {
+	name := view.Title
+	if name == "" {
+		name = "Расписание БГУИР"
+	}
+	calendar := ical.Calendar{ProdID: "-//schedluer//RU", Name: name}
+
+	for _, entry := range view.Timetable.Entries {
+		lesson := entry.Lesson
+		// Личные события в публичные ссылки не попадают
+		if lesson == nil {
+			continue
+		}
+
+		summary := lesson.Subject
+		if lesson.LessonTypeAbbrev != "" {
+			summary += " (" + lesson.LessonTypeAbbrev + ")"
+		}
+
+		var description []string
+		for _, employee := range lesson.Employees {
+			description = append(description, strings.TrimSpace(employee.LastName+" "+employee.FirstName+" "+employee.MiddleName))
+		}
+		var groups []string
+		for _, group := range lesson.StudentGroups {
+			groups = append(groups, group.Name)
+		}
+		if len(groups) > 0 {
+			description = append(description, "Группы: "+strings.Join(groups, ", "))
+		}
+		if lesson.NumSubgroup > 0 {
+			description = append(description, fmt.Sprintf("Подгруппа %d", lesson.NumSubgroup))
+		}
+		if lesson.Note != "" {
+			description = append(description, lesson.Note)
+		}
+
+		uid := sha256.Sum256([]byte(strings.Join([]string{
+			entry.Start.UTC().Format(time.RFC3339),
+			lesson.Subject,
+			lesson.LessonTypeAbbrev,
+			strings.Join(lesson.Auditories, ","),
+			fmt.Sprint(lesson.NumSubgroup),
+		}, "|")))
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         hex.EncodeToString(uid[:16]) + "@schedluer",
+			Start:       entry.Start,
+			End:         entry.End,
+			Summary:     summary,
+			Location:    strings.Join(lesson.Auditories, ", "),
+			Description: strings.Join(description, "\n"),
+			Stamp:       stamp,
+		})
+	}
+	return calendar
+}
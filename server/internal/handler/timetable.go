@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/service"
+	"schedluer/pkg/converter"
+)
+
+// defaultTimetableDays — период ленты, если to не задан
+const defaultTimetableDays = 7
+
+type TimetableHandler struct {
+	timetableService service.TimetableService
+	logger           *logrus.Logger
+}
+
+func NewTimetableHandler(timetableService service.TimetableService, logger *logrus.Logger) *TimetableHandler {
+	return &TimetableHandler{
+		timetableService: timetableService,
+		logger:           logger,
+	}
+}
+
+// GetTimetable собирает расписания избранного пользователя в одну ленту
+// @Summary      Моя лента занятий
+// @Description  Объединяет расписания всех избранных групп и преподавателей в занятия по датам. У каждого занятия указаны источники; пересекающиеся по времени занятия отмечены в overlaps.
+// @Tags         timetable
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Failure      401  {object}  map[string]string
+// @Param        from     query     string  false  "Первый день, YYYY-MM-DD (по умолчанию сегодня)"
+// @Param        to       query     string  false  "Последний день включительно, YYYY-MM-DD (по умолчанию неделя от from)"
+// @Success      200      {object}  models.Timetable
+// @Failure      400      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /me/timetable [get]
+func (h *TimetableHandler) GetTimetable(c *gin.Context) {
+	userID := currentUser(c)
+
+	from, to, ok := dateRange(c, defaultTimetableDays)
+	if !ok {
+		return
+	}
+
+	timetable, err := h.timetableService.GetTimetable(c.Request.Context(), userID, from, to)
+	if errors.Is(err, service.ErrInvalidRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		requestLog(c, h.logger).WithError(err).Error("Failed to build timetable")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build timetable"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timetable)
+}
+
+// dateRange разбирает from и to (YYYY-MM-DD, по Минску). Без from — сегодня, без to — days дней от from.
+// При ошибке ответ 400 уже отправлен.
+func dateRange(c *gin.Context, days int) (from, to time.Time, ok bool) {
+	now := time.Now().In(converter.Minsk)
+	from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, converter.Minsk)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, value, converter.Minsk)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date in YYYY-MM-DD format"})
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	to = from.AddDate(0, 0, days-1)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, value, converter.Minsk)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	return from, to, true
+}
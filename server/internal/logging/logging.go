@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// requestLog — логгер одного запроса. Поля, добавленные слоями ниже (кэш, upstream),
+// попадают и в их собственные записи, и в итоговую запись access-лога.
+type requestLog struct {
+	mu    sync.Mutex
+	entry *logrus.Entry
+}
+
+// WithLogger кладет в контекст логгер запроса
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestLog{entry: entry})
+}
+
+// FromContext возвращает логгер запроса, а вне запроса — базовый логгер
+func FromContext(ctx context.Context, fallback *logrus.Logger) *logrus.Entry {
+	if log, ok := ctx.Value(contextKey{}).(*requestLog); ok {
+		log.mu.Lock()
+		defer log.mu.Unlock()
+		return log.entry
+	}
+	return logrus.NewEntry(fallback)
+}
+
+// AddFields дополняет логгер запроса полями, которые нужны в access-логе
+func AddFields(ctx context.Context, fields logrus.Fields) {
+	if log, ok := ctx.Value(contextKey{}).(*requestLog); ok {
+		log.mu.Lock()
+		log.entry = log.entry.WithFields(fields)
+		log.mu.Unlock()
+	}
+}
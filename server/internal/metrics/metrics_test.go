@@ -0,0 +1,96 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"schedluer/internal/config"
+	"schedluer/internal/metrics"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/bsuir/bsuirtest"
+)
+
+// TestMetricsScrape — /metrics после обычного запроса, запроса с упавшим API БГУИРа и запроса мимо маршрутов
+func TestMetricsScrape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := bsuirtest.NewServer(bsuirtest.DefaultFixtures())
+	defer fake.Close()
+	fake.FailNext("/schedule", http.StatusServiceUnavailable, 1)
+	client := bsuir.NewClient(&config.BSUIRAPIConfig{BaseURL: fake.BaseURL(), Timeout: 5 * time.Second})
+
+	router := gin.New()
+	router.Use(metrics.Middleware())
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/api/v1/schedule/groups/:groupNumber", func(c *gin.Context) {
+		if _, err := client.GetGroupSchedule(c.Request.Context(), c.Param("groupNumber")); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to get schedule"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+	// Первый запрос получает ошибку API, второй — расписание
+	for _, request := range []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/schedule/groups/221701", http.StatusBadGateway},
+		{"/api/v1/schedule/groups/221701", http.StatusOK},
+		{"/api/v1/unknown/221701", http.StatusNotFound},
+	} {
+		if code := serve(request.path).Code; code != request.want {
+			t.Fatalf("GET %s = %d, want %d", request.path, code, request.want)
+		}
+	}
+
+	response := serve("/metrics")
+	if response.Code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d", response.Code)
+	}
+	body, _ := io.ReadAll(response.Body)
+	scrape := string(body)
+
+	const route = `route="/api/v1/schedule/groups/:groupNumber"`
+	for _, want := range []string{
+		"# TYPE schedluer_http_requests_total counter",
+		"# TYPE schedluer_http_request_duration_seconds histogram",
+		"# TYPE schedluer_bsuir_requests_total counter",
+		"# TYPE schedluer_bsuir_request_duration_seconds histogram",
+
+		// Метки — шаблон маршрута и итоговый статус
+		`schedluer_http_requests_total{method="GET",` + route + `,status="502"} 1`,
+		`schedluer_http_requests_total{method="GET",` + route + `,status="200"} 1`,
+		`schedluer_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`schedluer_http_request_duration_seconds_bucket{method="GET",` + route + `,le="+Inf"} 2`,
+		`schedluer_http_request_duration_seconds_count{method="GET",` + route + `} 2`,
+
+		// Ошибка API считается отдельно от успешного ответа, латентность — по методу клиента
+		`schedluer_bsuir_requests_total{code="503",method="GetGroupSchedule"} 1`,
+		`schedluer_bsuir_requests_total{code="200",method="GetGroupSchedule"} 1`,
+		`schedluer_bsuir_request_duration_seconds_bucket{method="GetGroupSchedule",le="+Inf"} 2`,
+		`schedluer_bsuir_request_duration_seconds_count{method="GetGroupSchedule"} 2`,
+	} {
+		if !strings.Contains(scrape, want) {
+			t.Errorf("scrape has no %s", want)
+		}
+	}
+
+	// Фактические пути с номером группы не становятся метками
+	for _, line := range strings.Split(scrape, "\n") {
+		if strings.HasPrefix(line, "schedluer_") && strings.Contains(line, "221701") {
+			t.Errorf("raw path in a label: %s", line)
+		}
+	}
+}
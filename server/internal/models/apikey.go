@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RoleAdmin = "admin"
+	// RoleUser — ключ пользователя: дает доступ к его личным данным (/me/...), но не к администрированию
+	RoleUser = "user"
+)
+
+type APIKey struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name    string             `bson:"name" json:"name"`
+	Prefix  string             `bson:"prefix" json:"prefix"`
+	KeyHash string             `bson:"key_hash" json:"-"`
+	Role    string             `bson:"role" json:"role"`
+	// UserID — пользователь, от имени которого действует ключ роли user; у ключей admin пусто
+	UserID    string     `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event — личное событие пользователя: консультация, подготовка к экзамену, кружок.
+// Попадает в ленту /me/timetable рядом с занятиями из расписания.
+type Event struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      string             `bson:"user_id" json:"user_id"`
+	Title       string             `bson:"title" json:"title"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Location    string             `bson:"location,omitempty" json:"location,omitempty"`
+	// Start и End — первое повторение; остальные повторения той же длительности
+	Start time.Time `bson:"start" json:"start"`
+	End   time.Time `bson:"end" json:"end"`
+	// Recurrence — правило повторения RRULE (FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20261225); пусто — разовое событие
+	Recurrence string `bson:"recurrence,omitempty" json:"recurrence,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// EventRequest — создание или полная замена личного события
+type EventRequest struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Recurrence  string    `json:"recurrence,omitempty"`
+}
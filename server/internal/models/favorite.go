@@ -6,6 +6,109 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	FavoriteTypeGroup    = "group"
+	FavoriteTypeEmployee = "employee"
+	FavoriteTypeAuditory = "auditory"
+)
+
+// FavoriteTypes — допустимые значения Favorite.Type
+var FavoriteTypes = []string{FavoriteTypeGroup, FavoriteTypeEmployee, FavoriteTypeAuditory}
+
+func IsFavoriteType(favoriteType string) bool {
+	for _, t := range FavoriteTypes {
+		if t == favoriteType {
+			return true
+		}
+	}
+	return false
+}
+
+// Favorite — элемент избранного пользователя. Пара (Type, Key) уникальна для пользователя.
+type Favorite struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"user_id"`
+	Type   string             `bson:"type" json:"type"`
+	// Key — номер группы, URL ID преподавателя или аудитория с корпусом, как в расписании ("505-5 к.")
+	Key string `bson:"key" json:"key"`
+
+	// CollectionID — hex ID коллекции пользователя; пусто — элемент вне коллекций
+	CollectionID string `bson:"collection_id,omitempty" json:"collection_id,omitempty"`
+	Label        string `bson:"label,omitempty" json:"label,omitempty"`
+	// Color — цвет метки в формате #RRGGBB
+	Color  string `bson:"color,omitempty" json:"color,omitempty"`
+	Pinned bool   `bson:"pinned,omitempty" json:"pinned,omitempty"`
+	// Position задает порядок внутри коллекции. Новые элементы получают время добавления в мс
+	// и встают в конец; после перестановки позиции — 0, 1, 2...
+	Position int64 `bson:"position" json:"position"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// FavoriteCollection — именованная группа избранного пользователя ("мои группы", "нагрузка")
+type FavoriteCollection struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	Name      string             `bson:"name" json:"name"`
+	Color     string             `bson:"color,omitempty" json:"color,omitempty"`
+	Position  int64              `bson:"position" json:"position"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// FavoriteDetails — изменение оформления элемента избранного; nil-поля остаются как были.
+// Пустой CollectionID убирает элемент из коллекции, пустые Label и Color — подпись и цвет.
+type FavoriteDetails struct {
+	CollectionID *string `json:"collection_id,omitempty"`
+	Label        *string `json:"label,omitempty"`
+	Color        *string `json:"color,omitempty"`
+	Pinned       *bool   `json:"pinned,omitempty"`
+}
+
+// FavoriteCollectionDetails — создание или изменение коллекции; при изменении nil-поля остаются как были
+type FavoriteCollectionDetails struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+// FavoriteOrder — новый порядок элементов коллекции ("" — вне коллекций) или самих коллекций
+type FavoriteOrder struct {
+	CollectionID string   `json:"collection_id,omitempty"`
+	IDs          []string `json:"ids"`
+}
+
+// FavoriteLess — порядок выдачи избранного: закрепленные раньше, затем по позиции и времени добавления
+func FavoriteLess(a, b Favorite) bool {
+	if a.Pinned != b.Pinned {
+		return a.Pinned
+	}
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.Key < b.Key
+}
+
+// FavoriteItem — элемент избранного с данными для отображения из закэшированных списков.
+// Если группы или преподавателя нет в кэше, соответствующее поле пустое.
+type FavoriteItem struct {
+	Favorite
+
+	// Title — подпись для списка: номер группы, ФИО преподавателя или аудитория
+	Title    string                `json:"title"`
+	Group    *StudentGroupListItem `json:"group,omitempty"`
+	Employee *EmployeeListItem     `json:"employee,omitempty"`
+	Auditory *Auditory             `json:"auditory,omitempty"`
+}
+
+// FavoriteGroup — избранное до появления типов: только группы.
+// В этом виде данные лежат в старой коллекции favorite_groups и отдаются эндпоинтами /favorites/:groupNumber.
 type FavoriteGroup struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	GroupNumber string             `bson:"group_number" json:"group_number"`
@@ -13,3 +116,22 @@ type FavoriteGroup struct {
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
 }
+
+// FavoriteGroupFrom приводит избранную группу к старому формату ответа
+func FavoriteGroupFrom(favorite Favorite) FavoriteGroup {
+	return FavoriteGroup{
+		ID:          favorite.ID,
+		GroupNumber: favorite.Key,
+		UserID:      favorite.UserID,
+		CreatedAt:   favorite.CreatedAt,
+		UpdatedAt:   favorite.UpdatedAt,
+	}
+}
+
+// AuditoryKey — ключ избранной аудитории в том виде, в каком аудитории записаны в занятиях
+func AuditoryKey(auditory Auditory) string {
+	if auditory.BuildingNumber.Name == "" {
+		return auditory.Name
+	}
+	return auditory.Name + "-" + auditory.BuildingNumber.Name
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	NoteKindNote     = "note"
+	NoteKindHomework = "homework"
+)
+
+// LessonRef — конкретное занятие группы в конкретную дату, как его видно в расписании:
+// Subject, LessonType и Start совпадают с Schedule.Subject, LessonTypeAbbrev и StartLessonTime
+type LessonRef struct {
+	GroupNumber string `bson:"group_number" json:"group_number"`
+	Subject     string `bson:"subject" json:"subject"`
+	LessonType  string `bson:"lesson_type" json:"lesson_type"`
+	// Date — дата занятия, YYYY-MM-DD
+	Date string `bson:"date" json:"date"`
+	// Start — время начала, HH:MM
+	Start string `bson:"start" json:"start"`
+}
+
+// Attachment — описание вложения; сами файлы сервис не хранит, только ссылку на них
+type Attachment struct {
+	Name        string `bson:"name" json:"name"`
+	URL         string `bson:"url" json:"url"`
+	ContentType string `bson:"content_type,omitempty" json:"content_type,omitempty"`
+	Size        int64  `bson:"size,omitempty" json:"size,omitempty"`
+}
+
+// LessonNote — заметка или домашнее задание пользователя к занятию. У домашнего задания
+// есть срок сдачи и отметка о выполнении, у заметки их нет.
+type LessonNote struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      string             `bson:"user_id" json:"user_id"`
+	Kind        string             `bson:"kind" json:"kind"`
+	Lesson      LessonRef          `bson:"lesson" json:"lesson"`
+	Text        string             `bson:"text" json:"text"`
+	Attachments []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
+
+	DueAt  *time.Time `bson:"due_at,omitempty" json:"due_at,omitempty"`
+	Done   bool       `bson:"done" json:"done"`
+	DoneAt *time.Time `bson:"done_at,omitempty" json:"done_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// NoteRequest — создание заметки или домашнего задания
+type NoteRequest struct {
+	Kind        string       `json:"kind"`
+	Lesson      LessonRef    `json:"lesson"`
+	Text        string       `json:"text"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+	DueAt       *time.Time   `json:"due_at,omitempty"`
+}
+
+// NoteDetails — изменение заметки; nil-поля остаются как были, Attachments заменяют список целиком
+type NoteDetails struct {
+	Text        *string       `json:"text,omitempty"`
+	Attachments *[]Attachment `json:"attachments,omitempty"`
+	DueAt       *time.Time    `json:"due_at,omitempty"`
+	Done        *bool         `json:"done,omitempty"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LessonSeries — повторяющееся занятие в расписании группы или преподавателя. Subject обязателен,
+// пустые LessonType, Weekday и Start и нулевой Subgroup подходят к любому значению — так одной записью
+// скрывается весь факультатив.
+type LessonSeries struct {
+	Subject    string `bson:"subject" json:"subject"`
+	LessonType string `bson:"lesson_type,omitempty" json:"lesson_type,omitempty"`
+	// Weekday — день недели, как в ключах ScheduleResponse.Schedules («Понедельник»)
+	Weekday string `bson:"weekday,omitempty" json:"weekday,omitempty"`
+	// Start — время начала, HH:MM
+	Start    string `bson:"start,omitempty" json:"start,omitempty"`
+	Subgroup int    `bson:"subgroup,omitempty" json:"subgroup,omitempty"`
+}
+
+// LessonOverride — личная правка пользователя к серии занятий: скрыть ее или показать в другой
+// аудитории и в другое время. Применяется к расписанию только по запросу с personalized=true.
+type LessonOverride struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"user_id"`
+	// ScheduleType и Key — чье расписание правится: group и номер группы или employee и urlId
+	ScheduleType string       `bson:"schedule_type" json:"schedule_type"`
+	Key          string       `bson:"key" json:"key"`
+	Series       LessonSeries `bson:"series" json:"series"`
+
+	Hidden     bool     `bson:"hidden" json:"hidden"`
+	Auditories []string `bson:"auditories,omitempty" json:"auditories,omitempty"`
+	StartTime  string   `bson:"start_time,omitempty" json:"start_time,omitempty"`
+	EndTime    string   `bson:"end_time,omitempty" json:"end_time,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// OverrideRequest — создание или замена правки. Либо hidden, либо хотя бы одно из auditories,
+// start_time и end_time; пустые поля оставляют значение из расписания.
+type OverrideRequest struct {
+	ScheduleType string       `json:"schedule_type"`
+	Key          string       `json:"key"`
+	Series       LessonSeries `json:"series"`
+	Hidden       bool         `json:"hidden"`
+	Auditories   []string     `json:"auditories,omitempty"`
+	StartTime    string       `json:"start_time,omitempty"`
+	EndTime      string       `json:"end_time,omitempty"`
+}
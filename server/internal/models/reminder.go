@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReminderChannelWebhook  = "webhook"
+	ReminderChannelEmail    = "email"
+	ReminderChannelTelegram = "telegram"
+)
+
+// QuietHours — время по Минску, когда напоминания не отправляются; From позже To — интервал через полночь
+type QuietHours struct {
+	// From и To — HH:MM
+	From string `bson:"from" json:"from"`
+	To   string `bson:"to" json:"to"`
+}
+
+// ReminderSubscription — подписка пользователя на напоминания о занятиях из его ленты (/me/timetable).
+// У пользователя одна подписка.
+type ReminderSubscription struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"user_id"`
+	// Channel — webhook, email или telegram; Target — URL, адрес почты или chat_id соответственно
+	Channel string `bson:"channel" json:"channel"`
+	Target  string `bson:"target" json:"target"`
+	// LeadMinutes — за сколько минут до начала занятия напомнить
+	LeadMinutes int         `bson:"lead_minutes" json:"lead_minutes"`
+	QuietHours  *QuietHours `bson:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// ReminderRequest — создание или замена подписки
+type ReminderRequest struct {
+	Channel     string      `json:"channel"`
+	Target      string      `json:"target"`
+	LeadMinutes int         `json:"lead_minutes"`
+	QuietHours  *QuietHours `json:"quiet_hours,omitempty"`
+}
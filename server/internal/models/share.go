@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// ShareKindFavorites — ссылка на список избранного
+	ShareKindFavorites = "favorites"
+	// ShareKindTimetable — ссылка на ленту занятий избранного
+	ShareKindTimetable = "timetable"
+)
+
+// Share — публичная ссылка на избранное или ленту пользователя. Ссылка живая: по ней видно
+// текущее избранное владельца (или одной его коллекции), а не снимок на момент создания.
+type Share struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"user_id"`
+	Kind   string             `bson:"kind" json:"kind"`
+	Title  string             `bson:"title,omitempty" json:"title,omitempty"`
+	// CollectionID — hex ID коллекции избранного; пусто — все избранное
+	CollectionID string `bson:"collection_id,omitempty" json:"collection_id,omitempty"`
+	// Prefix — начало токена, чтобы владелец узнал ссылку в списке; сам токен хранится только хэшем
+	Prefix    string     `bson:"prefix" json:"prefix"`
+	TokenHash string     `bson:"token_hash" json:"-"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// IsActive — ссылка не отозвана и не истекла к моменту now
+func (s *Share) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || now.Before(*s.ExpiresAt))
+}
+
+// ShareRequest — параметры новой ссылки. ExpiresAt не задан — ссылка бессрочная.
+type ShareRequest struct {
+	Kind         string     `json:"kind"`
+	Title        string     `json:"title,omitempty"`
+	CollectionID string     `json:"collection_id,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreatedShare — ответ на создание ссылки; Token показывается только здесь
+type CreatedShare struct {
+	Share
+	Token string `json:"token"`
+}
+
+// SharedView — то, что видит получатель ссылки. Заполнено Favorites или Timetable в зависимости от Kind.
+type SharedView struct {
+	Kind      string         `json:"kind"`
+	Title     string         `json:"title,omitempty"`
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
+	Favorites []FavoriteItem `json:"favorites,omitempty"`
+	Timetable *Timetable     `json:"timetable,omitempty"`
+}
@@ -8,7 +8,7 @@ import (
 
 type StoredSchedule struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	GroupNumber    string             `bson:"group_number" json:"group_number"`
+	GroupNumber    string             `bson:"group_number,omitempty" json:"group_number"`
 	EmployeeURLID  string             `bson:"employee_url_id,omitempty" json:"employee_url_id,omitempty"`
 	ScheduleData   ScheduleResponse   `bson:"schedule_data" json:"schedule_data"`
 	LastUpdateDate string             `bson:"last_update_date" json:"last_update_date"`
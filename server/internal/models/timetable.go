@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// TimetableSourceEvent — тип источника для личных событий пользователя, Key — ID события
+const TimetableSourceEvent = "event"
+
+// Timetable — занятия всех избранных групп и преподавателей пользователя по датам вместе с его личными событиями
+type Timetable struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Entries []TimetableEntry `json:"entries"`
+	// Unavailable — избранное, расписание которого получить не удалось; остальные занятия все равно отдаются
+	Unavailable []TimetableSource `json:"unavailable,omitempty"`
+}
+
+// TimetableEntry — занятие или личное событие в конкретную дату. Одно и то же занятие из расписаний
+// нескольких групп или преподавателя попадает в ленту один раз, с несколькими источниками.
+// Заполнено ровно одно из Lesson и Event.
+type TimetableEntry struct {
+	Date    string            `json:"date"`
+	Weekday string            `json:"weekday"`
+	Start   time.Time         `json:"start"`
+	End     time.Time         `json:"end"`
+	Exam    bool              `json:"exam,omitempty"`
+	Lesson  *Schedule         `json:"lesson,omitempty"`
+	Event   *Event            `json:"event,omitempty"`
+	Sources []TimetableSource `json:"sources"`
+	// Overlaps — индексы других занятий в Entries, пересекающихся с этим по времени
+	Overlaps []int `json:"overlaps,omitempty"`
+}
+
+// TimetableSource — избранное, из расписания которого взято занятие, или личное событие
+type TimetableSource struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+}
@@ -0,0 +1,101 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// apiKeyRecord хранит и хэш ключа, который в models.APIKey скрыт от JSON
+type apiKeyRecord struct {
+	models.APIKey
+	KeyHash string `json:"key_hash"`
+}
+
+func (r *apiKeyRecord) key() *models.APIKey {
+	key := r.APIKey
+	key.KeyHash = r.KeyHash
+	return &key
+}
+
+type apiKeyRepository struct {
+	db *bbolt.DB
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (key *models.APIKey, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		prefix := tx.Bucket(bucketAPIKeysByHash).Get([]byte(keyHash))
+		if prefix == nil {
+			return nil
+		}
+		record, err := get[apiKeyRecord](tx.Bucket(bucketAPIKeys), prefix)
+		if err != nil || record == nil {
+			return err
+		}
+		key = record.key()
+		return nil
+	})
+	return key, err
+}
+
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketAPIKeys).ForEach(func(_, data []byte) error {
+			var record apiKeyRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			keys = append(keys, *record.key())
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		keys := tx.Bucket(bucketAPIKeys)
+		byHash := tx.Bucket(bucketAPIKeysByHash)
+		if keys.Get([]byte(key.Prefix)) != nil || byHash.Get([]byte(key.KeyHash)) != nil {
+			return repository.ErrDuplicate
+		}
+
+		if key.ID.IsZero() {
+			key.ID = primitive.NewObjectID()
+		}
+		if err := put(keys, []byte(key.Prefix), apiKeyRecord{APIKey: *key, KeyHash: key.KeyHash}); err != nil {
+			return err
+		}
+		return byHash.Put([]byte(key.KeyHash), []byte(key.Prefix))
+	})
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, prefix string, revokedAt time.Time) (revoked bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		keys := tx.Bucket(bucketAPIKeys)
+		record, err := get[apiKeyRecord](keys, []byte(prefix))
+		if err != nil || record == nil || record.RevokedAt != nil {
+			return err
+		}
+
+		record.RevokedAt = &revokedAt
+		revoked = true
+		return put(keys, []byte(prefix), record)
+	})
+	return revoked, err
+}
@@ -0,0 +1,176 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type employeeRepository struct {
+	db *bbolt.DB
+}
+
+func (r *employeeRepository) GetByURLID(ctx context.Context, urlID string) (employee *models.StoredEmployee, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(bucketEmployeesByURLID).Get([]byte(urlID))
+		if id == nil {
+			return nil
+		}
+		employee, err = get[models.StoredEmployee](tx.Bucket(bucketEmployees), id)
+		return err
+	})
+	return employee, err
+}
+
+// GetByURLIDs читает все записи в одной транзакции
+func (r *employeeRepository) GetByURLIDs(ctx context.Context, urlIDs []string) (employees []models.StoredEmployee, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		for _, key := range urlIDs {
+			id := tx.Bucket(bucketEmployeesByURLID).Get([]byte(key))
+			if id == nil {
+				continue
+			}
+			employee, err := get[models.StoredEmployee](tx.Bucket(bucketEmployees), id)
+			if err != nil {
+				return err
+			}
+			if employee != nil {
+				employees = append(employees, *employee)
+			}
+		}
+		return nil
+	})
+	return employees, err
+}
+
+func (r *employeeRepository) GetByID(ctx context.Context, id int) (employee *models.StoredEmployee, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		employee, err = get[models.StoredEmployee](tx.Bucket(bucketEmployees), intKey(id))
+		return err
+	})
+	return employee, err
+}
+
+func (r *employeeRepository) GetAll(ctx context.Context) (employees []models.StoredEmployee, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketEmployees).ForEach(func(_, data []byte) error {
+			var employee models.StoredEmployee
+			if err := json.Unmarshal(data, &employee); err != nil {
+				// Пропускаем записи с ошибками декодирования, как и репозиторий MongoDB
+				return nil
+			}
+			employees = append(employees, employee)
+			return nil
+		})
+	})
+	return employees, err
+}
+
+func (r *employeeRepository) Count(ctx context.Context) (count int64, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		count = int64(tx.Bucket(bucketEmployees).Stats().KeyN)
+		return nil
+	})
+	return count, err
+}
+
+func (r *employeeRepository) Save(ctx context.Context, employee *models.StoredEmployee) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return insertEmployee(tx, employee)
+	})
+}
+
+// SaveMany, как неупорядоченная вставка в MongoDB, сохраняет всех преподавателей без конфликтов
+// и возвращает ErrDuplicate, если конфликты были
+func (r *employeeRepository) SaveMany(ctx context.Context, employees []models.StoredEmployee) error {
+	var duplicate error
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		for i := range employees {
+			err := insertEmployee(tx, &employees[i])
+			if errors.Is(err, repository.ErrDuplicate) {
+				duplicate = err
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return duplicate
+}
+
+// Update создает или заменяет преподавателя с тем же BSUIRID, сохраняя исходные ID и CreatedAt.
+// URL ID должен оставаться уникальным и среди остальных преподавателей.
+func (r *employeeRepository) Update(ctx context.Context, employee *models.StoredEmployee) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		key := intKey(employee.BSUIRID)
+		if owner := tx.Bucket(bucketEmployeesByURLID).Get([]byte(employee.URLID)); owner != nil && !bytes.Equal(owner, key) {
+			return repository.ErrDuplicate
+		}
+
+		existing, err := get[models.StoredEmployee](tx.Bucket(bucketEmployees), key)
+		if err != nil {
+			return err
+		}
+
+		stored := *employee
+		if existing != nil {
+			stored.ID = existing.ID
+			stored.CreatedAt = existing.CreatedAt
+			if err := tx.Bucket(bucketEmployeesByURLID).Delete([]byte(existing.URLID)); err != nil {
+				return err
+			}
+		} else {
+			stored.ID = primitive.NewObjectID()
+		}
+		return putEmployee(tx, &stored)
+	})
+}
+
+func (r *employeeRepository) Delete(ctx context.Context, id int) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := get[models.StoredEmployee](tx.Bucket(bucketEmployees), intKey(id))
+		if err != nil || existing == nil {
+			return err
+		}
+		if err := tx.Bucket(bucketEmployeesByURLID).Delete([]byte(existing.URLID)); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketEmployees).Delete(intKey(id))
+	})
+}
+
+func insertEmployee(tx *bbolt.Tx, employee *models.StoredEmployee) error {
+	if tx.Bucket(bucketEmployees).Get(intKey(employee.BSUIRID)) != nil {
+		return repository.ErrDuplicate
+	}
+	if employee.URLID != "" && tx.Bucket(bucketEmployeesByURLID).Get([]byte(employee.URLID)) != nil {
+		return repository.ErrDuplicate
+	}
+	if employee.ID.IsZero() {
+		employee.ID = primitive.NewObjectID()
+	}
+	return putEmployee(tx, employee)
+}
+
+func putEmployee(tx *bbolt.Tx, employee *models.StoredEmployee) error {
+	key := intKey(employee.BSUIRID)
+	if err := put(tx.Bucket(bucketEmployees), key, employee); err != nil {
+		return err
+	}
+	if employee.URLID == "" {
+		return nil
+	}
+	return tx.Bucket(bucketEmployeesByURLID).Put([]byte(employee.URLID), key)
+}
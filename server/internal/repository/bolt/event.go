@@ -0,0 +1,101 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type eventRepository struct {
+	db *bbolt.DB
+}
+
+// eventKey — user_id и hex ID: события пользователя лежат одним диапазоном
+func eventKey(userID string, id primitive.ObjectID) []byte {
+	return []byte(userID + "\x00" + id.Hex())
+}
+
+func (r *eventRepository) GetByUser(ctx context.Context, userID string) ([]models.Event, error) {
+	events := []models.Event{}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		prefix := []byte(userID + "\x00")
+		cursor := tx.Bucket(bucketEvents).Cursor()
+		for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+			var event models.Event
+			if err := json.Unmarshal(data, &event); err != nil {
+				return fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ключи упорядочены по ID, а события отдаются по времени начала
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events, nil
+}
+
+func (r *eventRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (event *models.Event, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		event, err = get[models.Event](tx.Bucket(bucketEvents), eventKey(userID, id))
+		return err
+	})
+	return event, err
+}
+
+func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		events := tx.Bucket(bucketEvents)
+		if event.ID.IsZero() {
+			event.ID = primitive.NewObjectID()
+		}
+		key := eventKey(event.UserID, event.ID)
+		if events.Get(key) != nil {
+			return repository.ErrDuplicate
+		}
+		return put(events, key, event)
+	})
+}
+
+func (r *eventRepository) Update(ctx context.Context, event *models.Event) (updated bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		events := tx.Bucket(bucketEvents)
+		key := eventKey(event.UserID, event.ID)
+		stored, err := get[models.Event](events, key)
+		if err != nil || stored == nil {
+			return err
+		}
+
+		replaced := *event
+		replaced.CreatedAt = stored.CreatedAt
+		updated = true
+		return put(events, key, replaced)
+	})
+	return updated, err
+}
+
+func (r *eventRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (deleted bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		events := tx.Bucket(bucketEvents)
+		key := eventKey(userID, id)
+		if events.Get(key) == nil {
+			return nil
+		}
+		deleted = true
+		return events.Delete(key)
+	})
+	return deleted, err
+}
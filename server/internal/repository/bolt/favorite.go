@@ -0,0 +1,377 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// searchLimit совпадает с $limit в поиске MongoDB
+const searchLimit = 100
+
+type favoriteRepository struct {
+	db *bbolt.DB
+}
+
+// favoriteKey — user_id, тип и ключ через нулевой байт: уникальность тройки
+// обеспечивается самим ключом, а избранное пользователя (и отдельного типа) лежит одним диапазоном
+func favoriteKey(userID, favoriteType, key string) []byte {
+	return []byte(userID + "\x00" + favoriteType + "\x00" + key)
+}
+
+// favoritePrefix — диапазон избранного пользователя; с типом — только этого типа
+func favoritePrefix(userID, favoriteType string) []byte {
+	if favoriteType == "" {
+		return []byte(userID + "\x00")
+	}
+	return favoriteKey(userID, favoriteType, "")
+}
+
+func (r *favoriteRepository) GetAll(ctx context.Context, userID string, favoriteType string) ([]models.Favorite, error) {
+	return r.find(favoritePrefix(userID, favoriteType), func(models.Favorite) bool { return true }, 0)
+}
+
+func (r *favoriteRepository) Get(ctx context.Context, userID string, favoriteType string, key string) (favorite *models.Favorite, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		favorite, err = get[models.Favorite](tx.Bucket(bucketFavorites), favoriteKey(userID, favoriteType, key))
+		return err
+	})
+	return favorite, err
+}
+
+// Search ищет без учета регистра по подстроке ключа, как regex-поиск MongoDB без поискового индекса
+func (r *favoriteRepository) Search(ctx context.Context, userID string, favoriteType string, query string) ([]models.Favorite, error) {
+	query = strings.ToLower(query)
+	return r.find(favoritePrefix(userID, favoriteType), func(f models.Favorite) bool {
+		return strings.Contains(strings.ToLower(f.Key), query)
+	}, searchLimit)
+}
+
+// Add добавляет элемент в избранное; повторное добавление обновляет UpdatedAt, сохраняя CreatedAt
+func (r *favoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketFavorites)
+		key := favoriteKey(favorite.UserID, favorite.Type, favorite.Key)
+
+		existing, err := get[models.Favorite](bucket, key)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			existing.UpdatedAt = favorite.UpdatedAt
+			return put(bucket, key, existing)
+		}
+		stored := *favorite
+		if stored.ID.IsZero() {
+			stored.ID = primitive.NewObjectID()
+		}
+		return put(bucket, key, &stored)
+	})
+}
+
+func (r *favoriteRepository) UpdateDetails(ctx context.Context, favorite *models.Favorite) (found bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketFavorites)
+		key := favoriteKey(favorite.UserID, favorite.Type, favorite.Key)
+
+		existing, err := get[models.Favorite](bucket, key)
+		if err != nil || existing == nil {
+			return err
+		}
+		found = true
+		existing.CollectionID = favorite.CollectionID
+		existing.Label = favorite.Label
+		existing.Color = favorite.Color
+		existing.Pinned = favorite.Pinned
+		existing.Position = favorite.Position
+		existing.UpdatedAt = favorite.UpdatedAt
+		return put(bucket, key, existing)
+	})
+	return found, err
+}
+
+func (r *favoriteRepository) Delete(ctx context.Context, userID string, favoriteType string, key string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketFavorites).Delete(favoriteKey(userID, favoriteType, key))
+	})
+}
+
+func (r *favoriteRepository) IsFavorite(ctx context.Context, userID string, favoriteType string, key string) (found bool, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		found = tx.Bucket(bucketFavorites).Get(favoriteKey(userID, favoriteType, key)) != nil
+		return nil
+	})
+	return found, err
+}
+
+// find возвращает избранное из диапазона prefix в порядке models.FavoriteLess; limit 0 — без ограничения
+func (r *favoriteRepository) find(prefix []byte, match func(models.Favorite) bool, limit int) ([]models.Favorite, error) {
+	favorites := []models.Favorite{}
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(bucketFavorites).Cursor()
+		for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+			var favorite models.Favorite
+			if err := json.Unmarshal(data, &favorite); err != nil {
+				continue
+			}
+			if match(favorite) {
+				favorites = append(favorites, favorite)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(favorites, func(i, j int) bool {
+		return models.FavoriteLess(favorites[i], favorites[j])
+	})
+
+	if limit > 0 && len(favorites) > limit {
+		favorites = favorites[:limit]
+	}
+	return favorites, nil
+}
+
+// Reorder читает и переписывает позиции в одной транзакции записи
+func (r *favoriteRepository) Reorder(ctx context.Context, userID string, collectionID string, ids []primitive.ObjectID) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketFavorites)
+		prefix := favoritePrefix(userID, "")
+
+		keys := make(map[primitive.ObjectID][]byte)
+		byID := make(map[primitive.ObjectID]*models.Favorite)
+		var stored []primitive.ObjectID
+		cursor := bucket.Cursor()
+		for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+			var favorite models.Favorite
+			if err := json.Unmarshal(data, &favorite); err != nil {
+				return fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+			if favorite.CollectionID == collectionID {
+				keys[favorite.ID] = bytes.Clone(key)
+				byID[favorite.ID] = &favorite
+				stored = append(stored, favorite.ID)
+			}
+		}
+		if !repository.SameIDs(stored, ids) {
+			return repository.ErrReorderMismatch
+		}
+
+		for position, id := range ids {
+			byID[id].Position = int64(position)
+			if err := put(bucket, keys[id], byID[id]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// collectionKey — user_id и hex ID коллекции: коллекции пользователя лежат одним диапазоном
+func collectionKey(userID string, id primitive.ObjectID) []byte {
+	return []byte(userID + "\x00" + id.Hex())
+}
+
+func (r *favoriteRepository) GetCollections(ctx context.Context, userID string) (collections []models.FavoriteCollection, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		collections, err = userCollections(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(collections, func(i, j int) bool {
+		if collections[i].Position != collections[j].Position {
+			return collections[i].Position < collections[j].Position
+		}
+		return collections[i].Name < collections[j].Name
+	})
+	return collections, nil
+}
+
+func (r *favoriteRepository) GetCollection(ctx context.Context, userID string, id primitive.ObjectID) (collection *models.FavoriteCollection, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		collection, err = get[models.FavoriteCollection](tx.Bucket(bucketFavoriteCollections), collectionKey(userID, id))
+		return err
+	})
+	return collection, err
+}
+
+func (r *favoriteRepository) CreateCollection(ctx context.Context, collection *models.FavoriteCollection) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := userCollections(tx, collection.UserID)
+		if err != nil {
+			return err
+		}
+		if hasCollectionName(existing, collection.Name, primitive.NilObjectID) {
+			return fmt.Errorf("%w: collection %q", repository.ErrDuplicate, collection.Name)
+		}
+
+		if collection.ID.IsZero() {
+			collection.ID = primitive.NewObjectID()
+		}
+		return put(tx.Bucket(bucketFavoriteCollections), collectionKey(collection.UserID, collection.ID), collection)
+	})
+}
+
+func (r *favoriteRepository) UpdateCollection(ctx context.Context, collection *models.FavoriteCollection) (found bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketFavoriteCollections)
+		key := collectionKey(collection.UserID, collection.ID)
+
+		stored, err := get[models.FavoriteCollection](bucket, key)
+		if err != nil || stored == nil {
+			return err
+		}
+		found = true
+
+		existing, err := userCollections(tx, collection.UserID)
+		if err != nil {
+			return err
+		}
+		if hasCollectionName(existing, collection.Name, collection.ID) {
+			return fmt.Errorf("%w: collection %q", repository.ErrDuplicate, collection.Name)
+		}
+
+		stored.Name = collection.Name
+		stored.Color = collection.Color
+		stored.UpdatedAt = collection.UpdatedAt
+		return put(bucket, key, stored)
+	})
+	return found, err
+}
+
+// DeleteCollection удаляет коллекцию и отвязывает ее элементы в одной транзакции
+func (r *favoriteRepository) DeleteCollection(ctx context.Context, userID string, id primitive.ObjectID) (found bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		collections := tx.Bucket(bucketFavoriteCollections)
+		key := collectionKey(userID, id)
+		if collections.Get(key) == nil {
+			return nil
+		}
+		found = true
+		if err := collections.Delete(key); err != nil {
+			return err
+		}
+
+		favorites := tx.Bucket(bucketFavorites)
+		prefix := favoritePrefix(userID, "")
+		updated := make(map[string]*models.Favorite)
+		cursor := favorites.Cursor()
+		for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+			var favorite models.Favorite
+			if err := json.Unmarshal(data, &favorite); err != nil {
+				continue
+			}
+			if favorite.CollectionID == id.Hex() {
+				favorite.CollectionID = ""
+				updated[string(key)] = &favorite
+			}
+		}
+		// Пишем после обхода: изменение бакета под курсором сбивает итерацию
+		for key, favorite := range updated {
+			if err := put(favorites, []byte(key), favorite); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+func (r *favoriteRepository) ReorderCollections(ctx context.Context, userID string, ids []primitive.ObjectID) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		collections, err := userCollections(tx, userID)
+		if err != nil {
+			return err
+		}
+		stored := make([]primitive.ObjectID, len(collections))
+		byID := make(map[primitive.ObjectID]*models.FavoriteCollection, len(collections))
+		for i := range collections {
+			stored[i] = collections[i].ID
+			byID[collections[i].ID] = &collections[i]
+		}
+		if !repository.SameIDs(stored, ids) {
+			return repository.ErrReorderMismatch
+		}
+
+		bucket := tx.Bucket(bucketFavoriteCollections)
+		for position, id := range ids {
+			byID[id].Position = int64(position)
+			if err := put(bucket, collectionKey(userID, id), byID[id]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func userCollections(tx *bbolt.Tx, userID string) ([]models.FavoriteCollection, error) {
+	collections := []models.FavoriteCollection{}
+	prefix := []byte(userID + "\x00")
+	cursor := tx.Bucket(bucketFavoriteCollections).Cursor()
+	for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+		var collection models.FavoriteCollection
+		if err := json.Unmarshal(data, &collection); err != nil {
+			return nil, fmt.Errorf("failed to decode %q: %w", key, err)
+		}
+		collections = append(collections, collection)
+	}
+	return collections, nil
+}
+
+func hasCollectionName(collections []models.FavoriteCollection, name string, except primitive.ObjectID) bool {
+	for _, collection := range collections {
+		if collection.ID != except && collection.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateLegacyFavorites переносит избранные группы из бакета favorite_groups в favorites
+// и удаляет старый бакет; все в одной транзакции, поэтому перенос либо прошел целиком, либо нет
+func migrateLegacyFavorites(tx *bbolt.Tx) error {
+	legacy := tx.Bucket(bucketLegacyFavorites)
+	if legacy == nil {
+		return nil
+	}
+
+	favorites := tx.Bucket(bucketFavorites)
+	err := legacy.ForEach(func(_, data []byte) error {
+		var group models.FavoriteGroup
+		if err := json.Unmarshal(data, &group); err != nil {
+			return nil
+		}
+		favorite := models.Favorite{
+			ID:        group.ID,
+			UserID:    group.UserID,
+			Type:      models.FavoriteTypeGroup,
+			Key:       group.GroupNumber,
+			CreatedAt: group.CreatedAt,
+			UpdatedAt: group.UpdatedAt,
+		}
+		if favorite.ID.IsZero() {
+			favorite.ID = primitive.NewObjectID()
+		}
+		return put(favorites, favoriteKey(favorite.UserID, favorite.Type, favorite.Key), &favorite)
+	})
+	if err != nil {
+		return err
+	}
+	return tx.DeleteBucket(bucketLegacyFavorites)
+}
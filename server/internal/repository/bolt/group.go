@@ -0,0 +1,173 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type groupRepository struct {
+	db *bbolt.DB
+}
+
+func (r *groupRepository) GetByNumber(ctx context.Context, groupNumber string) (group *models.StoredGroup, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(bucketGroupsByName).Get([]byte(groupNumber))
+		if id == nil {
+			return nil
+		}
+		group, err = get[models.StoredGroup](tx.Bucket(bucketGroups), id)
+		return err
+	})
+	return group, err
+}
+
+// GetByNumbers читает все записи в одной транзакции
+func (r *groupRepository) GetByNumbers(ctx context.Context, groupNumbers []string) (groups []models.StoredGroup, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		for _, key := range groupNumbers {
+			id := tx.Bucket(bucketGroupsByName).Get([]byte(key))
+			if id == nil {
+				continue
+			}
+			group, err := get[models.StoredGroup](tx.Bucket(bucketGroups), id)
+			if err != nil {
+				return err
+			}
+			if group != nil {
+				groups = append(groups, *group)
+			}
+		}
+		return nil
+	})
+	return groups, err
+}
+
+func (r *groupRepository) GetByID(ctx context.Context, id int) (group *models.StoredGroup, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		group, err = get[models.StoredGroup](tx.Bucket(bucketGroups), intKey(id))
+		return err
+	})
+	return group, err
+}
+
+func (r *groupRepository) GetAll(ctx context.Context) (groups []models.StoredGroup, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketGroups).ForEach(func(_, data []byte) error {
+			var group models.StoredGroup
+			if err := json.Unmarshal(data, &group); err != nil {
+				// Пропускаем записи с ошибками декодирования, как и репозиторий MongoDB
+				return nil
+			}
+			groups = append(groups, group)
+			return nil
+		})
+	})
+	return groups, err
+}
+
+func (r *groupRepository) Count(ctx context.Context) (count int64, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		count = int64(tx.Bucket(bucketGroups).Stats().KeyN)
+		return nil
+	})
+	return count, err
+}
+
+func (r *groupRepository) Save(ctx context.Context, group *models.StoredGroup) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return insertGroup(tx, group)
+	})
+}
+
+// SaveMany, как неупорядоченная вставка в MongoDB, сохраняет все группы без конфликтов
+// и возвращает ErrDuplicate, если конфликты были
+func (r *groupRepository) SaveMany(ctx context.Context, groups []models.StoredGroup) error {
+	var duplicate error
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		for i := range groups {
+			err := insertGroup(tx, &groups[i])
+			if errors.Is(err, repository.ErrDuplicate) {
+				duplicate = err
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return duplicate
+}
+
+// Update создает или заменяет группу с тем же BSUIRID, сохраняя исходные ID и CreatedAt
+func (r *groupRepository) Update(ctx context.Context, group *models.StoredGroup) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := get[models.StoredGroup](tx.Bucket(bucketGroups), intKey(group.BSUIRID))
+		if err != nil {
+			return err
+		}
+
+		stored := *group
+		if existing != nil {
+			stored.ID = existing.ID
+			stored.CreatedAt = existing.CreatedAt
+			unindexGroup(tx, existing)
+		} else {
+			stored.ID = primitive.NewObjectID()
+		}
+		return putGroup(tx, &stored)
+	})
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id int) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := get[models.StoredGroup](tx.Bucket(bucketGroups), intKey(id))
+		if err != nil || existing == nil {
+			return err
+		}
+		unindexGroup(tx, existing)
+		return tx.Bucket(bucketGroups).Delete(intKey(id))
+	})
+}
+
+func insertGroup(tx *bbolt.Tx, group *models.StoredGroup) error {
+	if tx.Bucket(bucketGroups).Get(intKey(group.BSUIRID)) != nil {
+		return repository.ErrDuplicate
+	}
+	if group.ID.IsZero() {
+		group.ID = primitive.NewObjectID()
+	}
+	return putGroup(tx, group)
+}
+
+func putGroup(tx *bbolt.Tx, group *models.StoredGroup) error {
+	key := intKey(group.BSUIRID)
+	if err := put(tx.Bucket(bucketGroups), key, group); err != nil {
+		return err
+	}
+	if group.GroupData.Name == "" {
+		return nil
+	}
+	// Индекс по имени неуникальный, как в MongoDB: при совпадении имен находится последняя записанная группа
+	return tx.Bucket(bucketGroupsByName).Put([]byte(group.GroupData.Name), key)
+}
+
+// unindexGroup убирает запись индекса по имени, если она указывает на эту группу
+func unindexGroup(tx *bbolt.Tx, group *models.StoredGroup) {
+	index := tx.Bucket(bucketGroupsByName)
+	name := []byte(group.GroupData.Name)
+	if bytes.Equal(index.Get(name), intKey(group.BSUIRID)) {
+		_ = index.Delete(name)
+	}
+}
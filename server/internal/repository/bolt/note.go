@@ -0,0 +1,116 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type noteRepository struct {
+	db *bbolt.DB
+}
+
+// noteKey — user_id и hex ID: записи пользователя лежат одним диапазоном
+func noteKey(userID string, id primitive.ObjectID) []byte {
+	return []byte(userID + "\x00" + id.Hex())
+}
+
+func (r *noteRepository) GetByUser(ctx context.Context, userID string, filter repository.NoteFilter) ([]models.LessonNote, error) {
+	notes, err := r.collect(userID, filter.Match)
+	if err != nil {
+		return nil, err
+	}
+	repository.SortNotes(notes)
+	return notes, nil
+}
+
+func (r *noteRepository) GetHomework(ctx context.Context, userID string, filter repository.HomeworkFilter) ([]models.LessonNote, error) {
+	notes, err := r.collect(userID, filter.Match)
+	if err != nil {
+		return nil, err
+	}
+	repository.SortHomework(notes)
+	return notes, nil
+}
+
+func (r *noteRepository) collect(userID string, match func(*models.LessonNote) bool) ([]models.LessonNote, error) {
+	notes := []models.LessonNote{}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		prefix := []byte(userID + "\x00")
+		cursor := tx.Bucket(bucketLessonNotes).Cursor()
+		for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+			var note models.LessonNote
+			if err := json.Unmarshal(data, &note); err != nil {
+				return fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+			if match(&note) {
+				notes = append(notes, note)
+			}
+		}
+		return nil
+	})
+	return notes, err
+}
+
+func (r *noteRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (note *models.LessonNote, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		note, err = get[models.LessonNote](tx.Bucket(bucketLessonNotes), noteKey(userID, id))
+		return err
+	})
+	return note, err
+}
+
+func (r *noteRepository) Create(ctx context.Context, note *models.LessonNote) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		notes := tx.Bucket(bucketLessonNotes)
+		if note.ID.IsZero() {
+			note.ID = primitive.NewObjectID()
+		}
+		key := noteKey(note.UserID, note.ID)
+		if notes.Get(key) != nil {
+			return repository.ErrDuplicate
+		}
+		return put(notes, key, note)
+	})
+}
+
+func (r *noteRepository) Update(ctx context.Context, note *models.LessonNote) (updated bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		notes := tx.Bucket(bucketLessonNotes)
+		key := noteKey(note.UserID, note.ID)
+		stored, err := get[models.LessonNote](notes, key)
+		if err != nil || stored == nil {
+			return err
+		}
+
+		stored.Text = note.Text
+		stored.Attachments = note.Attachments
+		stored.DueAt = note.DueAt
+		stored.Done = note.Done
+		stored.DoneAt = note.DoneAt
+		stored.UpdatedAt = note.UpdatedAt
+		updated = true
+		return put(notes, key, stored)
+	})
+	return updated, err
+}
+
+func (r *noteRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (deleted bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		notes := tx.Bucket(bucketLessonNotes)
+		key := noteKey(userID, id)
+		if notes.Get(key) == nil {
+			return nil
+		}
+		deleted = true
+		return notes.Delete(key)
+	})
+	return deleted, err
+}
@@ -0,0 +1,109 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type overrideRepository struct {
+	db *bbolt.DB
+}
+
+// overrideKey — user_id и hex ID: правки пользователя лежат одним диапазоном
+func overrideKey(userID string, id primitive.ObjectID) []byte {
+	return []byte(userID + "\x00" + id.Hex())
+}
+
+func (r *overrideRepository) GetByUser(ctx context.Context, userID string) ([]models.LessonOverride, error) {
+	return r.collect(userID, func(*models.LessonOverride) bool { return true })
+}
+
+func (r *overrideRepository) GetBySchedule(ctx context.Context, userID, scheduleType, key string) ([]models.LessonOverride, error) {
+	return r.collect(userID, func(override *models.LessonOverride) bool {
+		return override.ScheduleType == scheduleType && override.Key == key
+	})
+}
+
+func (r *overrideRepository) collect(userID string, match func(*models.LessonOverride) bool) ([]models.LessonOverride, error) {
+	overrides := []models.LessonOverride{}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		prefix := []byte(userID + "\x00")
+		cursor := tx.Bucket(bucketLessonOverrides).Cursor()
+		for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+			var override models.LessonOverride
+			if err := json.Unmarshal(data, &override); err != nil {
+				return fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+			if match(&override) {
+				overrides = append(overrides, override)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repository.SortOverrides(overrides)
+	return overrides, nil
+}
+
+func (r *overrideRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (override *models.LessonOverride, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		override, err = get[models.LessonOverride](tx.Bucket(bucketLessonOverrides), overrideKey(userID, id))
+		return err
+	})
+	return override, err
+}
+
+func (r *overrideRepository) Create(ctx context.Context, override *models.LessonOverride) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		overrides := tx.Bucket(bucketLessonOverrides)
+		if override.ID.IsZero() {
+			override.ID = primitive.NewObjectID()
+		}
+		key := overrideKey(override.UserID, override.ID)
+		if overrides.Get(key) != nil {
+			return repository.ErrDuplicate
+		}
+		return put(overrides, key, override)
+	})
+}
+
+func (r *overrideRepository) Update(ctx context.Context, override *models.LessonOverride) (updated bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		overrides := tx.Bucket(bucketLessonOverrides)
+		key := overrideKey(override.UserID, override.ID)
+		stored, err := get[models.LessonOverride](overrides, key)
+		if err != nil || stored == nil {
+			return err
+		}
+
+		replaced := *override
+		replaced.CreatedAt = stored.CreatedAt
+		updated = true
+		return put(overrides, key, replaced)
+	})
+	return updated, err
+}
+
+func (r *overrideRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (deleted bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		overrides := tx.Bucket(bucketLessonOverrides)
+		key := overrideKey(userID, id)
+		if overrides.Get(key) == nil {
+			return nil
+		}
+		deleted = true
+		return overrides.Delete(key)
+	})
+	return deleted, err
+}
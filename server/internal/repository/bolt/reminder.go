@@ -0,0 +1,125 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+)
+
+// reminderRepository хранит подписки по user_id в bucketReminderSubscriptions,
+// а отметки об отправке — по user_id и ключу напоминания в bucketSentReminders
+type reminderRepository struct {
+	db *bbolt.DB
+}
+
+func sentReminderKey(userID, key string) []byte {
+	return []byte(userID + "\x00" + key)
+}
+
+func (r *reminderRepository) GetSubscriptions(ctx context.Context) ([]models.ReminderSubscription, error) {
+	subscriptions := []models.ReminderSubscription{}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketReminderSubscriptions).ForEach(func(key, data []byte) error {
+			var subscription models.ReminderSubscription
+			if err := json.Unmarshal(data, &subscription); err != nil {
+				return fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+			subscriptions = append(subscriptions, subscription)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *reminderRepository) GetSubscription(ctx context.Context, userID string) (subscription *models.ReminderSubscription, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		subscription, err = get[models.ReminderSubscription](tx.Bucket(bucketReminderSubscriptions), []byte(userID))
+		return err
+	})
+	return subscription, err
+}
+
+func (r *reminderRepository) SaveSubscription(ctx context.Context, subscription *models.ReminderSubscription) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		subscriptions := tx.Bucket(bucketReminderSubscriptions)
+		stored, err := get[models.ReminderSubscription](subscriptions, []byte(subscription.UserID))
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			subscription.ID, subscription.CreatedAt = stored.ID, stored.CreatedAt
+		} else if subscription.ID.IsZero() {
+			subscription.ID = primitive.NewObjectID()
+		}
+		return put(subscriptions, []byte(subscription.UserID), subscription)
+	})
+}
+
+func (r *reminderRepository) DeleteSubscription(ctx context.Context, userID string) (deleted bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		subscriptions := tx.Bucket(bucketReminderSubscriptions)
+		if subscriptions.Get([]byte(userID)) == nil {
+			return nil
+		}
+		deleted = true
+		return subscriptions.Delete([]byte(userID))
+	})
+	return deleted, err
+}
+
+func (r *reminderRepository) MarkSent(ctx context.Context, userID, key string, sentAt time.Time) (marked bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		sent := tx.Bucket(bucketSentReminders)
+		sentKey := sentReminderKey(userID, key)
+		if sent.Get(sentKey) != nil {
+			return nil
+		}
+		marked = true
+		return put(sent, sentKey, sentAt)
+	})
+	return marked, err
+}
+
+func (r *reminderRepository) UnmarkSent(ctx context.Context, userID, key string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketSentReminders).Delete(sentReminderKey(userID, key))
+	})
+}
+
+func (r *reminderRepository) DeleteSentBefore(ctx context.Context, before time.Time) (deleted int64, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		sent := tx.Bucket(bucketSentReminders)
+		var expired [][]byte
+		err := sent.ForEach(func(key, data []byte) error {
+			var sentAt time.Time
+			if err := json.Unmarshal(data, &sentAt); err != nil {
+				return fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+			if sentAt.Before(before) {
+				expired = append(expired, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// Удалять ключи во время ForEach bbolt не разрешает
+		for _, key := range expired {
+			if err := sent.Delete(key); err != nil {
+				return err
+			}
+		}
+		deleted = int64(len(expired))
+		return nil
+	})
+	return deleted, err
+}
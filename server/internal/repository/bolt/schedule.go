@@ -0,0 +1,112 @@
+package bolt
+
+import (
+	"context"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type scheduleRepository struct {
+	db *bbolt.DB
+}
+
+func (r *scheduleRepository) GetByGroupNumber(ctx context.Context, groupNumber string) (schedule *models.StoredSchedule, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		schedule, err = get[models.StoredSchedule](tx.Bucket(bucketSchedulesByGroup), []byte(groupNumber))
+		return err
+	})
+	return schedule, err
+}
+
+func (r *scheduleRepository) GetByEmployeeURLID(ctx context.Context, urlID string) (schedule *models.StoredSchedule, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		schedule, err = get[models.StoredSchedule](tx.Bucket(bucketSchedulesByEmployee), []byte(urlID))
+		return err
+	})
+	return schedule, err
+}
+
+func (r *scheduleRepository) Save(ctx context.Context, schedule *models.StoredSchedule) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := existingSchedule(tx, schedule)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return repository.ErrDuplicate
+		}
+
+		schedule.CreatedAt = time.Now()
+		schedule.UpdatedAt = time.Now()
+		if schedule.ID.IsZero() {
+			schedule.ID = primitive.NewObjectID()
+		}
+		return putSchedule(tx, schedule)
+	})
+}
+
+// Update создает или заменяет расписание группы либо преподавателя
+func (r *scheduleRepository) Update(ctx context.Context, schedule *models.StoredSchedule) error {
+	schedule.UpdatedAt = time.Now()
+
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := existingSchedule(tx, schedule)
+		if err != nil {
+			return err
+		}
+
+		stored := *schedule
+		if existing != nil {
+			stored.ID = existing.ID
+			stored.CreatedAt = existing.CreatedAt
+		} else {
+			stored.ID = primitive.NewObjectID()
+			stored.CreatedAt = schedule.UpdatedAt
+		}
+		return putSchedule(tx, &stored)
+	})
+}
+
+func (r *scheduleRepository) Delete(ctx context.Context, groupNumber string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketSchedulesByGroup).Delete([]byte(groupNumber))
+	})
+}
+
+func (r *scheduleRepository) DeleteByEmployeeURLID(ctx context.Context, urlID string) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketSchedulesByEmployee).Delete([]byte(urlID))
+	})
+}
+
+func existingSchedule(tx *bbolt.Tx, schedule *models.StoredSchedule) (*models.StoredSchedule, error) {
+	if schedule.GroupNumber != "" {
+		existing, err := get[models.StoredSchedule](tx.Bucket(bucketSchedulesByGroup), []byte(schedule.GroupNumber))
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+	if schedule.EmployeeURLID != "" {
+		return get[models.StoredSchedule](tx.Bucket(bucketSchedulesByEmployee), []byte(schedule.EmployeeURLID))
+	}
+	return nil, nil
+}
+
+func putSchedule(tx *bbolt.Tx, schedule *models.StoredSchedule) error {
+	if schedule.GroupNumber != "" {
+		if err := put(tx.Bucket(bucketSchedulesByGroup), []byte(schedule.GroupNumber), schedule); err != nil {
+			return err
+		}
+	}
+	if schedule.EmployeeURLID != "" {
+		if err := put(tx.Bucket(bucketSchedulesByEmployee), []byte(schedule.EmployeeURLID), schedule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
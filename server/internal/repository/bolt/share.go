@@ -0,0 +1,111 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// shareRecord хранит и хэш токена, который в models.Share скрыт от JSON
+type shareRecord struct {
+	models.Share
+	TokenHash string `json:"token_hash"`
+}
+
+func (r *shareRecord) share() *models.Share {
+	share := r.Share
+	share.TokenHash = r.TokenHash
+	return &share
+}
+
+type shareRepository struct {
+	db *bbolt.DB
+}
+
+// shareKey — user_id и hex ID: ссылки пользователя лежат одним диапазоном
+func shareKey(userID string, id primitive.ObjectID) []byte {
+	return []byte(userID + "\x00" + id.Hex())
+}
+
+func (r *shareRepository) GetByHash(ctx context.Context, tokenHash string) (share *models.Share, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		key := tx.Bucket(bucketSharesByHash).Get([]byte(tokenHash))
+		if key == nil {
+			return nil
+		}
+		record, err := get[shareRecord](tx.Bucket(bucketShares), key)
+		if err != nil || record == nil {
+			return err
+		}
+		share = record.share()
+		return nil
+	})
+	return share, err
+}
+
+func (r *shareRepository) GetByUser(ctx context.Context, userID string) ([]models.Share, error) {
+	shares := []models.Share{}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		prefix := []byte(userID + "\x00")
+		cursor := tx.Bucket(bucketShares).Cursor()
+		for key, data := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = cursor.Next() {
+			var record shareRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+			shares = append(shares, *record.share())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+	})
+	return shares, nil
+}
+
+func (r *shareRepository) Create(ctx context.Context, share *models.Share) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		byHash := tx.Bucket(bucketSharesByHash)
+		if byHash.Get([]byte(share.TokenHash)) != nil {
+			return repository.ErrDuplicate
+		}
+
+		if share.ID.IsZero() {
+			share.ID = primitive.NewObjectID()
+		}
+		key := shareKey(share.UserID, share.ID)
+		if err := put(tx.Bucket(bucketShares), key, shareRecord{Share: *share, TokenHash: share.TokenHash}); err != nil {
+			return err
+		}
+		return byHash.Put([]byte(share.TokenHash), key)
+	})
+}
+
+func (r *shareRepository) Revoke(ctx context.Context, userID string, id primitive.ObjectID, revokedAt time.Time) (revoked bool, err error) {
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		shares := tx.Bucket(bucketShares)
+		key := shareKey(userID, id)
+		record, err := get[shareRecord](shares, key)
+		if err != nil || record == nil || record.RevokedAt != nil {
+			return err
+		}
+
+		record.RevokedAt = &revokedAt
+		revoked = true
+		return put(shares, key, record)
+	})
+	return revoked, err
+}
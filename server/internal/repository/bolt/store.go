@@ -0,0 +1,158 @@
+// Package bolt — встроенное файловое хранилище на bbolt для небольших установок без MongoDB.
+// Файл открывается одним процессом: пока сервер запущен, schedluer-admin к нему не подключится.
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"schedluer/internal/repository"
+)
+
+// Бакеты с данными и индексами уникальности, повторяющими индексы MongoDB
+var (
+	bucketSchedulesByGroup      = []byte("schedules_by_group")
+	bucketSchedulesByEmployee   = []byte("schedules_by_employee")
+	bucketGroups                = []byte("groups")
+	bucketGroupsByName          = []byte("groups_by_name")
+	bucketEmployees             = []byte("employees")
+	bucketEmployeesByURLID      = []byte("employees_by_url_id")
+	bucketFavorites             = []byte("favorites")
+	bucketFavoriteCollections   = []byte("favorite_collections")
+	bucketAPIKeys               = []byte("api_keys")
+	bucketAPIKeysByHash         = []byte("api_keys_by_hash")
+	bucketShares                = []byte("shares")
+	bucketSharesByHash          = []byte("shares_by_hash")
+	bucketEvents                = []byte("events")
+	bucketLessonNotes           = []byte("lesson_notes")
+	bucketLessonOverrides       = []byte("lesson_overrides")
+	bucketReminderSubscriptions = []byte("reminder_subscriptions")
+	bucketSentReminders         = []byte("sent_reminders")
+
+	// bucketLegacyFavorites — избранные группы до появления типов, переносятся в bucketFavorites при открытии
+	bucketLegacyFavorites = []byte("favorite_groups")
+)
+
+// openTimeout — сколько ждать блокировку файла, если его держит другой процесс
+const openTimeout = 5 * time.Second
+
+type store struct {
+	db *bbolt.DB
+
+	schedules repository.ScheduleRepository
+	groups    repository.GroupRepository
+	employees repository.EmployeeRepository
+	favorites repository.FavoriteRepository
+	apiKeys   repository.APIKeyRepository
+	shares    repository.ShareRepository
+	events    repository.EventRepository
+	notes     repository.NoteRepository
+	overrides repository.OverrideRepository
+	reminders repository.ReminderRepository
+}
+
+// Open открывает (или создает) файл базы и все бакеты
+func Open(path string) (repository.Store, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if errors.Is(err, bbolt.ErrTimeout) {
+		return nil, fmt.Errorf("database file %s is locked by another process (is the server running?)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database file %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{
+			bucketSchedulesByGroup, bucketSchedulesByEmployee,
+			bucketGroups, bucketGroupsByName,
+			bucketEmployees, bucketEmployeesByURLID,
+			bucketFavorites, bucketFavoriteCollections,
+			bucketAPIKeys, bucketAPIKeysByHash,
+			bucketShares, bucketSharesByHash,
+			bucketEvents, bucketLessonNotes, bucketLessonOverrides,
+			bucketReminderSubscriptions, bucketSentReminders,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return migrateLegacyFavorites(tx)
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	return &store{
+		db:        db,
+		schedules: &scheduleRepository{db: db},
+		groups:    &groupRepository{db: db},
+		employees: &employeeRepository{db: db},
+		favorites: &favoriteRepository{db: db},
+		apiKeys:   &apiKeyRepository{db: db},
+		shares:    &shareRepository{db: db},
+		events:    &eventRepository{db: db},
+		notes:     &noteRepository{db: db},
+		overrides: &overrideRepository{db: db},
+		reminders: &reminderRepository{db: db},
+	}, nil
+}
+
+func (s *store) Schedules() repository.ScheduleRepository { return s.schedules }
+func (s *store) Groups() repository.GroupRepository       { return s.groups }
+func (s *store) Employees() repository.EmployeeRepository { return s.employees }
+func (s *store) Favorites() repository.FavoriteRepository { return s.favorites }
+func (s *store) APIKeys() repository.APIKeyRepository     { return s.apiKeys }
+func (s *store) Shares() repository.ShareRepository       { return s.shares }
+func (s *store) Events() repository.EventRepository       { return s.events }
+func (s *store) Notes() repository.NoteRepository         { return s.notes }
+func (s *store) Overrides() repository.OverrideRepository { return s.overrides }
+func (s *store) Reminders() repository.ReminderRepository { return s.reminders }
+
+func (s *store) Driver() string { return "bolt" }
+
+func (s *store) Health(ctx context.Context) error {
+	return s.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(bucketGroups) == nil {
+			return errors.New("bucket groups is missing")
+		}
+		return nil
+	})
+}
+
+func (s *store) Close(ctx context.Context) error {
+	return s.db.Close()
+}
+
+func intKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+// get декодирует значение по ключу; nil, nil — если ключа нет
+func get[T any](bucket *bbolt.Bucket, key []byte) (*T, error) {
+	data := bucket.Get(key)
+	if data == nil {
+		return nil, nil
+	}
+	value := new(T)
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, fmt.Errorf("failed to decode %q: %w", key, err)
+	}
+	return value, nil
+}
+
+func put(bucket *bbolt.Bucket, key []byte, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
@@ -0,0 +1,63 @@
+package bolt_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/repository/bolt"
+	"schedluer/internal/repository/repotest"
+)
+
+func TestStoreConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.Store {
+		store, err := bolt.Open(filepath.Join(t.TempDir(), "schedluer.db"))
+		if err != nil {
+			t.Fatalf("failed to open bolt store: %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close(context.Background()) })
+		return store
+	})
+}
+
+func TestLegacyFavoritesMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedluer.db")
+
+	db, err := bbolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucket([]byte("favorite_groups"))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte("user-1\x00221701"), []byte(`{"group_number":"221701","user_id":"user-1","created_at":"2025-09-01T10:00:00Z"}`))
+	})
+	if err != nil {
+		t.Fatalf("failed to write legacy favorites: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := bolt.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open bolt store: %v", err)
+	}
+	defer func() { _ = store.Close(context.Background()) }()
+
+	favorites, err := store.Favorites().GetAll(context.Background(), "user-1", "")
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(favorites) != 1 || favorites[0].Type != models.FavoriteTypeGroup || favorites[0].Key != "221701" {
+		t.Fatalf("legacy favorite was not migrated: %+v", favorites)
+	}
+	if !favorites[0].CreatedAt.Equal(time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("migration lost CreatedAt: %v", favorites[0].CreatedAt)
+	}
+}
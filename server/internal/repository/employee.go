@@ -13,8 +13,11 @@ import (
 
 type EmployeeRepository interface {
 	GetByURLID(ctx context.Context, urlID string) (*models.StoredEmployee, error)
+	// GetByURLIDs возвращает преподавателей с этими URL ID одним запросом; отсутствующие пропускаются
+	GetByURLIDs(ctx context.Context, urlIDs []string) ([]models.StoredEmployee, error)
 	GetByID(ctx context.Context, id int) (*models.StoredEmployee, error)
 	GetAll(ctx context.Context) ([]models.StoredEmployee, error)
+	Count(ctx context.Context) (int64, error)
 	Save(ctx context.Context, employee *models.StoredEmployee) error
 	SaveMany(ctx context.Context, employees []models.StoredEmployee) error
 	Update(ctx context.Context, employee *models.StoredEmployee) error
@@ -71,8 +74,19 @@ func (r *employeeRepository) GetByID(ctx context.Context, id int) (*models.Store
 	return &employee, nil
 }
 
+func (r *employeeRepository) GetByURLIDs(ctx context.Context, urlIDs []string) ([]models.StoredEmployee, error) {
+	if len(urlIDs) == 0 {
+		return nil, nil
+	}
+	return r.find(ctx, bson.M{"url_id": bson.M{"$in": urlIDs}})
+}
+
 func (r *employeeRepository) GetAll(ctx context.Context) ([]models.StoredEmployee, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	return r.find(ctx, bson.M{})
+}
+
+func (r *employeeRepository) find(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]models.StoredEmployee, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,9 +114,13 @@ func (r *employeeRepository) GetAll(ctx context.Context) ([]models.StoredEmploye
 	return employees, nil
 }
 
+func (r *employeeRepository) Count(ctx context.Context) (int64, error) {
+	return r.collection.EstimatedDocumentCount(ctx)
+}
+
 func (r *employeeRepository) Save(ctx context.Context, employee *models.StoredEmployee) error {
 	_, err := r.collection.InsertOne(ctx, employee)
-	return err
+	return mongoError(err)
 }
 
 func (r *employeeRepository) SaveMany(ctx context.Context, employees []models.StoredEmployee) error {
@@ -117,7 +135,7 @@ func (r *employeeRepository) SaveMany(ctx context.Context, employees []models.St
 
 	opts := options.InsertMany().SetOrdered(false)
 	_, err := r.collection.InsertMany(ctx, docs, opts)
-	return err
+	return mongoError(err)
 }
 
 func (r *employeeRepository) Update(ctx context.Context, employee *models.StoredEmployee) error {
@@ -138,7 +156,7 @@ func (r *employeeRepository) Update(ctx context.Context, employee *models.Stored
 
 	opts := options.UpdateOne().SetUpsert(true)
 	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	return mongoError(err)
 }
 
 func (r *employeeRepository) Delete(ctx context.Context, id int) error {
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"schedluer/internal/models"
+)
+
+// EventRepository хранит личные события пользователей. Событие видно и меняется только владельцем.
+type EventRepository interface {
+	// GetByUser возвращает события пользователя по времени первого повторения
+	GetByUser(ctx context.Context, userID string) ([]models.Event, error)
+	GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.Event, error)
+	Create(ctx context.Context, event *models.Event) error
+	// Update заменяет содержимое события, CreatedAt остается прежним; false, если события нет
+	Update(ctx context.Context, event *models.Event) (bool, error)
+	Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+}
+
+type eventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEventRepository(db *mongo.Database) EventRepository {
+	collection := db.Collection("events")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "start", Value: 1}},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &eventRepository{
+		collection: collection,
+	}
+}
+
+func (r *eventRepository) GetByUser(ctx context.Context, userID string) ([]models.Event, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []models.Event{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *eventRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.Event, error) {
+	var event models.Event
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, event)
+	return mongoError(err)
+}
+
+func (r *eventRepository) Update(ctx context.Context, event *models.Event) (bool, error) {
+	filter := bson.M{"_id": event.ID, "user_id": event.UserID}
+	update := bson.M{"$set": bson.M{
+		"title":       event.Title,
+		"description": event.Description,
+		"location":    event.Location,
+		"start":       event.Start,
+		"end":         event.End,
+		"recurrence":  event.Recurrence,
+		"updated_at":  event.UpdatedAt,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *eventRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"errors"
-	"time"
+	"maps"
+	"regexp"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,124 +13,111 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
+	"schedluer/internal/logging"
 	"schedluer/internal/models"
 )
 
+// FavoriteRepository хранит типизированное избранное и коллекции пользователя.
+// Пустой favoriteType в GetAll и Search — все типы. Списки отдаются в порядке models.FavoriteLess,
+// коллекции — по позиции. Перестановка применяется, только если набор элементов не изменился.
 type FavoriteRepository interface {
-	GetAll(ctx context.Context, userID string) ([]models.FavoriteGroup, error)
-	GetByGroupNumber(ctx context.Context, userID string, groupNumber string) (*models.FavoriteGroup, error)
-	Search(ctx context.Context, userID string, query string) ([]models.FavoriteGroup, error)
-	Add(ctx context.Context, favorite *models.FavoriteGroup) error
-	Delete(ctx context.Context, userID string, groupNumber string) error
-	IsFavorite(ctx context.Context, userID string, groupNumber string) (bool, error)
+	GetAll(ctx context.Context, userID string, favoriteType string) ([]models.Favorite, error)
+	Get(ctx context.Context, userID string, favoriteType string, key string) (*models.Favorite, error)
+	Search(ctx context.Context, userID string, favoriteType string, query string) ([]models.Favorite, error)
+	// Add добавляет элемент; повторное добавление обновляет только UpdatedAt
+	Add(ctx context.Context, favorite *models.Favorite) error
+	// UpdateDetails сохраняет коллекцию, подпись, цвет, закрепление и позицию; false — элемента нет
+	UpdateDetails(ctx context.Context, favorite *models.Favorite) (bool, error)
+	Delete(ctx context.Context, userID string, favoriteType string, key string) error
+	IsFavorite(ctx context.Context, userID string, favoriteType string, key string) (bool, error)
+	// Reorder расставляет элементы коллекции collectionID ("" — вне коллекций) в порядке ids.
+	// ids должны совпадать с набором элементов коллекции, иначе ErrReorderMismatch.
+	Reorder(ctx context.Context, userID string, collectionID string, ids []primitive.ObjectID) error
+
+	GetCollections(ctx context.Context, userID string) ([]models.FavoriteCollection, error)
+	GetCollection(ctx context.Context, userID string, id primitive.ObjectID) (*models.FavoriteCollection, error)
+	// CreateCollection возвращает ErrDuplicate, если у пользователя уже есть коллекция с таким именем
+	CreateCollection(ctx context.Context, collection *models.FavoriteCollection) error
+	// UpdateCollection сохраняет имя и цвет; false — коллекции нет
+	UpdateCollection(ctx context.Context, collection *models.FavoriteCollection) (bool, error)
+	// DeleteCollection удаляет коллекцию, ее элементы остаются в избранном вне коллекций; false — коллекции нет
+	DeleteCollection(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+	// ReorderCollections расставляет коллекции в порядке ids; ids должны совпадать со всеми коллекциями пользователя
+	ReorderCollections(ctx context.Context, userID string, ids []primitive.ObjectID) error
 }
 
 type favoriteRepository struct {
-	collection *mongo.Collection
-	logger     *logrus.Logger
+	client      *mongo.Client
+	collection  *mongo.Collection
+	collections *mongo.Collection
+	logger      *logrus.Logger
+	// noTransactions — сервер оказался одиночным mongod, перестановки идут без транзакций
+	noTransactions atomic.Bool
+}
+
+// illegalOperationCode — ответ одиночного mongod на попытку начать транзакцию
+const illegalOperationCode = 20
+
+// favoriteSort — порядок models.FavoriteLess
+var favoriteSort = bson.D{
+	{Key: "pinned", Value: -1},
+	{Key: "position", Value: 1},
+	{Key: "created_at", Value: 1},
+	{Key: "type", Value: 1},
+	{Key: "key", Value: 1},
 }
 
 func NewFavoriteRepository(db *mongo.Database, logger *logrus.Logger) FavoriteRepository {
-	collection := db.Collection("favorite_groups")
+	collection := db.Collection("favorites")
 
 	indexes := []mongo.IndexModel{
 		{
-			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "group_number", Value: 1}},
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "type", Value: 1}, {Key: "key", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}},
 		},
 	}
 
 	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
 
+	collections := db.Collection("favorite_collections")
+	_, _ = collections.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
 	return &favoriteRepository{
-		collection: collection,
-		logger:     logger,
+		client:      db.Client(),
+		collection:  collection,
+		collections: collections,
+		logger:      logger,
 	}
 }
 
-func (r *favoriteRepository) GetAll(ctx context.Context, userID string) ([]models.FavoriteGroup, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
-	if err != nil {
-		return nil, err
-	}
-	defer func(cursor *mongo.Cursor, ctx context.Context) {
-		err := cursor.Close(ctx)
-		if err != nil {
-			return
-		}
-	}(cursor, ctx)
-
-	var favorites []models.FavoriteGroup
-	for cursor.Next(ctx) {
-		var rawDoc bson.M
-		if err := cursor.Decode(&rawDoc); err != nil {
-			if r.logger != nil {
-				r.logger.Warnf("Failed to decode favorite document (raw): %v", err)
-			}
-			continue
-		}
-
-		favorite := models.FavoriteGroup{}
-
-		if id, ok := rawDoc["_id"]; ok {
-			if idArray, ok := id.([]interface{}); ok && len(idArray) > 0 {
-				id = idArray[0]
-				if r.logger != nil {
-					r.logger.Debugf("Fixed _id from array to single value")
-				}
-			} else if idArray, ok := id.(bson.A); ok && len(idArray) > 0 {
-				id = idArray[0]
-				if r.logger != nil {
-					r.logger.Debugf("Fixed _id from bson.A to single value")
-				}
-			}
-
-			if objID, ok := id.(primitive.ObjectID); ok {
-				favorite.ID = objID
-			} else if idStr, ok := id.(string); ok {
-				if objID, err := primitive.ObjectIDFromHex(idStr); err == nil {
-					favorite.ID = objID
-				}
-			}
-		}
-
-		if groupNumber, ok := rawDoc["group_number"].(string); ok {
-			favorite.GroupNumber = groupNumber
-		}
-		if userID, ok := rawDoc["user_id"].(string); ok {
-			favorite.UserID = userID
-		}
-		if createdAt, ok := rawDoc["created_at"].(primitive.DateTime); ok {
-			favorite.CreatedAt = createdAt.Time()
-		} else if createdAt, ok := rawDoc["created_at"].(time.Time); ok {
-			favorite.CreatedAt = createdAt
-		}
-		if updatedAt, ok := rawDoc["updated_at"].(primitive.DateTime); ok {
-			favorite.UpdatedAt = updatedAt.Time()
-		} else if updatedAt, ok := rawDoc["updated_at"].(time.Time); ok {
-			favorite.UpdatedAt = updatedAt
-		}
-
-		favorites = append(favorites, favorite)
+func favoriteFilter(userID, favoriteType string) bson.M {
+	filter := bson.M{"user_id": userID}
+	if favoriteType != "" {
+		filter["type"] = favoriteType
 	}
+	return filter
+}
 
-	if err := cursor.Err(); err != nil {
+func (r *favoriteRepository) GetAll(ctx context.Context, userID string, favoriteType string) ([]models.Favorite, error) {
+	opts := options.Find().SetSort(favoriteSort)
+	cursor, err := r.collection.Find(ctx, favoriteFilter(userID, favoriteType), opts)
+	if err != nil {
 		return nil, err
 	}
-
-	if favorites == nil {
-		favorites = []models.FavoriteGroup{}
-	}
-
-	return favorites, nil
+	return decodeFavorites(ctx, cursor)
 }
 
-func (r *favoriteRepository) GetByGroupNumber(ctx context.Context, userID string, groupNumber string) (*models.FavoriteGroup, error) {
-	var favorite models.FavoriteGroup
-	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "group_number": groupNumber}).Decode(&favorite)
+func (r *favoriteRepository) Get(ctx context.Context, userID string, favoriteType string, key string) (*models.Favorite, error) {
+	var favorite models.Favorite
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "type": favoriteType, "key": key}).Decode(&favorite)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, nil
 	}
@@ -138,57 +127,80 @@ func (r *favoriteRepository) GetByGroupNumber(ctx context.Context, userID string
 	return &favorite, nil
 }
 
-func (r *favoriteRepository) Add(ctx context.Context, favorite *models.FavoriteGroup) error {
+func (r *favoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
 	opts := options.UpdateOne().SetUpsert(true)
-	filter := bson.M{"user_id": favorite.UserID, "group_number": favorite.GroupNumber}
+	filter := bson.M{"user_id": favorite.UserID, "type": favorite.Type, "key": favorite.Key}
+	setOnInsert := bson.M{
+		"position":   favorite.Position,
+		"created_at": favorite.CreatedAt,
+	}
+	// Без заданного _id ObjectID выдаст сервер
+	if !favorite.ID.IsZero() {
+		setOnInsert["_id"] = favorite.ID
+	}
 	update := bson.M{
 		"$set": bson.M{
-			"group_number": favorite.GroupNumber,
-			"user_id":      favorite.UserID,
-			"updated_at":   favorite.UpdatedAt,
-		},
-		"$setOnInsert": bson.M{
-			"created_at": favorite.CreatedAt,
+			"updated_at": favorite.UpdatedAt,
 		},
+		"$setOnInsert": setOnInsert,
 	}
 	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	return mongoError(err)
+}
+
+func (r *favoriteRepository) UpdateDetails(ctx context.Context, favorite *models.Favorite) (bool, error) {
+	filter := bson.M{"user_id": favorite.UserID, "type": favorite.Type, "key": favorite.Key}
+	update := bson.M{"$set": bson.M{
+		"collection_id": favorite.CollectionID,
+		"label":         favorite.Label,
+		"color":         favorite.Color,
+		"pinned":        favorite.Pinned,
+		"position":      favorite.Position,
+		"updated_at":    favorite.UpdatedAt,
+	}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
 }
 
-func (r *favoriteRepository) Delete(ctx context.Context, userID string, groupNumber string) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "group_number": groupNumber})
+func (r *favoriteRepository) Delete(ctx context.Context, userID string, favoriteType string, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "type": favoriteType, "key": key})
 	return err
 }
 
-func (r *favoriteRepository) IsFavorite(ctx context.Context, userID string, groupNumber string) (bool, error) {
-	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "group_number": groupNumber})
+func (r *favoriteRepository) IsFavorite(ctx context.Context, userID string, favoriteType string, key string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "type": favoriteType, "key": key})
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *favoriteRepository) Search(ctx context.Context, userID string, query string) ([]models.FavoriteGroup, error) {
+func (r *favoriteRepository) Search(ctx context.Context, userID string, favoriteType string, query string) ([]models.Favorite, error) {
+	must := []bson.M{
+		{
+			"text": bson.M{
+				"query": query,
+				"path":  "key",
+			},
+		},
+		{
+			"equals": bson.M{
+				"value": userID,
+				"path":  "user_id",
+			},
+		},
+	}
+	if favoriteType != "" {
+		must = append(must, bson.M{"equals": bson.M{"value": favoriteType, "path": "type"}})
+	}
 	pipeline := []bson.M{
 		{
 			"$search": bson.M{
-				"index": "default",
-				"compound": bson.M{
-					"must": []bson.M{
-						{
-							"text": bson.M{
-								"query": query,
-								"path":  "group_number",
-							},
-						},
-						{
-							"equals": bson.M{
-								"value": userID,
-								"path":  "user_id",
-							},
-						},
-					},
-				},
+				"index":    "default",
+				"compound": bson.M{"must": must},
 			},
 		},
 		{
@@ -199,81 +211,195 @@ func (r *favoriteRepository) Search(ctx context.Context, userID string, query st
 	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		if r.logger != nil {
-			r.logger.Warnf("Search index not available, falling back to regex search: %v", err)
-		}
-		filter := bson.M{
-			"user_id":      userID,
-			"group_number": bson.M{"$regex": query, "$options": "i"},
+			logging.FromContext(ctx, r.logger).WithError(err).Warn("Search index not available, falling back to regex search")
 		}
-		cursor, err = r.collection.Find(ctx, filter)
+		filter := favoriteFilter(userID, favoriteType)
+		filter["key"] = bson.M{"$regex": regexp.QuoteMeta(query), "$options": "i"}
+		cursor, err = r.collection.Find(ctx, filter, options.Find().SetSort(favoriteSort).SetLimit(100))
 		if err != nil {
 			return nil, err
 		}
 	}
-	defer func(cursor *mongo.Cursor, ctx context.Context) {
-		err := cursor.Close(ctx)
-		if err != nil {
-			return
-		}
-	}(cursor, ctx)
-
-	var favorites []models.FavoriteGroup
-	for cursor.Next(ctx) {
-		var rawDoc bson.M
-		if err := cursor.Decode(&rawDoc); err != nil {
-			if r.logger != nil {
-				r.logger.Warnf("Failed to decode favorite document (raw) in search: %v", err)
-			}
-			continue
-		}
+	return decodeFavorites(ctx, cursor)
+}
 
-		// Создаем структуру вручную, чтобы избежать проблем с _id
-		favorite := models.FavoriteGroup{}
-
-		// Обрабатываем _id отдельно - если это массив, берем первый элемент
-		if id, ok := rawDoc["_id"]; ok {
-			if idArray, ok := id.([]interface{}); ok && len(idArray) > 0 {
-				id = idArray[0]
-			} else if idArray, ok := id.(bson.A); ok && len(idArray) > 0 {
-				id = idArray[0]
-			}
-
-			if objID, ok := id.(primitive.ObjectID); ok {
-				favorite.ID = objID
-			} else if idStr, ok := id.(string); ok {
-				if objID, err := primitive.ObjectIDFromHex(idStr); err == nil {
-					favorite.ID = objID
-				}
-			}
-		}
+// Reorder работает и на одиночном mongod, где нет транзакций; гарантии описаны у reorder
+func (r *favoriteRepository) Reorder(ctx context.Context, userID string, collectionID string, ids []primitive.ObjectID) error {
+	filter := bson.M{"user_id": userID, "collection_id": collectionID}
+	if collectionID == "" {
+		filter["collection_id"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+	return r.reorder(ctx, r.collection, filter, ids)
+}
 
-		if groupNumber, ok := rawDoc["group_number"].(string); ok {
-			favorite.GroupNumber = groupNumber
-		}
-		if userID, ok := rawDoc["user_id"].(string); ok {
-			favorite.UserID = userID
-		}
-		if createdAt, ok := rawDoc["created_at"].(primitive.DateTime); ok {
-			favorite.CreatedAt = createdAt.Time()
-		} else if createdAt, ok := rawDoc["created_at"].(time.Time); ok {
-			favorite.CreatedAt = createdAt
-		}
-		if updatedAt, ok := rawDoc["updated_at"].(primitive.DateTime); ok {
-			favorite.UpdatedAt = updatedAt.Time()
-		} else if updatedAt, ok := rawDoc["updated_at"].(time.Time); ok {
-			favorite.UpdatedAt = updatedAt
+func (r *favoriteRepository) GetCollections(ctx context.Context, userID string) ([]models.FavoriteCollection, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := r.collections.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	collections := []models.FavoriteCollection{}
+	if err := cursor.All(ctx, &collections); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+func (r *favoriteRepository) GetCollection(ctx context.Context, userID string, id primitive.ObjectID) (*models.FavoriteCollection, error) {
+	var collection models.FavoriteCollection
+	err := r.collections.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&collection)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *favoriteRepository) CreateCollection(ctx context.Context, collection *models.FavoriteCollection) error {
+	if collection.ID.IsZero() {
+		collection.ID = primitive.NewObjectID()
+	}
+	_, err := r.collections.InsertOne(ctx, collection)
+	return mongoError(err)
+}
+
+func (r *favoriteRepository) UpdateCollection(ctx context.Context, collection *models.FavoriteCollection) (bool, error) {
+	filter := bson.M{"_id": collection.ID, "user_id": collection.UserID}
+	update := bson.M{"$set": bson.M{
+		"name":       collection.Name,
+		"color":      collection.Color,
+		"updated_at": collection.UpdatedAt,
+	}}
+	result, err := r.collections.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, mongoError(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteCollection удаляет коллекцию и затем отвязывает ее элементы. Отвязка выполняется и для уже
+// удаленной коллекции: если она сорвалась, повторный вызов дочищает элементы.
+func (r *favoriteRepository) DeleteCollection(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	result, err := r.collections.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return false, err
+	}
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "collection_id": id.Hex()},
+		bson.M{"$unset": bson.M{"collection_id": ""}})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *favoriteRepository) ReorderCollections(ctx context.Context, userID string, ids []primitive.ObjectID) error {
+	return r.reorder(ctx, r.collections, bson.M{"user_id": userID}, ids)
+}
+
+// reorder проставляет позиции документам по filter в порядке ids. На наборе реплик и mongos сверка
+// и запись идут в одной транзакции: при несовпадении набора не записывается ничего, а параллельная
+// правка тех же документов повторяет транзакцию. Одиночный mongod транзакций не поддерживает —
+// там позиции пишутся без нее с гарантиями writePositions; это выясняется на первой перестановке.
+func (r *favoriteRepository) reorder(ctx context.Context, collection *mongo.Collection, filter bson.M, ids []primitive.ObjectID) error {
+	if !r.noTransactions.Load() {
+		err := r.inTransaction(ctx, func(ctx context.Context) error {
+			return writePositions(ctx, collection, filter, ids)
+		})
+		if !transactionsUnsupported(err) {
+			return err
 		}
+		r.noTransactions.Store(true)
+		r.logger.Info("MongoDB does not support transactions, reordering favorites without them")
+	}
+	return writePositions(ctx, collection, filter, ids)
+}
 
-		favorites = append(favorites, favorite)
+func (r *favoriteRepository) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
 	}
+	defer session.EndSession(ctx)
 
-	if err := cursor.Err(); err != nil {
-		return nil, err
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+func transactionsUnsupported(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(illegalOperationCode)
+}
+
+// writePositions сверяет ids с _id документов по filter до любой записи и одним пакетом проставляет позиции.
+// Вне транзакции каждое обновление повторяет filter, поэтому документ, который успели перенести,
+// не трогается, а после записи набор сверяется еще раз. Если набор поменялся параллельно,
+// возвращается ErrReorderMismatch; часть позиций могла записаться, но позиции — только ключ
+// сортировки, и повтор запроса с актуальным набором расставит все заново.
+func writePositions(ctx context.Context, collection *mongo.Collection, filter bson.M, ids []primitive.ObjectID) error {
+	stored, err := documentIDs(ctx, collection, filter)
+	if err != nil {
+		return err
+	}
+	if !SameIDs(stored, ids) {
+		return ErrReorderMismatch
+	}
+	if len(ids) == 0 {
+		return nil
 	}
 
-	if favorites == nil {
-		favorites = []models.FavoriteGroup{}
+	writes := make([]mongo.WriteModel, len(ids))
+	for position, id := range ids {
+		guarded := bson.M{"_id": id}
+		maps.Copy(guarded, filter)
+		writes[position] = mongo.NewUpdateOneModel().
+			SetFilter(guarded).
+			SetUpdate(bson.M{"$set": bson.M{"position": position}})
+	}
+	result, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount != int64(len(ids)) {
+		return ErrReorderMismatch
 	}
 
+	// Добавленный за это время документ обновления не заметят — его ловит повторная сверка
+	if stored, err = documentIDs(ctx, collection, filter); err != nil {
+		return err
+	}
+	if !SameIDs(stored, ids) {
+		return ErrReorderMismatch
+	}
+	return nil
+}
+
+func documentIDs(ctx context.Context, collection *mongo.Collection, filter bson.M) ([]primitive.ObjectID, error) {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
+func decodeFavorites(ctx context.Context, cursor *mongo.Cursor) ([]models.Favorite, error) {
+	favorites := []models.Favorite{}
+	if err := cursor.All(ctx, &favorites); err != nil {
+		return nil, err
+	}
 	return favorites, nil
 }
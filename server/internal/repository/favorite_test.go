@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestTransactionsUnsupported(t *testing.T) {
+	standalone := mongo.CommandError{Code: illegalOperationCode, Name: "IllegalOperation", Message: "Transaction numbers are only allowed on a replica set member or mongos"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"standalone mongod", standalone, true},
+		{"wrapped", fmt.Errorf("find: %w", standalone), true},
+		{"write conflict", mongo.CommandError{Code: 112, Name: "WriteConflict"}, false},
+		{"reorder mismatch", ErrReorderMismatch, false},
+		{"other error", errors.New("connection reset"), false},
+		{"no error", nil, false},
+	}
+	for _, tt := range tests {
+		if got := transactionsUnsupported(tt.err); got != tt.want {
+			t.Errorf("%s: transactionsUnsupported(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
@@ -13,8 +13,12 @@ import (
 
 type GroupRepository interface {
 	GetByNumber(ctx context.Context, groupNumber string) (*models.StoredGroup, error)
+	// GetByNumbers возвращает группы с этими номерами одним запросом; отсутствующие номера пропускаются,
+	// из групп с одинаковым номером последней идет обновленная позже
+	GetByNumbers(ctx context.Context, groupNumbers []string) ([]models.StoredGroup, error)
 	GetByID(ctx context.Context, id int) (*models.StoredGroup, error)
 	GetAll(ctx context.Context) ([]models.StoredGroup, error)
+	Count(ctx context.Context) (int64, error)
 	Save(ctx context.Context, group *models.StoredGroup) error
 	SaveMany(ctx context.Context, groups []models.StoredGroup) error
 	Update(ctx context.Context, group *models.StoredGroup) error
@@ -69,8 +73,19 @@ func (r *groupRepository) GetByID(ctx context.Context, id int) (*models.StoredGr
 	return &group, nil
 }
 
+func (r *groupRepository) GetByNumbers(ctx context.Context, groupNumbers []string) ([]models.StoredGroup, error) {
+	if len(groupNumbers) == 0 {
+		return nil, nil
+	}
+	return r.find(ctx, bson.M{"group_data.name": bson.M{"$in": groupNumbers}}, options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}))
+}
+
 func (r *groupRepository) GetAll(ctx context.Context) ([]models.StoredGroup, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	return r.find(ctx, bson.M{})
+}
+
+func (r *groupRepository) find(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]models.StoredGroup, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,9 +113,13 @@ func (r *groupRepository) GetAll(ctx context.Context) ([]models.StoredGroup, err
 	return groups, nil
 }
 
+func (r *groupRepository) Count(ctx context.Context) (int64, error) {
+	return r.collection.EstimatedDocumentCount(ctx)
+}
+
 func (r *groupRepository) Save(ctx context.Context, group *models.StoredGroup) error {
 	_, err := r.collection.InsertOne(ctx, group)
-	return err
+	return mongoError(err)
 }
 
 func (r *groupRepository) SaveMany(ctx context.Context, groups []models.StoredGroup) error {
@@ -115,7 +134,7 @@ func (r *groupRepository) SaveMany(ctx context.Context, groups []models.StoredGr
 
 	opts := options.InsertMany().SetOrdered(false)
 	_, err := r.collection.InsertMany(ctx, docs, opts)
-	return err
+	return mongoError(err)
 }
 
 func (r *groupRepository) Update(ctx context.Context, group *models.StoredGroup) error {
@@ -135,7 +154,7 @@ func (r *groupRepository) Update(ctx context.Context, group *models.StoredGroup)
 
 	opts := options.UpdateOne().SetUpsert(true)
 	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	return mongoError(err)
 }
 
 func (r *groupRepository) Delete(ctx context.Context, id int) error {
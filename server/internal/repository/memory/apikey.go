@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type apiKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]*models.APIKey // по префиксу
+}
+
+func NewAPIKeyRepository() repository.APIKeyRepository {
+	return &apiKeyRepository{
+		keys: make(map[string]*models.APIKey),
+	}
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.keys[key.Prefix]; ok {
+		return repository.ErrDuplicate
+	}
+	for _, existing := range r.keys {
+		if existing.KeyHash == key.KeyHash {
+			return repository.ErrDuplicate
+		}
+	}
+
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+	copied := *key
+	r.keys[key.Prefix] = &copied
+	return nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, prefix string, revokedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[prefix]
+	if !ok || key.RevokedAt != nil {
+		return false, nil
+	}
+	key.RevokedAt = &revokedAt
+	return true, nil
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type employeeRepository struct {
+	mu        sync.RWMutex
+	employees map[int]*models.StoredEmployee // по BSUIRID
+}
+
+func NewEmployeeRepository() repository.EmployeeRepository {
+	return &employeeRepository{
+		employees: make(map[int]*models.StoredEmployee),
+	}
+}
+
+func (r *employeeRepository) GetByURLID(ctx context.Context, urlID string) (*models.StoredEmployee, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if employee := r.byURLID(urlID); employee != nil {
+		return copyEmployee(employee), nil
+	}
+	return nil, nil
+}
+
+func (r *employeeRepository) GetByURLIDs(ctx context.Context, urlIDs []string) ([]models.StoredEmployee, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(urlIDs))
+	for _, urlID := range urlIDs {
+		wanted[urlID] = true
+	}
+	var employees []models.StoredEmployee
+	for _, id := range r.sortedIDs() {
+		if employee := r.employees[id]; wanted[employee.URLID] {
+			employees = append(employees, *copyEmployee(employee))
+		}
+	}
+	return employees, nil
+}
+
+func (r *employeeRepository) GetByID(ctx context.Context, id int) (*models.StoredEmployee, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return copyEmployee(r.employees[id]), nil
+}
+
+func (r *employeeRepository) GetAll(ctx context.Context) ([]models.StoredEmployee, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var employees []models.StoredEmployee
+	for _, id := range r.sortedIDs() {
+		employees = append(employees, *copyEmployee(r.employees[id]))
+	}
+	return employees, nil
+}
+
+func (r *employeeRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.employees)), nil
+}
+
+func (r *employeeRepository) Save(ctx context.Context, employee *models.StoredEmployee) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.insert(employee)
+}
+
+// SaveMany, как неупорядоченная вставка в MongoDB, сохраняет всех преподавателей без конфликтов
+// и возвращает ErrDuplicate, если конфликты были
+func (r *employeeRepository) SaveMany(ctx context.Context, employees []models.StoredEmployee) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var duplicate error
+	for i := range employees {
+		if err := r.insert(&employees[i]); err != nil {
+			duplicate = err
+		}
+	}
+	return duplicate
+}
+
+// Update создает или заменяет преподавателя с тем же BSUIRID, сохраняя исходные ID и CreatedAt.
+// URL ID должен оставаться уникальным и среди остальных преподавателей.
+func (r *employeeRepository) Update(ctx context.Context, employee *models.StoredEmployee) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if other := r.byURLID(employee.URLID); other != nil && other.BSUIRID != employee.BSUIRID {
+		return repository.ErrDuplicate
+	}
+
+	stored := copyEmployee(employee)
+	if existing, ok := r.employees[employee.BSUIRID]; ok {
+		stored.ID = existing.ID
+		stored.CreatedAt = existing.CreatedAt
+	} else {
+		stored.ID = primitive.NewObjectID()
+	}
+	r.employees[employee.BSUIRID] = stored
+	return nil
+}
+
+func (r *employeeRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.employees, id)
+	return nil
+}
+
+func (r *employeeRepository) insert(employee *models.StoredEmployee) error {
+	if _, ok := r.employees[employee.BSUIRID]; ok {
+		return repository.ErrDuplicate
+	}
+	if r.byURLID(employee.URLID) != nil {
+		return repository.ErrDuplicate
+	}
+	if employee.ID.IsZero() {
+		employee.ID = primitive.NewObjectID()
+	}
+	r.employees[employee.BSUIRID] = copyEmployee(employee)
+	return nil
+}
+
+func (r *employeeRepository) byURLID(urlID string) *models.StoredEmployee {
+	for _, employee := range r.employees {
+		if employee.URLID == urlID {
+			return employee
+		}
+	}
+	return nil
+}
+
+func (r *employeeRepository) sortedIDs() []int {
+	ids := make([]int, 0, len(r.employees))
+	for id := range r.employees {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func copyEmployee(employee *models.StoredEmployee) *models.StoredEmployee {
+	if employee == nil {
+		return nil
+	}
+	copied := *employee
+	copied.EmployeeData = cloneJSON(employee.EmployeeData)
+	return &copied
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type eventRepository struct {
+	mu     sync.RWMutex
+	events map[primitive.ObjectID]*models.Event
+}
+
+func NewEventRepository() repository.EventRepository {
+	return &eventRepository{
+		events: make(map[primitive.ObjectID]*models.Event),
+	}
+}
+
+func (r *eventRepository) GetByUser(ctx context.Context, userID string) ([]models.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := []models.Event{}
+	for _, event := range r.events {
+		if event.UserID == userID {
+			events = append(events, *event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].ID.Hex() < events[j].ID.Hex()
+	})
+	return events, nil
+}
+
+func (r *eventRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	event, ok := r.events[id]
+	if !ok || event.UserID != userID {
+		return nil, nil
+	}
+	copied := *event
+	return &copied, nil
+}
+
+func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	if _, ok := r.events[event.ID]; ok {
+		return repository.ErrDuplicate
+	}
+	copied := *event
+	r.events[event.ID] = &copied
+	return nil
+}
+
+func (r *eventRepository) Update(ctx context.Context, event *models.Event) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.events[event.ID]
+	if !ok || stored.UserID != event.UserID {
+		return false, nil
+	}
+	updated := *event
+	updated.CreatedAt = stored.CreatedAt
+	r.events[event.ID] = &updated
+	return true, nil
+}
+
+func (r *eventRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[id]
+	if !ok || event.UserID != userID {
+		return false, nil
+	}
+	delete(r.events, id)
+	return true, nil
+}
@@ -0,0 +1,268 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// searchLimit совпадает с $limit в поиске MongoDB
+const searchLimit = 100
+
+type favoriteKey struct {
+	userID       string
+	favoriteType string
+	key          string
+}
+
+type favoriteRepository struct {
+	mu          sync.RWMutex
+	favorites   map[favoriteKey]*models.Favorite
+	collections map[primitive.ObjectID]*models.FavoriteCollection
+}
+
+func NewFavoriteRepository() repository.FavoriteRepository {
+	return &favoriteRepository{
+		favorites:   make(map[favoriteKey]*models.Favorite),
+		collections: make(map[primitive.ObjectID]*models.FavoriteCollection),
+	}
+}
+
+func (r *favoriteRepository) GetAll(ctx context.Context, userID string, favoriteType string) ([]models.Favorite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(userID, favoriteType, func(models.Favorite) bool { return true }, 0), nil
+}
+
+func (r *favoriteRepository) Get(ctx context.Context, userID string, favoriteType string, key string) (*models.Favorite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	favorite, ok := r.favorites[favoriteKey{userID, favoriteType, key}]
+	if !ok {
+		return nil, nil
+	}
+	copied := *favorite
+	return &copied, nil
+}
+
+// Search ищет без учета регистра по подстроке ключа, как regex-поиск MongoDB без поискового индекса
+func (r *favoriteRepository) Search(ctx context.Context, userID string, favoriteType string, query string) ([]models.Favorite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query = strings.ToLower(query)
+	return r.find(userID, favoriteType, func(f models.Favorite) bool {
+		return strings.Contains(strings.ToLower(f.Key), query)
+	}, searchLimit), nil
+}
+
+// Add добавляет элемент в избранное; повторное добавление обновляет UpdatedAt, сохраняя CreatedAt
+func (r *favoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := favoriteKey{favorite.UserID, favorite.Type, favorite.Key}
+	stored := *favorite
+	if existing, ok := r.favorites[key]; ok {
+		existing.UpdatedAt = favorite.UpdatedAt
+		return nil
+	}
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+	r.favorites[key] = &stored
+	return nil
+}
+
+func (r *favoriteRepository) UpdateDetails(ctx context.Context, favorite *models.Favorite) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.favorites[favoriteKey{favorite.UserID, favorite.Type, favorite.Key}]
+	if !ok {
+		return false, nil
+	}
+	existing.CollectionID = favorite.CollectionID
+	existing.Label = favorite.Label
+	existing.Color = favorite.Color
+	existing.Pinned = favorite.Pinned
+	existing.Position = favorite.Position
+	existing.UpdatedAt = favorite.UpdatedAt
+	return true, nil
+}
+
+func (r *favoriteRepository) Delete(ctx context.Context, userID string, favoriteType string, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.favorites, favoriteKey{userID, favoriteType, key})
+	return nil
+}
+
+func (r *favoriteRepository) IsFavorite(ctx context.Context, userID string, favoriteType string, key string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.favorites[favoriteKey{userID, favoriteType, key}]
+	return ok, nil
+}
+
+// find возвращает избранное пользователя в порядке models.FavoriteLess; limit 0 — без ограничения
+func (r *favoriteRepository) find(userID, favoriteType string, match func(models.Favorite) bool, limit int) []models.Favorite {
+	favorites := []models.Favorite{}
+	for key, favorite := range r.favorites {
+		if key.userID == userID && (favoriteType == "" || key.favoriteType == favoriteType) && match(*favorite) {
+			favorites = append(favorites, *favorite)
+		}
+	}
+
+	sort.Slice(favorites, func(i, j int) bool {
+		return models.FavoriteLess(favorites[i], favorites[j])
+	})
+
+	if limit > 0 && len(favorites) > limit {
+		favorites = favorites[:limit]
+	}
+	return favorites
+}
+
+func (r *favoriteRepository) Reorder(ctx context.Context, userID string, collectionID string, ids []primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byID := make(map[primitive.ObjectID]*models.Favorite)
+	var stored []primitive.ObjectID
+	for key, favorite := range r.favorites {
+		if key.userID == userID && favorite.CollectionID == collectionID {
+			byID[favorite.ID] = favorite
+			stored = append(stored, favorite.ID)
+		}
+	}
+	if !repository.SameIDs(stored, ids) {
+		return repository.ErrReorderMismatch
+	}
+
+	for position, id := range ids {
+		byID[id].Position = int64(position)
+	}
+	return nil
+}
+
+func (r *favoriteRepository) GetCollections(ctx context.Context, userID string) ([]models.FavoriteCollection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collections := []models.FavoriteCollection{}
+	for _, collection := range r.collections {
+		if collection.UserID == userID {
+			collections = append(collections, *collection)
+		}
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		if collections[i].Position != collections[j].Position {
+			return collections[i].Position < collections[j].Position
+		}
+		return collections[i].Name < collections[j].Name
+	})
+	return collections, nil
+}
+
+func (r *favoriteRepository) GetCollection(ctx context.Context, userID string, id primitive.ObjectID) (*models.FavoriteCollection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.collections[id]
+	if !ok || collection.UserID != userID {
+		return nil, nil
+	}
+	copied := *collection
+	return &copied, nil
+}
+
+func (r *favoriteRepository) CreateCollection(ctx context.Context, collection *models.FavoriteCollection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hasCollectionName(collection.UserID, collection.Name, primitive.NilObjectID) {
+		return fmt.Errorf("%w: collection %q", repository.ErrDuplicate, collection.Name)
+	}
+
+	stored := *collection
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+	collection.ID = stored.ID
+	r.collections[stored.ID] = &stored
+	return nil
+}
+
+func (r *favoriteRepository) UpdateCollection(ctx context.Context, collection *models.FavoriteCollection) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.collections[collection.ID]
+	if !ok || existing.UserID != collection.UserID {
+		return false, nil
+	}
+	if r.hasCollectionName(collection.UserID, collection.Name, collection.ID) {
+		return false, fmt.Errorf("%w: collection %q", repository.ErrDuplicate, collection.Name)
+	}
+	existing.Name = collection.Name
+	existing.Color = collection.Color
+	existing.UpdatedAt = collection.UpdatedAt
+	return true, nil
+}
+
+func (r *favoriteRepository) DeleteCollection(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.collections[id]
+	if !ok || existing.UserID != userID {
+		return false, nil
+	}
+	delete(r.collections, id)
+
+	for key, favorite := range r.favorites {
+		if key.userID == userID && favorite.CollectionID == id.Hex() {
+			favorite.CollectionID = ""
+		}
+	}
+	return true, nil
+}
+
+func (r *favoriteRepository) ReorderCollections(ctx context.Context, userID string, ids []primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stored []primitive.ObjectID
+	for id, collection := range r.collections {
+		if collection.UserID == userID {
+			stored = append(stored, id)
+		}
+	}
+	if !repository.SameIDs(stored, ids) {
+		return repository.ErrReorderMismatch
+	}
+
+	for position, id := range ids {
+		r.collections[id].Position = int64(position)
+	}
+	return nil
+}
+
+func (r *favoriteRepository) hasCollectionName(userID, name string, except primitive.ObjectID) bool {
+	for id, collection := range r.collections {
+		if id != except && collection.UserID == userID && collection.Name == name {
+			return true
+		}
+	}
+	return false
+}
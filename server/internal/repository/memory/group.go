@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type groupRepository struct {
+	mu     sync.RWMutex
+	groups map[int]*models.StoredGroup // по BSUIRID
+}
+
+func NewGroupRepository() repository.GroupRepository {
+	return &groupRepository{
+		groups: make(map[int]*models.StoredGroup),
+	}
+}
+
+func (r *groupRepository) GetByNumber(ctx context.Context, groupNumber string) (*models.StoredGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.sortedIDs() {
+		if group := r.groups[id]; group.GroupData.Name == groupNumber {
+			return copyGroup(group), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *groupRepository) GetByNumbers(ctx context.Context, groupNumbers []string) ([]models.StoredGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(groupNumbers))
+	for _, groupNumber := range groupNumbers {
+		wanted[groupNumber] = true
+	}
+	var groups []models.StoredGroup
+	for _, id := range r.sortedIDs() {
+		if group := r.groups[id]; wanted[group.GroupData.Name] {
+			groups = append(groups, *copyGroup(group))
+		}
+	}
+	return groups, nil
+}
+
+func (r *groupRepository) GetByID(ctx context.Context, id int) (*models.StoredGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return copyGroup(r.groups[id]), nil
+}
+
+func (r *groupRepository) GetAll(ctx context.Context) ([]models.StoredGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var groups []models.StoredGroup
+	for _, id := range r.sortedIDs() {
+		groups = append(groups, *copyGroup(r.groups[id]))
+	}
+	return groups, nil
+}
+
+func (r *groupRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.groups)), nil
+}
+
+func (r *groupRepository) Save(ctx context.Context, group *models.StoredGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.insert(group)
+}
+
+// SaveMany, как неупорядоченная вставка в MongoDB, сохраняет все группы без конфликтов
+// и возвращает ErrDuplicate, если конфликты были
+func (r *groupRepository) SaveMany(ctx context.Context, groups []models.StoredGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var duplicate error
+	for i := range groups {
+		if err := r.insert(&groups[i]); err != nil {
+			duplicate = err
+		}
+	}
+	return duplicate
+}
+
+// Update создает или заменяет группу с тем же BSUIRID, сохраняя исходные ID и CreatedAt
+func (r *groupRepository) Update(ctx context.Context, group *models.StoredGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyGroup(group)
+	if existing, ok := r.groups[group.BSUIRID]; ok {
+		stored.ID = existing.ID
+		stored.CreatedAt = existing.CreatedAt
+	} else {
+		stored.ID = primitive.NewObjectID()
+	}
+	r.groups[group.BSUIRID] = stored
+	return nil
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.groups, id)
+	return nil
+}
+
+func (r *groupRepository) insert(group *models.StoredGroup) error {
+	if _, ok := r.groups[group.BSUIRID]; ok {
+		return repository.ErrDuplicate
+	}
+	if group.ID.IsZero() {
+		group.ID = primitive.NewObjectID()
+	}
+	r.groups[group.BSUIRID] = copyGroup(group)
+	return nil
+}
+
+func (r *groupRepository) sortedIDs() []int {
+	ids := make([]int, 0, len(r.groups))
+	for id := range r.groups {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func copyGroup(group *models.StoredGroup) *models.StoredGroup {
+	if group == nil {
+		return nil
+	}
+	copied := *group
+	copied.GroupData = cloneJSON(group.GroupData)
+	return &copied
+}
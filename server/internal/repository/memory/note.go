@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type noteRepository struct {
+	mu    sync.RWMutex
+	notes map[primitive.ObjectID]*models.LessonNote
+}
+
+func NewNoteRepository() repository.NoteRepository {
+	return &noteRepository{
+		notes: make(map[primitive.ObjectID]*models.LessonNote),
+	}
+}
+
+func (r *noteRepository) GetByUser(ctx context.Context, userID string, filter repository.NoteFilter) ([]models.LessonNote, error) {
+	notes := r.collect(userID, filter.Match)
+	repository.SortNotes(notes)
+	return notes, nil
+}
+
+func (r *noteRepository) GetHomework(ctx context.Context, userID string, filter repository.HomeworkFilter) ([]models.LessonNote, error) {
+	notes := r.collect(userID, filter.Match)
+	repository.SortHomework(notes)
+	return notes, nil
+}
+
+func (r *noteRepository) collect(userID string, match func(*models.LessonNote) bool) []models.LessonNote {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notes := []models.LessonNote{}
+	for _, note := range r.notes {
+		if note.UserID == userID && match(note) {
+			notes = append(notes, cloneNote(note))
+		}
+	}
+	return notes
+}
+
+func (r *noteRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonNote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	note, ok := r.notes[id]
+	if !ok || note.UserID != userID {
+		return nil, nil
+	}
+	copied := cloneNote(note)
+	return &copied, nil
+}
+
+func (r *noteRepository) Create(ctx context.Context, note *models.LessonNote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if note.ID.IsZero() {
+		note.ID = primitive.NewObjectID()
+	}
+	if _, ok := r.notes[note.ID]; ok {
+		return repository.ErrDuplicate
+	}
+	copied := cloneNote(note)
+	r.notes[note.ID] = &copied
+	return nil
+}
+
+func (r *noteRepository) Update(ctx context.Context, note *models.LessonNote) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.notes[note.ID]
+	if !ok || stored.UserID != note.UserID {
+		return false, nil
+	}
+	updated := cloneNote(note)
+	stored.Text = updated.Text
+	stored.Attachments = updated.Attachments
+	stored.DueAt = updated.DueAt
+	stored.Done = updated.Done
+	stored.DoneAt = updated.DoneAt
+	stored.UpdatedAt = updated.UpdatedAt
+	return true, nil
+}
+
+func (r *noteRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, ok := r.notes[id]
+	if !ok || note.UserID != userID {
+		return false, nil
+	}
+	delete(r.notes, id)
+	return true, nil
+}
+
+// cloneNote копирует запись вместе со списком вложений и сроками
+func cloneNote(note *models.LessonNote) models.LessonNote {
+	copied := *note
+	copied.Attachments = slices.Clone(note.Attachments)
+	if note.DueAt != nil {
+		dueAt := *note.DueAt
+		copied.DueAt = &dueAt
+	}
+	if note.DoneAt != nil {
+		doneAt := *note.DoneAt
+		copied.DoneAt = &doneAt
+	}
+	return copied
+}
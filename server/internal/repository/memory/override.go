@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type overrideRepository struct {
+	mu        sync.RWMutex
+	overrides map[primitive.ObjectID]*models.LessonOverride
+}
+
+func NewOverrideRepository() repository.OverrideRepository {
+	return &overrideRepository{
+		overrides: make(map[primitive.ObjectID]*models.LessonOverride),
+	}
+}
+
+func (r *overrideRepository) GetByUser(ctx context.Context, userID string) ([]models.LessonOverride, error) {
+	return r.collect(func(override *models.LessonOverride) bool {
+		return override.UserID == userID
+	}), nil
+}
+
+func (r *overrideRepository) GetBySchedule(ctx context.Context, userID, scheduleType, key string) ([]models.LessonOverride, error) {
+	return r.collect(func(override *models.LessonOverride) bool {
+		return override.UserID == userID && override.ScheduleType == scheduleType && override.Key == key
+	}), nil
+}
+
+func (r *overrideRepository) collect(match func(*models.LessonOverride) bool) []models.LessonOverride {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overrides := []models.LessonOverride{}
+	for _, override := range r.overrides {
+		if match(override) {
+			overrides = append(overrides, cloneOverride(override))
+		}
+	}
+	repository.SortOverrides(overrides)
+	return overrides
+}
+
+func (r *overrideRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	override, ok := r.overrides[id]
+	if !ok || override.UserID != userID {
+		return nil, nil
+	}
+	copied := cloneOverride(override)
+	return &copied, nil
+}
+
+func (r *overrideRepository) Create(ctx context.Context, override *models.LessonOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if override.ID.IsZero() {
+		override.ID = primitive.NewObjectID()
+	}
+	if _, ok := r.overrides[override.ID]; ok {
+		return repository.ErrDuplicate
+	}
+	copied := cloneOverride(override)
+	r.overrides[override.ID] = &copied
+	return nil
+}
+
+func (r *overrideRepository) Update(ctx context.Context, override *models.LessonOverride) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.overrides[override.ID]
+	if !ok || stored.UserID != override.UserID {
+		return false, nil
+	}
+	updated := cloneOverride(override)
+	updated.CreatedAt = stored.CreatedAt
+	r.overrides[override.ID] = &updated
+	return true, nil
+}
+
+func (r *overrideRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	override, ok := r.overrides[id]
+	if !ok || override.UserID != userID {
+		return false, nil
+	}
+	delete(r.overrides, id)
+	return true, nil
+}
+
+// cloneOverride копирует правку вместе со списком аудиторий, чтобы вызывающий не менял хранимое
+func cloneOverride(override *models.LessonOverride) models.LessonOverride {
+	copied := *override
+	copied.Auditories = slices.Clone(override.Auditories)
+	return copied
+}
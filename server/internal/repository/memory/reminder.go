@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type reminderRepository struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*models.ReminderSubscription
+	// sent — время отметки по user_id и ключу напоминания
+	sent map[string]map[string]time.Time
+}
+
+func NewReminderRepository() repository.ReminderRepository {
+	return &reminderRepository{
+		subscriptions: make(map[string]*models.ReminderSubscription),
+		sent:          make(map[string]map[string]time.Time),
+	}
+}
+
+func (r *reminderRepository) GetSubscriptions(ctx context.Context) ([]models.ReminderSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := make([]models.ReminderSubscription, 0, len(r.subscriptions))
+	for _, subscription := range r.subscriptions {
+		subscriptions = append(subscriptions, cloneSubscription(subscription))
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].UserID < subscriptions[j].UserID
+	})
+	return subscriptions, nil
+}
+
+func (r *reminderRepository) GetSubscription(ctx context.Context, userID string) (*models.ReminderSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscription, ok := r.subscriptions[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := cloneSubscription(subscription)
+	return &copied, nil
+}
+
+func (r *reminderRepository) SaveSubscription(ctx context.Context, subscription *models.ReminderSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.subscriptions[subscription.UserID]; ok {
+		subscription.ID, subscription.CreatedAt = stored.ID, stored.CreatedAt
+	} else if subscription.ID.IsZero() {
+		subscription.ID = primitive.NewObjectID()
+	}
+	copied := cloneSubscription(subscription)
+	r.subscriptions[subscription.UserID] = &copied
+	return nil
+}
+
+func (r *reminderRepository) DeleteSubscription(ctx context.Context, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[userID]; !ok {
+		return false, nil
+	}
+	delete(r.subscriptions, userID)
+	return true, nil
+}
+
+func (r *reminderRepository) MarkSent(ctx context.Context, userID, key string, sentAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sent, ok := r.sent[userID]
+	if !ok {
+		sent = make(map[string]time.Time)
+		r.sent[userID] = sent
+	}
+	if _, ok := sent[key]; ok {
+		return false, nil
+	}
+	sent[key] = sentAt
+	return true, nil
+}
+
+func (r *reminderRepository) UnmarkSent(ctx context.Context, userID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sent[userID], key)
+	return nil
+}
+
+func (r *reminderRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for userID, sent := range r.sent {
+		for key, sentAt := range sent {
+			if sentAt.Before(before) {
+				delete(sent, key)
+				deleted++
+			}
+		}
+		if len(sent) == 0 {
+			delete(r.sent, userID)
+		}
+	}
+	return deleted, nil
+}
+
+// cloneSubscription копирует подписку вместе с тихими часами
+func cloneSubscription(subscription *models.ReminderSubscription) models.ReminderSubscription {
+	copied := *subscription
+	if subscription.QuietHours != nil {
+		quiet := *subscription.QuietHours
+		copied.QuietHours = &quiet
+	}
+	return copied
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type scheduleRepository struct {
+	mu         sync.RWMutex
+	byGroup    map[string]*models.StoredSchedule
+	byEmployee map[string]*models.StoredSchedule
+}
+
+func NewScheduleRepository() repository.ScheduleRepository {
+	return &scheduleRepository{
+		byGroup:    make(map[string]*models.StoredSchedule),
+		byEmployee: make(map[string]*models.StoredSchedule),
+	}
+}
+
+func (r *scheduleRepository) GetByGroupNumber(ctx context.Context, groupNumber string) (*models.StoredSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return copySchedule(r.byGroup[groupNumber]), nil
+}
+
+func (r *scheduleRepository) GetByEmployeeURLID(ctx context.Context, urlID string) (*models.StoredSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return copySchedule(r.byEmployee[urlID]), nil
+}
+
+func (r *scheduleRepository) Save(ctx context.Context, schedule *models.StoredSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.existing(schedule) != nil {
+		return repository.ErrDuplicate
+	}
+
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+	if schedule.ID.IsZero() {
+		schedule.ID = primitive.NewObjectID()
+	}
+	r.put(copySchedule(schedule))
+	return nil
+}
+
+// Update создает или заменяет расписание группы либо преподавателя
+func (r *scheduleRepository) Update(ctx context.Context, schedule *models.StoredSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	schedule.UpdatedAt = time.Now()
+
+	stored := copySchedule(schedule)
+	if existing := r.existing(schedule); existing != nil {
+		stored.ID = existing.ID
+		stored.CreatedAt = existing.CreatedAt
+	} else {
+		stored.ID = primitive.NewObjectID()
+		stored.CreatedAt = schedule.UpdatedAt
+	}
+	r.put(stored)
+	return nil
+}
+
+func (r *scheduleRepository) Delete(ctx context.Context, groupNumber string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byGroup, groupNumber)
+	return nil
+}
+
+func (r *scheduleRepository) DeleteByEmployeeURLID(ctx context.Context, urlID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byEmployee, urlID)
+	return nil
+}
+
+func (r *scheduleRepository) existing(schedule *models.StoredSchedule) *models.StoredSchedule {
+	if schedule.GroupNumber != "" {
+		if stored, ok := r.byGroup[schedule.GroupNumber]; ok {
+			return stored
+		}
+	}
+	if schedule.EmployeeURLID != "" {
+		if stored, ok := r.byEmployee[schedule.EmployeeURLID]; ok {
+			return stored
+		}
+	}
+	return nil
+}
+
+func (r *scheduleRepository) put(schedule *models.StoredSchedule) {
+	if schedule.GroupNumber != "" {
+		r.byGroup[schedule.GroupNumber] = schedule
+	}
+	if schedule.EmployeeURLID != "" {
+		r.byEmployee[schedule.EmployeeURLID] = schedule
+	}
+}
+
+func copySchedule(schedule *models.StoredSchedule) *models.StoredSchedule {
+	if schedule == nil {
+		return nil
+	}
+	copied := *schedule
+	copied.ScheduleData = cloneJSON(schedule.ScheduleData)
+	return &copied
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+type shareRepository struct {
+	mu     sync.RWMutex
+	shares map[primitive.ObjectID]*models.Share
+}
+
+func NewShareRepository() repository.ShareRepository {
+	return &shareRepository{
+		shares: make(map[primitive.ObjectID]*models.Share),
+	}
+}
+
+func (r *shareRepository) GetByHash(ctx context.Context, tokenHash string) (*models.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, share := range r.shares {
+		if share.TokenHash == tokenHash {
+			copied := *share
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *shareRepository) GetByUser(ctx context.Context, userID string) ([]models.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shares := []models.Share{}
+	for _, share := range r.shares {
+		if share.UserID == userID {
+			shares = append(shares, *share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+	})
+	return shares, nil
+}
+
+func (r *shareRepository) Create(ctx context.Context, share *models.Share) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.shares {
+		if existing.TokenHash == share.TokenHash {
+			return repository.ErrDuplicate
+		}
+	}
+
+	if share.ID.IsZero() {
+		share.ID = primitive.NewObjectID()
+	}
+	copied := *share
+	r.shares[share.ID] = &copied
+	return nil
+}
+
+func (r *shareRepository) Revoke(ctx context.Context, userID string, id primitive.ObjectID, revokedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	share, ok := r.shares[id]
+	if !ok || share.UserID != userID || share.RevokedAt != nil {
+		return false, nil
+	}
+	share.RevokedAt = &revokedAt
+	return true, nil
+}
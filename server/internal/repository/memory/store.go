@@ -0,0 +1,69 @@
+// Package memory — хранилище в памяти процесса для локальной разработки и тестов.
+// Данные теряются при перезапуске; ограничения уникальности те же, что у индексов MongoDB.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"schedluer/internal/repository"
+)
+
+type store struct {
+	schedules repository.ScheduleRepository
+	groups    repository.GroupRepository
+	employees repository.EmployeeRepository
+	favorites repository.FavoriteRepository
+	apiKeys   repository.APIKeyRepository
+	shares    repository.ShareRepository
+	events    repository.EventRepository
+	notes     repository.NoteRepository
+	overrides repository.OverrideRepository
+	reminders repository.ReminderRepository
+}
+
+func NewStore() repository.Store {
+	return &store{
+		schedules: NewScheduleRepository(),
+		groups:    NewGroupRepository(),
+		employees: NewEmployeeRepository(),
+		favorites: NewFavoriteRepository(),
+		apiKeys:   NewAPIKeyRepository(),
+		shares:    NewShareRepository(),
+		events:    NewEventRepository(),
+		notes:     NewNoteRepository(),
+		overrides: NewOverrideRepository(),
+		reminders: NewReminderRepository(),
+	}
+}
+
+func (s *store) Schedules() repository.ScheduleRepository { return s.schedules }
+func (s *store) Groups() repository.GroupRepository       { return s.groups }
+func (s *store) Employees() repository.EmployeeRepository { return s.employees }
+func (s *store) Favorites() repository.FavoriteRepository { return s.favorites }
+func (s *store) APIKeys() repository.APIKeyRepository     { return s.apiKeys }
+func (s *store) Shares() repository.ShareRepository       { return s.shares }
+func (s *store) Events() repository.EventRepository       { return s.events }
+func (s *store) Notes() repository.NoteRepository         { return s.notes }
+func (s *store) Overrides() repository.OverrideRepository { return s.overrides }
+func (s *store) Reminders() repository.ReminderRepository { return s.reminders }
+
+func (s *store) Driver() string { return "memory" }
+
+func (s *store) Health(ctx context.Context) error { return nil }
+
+func (s *store) Close(ctx context.Context) error { return nil }
+
+// cloneJSON делает глубокую копию данных из API БГУИРа, чтобы вызывающий код
+// не мог изменить сохраненное значение через общие слайсы и map'ы — как при чтении из БД
+func cloneJSON[T any](value T) T {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var clone T
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return value
+	}
+	return clone
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"schedluer/internal/repository"
+	"schedluer/internal/repository/memory"
+	"schedluer/internal/repository/repotest"
+)
+
+func TestStoreConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.Store {
+		return memory.NewStore()
+	})
+}
@@ -0,0 +1,450 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"schedluer/internal/models"
+)
+
+// mongoMigration — версия данных MongoDB. Примененные версии записываются в schema_migrations,
+// поэтому каждая миграция выполняется один раз; новые добавляются в конец списка.
+type mongoMigration struct {
+	version int
+	name    string
+	up      func(ctx context.Context, db *mongo.Database, logger *logrus.Logger) error
+}
+
+var mongoMigrations = []mongoMigration{
+	{version: 1, name: "legacy_favorite_groups", up: migrateLegacyFavorites},
+	{version: 2, name: "normalize_object_ids", up: normalizeObjectIDs},
+}
+
+const (
+	// migrationLease — срок блокировки: если процесс упал посреди миграций, другой подхватит их после истечения
+	migrationLease = 10 * time.Minute
+	// migrationLockRetry — пауза между попытками взять занятую блокировку
+	migrationLockRetry = time.Second
+)
+
+type appliedMigration struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// MongoMigrations возвращает все известные миграции MongoDB и отметку о применении каждой
+func MongoMigrations(ctx context.Context, db *mongo.Database) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(mongoMigrations))
+	for _, migration := range mongoMigrations {
+		status := MigrationStatus{Version: migration.version, Name: migration.name}
+		if record, ok := applied[migration.version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// MigrateMongo применяет непримененные миграции по порядку версий и возвращает их количество.
+// Несколько экземпляров сервиса могут стартовать одновременно: миграции выполняет тот,
+// кто взял блокировку, остальные ждут ее и затем видят уже примененные версии.
+func MigrateMongo(ctx context.Context, db *mongo.Database, logger *logrus.Logger) (int, error) {
+	release, err := lockMigrations(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range mongoMigrations {
+		if _, ok := applied[migration.version]; ok {
+			continue
+		}
+
+		started := time.Now()
+		if err := migration.up(ctx, db, logger); err != nil {
+			return count, fmt.Errorf("migration %d_%s failed: %w", migration.version, migration.name, err)
+		}
+		record := appliedMigration{Version: migration.version, Name: migration.name, AppliedAt: time.Now().UTC()}
+		if _, err := db.Collection("schema_migrations").InsertOne(ctx, record); err != nil {
+			return count, fmt.Errorf("failed to record migration %d: %w", migration.version, err)
+		}
+
+		logger.WithFields(logrus.Fields{
+			"version":  migration.version,
+			"name":     migration.name,
+			"duration": time.Since(started).String(),
+		}).Info("Applied MongoDB migration")
+		count++
+	}
+	return count, nil
+}
+
+func appliedMigrations(ctx context.Context, db *mongo.Database) (map[int]appliedMigration, error) {
+	cursor, err := db.Collection("schema_migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// lockMigrations берет блокировку-аренду в migration_lock. Upsert с условием на истекший срок
+// либо забирает свободную блокировку, либо падает на дубликате _id, если ее держит другой процесс.
+func lockMigrations(ctx context.Context, db *mongo.Database) (func(), error) {
+	locks := db.Collection("migration_lock")
+	owner := bson.NewObjectID()
+	host, _ := os.Hostname()
+
+	for {
+		now := time.Now()
+		filter := bson.M{"_id": "migrations", "expires_at": bson.M{"$lt": now}}
+		update := bson.M{"$set": bson.M{"owner": owner, "host": host, "expires_at": now.Add(migrationLease)}}
+		_, err := locks.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("failed to lock migrations: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to lock migrations: %w", ctx.Err())
+		case <-time.After(migrationLockRetry):
+		}
+	}
+
+	return func() {
+		// Снимаем блокировку и после отмены ctx, иначе следующий запуск прождет всю аренду
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		_, _ = locks.DeleteOne(ctx, bson.M{"_id": "migrations", "owner": owner})
+	}, nil
+}
+
+// objectIDCollections — коллекции, где _id читается в primitive.ObjectID моделей
+var objectIDCollections = []string{
+	"schedules",
+	"groups",
+	"employees",
+	"favorites",
+	"favorite_collections",
+	"api_keys",
+	"shares",
+}
+
+// normalizeObjectIDs приводит _id к BSON ObjectID. Драйвер v2 без кодека из database.Registry
+// писал primitive.ObjectID бинарными данными, а в части старых документов _id сохранен массивом
+// из 12 чисел; такие документы модели не читают. Байты идентификатора сохраняются, поэтому
+// ссылки на документ (например, collection_id избранного) остаются верными.
+func normalizeObjectIDs(ctx context.Context, db *mongo.Database, logger *logrus.Logger) error {
+	for _, name := range objectIDCollections {
+		count, err := normalizeCollectionIDs(ctx, db.Collection(name))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if count > 0 {
+			logger.WithFields(logrus.Fields{"collection": name, "count": count}).Info("Normalized document ids")
+		}
+	}
+	return nil
+}
+
+// idBackup — копия документа до переписывания _id вместе с новым идентификатором
+type idBackup struct {
+	NewID    bson.ObjectID `bson:"_id"`
+	OldID    any           `bson:"old_id"`
+	Document bson.D        `bson:"document"`
+}
+
+// normalizeCollectionIDs переписывает документы с _id не-ObjectID. _id менять нельзя, поэтому документ
+// удаляется и вставляется заново (удаление идет первым, чтобы копия не упала на уникальных индексах).
+// Транзакций нет, поэтому сначала все такие документы копируются в <коллекция>_id_backup вместе
+// с новым _id, и только потом переписываются оригиналы. Если процесс упал посередине, повторный запуск
+// миграции дописывает документы из копии; копия удаляется, когда все документы на месте.
+func normalizeCollectionIDs(ctx context.Context, collection *mongo.Collection) (int, error) {
+	backups := collection.Database().Collection(collection.Name() + "_id_backup")
+
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$not": bson.M{"$type": "objectId"}}})
+	if err != nil {
+		return 0, err
+	}
+	var documents []bson.D
+	if err := cursor.All(ctx, &documents); err != nil {
+		return 0, err
+	}
+	for _, document := range documents {
+		oldID := documentID(document)
+		// $setOnInsert: у копии, оставшейся от прерванного запуска, новый _id уже выбран
+		update := bson.M{"$setOnInsert": bson.M{"_id": toObjectID(oldID), "document": document}}
+		if _, err := backups.UpdateOne(ctx, bson.M{"old_id": oldID}, update, options.UpdateOne().SetUpsert(true)); err != nil {
+			return 0, fmt.Errorf("failed to back up document %v: %w", oldID, err)
+		}
+	}
+
+	cursor, err = backups.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	var entries []idBackup
+	if err := cursor.All(ctx, &entries); err != nil {
+		return 0, err
+	}
+
+	normalizedCount := 0
+	for _, entry := range entries {
+		if err := collection.FindOne(ctx, bson.M{"_id": entry.NewID}).Err(); err == nil {
+			continue
+		} else if !errors.Is(err, mongo.ErrNoDocuments) {
+			return normalizedCount, err
+		}
+
+		normalized := withID(entry.Document, entry.NewID)
+
+		if _, err := collection.DeleteOne(ctx, bson.M{"_id": entry.OldID}); err != nil {
+			return normalizedCount, err
+		}
+		if _, err := collection.InsertOne(ctx, normalized); err != nil {
+			if _, restoreErr := collection.InsertOne(ctx, entry.Document); restoreErr != nil {
+				return normalizedCount, errors.Join(err, fmt.Errorf("failed to restore document %v (a copy is kept in %s): %w", entry.OldID, backups.Name(), restoreErr))
+			}
+			return normalizedCount, err
+		}
+		normalizedCount++
+	}
+
+	if len(entries) > 0 {
+		if err := backups.Drop(ctx); err != nil {
+			return normalizedCount, fmt.Errorf("failed to drop %s: %w", backups.Name(), err)
+		}
+	}
+	return normalizedCount, nil
+}
+
+// withID возвращает копию документа с замененным _id; исходный документ не меняется
+func withID(document bson.D, id bson.ObjectID) bson.D {
+	normalized := slices.Clone(document)
+	for i := range normalized {
+		if normalized[i].Key == "_id" {
+			normalized[i].Value = id
+		}
+	}
+	return normalized
+}
+
+func documentID(document bson.D) any {
+	for _, element := range document {
+		if element.Key == "_id" {
+			return element.Value
+		}
+	}
+	return nil
+}
+
+// toObjectID переводит сохраненный _id в ObjectID с теми же байтами, а если байты
+// восстановить нельзя — выдает новый идентификатор
+func toObjectID(value any) bson.ObjectID {
+	var id bson.ObjectID
+	switch value := value.(type) {
+	case bson.Binary:
+		if len(value.Data) == len(id) {
+			copy(id[:], value.Data)
+			return id
+		}
+	case bson.A:
+		if len(value) != len(id) {
+			break
+		}
+		for i, item := range value {
+			b, ok := byteValue(item)
+			if !ok {
+				return bson.NewObjectID()
+			}
+			id[i] = b
+		}
+		return id
+	}
+	return bson.NewObjectID()
+}
+
+func byteValue(value any) (byte, bool) {
+	var n int64
+	switch value := value.(type) {
+	case int32:
+		n = int64(value)
+	case int64:
+		n = value
+	case float64:
+		if value != float64(int64(value)) {
+			return 0, false
+		}
+		n = int64(value)
+	default:
+		return 0, false
+	}
+	if n < 0 || n > 255 {
+		return 0, false
+	}
+	return byte(n), true
+}
+
+// legacyFavorite — избранная группа в старой коллекции favorite_groups
+type legacyFavorite struct {
+	// _id берем как есть, только чтобы пометить документ: в части старых документов он сохранен
+	// массивом, а в favorites у перенесенных записей будут свои идентификаторы
+	ID          any       `bson:"_id"`
+	GroupNumber string    `bson:"group_number"`
+	UserID      string    `bson:"user_id"`
+	CreatedAt   time.Time `bson:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+}
+
+// legacyFavoriteUpsert — запись в favorites для одного документа favorite_groups
+type legacyFavoriteUpsert struct {
+	legacyID any
+	filter   bson.M
+	update   bson.M
+}
+
+// migrateLegacyFavorites переносит избранные группы из старой коллекции favorite_groups в favorites.
+// Перенесенные документы помечаются migrated_at: если миграция прервалась, повторный запуск
+// их пропустит, и удаленное после переноса избранное не вернется.
+func migrateLegacyFavorites(ctx context.Context, db *mongo.Database, logger *logrus.Logger) error {
+	legacy := db.Collection("favorite_groups")
+	favorites := db.Collection("favorites")
+
+	// Старая коллекция невелика, поэтому читаем ее целиком: позиции раздаются по порядку добавления
+	cursor, err := legacy.Find(ctx, bson.M{"migrated_at": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			return
+		}
+	}(cursor, ctx)
+
+	var documents []legacyFavorite
+	for cursor.Next(ctx) {
+		var favorite legacyFavorite
+		if err := cursor.Decode(&favorite); err != nil {
+			logger.WithError(err).Warn("Failed to decode legacy favorite document")
+			continue
+		}
+		documents = append(documents, favorite)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(documents) == 0 {
+		return nil
+	}
+
+	positions, err := maxFavoritePositions(ctx, favorites)
+	if err != nil {
+		return err
+	}
+
+	migrated := 0
+	for _, upsert := range legacyFavoriteUpserts(documents, positions) {
+		if _, err := favorites.UpdateOne(ctx, upsert.filter, upsert.update, options.UpdateOne().SetUpsert(true)); err != nil {
+			return err
+		}
+		if _, err := legacy.UpdateOne(ctx, bson.M{"_id": upsert.legacyID}, bson.M{"$set": bson.M{"migrated_at": time.Now()}}); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		logger.WithField("count", migrated).Info("Migrated favorite groups to typed favorites")
+	}
+	return nil
+}
+
+// maxFavoritePositions возвращает наибольшую позицию избранного каждого пользователя
+func maxFavoritePositions(ctx context.Context, favorites *mongo.Collection) (map[string]int64, error) {
+	cursor, err := favorites.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "position": bson.M{"$max": "$position"}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		UserID   string `bson:"_id"`
+		Position int64  `bson:"position"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	positions := make(map[string]int64, len(results))
+	for _, result := range results {
+		positions[result.UserID] = result.Position
+	}
+	return positions, nil
+}
+
+// legacyFavoriteUpserts готовит перенос старых групп. Группы пользователя встают после его текущего
+// избранного (maxPositions) в порядке created_at; документы без номера группы пропускаются.
+// Уже добавленную в favorites группу upsert не трогает.
+func legacyFavoriteUpserts(documents []legacyFavorite, maxPositions map[string]int64) []legacyFavoriteUpsert {
+	sorted := slices.Clone(documents)
+	slices.SortStableFunc(sorted, func(a, b legacyFavorite) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	positions := maps.Clone(maxPositions)
+	if positions == nil {
+		positions = make(map[string]int64)
+	}
+	upserts := make([]legacyFavoriteUpsert, 0, len(sorted))
+	for _, favorite := range sorted {
+		if favorite.GroupNumber == "" {
+			continue
+		}
+		positions[favorite.UserID]++
+		upserts = append(upserts, legacyFavoriteUpsert{
+			legacyID: favorite.ID,
+			filter:   bson.M{"user_id": favorite.UserID, "type": models.FavoriteTypeGroup, "key": favorite.GroupNumber},
+			update: bson.M{"$setOnInsert": bson.M{
+				"position":   positions[favorite.UserID],
+				"created_at": favorite.CreatedAt,
+				"updated_at": favorite.UpdatedAt,
+			}},
+		})
+	}
+	return upserts
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"schedluer/internal/models"
+)
+
+// Преобразования миграций проверяются без MongoDB; сами миграции на настоящей базе — в TestMongoMigrations
+func TestToObjectID(t *testing.T) {
+	id := bson.NewObjectID()
+	int32Bytes, int64Bytes, floatBytes := bson.A{}, bson.A{}, bson.A{}
+	for _, b := range id {
+		int32Bytes = append(int32Bytes, int32(b))
+		int64Bytes = append(int64Bytes, int64(b))
+		floatBytes = append(floatBytes, float64(b))
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		keep  bool
+	}{
+		{"binary", bson.Binary{Data: id[:]}, true},
+		{"array of int32", int32Bytes, true},
+		{"array of int64", int64Bytes, true},
+		{"array of doubles", floatBytes, true},
+		{"short binary", bson.Binary{Data: id[:4]}, false},
+		{"short array", bson.A{int32(1), int32(2), int32(3)}, false},
+		{"byte out of range", append(bson.A{int32(256)}, int32Bytes[1:]...), false},
+		{"fractional byte", append(bson.A{1.5}, int32Bytes[1:]...), false},
+		{"string", id.Hex(), false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toObjectID(tt.value)
+			if tt.keep && got != id {
+				t.Errorf("toObjectID = %s, want the same bytes %s", got.Hex(), id.Hex())
+			}
+			if !tt.keep && (got.IsZero() || got == id) {
+				t.Errorf("toObjectID = %s, want a new id", got.Hex())
+			}
+		})
+	}
+}
+
+func TestWithID(t *testing.T) {
+	oldID := bson.A{int32(1), int32(2), int32(3)}
+	document := bson.D{{Key: "token", Value: "abc"}, {Key: "_id", Value: oldID}, {Key: "user_id", Value: "user-1"}}
+	newID := bson.NewObjectID()
+
+	normalized := withID(document, newID)
+	if id := documentID(normalized); id != newID {
+		t.Errorf("normalized _id = %v, want %s", id, newID.Hex())
+	}
+	if len(normalized) != len(document) || normalized[0] != document[0] || normalized[2] != document[2] {
+		t.Errorf("other fields changed: %v", normalized)
+	}
+	// Исходный документ остается в резервной копии и при ошибке вставки возвращается как был
+	if id, ok := documentID(document).(bson.A); !ok || len(id) != len(oldID) {
+		t.Errorf("original document was modified: %v", document)
+	}
+}
+
+func TestLegacyFavoriteUpserts(t *testing.T) {
+	day := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	documents := []legacyFavorite{
+		{ID: "c", UserID: "user-1", GroupNumber: "221703", CreatedAt: day.Add(3 * time.Hour)},
+		{ID: "a", UserID: "user-1", GroupNumber: "221701", CreatedAt: day.Add(time.Hour)},
+		{ID: "x", UserID: "user-2", GroupNumber: "", CreatedAt: day},
+		{ID: "d", UserID: "user-2", GroupNumber: "221704", CreatedAt: day.Add(2 * time.Hour)},
+		{ID: "b", UserID: "user-1", GroupNumber: "221702", CreatedAt: day.Add(2 * time.Hour)},
+	}
+	maxPositions := map[string]int64{"user-1": 1725148800000, "user-3": 7}
+
+	upserts := legacyFavoriteUpserts(documents, maxPositions)
+	want := []struct {
+		legacyID, userID, key string
+		position              int64
+	}{
+		{"a", "user-1", "221701", 1725148800001},
+		// Одинаковое время добавления — в порядке старой коллекции
+		{"d", "user-2", "221704", 1},
+		{"b", "user-1", "221702", 1725148800002},
+		{"c", "user-1", "221703", 1725148800003},
+	}
+	if len(upserts) != len(want) {
+		t.Fatalf("upserts = %+v, want %d", upserts, len(want))
+	}
+	for i, w := range want {
+		upsert := upserts[i]
+		if upsert.legacyID != w.legacyID {
+			t.Errorf("upsert %d is for %v, want %s", i, upsert.legacyID, w.legacyID)
+		}
+		if upsert.filter["user_id"] != w.userID || upsert.filter["type"] != models.FavoriteTypeGroup || upsert.filter["key"] != w.key {
+			t.Errorf("upsert %d filter = %v", i, upsert.filter)
+		}
+		fields, _ := upsert.update["$setOnInsert"].(bson.M)
+		if fields["position"] != w.position {
+			t.Errorf("upsert %d (%s) position = %v, want %d", i, w.key, fields["position"], w.position)
+		}
+	}
+	if maxPositions["user-1"] != 1725148800000 || len(maxPositions) != 2 {
+		t.Errorf("maxPositions was modified: %v", maxPositions)
+	}
+	if upserts := legacyFavoriteUpserts(documents[:1], nil); len(upserts) != 1 {
+		t.Errorf("upserts without existing favorites = %+v", upserts)
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/pkg/database"
+)
+
+type mongoStore struct {
+	db *database.MongoDB
+
+	schedules ScheduleRepository
+	groups    GroupRepository
+	employees EmployeeRepository
+	favorites FavoriteRepository
+	apiKeys   APIKeyRepository
+	shares    ShareRepository
+	events    EventRepository
+	notes     NoteRepository
+	overrides OverrideRepository
+	reminders ReminderRepository
+}
+
+// migrationTimeout ограничивает миграции при старте вместе с ожиданием чужой блокировки
+const migrationTimeout = 15 * time.Minute
+
+// NewMongoStore применяет миграции данных и возвращает хранилище. Без миграций модели
+// не прочитают документы со старыми _id, поэтому ошибка миграции не дает стартовать.
+func NewMongoStore(db *database.MongoDB, logger *logrus.Logger) (Store, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+	if _, err := MigrateMongo(ctx, db.Database, logger); err != nil {
+		return nil, fmt.Errorf("failed to migrate MongoDB: %w", err)
+	}
+
+	return &mongoStore{
+		db:        db,
+		schedules: NewScheduleRepository(db.Database),
+		groups:    NewGroupRepository(db.Database),
+		employees: NewEmployeeRepository(db.Database),
+		favorites: NewFavoriteRepository(db.Database, logger),
+		apiKeys:   NewAPIKeyRepository(db.Database),
+		shares:    NewShareRepository(db.Database),
+		events:    NewEventRepository(db.Database),
+		notes:     NewNoteRepository(db.Database),
+		overrides: NewOverrideRepository(db.Database),
+		reminders: NewReminderRepository(db.Database),
+	}, nil
+}
+
+func (s *mongoStore) Schedules() ScheduleRepository { return s.schedules }
+func (s *mongoStore) Groups() GroupRepository       { return s.groups }
+func (s *mongoStore) Employees() EmployeeRepository { return s.employees }
+func (s *mongoStore) Favorites() FavoriteRepository { return s.favorites }
+func (s *mongoStore) APIKeys() APIKeyRepository     { return s.apiKeys }
+func (s *mongoStore) Shares() ShareRepository       { return s.shares }
+func (s *mongoStore) Events() EventRepository       { return s.events }
+func (s *mongoStore) Notes() NoteRepository         { return s.notes }
+func (s *mongoStore) Overrides() OverrideRepository { return s.overrides }
+func (s *mongoStore) Reminders() ReminderRepository { return s.reminders }
+
+func (s *mongoStore) Migrations(ctx context.Context) ([]MigrationStatus, error) {
+	return MongoMigrations(ctx, s.db.Database)
+}
+
+func (s *mongoStore) Driver() string { return "mongodb" }
+
+func (s *mongoStore) Health(ctx context.Context) error {
+	return s.db.Health(ctx)
+}
+
+func (s *mongoStore) Close(ctx context.Context) error {
+	return s.db.Close(ctx)
+}
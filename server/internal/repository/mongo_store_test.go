@@ -0,0 +1,151 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"schedluer/internal/repository"
+	"schedluer/internal/repository/repotest"
+	"schedluer/pkg/database"
+)
+
+// Набор прогоняется против настоящей MongoDB, только если задан SCHEDLUER_TEST_MONGODB_URI,
+// например mongodb://localhost:27017. Каждый тест получает свою базу, которая удаляется после него.
+func TestMongoStoreConformance(t *testing.T) {
+	uri := os.Getenv("SCHEDLUER_TEST_MONGODB_URI")
+	if uri == "" {
+		t.Skip("SCHEDLUER_TEST_MONGODB_URI is not set")
+	}
+
+	repotest.Run(t, func(t *testing.T) repository.Store {
+		mongoDB := connectMongo(t, uri)
+		store, err := repository.NewMongoStore(mongoDB, logrus.StandardLogger())
+		if err != nil {
+			t.Fatalf("NewMongoStore: %v", err)
+		}
+		return store
+	})
+}
+
+func TestMongoMigrations(t *testing.T) {
+	uri := os.Getenv("SCHEDLUER_TEST_MONGODB_URI")
+	if uri == "" {
+		t.Skip("SCHEDLUER_TEST_MONGODB_URI is not set")
+	}
+	ctx := context.Background()
+	mongoDB := connectMongo(t, uri)
+
+	// Документы в том виде, в каком их оставили старые версии: _id массивом и бинарными данными,
+	// плюс группа со старой схемой избранного
+	id := primitive.NewObjectID()
+	collectionID := primitive.NewObjectID()
+	arrayID := bson.A{}
+	for _, b := range id {
+		arrayID = append(arrayID, int32(b))
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	favorites := mongoDB.Database.Collection("favorites")
+	_, err := favorites.InsertOne(ctx, bson.M{"_id": arrayID, "user_id": "user-1", "type": "group", "key": "221701", "created_at": now, "updated_at": now})
+	mustInsert(t, err)
+	_, err = mongoDB.Database.Collection("favorite_collections").InsertOne(ctx, bson.M{
+		"_id": bson.Binary{Data: collectionID[:]}, "user_id": "user-1", "name": "Учеба", "created_at": now, "updated_at": now,
+	})
+	mustInsert(t, err)
+	// Запуск, упавший между удалением документа и вставкой копии: документ остался только в резервной копии
+	shareID := primitive.NewObjectID()
+	_, err = mongoDB.Database.Collection("shares_id_backup").InsertOne(ctx, bson.M{
+		"_id": shareID, "old_id": bson.Binary{Data: shareID[:]},
+		"document": bson.D{{Key: "_id", Value: bson.Binary{Data: shareID[:]}}, {Key: "token", Value: "resumed"}},
+	})
+	mustInsert(t, err)
+	_, err = mongoDB.Database.Collection("favorite_groups").InsertOne(ctx, bson.M{"_id": bson.A{1, 2, 3}, "user_id": "user-1", "group_number": "221702", "created_at": now})
+	mustInsert(t, err)
+	// Старые группы встают после уже имеющегося избранного в порядке добавления, а не вставки
+	_, err = favorites.InsertOne(ctx, bson.M{"_id": primitive.NewObjectID(), "user_id": "user-2", "type": "employee", "key": "i-ivanov", "position": 5, "created_at": now, "updated_at": now})
+	mustInsert(t, err)
+	_, err = mongoDB.Database.Collection("favorite_groups").InsertMany(ctx, []any{
+		bson.M{"user_id": "user-2", "group_number": "221704", "created_at": now.Add(time.Hour)},
+		bson.M{"user_id": "user-2", "group_number": "221703", "created_at": now},
+	})
+	mustInsert(t, err)
+
+	store, err := repository.NewMongoStore(mongoDB, logrus.StandardLogger())
+	if err != nil {
+		t.Fatalf("NewMongoStore: %v", err)
+	}
+
+	items, err := store.Favorites().GetAll(ctx, "user-1", "")
+	if err != nil {
+		t.Fatalf("GetAll after migration: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != id || items[1].Key != "221702" {
+		t.Errorf("unexpected favorites after migration: %+v", items)
+	}
+	items, err = store.Favorites().GetAll(ctx, "user-2", "")
+	if err != nil {
+		t.Fatalf("GetAll after migration: %v", err)
+	}
+	if len(items) != 3 || items[0].Key != "i-ivanov" || items[1].Key != "221703" || items[1].Position != 6 || items[2].Key != "221704" || items[2].Position != 7 {
+		t.Errorf("legacy favorites were not appended in created_at order: %+v", items)
+	}
+	collections, err := store.Favorites().GetCollections(ctx, "user-1")
+	if err != nil || len(collections) != 1 || collections[0].ID != collectionID {
+		t.Errorf("collection id was not preserved: %+v, %v", collections, err)
+	}
+
+	var share bson.M
+	if err := mongoDB.Database.Collection("shares").FindOne(ctx, bson.M{"_id": shareID}).Decode(&share); err != nil || share["token"] != "resumed" {
+		t.Errorf("interrupted normalization was not resumed: %v, %v", share, err)
+	}
+	if names, err := mongoDB.Database.ListCollectionNames(ctx, bson.M{"name": bson.M{"$regex": "_id_backup$"}}); err != nil || len(names) != 0 {
+		t.Errorf("backup collections were not dropped: %v, %v", names, err)
+	}
+
+	migrations, err := store.(repository.Migrator).Migrations(ctx)
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+	for _, migration := range migrations {
+		if migration.AppliedAt == nil {
+			t.Errorf("migration %d_%s is not applied", migration.Version, migration.Name)
+		}
+	}
+
+	// Повторный запуск ничего не применяет
+	if count, err := repository.MigrateMongo(ctx, mongoDB.Database, logrus.StandardLogger()); err != nil || count != 0 {
+		t.Errorf("second run applied %d migrations, %v", count, err)
+	}
+}
+
+// connectMongo подключается к отдельной базе теста, которая удаляется после него
+func connectMongo(t *testing.T, uri string) *database.MongoDB {
+	t.Helper()
+	mongoDB, err := database.NewMongoDB(database.Config{
+		URI:         uri,
+		Database:    "schedluer_test_" + primitive.NewObjectID().Hex(),
+		Timeout:     10 * time.Second,
+		MaxPoolSize: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx := context.Background()
+		_ = mongoDB.Database.Drop(ctx)
+		_ = mongoDB.Close(ctx)
+	})
+	return mongoDB
+}
+
+func mustInsert(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("failed to insert fixture: %v", err)
+	}
+}
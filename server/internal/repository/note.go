@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"schedluer/internal/models"
+)
+
+// NoteFilter сужает список заметок к занятиям; пустые поля не фильтруют
+type NoteFilter struct {
+	GroupNumber string
+	// Date — дата занятия, YYYY-MM-DD
+	Date string
+}
+
+// HomeworkFilter сужает список домашних заданий
+type HomeworkFilter struct {
+	// DueBefore — только задания со сроком раньше; нулевое значение — все
+	DueBefore   time.Time
+	IncludeDone bool
+}
+
+// Match проверяет запись по фильтру — для хранилищ, которые фильтруют сами (bolt, memory)
+func (f NoteFilter) Match(note *models.LessonNote) bool {
+	return (f.GroupNumber == "" || note.Lesson.GroupNumber == f.GroupNumber) &&
+		(f.Date == "" || note.Lesson.Date == f.Date)
+}
+
+func (f HomeworkFilter) Match(note *models.LessonNote) bool {
+	if note.Kind != models.NoteKindHomework || (note.Done && !f.IncludeDone) {
+		return false
+	}
+	return f.DueBefore.IsZero() || (note.DueAt != nil && note.DueAt.Before(f.DueBefore))
+}
+
+// SortNotes упорядочивает заметки как GetByUser
+func SortNotes(notes []models.LessonNote) {
+	sort.Slice(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		if a.Lesson.Date != b.Lesson.Date {
+			return a.Lesson.Date < b.Lesson.Date
+		}
+		if a.Lesson.Start != b.Lesson.Start {
+			return a.Lesson.Start < b.Lesson.Start
+		}
+		return createdBefore(a, b)
+	})
+}
+
+// SortHomework упорядочивает задания как GetHomework: задания без срока — первыми, как null в MongoDB
+func SortHomework(notes []models.LessonNote) {
+	sort.Slice(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		switch {
+		case a.DueAt == nil && b.DueAt != nil:
+			return true
+		case a.DueAt != nil && b.DueAt == nil:
+			return false
+		case a.DueAt != nil && !a.DueAt.Equal(*b.DueAt):
+			return a.DueAt.Before(*b.DueAt)
+		}
+		return createdBefore(a, b)
+	})
+}
+
+func createdBefore(a, b models.LessonNote) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID.Hex() < b.ID.Hex()
+}
+
+// NoteRepository хранит заметки и домашние задания пользователей к занятиям.
+// Запись видна и меняется только владельцем.
+type NoteRepository interface {
+	// GetByUser возвращает заметки и задания по дате и времени занятия, затем по времени создания
+	GetByUser(ctx context.Context, userID string, filter NoteFilter) ([]models.LessonNote, error)
+	// GetHomework возвращает домашние задания по сроку сдачи
+	GetHomework(ctx context.Context, userID string, filter HomeworkFilter) ([]models.LessonNote, error)
+	GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonNote, error)
+	Create(ctx context.Context, note *models.LessonNote) error
+	// Update сохраняет текст, вложения, срок и отметку о выполнении; false, если записи нет
+	Update(ctx context.Context, note *models.LessonNote) (bool, error)
+	Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+}
+
+type noteRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNoteRepository(db *mongo.Database) NoteRepository {
+	collection := db.Collection("lesson_notes")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "lesson.date", Value: 1}, {Key: "lesson.start", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "due_at", Value: 1}},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &noteRepository{
+		collection: collection,
+	}
+}
+
+func (r *noteRepository) GetByUser(ctx context.Context, userID string, filter NoteFilter) ([]models.LessonNote, error) {
+	query := bson.M{"user_id": userID}
+	if filter.GroupNumber != "" {
+		query["lesson.group_number"] = filter.GroupNumber
+	}
+	if filter.Date != "" {
+		query["lesson.date"] = filter.Date
+	}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "lesson.date", Value: 1},
+		{Key: "lesson.start", Value: 1},
+		{Key: "created_at", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+	return r.find(ctx, query, opts)
+}
+
+func (r *noteRepository) GetHomework(ctx context.Context, userID string, filter HomeworkFilter) ([]models.LessonNote, error) {
+	query := bson.M{"user_id": userID, "kind": models.NoteKindHomework}
+	if !filter.DueBefore.IsZero() {
+		query["due_at"] = bson.M{"$lt": filter.DueBefore}
+	}
+	if !filter.IncludeDone {
+		query["done"] = false
+	}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "due_at", Value: 1},
+		{Key: "created_at", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+	return r.find(ctx, query, opts)
+}
+
+func (r *noteRepository) find(ctx context.Context, query bson.M, opts *options.FindOptionsBuilder) ([]models.LessonNote, error) {
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []models.LessonNote{}
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (r *noteRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonNote, error) {
+	var note models.LessonNote
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&note)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (r *noteRepository) Create(ctx context.Context, note *models.LessonNote) error {
+	if note.ID.IsZero() {
+		note.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, note)
+	return mongoError(err)
+}
+
+func (r *noteRepository) Update(ctx context.Context, note *models.LessonNote) (bool, error) {
+	filter := bson.M{"_id": note.ID, "user_id": note.UserID}
+	update := bson.M{"$set": bson.M{
+		"text":        note.Text,
+		"attachments": note.Attachments,
+		"due_at":      note.DueAt,
+		"done":        note.Done,
+		"done_at":     note.DoneAt,
+		"updated_at":  note.UpdatedAt,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *noteRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
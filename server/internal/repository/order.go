@@ -0,0 +1,21 @@
+package repository
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// SameIDs сообщает, совпадает ли список для перестановки с сохраненными элементами: без повторов и пропусков
+func SameIDs(stored, ids []primitive.ObjectID) bool {
+	if len(stored) != len(ids) {
+		return false
+	}
+	seen := make(map[primitive.ObjectID]bool, len(stored))
+	for _, id := range stored {
+		seen[id] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
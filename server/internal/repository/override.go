@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"schedluer/internal/models"
+)
+
+// OverrideRepository хранит личные правки расписания. Правки отдаются в порядке создания:
+// при наложении более поздняя перекрывает более раннюю.
+type OverrideRepository interface {
+	GetByUser(ctx context.Context, userID string) ([]models.LessonOverride, error)
+	// GetBySchedule возвращает правки пользователя к одному расписанию
+	GetBySchedule(ctx context.Context, userID, scheduleType, key string) ([]models.LessonOverride, error)
+	GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonOverride, error)
+	Create(ctx context.Context, override *models.LessonOverride) error
+	// Update заменяет правку целиком, CreatedAt остается прежним; false, если правки нет
+	Update(ctx context.Context, override *models.LessonOverride) (bool, error)
+	Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+}
+
+// SortOverrides упорядочивает правки как GetByUser — для хранилищ, которые сортируют сами (bolt, memory)
+func SortOverrides(overrides []models.LessonOverride) {
+	sort.Slice(overrides, func(i, j int) bool {
+		a, b := overrides[i], overrides[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	})
+}
+
+type overrideRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOverrideRepository(db *mongo.Database) OverrideRepository {
+	collection := db.Collection("lesson_overrides")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "schedule_type", Value: 1}, {Key: "key", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &overrideRepository{
+		collection: collection,
+	}
+}
+
+func (r *overrideRepository) GetByUser(ctx context.Context, userID string) ([]models.LessonOverride, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *overrideRepository) GetBySchedule(ctx context.Context, userID, scheduleType, key string) ([]models.LessonOverride, error) {
+	return r.find(ctx, bson.M{"user_id": userID, "schedule_type": scheduleType, "key": key})
+}
+
+func (r *overrideRepository) find(ctx context.Context, filter bson.M) ([]models.LessonOverride, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := []models.LessonOverride{}
+	if err := cursor.All(ctx, &overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func (r *overrideRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonOverride, error) {
+	var override models.LessonOverride
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&override)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *overrideRepository) Create(ctx context.Context, override *models.LessonOverride) error {
+	if override.ID.IsZero() {
+		override.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, override)
+	return mongoError(err)
+}
+
+func (r *overrideRepository) Update(ctx context.Context, override *models.LessonOverride) (bool, error) {
+	filter := bson.M{"_id": override.ID, "user_id": override.UserID}
+	update := bson.M{"$set": bson.M{
+		"schedule_type": override.ScheduleType,
+		"key":           override.Key,
+		"series":        override.Series,
+		"hidden":        override.Hidden,
+		"auditories":    override.Auditories,
+		"start_time":    override.StartTime,
+		"end_time":      override.EndTime,
+		"updated_at":    override.UpdatedAt,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *overrideRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
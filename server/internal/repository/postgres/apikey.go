@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"schedluer/internal/models"
+)
+
+const apiKeyColumns = `id, name, prefix, key_hash, role, user_id, created_at, revoked_at`
+
+type apiKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, keyHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return key, err
+}
+
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	key.ID = newID(key.ID)
+	_, err := r.pool.Exec(ctx, `INSERT INTO api_keys (`+apiKeyColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		key.ID.Hex(), key.Name, key.Prefix, key.KeyHash, key.Role, key.UserID, key.CreatedAt, key.RevokedAt)
+	return dbError(err)
+}
+
+// Revoke отзывает ключ; false, если ключа нет или он уже отозван
+func (r *apiKeyRepository) Revoke(ctx context.Context, prefix string, revokedAt time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE api_keys SET revoked_at = $2 WHERE prefix = $1 AND revoked_at IS NULL`, prefix, revokedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var (
+		key models.APIKey
+		id  string
+	)
+	if err := row.Scan(&id, &key.Name, &key.Prefix, &key.KeyHash, &key.Role, &key.UserID, &key.CreatedAt, &key.RevokedAt); err != nil {
+		return nil, err
+	}
+	key.ID = parseID(id)
+	return &key, nil
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+)
+
+const employeeColumns = `id, bsuir_id, url_id, employee_data, last_update_date, created_at, updated_at`
+
+type employeeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *employeeRepository) GetByURLID(ctx context.Context, urlID string) (*models.StoredEmployee, error) {
+	return r.getOne(ctx, `SELECT `+employeeColumns+` FROM employees WHERE url_id = $1`, urlID)
+}
+
+func (r *employeeRepository) GetByID(ctx context.Context, id int) (*models.StoredEmployee, error) {
+	return r.getOne(ctx, `SELECT `+employeeColumns+` FROM employees WHERE bsuir_id = $1`, id)
+}
+
+func (r *employeeRepository) GetByURLIDs(ctx context.Context, urlIDs []string) ([]models.StoredEmployee, error) {
+	return r.getMany(ctx, `SELECT `+employeeColumns+` FROM employees WHERE url_id = ANY($1) ORDER BY bsuir_id`, urlIDs)
+}
+
+func (r *employeeRepository) GetAll(ctx context.Context) ([]models.StoredEmployee, error) {
+	return r.getMany(ctx, `SELECT `+employeeColumns+` FROM employees ORDER BY bsuir_id`)
+}
+
+func (r *employeeRepository) getMany(ctx context.Context, query string, args ...any) ([]models.StoredEmployee, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var employees []models.StoredEmployee
+	for rows.Next() {
+		employee, err := scanEmployee(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		employees = append(employees, *employee)
+	}
+	return employees, rows.Err()
+}
+
+func (r *employeeRepository) Count(ctx context.Context) (count int64, err error) {
+	err = r.pool.QueryRow(ctx, `SELECT count(*) FROM employees`).Scan(&count)
+	return count, err
+}
+
+func (r *employeeRepository) Save(ctx context.Context, employee *models.StoredEmployee) error {
+	employee.ID = newID(employee.ID)
+	_, err := r.pool.Exec(ctx, `INSERT INTO employees (`+employeeColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)`, employeeArgs(employee)...)
+	return dbError(err)
+}
+
+// SaveMany, как неупорядоченная вставка в MongoDB, сохраняет всех преподавателей без конфликтов
+// и возвращает ErrDuplicate, если конфликты были
+func (r *employeeRepository) SaveMany(ctx context.Context, employees []models.StoredEmployee) error {
+	if len(employees) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for i := range employees {
+		employees[i].ID = newID(employees[i].ID)
+		batch.Queue(`INSERT INTO employees (`+employeeColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT DO NOTHING`, employeeArgs(&employees[i])...)
+	}
+	return execMany(ctx, r.pool, batch)
+}
+
+// Update создает или заменяет преподавателя с тем же BSUIRID, сохраняя исходные ID и CreatedAt.
+// Если url_id уже занят другим преподавателем, возвращает ErrDuplicate.
+func (r *employeeRepository) Update(ctx context.Context, employee *models.StoredEmployee) error {
+	stored := *employee
+	stored.ID = primitive.NewObjectID()
+
+	_, err := r.pool.Exec(ctx, `INSERT INTO employees (`+employeeColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (bsuir_id) DO UPDATE SET
+			url_id = EXCLUDED.url_id,
+			employee_data = EXCLUDED.employee_data,
+			last_update_date = EXCLUDED.last_update_date,
+			updated_at = EXCLUDED.updated_at`,
+		employeeArgs(&stored)...)
+	return dbError(err)
+}
+
+func (r *employeeRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM employees WHERE bsuir_id = $1`, id)
+	return err
+}
+
+func (r *employeeRepository) getOne(ctx context.Context, query string, args ...any) (*models.StoredEmployee, error) {
+	employee, err := scanEmployee(r.pool.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return employee, err
+}
+
+func employeeArgs(employee *models.StoredEmployee) []any {
+	return []any{
+		employee.ID.Hex(), employee.BSUIRID, employee.URLID, employee.EmployeeData,
+		employee.LastUpdateDate, employee.CreatedAt, employee.UpdatedAt,
+	}
+}
+
+func scanEmployee(row pgx.Row) (*models.StoredEmployee, error) {
+	var (
+		employee models.StoredEmployee
+		id       string
+	)
+	err := row.Scan(&id, &employee.BSUIRID, &employee.URLID, &employee.EmployeeData,
+		&employee.LastUpdateDate, &employee.CreatedAt, &employee.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	employee.ID = parseID(id)
+	return &employee, nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+)
+
+const eventColumns = `id, user_id, title, description, location, start_at, end_at, recurrence, created_at, updated_at`
+
+type eventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *eventRepository) GetByUser(ctx context.Context, userID string) ([]models.Event, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+eventColumns+` FROM events WHERE user_id = $1 ORDER BY start_at, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []models.Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	return events, rows.Err()
+}
+
+func (r *eventRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.Event, error) {
+	event, err := scanEvent(r.pool.QueryRow(ctx, `SELECT `+eventColumns+` FROM events WHERE id = $1 AND user_id = $2`, id.Hex(), userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return event, err
+}
+
+func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
+	event.ID = newID(event.ID)
+	_, err := r.pool.Exec(ctx, `INSERT INTO events (`+eventColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		event.ID.Hex(), event.UserID, event.Title, event.Description, event.Location,
+		event.Start, event.End, event.Recurrence, event.CreatedAt, event.UpdatedAt)
+	return dbError(err)
+}
+
+func (r *eventRepository) Update(ctx context.Context, event *models.Event) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE events
+		SET title = $3, description = $4, location = $5, start_at = $6, end_at = $7, recurrence = $8, updated_at = $9
+		WHERE id = $1 AND user_id = $2`,
+		event.ID.Hex(), event.UserID, event.Title, event.Description, event.Location,
+		event.Start, event.End, event.Recurrence, event.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *eventRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM events WHERE id = $1 AND user_id = $2`, id.Hex(), userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanEvent(row pgx.Row) (*models.Event, error) {
+	var (
+		event models.Event
+		id    string
+	)
+	if err := row.Scan(&id, &event.UserID, &event.Title, &event.Description, &event.Location,
+		&event.Start, &event.End, &event.Recurrence, &event.CreatedAt, &event.UpdatedAt); err != nil {
+		return nil, err
+	}
+	event.ID = parseID(id)
+	return &event, nil
+}
@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// searchLimit совпадает с $limit в поиске MongoDB
+const searchLimit = 100
+
+const favoriteColumns = `id, user_id, type, key, collection_id, label, color, pinned, position, created_at, updated_at`
+
+// favoriteOrder — порядок models.FavoriteLess
+const favoriteOrder = `ORDER BY pinned DESC, position, created_at, type, key`
+
+const collectionColumns = `id, user_id, name, color, position, created_at, updated_at`
+
+type favoriteRepository struct {
+	pool *pgxpool.Pool
+}
+
+// Пустой тип в запросах ниже отключает фильтр по типу: ($2 = '' OR type = $2)
+
+func (r *favoriteRepository) GetAll(ctx context.Context, userID string, favoriteType string) ([]models.Favorite, error) {
+	return r.find(ctx, `SELECT `+favoriteColumns+` FROM favorites
+		WHERE user_id = $1 AND ($2 = '' OR type = $2)
+		`+favoriteOrder, userID, favoriteType)
+}
+
+func (r *favoriteRepository) Get(ctx context.Context, userID string, favoriteType string, key string) (*models.Favorite, error) {
+	favorite, err := scanFavorite(r.pool.QueryRow(ctx, `SELECT `+favoriteColumns+` FROM favorites
+		WHERE user_id = $1 AND type = $2 AND key = $3`, userID, favoriteType, key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return favorite, err
+}
+
+// Search ищет без учета регистра по подстроке ключа, как regex-поиск MongoDB без поискового индекса
+func (r *favoriteRepository) Search(ctx context.Context, userID string, favoriteType string, query string) ([]models.Favorite, error) {
+	return r.find(ctx, `SELECT `+favoriteColumns+` FROM favorites
+		WHERE user_id = $1 AND ($2 = '' OR type = $2) AND key ILIKE $3
+		`+favoriteOrder+` LIMIT $4`, userID, favoriteType, likePattern(query), searchLimit)
+}
+
+// Add добавляет элемент в избранное; повторное добавление обновляет только UpdatedAt
+func (r *favoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO favorites (`+favoriteColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (user_id, type, key) DO UPDATE SET updated_at = EXCLUDED.updated_at`,
+		newID(favorite.ID).Hex(), favorite.UserID, favorite.Type, favorite.Key,
+		favorite.CollectionID, favorite.Label, favorite.Color, favorite.Pinned, favorite.Position,
+		favorite.CreatedAt, favorite.UpdatedAt)
+	return dbError(err)
+}
+
+func (r *favoriteRepository) UpdateDetails(ctx context.Context, favorite *models.Favorite) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE favorites
+		SET collection_id = $4, label = $5, color = $6, pinned = $7, position = $8, updated_at = $9
+		WHERE user_id = $1 AND type = $2 AND key = $3`,
+		favorite.UserID, favorite.Type, favorite.Key,
+		favorite.CollectionID, favorite.Label, favorite.Color, favorite.Pinned, favorite.Position, favorite.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *favoriteRepository) Delete(ctx context.Context, userID string, favoriteType string, key string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM favorites WHERE user_id = $1 AND type = $2 AND key = $3`, userID, favoriteType, key)
+	return err
+}
+
+func (r *favoriteRepository) IsFavorite(ctx context.Context, userID string, favoriteType string, key string) (found bool, err error) {
+	err = r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM favorites
+		WHERE user_id = $1 AND type = $2 AND key = $3)`, userID, favoriteType, key).Scan(&found)
+	return found, err
+}
+
+// Reorder блокирует строки коллекции (SELECT ... FOR UPDATE), сверяет набор и переписывает позиции в одной транзакции
+func (r *favoriteRepository) Reorder(ctx context.Context, userID string, collectionID string, ids []primitive.ObjectID) error {
+	return reorder(ctx, r.pool, "favorites", `user_id = $1 AND collection_id = $2`, []any{userID, collectionID}, ids)
+}
+
+func (r *favoriteRepository) GetCollections(ctx context.Context, userID string) ([]models.FavoriteCollection, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+collectionColumns+` FROM favorite_collections
+		WHERE user_id = $1 ORDER BY position, name`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []models.FavoriteCollection{}
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		collections = append(collections, *collection)
+	}
+	return collections, rows.Err()
+}
+
+func (r *favoriteRepository) GetCollection(ctx context.Context, userID string, id primitive.ObjectID) (*models.FavoriteCollection, error) {
+	collection, err := scanCollection(r.pool.QueryRow(ctx, `SELECT `+collectionColumns+` FROM favorite_collections
+		WHERE id = $1 AND user_id = $2`, id.Hex(), userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return collection, err
+}
+
+func (r *favoriteRepository) CreateCollection(ctx context.Context, collection *models.FavoriteCollection) error {
+	collection.ID = newID(collection.ID)
+	_, err := r.pool.Exec(ctx, `INSERT INTO favorite_collections (`+collectionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		collection.ID.Hex(), collection.UserID, collection.Name, collection.Color, collection.Position,
+		collection.CreatedAt, collection.UpdatedAt)
+	return dbError(err)
+}
+
+func (r *favoriteRepository) UpdateCollection(ctx context.Context, collection *models.FavoriteCollection) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE favorite_collections SET name = $3, color = $4, updated_at = $5
+		WHERE id = $1 AND user_id = $2`,
+		collection.ID.Hex(), collection.UserID, collection.Name, collection.Color, collection.UpdatedAt)
+	if err != nil {
+		return false, dbError(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteCollection удаляет коллекцию и отвязывает ее элементы в одной транзакции
+func (r *favoriteRepository) DeleteCollection(ctx context.Context, userID string, id primitive.ObjectID) (found bool, err error) {
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM favorite_collections WHERE id = $1 AND user_id = $2`, id.Hex(), userID)
+		if err != nil {
+			return err
+		}
+		found = tag.RowsAffected() > 0
+		if !found {
+			return nil
+		}
+		_, err = tx.Exec(ctx, `UPDATE favorites SET collection_id = '' WHERE user_id = $1 AND collection_id = $2`, userID, id.Hex())
+		return err
+	})
+	return found, err
+}
+
+func (r *favoriteRepository) ReorderCollections(ctx context.Context, userID string, ids []primitive.ObjectID) error {
+	return reorder(ctx, r.pool, "favorite_collections", `user_id = $1`, []any{userID}, ids)
+}
+
+// reorder сверяет ids с заблокированными строками table по условию where и проставляет позиции 0, 1, 2...
+func reorder(ctx context.Context, pool *pgxpool.Pool, table, where string, args []any, ids []primitive.ObjectID) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `SELECT id FROM `+table+` WHERE `+where+` FOR UPDATE`, args...)
+		if err != nil {
+			return err
+		}
+		stored, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (primitive.ObjectID, error) {
+			var id string
+			err := row.Scan(&id)
+			return parseID(id), err
+		})
+		if err != nil {
+			return err
+		}
+		if !repository.SameIDs(stored, ids) {
+			return repository.ErrReorderMismatch
+		}
+
+		hexIDs := make([]string, len(ids))
+		for i, id := range ids {
+			hexIDs[i] = id.Hex()
+		}
+		_, err = tx.Exec(ctx, `UPDATE `+table+` SET position = v.position - 1
+			FROM unnest($1::text[]) WITH ORDINALITY AS v(id, position)
+			WHERE `+table+`.id = v.id`, hexIDs)
+		return err
+	})
+}
+
+func (r *favoriteRepository) find(ctx context.Context, query string, args ...any) ([]models.Favorite, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites := []models.Favorite{}
+	for rows.Next() {
+		favorite, err := scanFavorite(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		favorites = append(favorites, *favorite)
+	}
+	return favorites, rows.Err()
+}
+
+func scanFavorite(row pgx.Row) (*models.Favorite, error) {
+	var (
+		favorite models.Favorite
+		id       string
+	)
+	if err := row.Scan(&id, &favorite.UserID, &favorite.Type, &favorite.Key,
+		&favorite.CollectionID, &favorite.Label, &favorite.Color, &favorite.Pinned, &favorite.Position,
+		&favorite.CreatedAt, &favorite.UpdatedAt); err != nil {
+		return nil, err
+	}
+	favorite.ID = parseID(id)
+	return &favorite, nil
+}
+
+func scanCollection(row pgx.Row) (*models.FavoriteCollection, error) {
+	var (
+		collection models.FavoriteCollection
+		id         string
+	)
+	if err := row.Scan(&id, &collection.UserID, &collection.Name, &collection.Color, &collection.Position,
+		&collection.CreatedAt, &collection.UpdatedAt); err != nil {
+		return nil, err
+	}
+	collection.ID = parseID(id)
+	return &collection, nil
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+)
+
+const groupColumns = `id, bsuir_id, group_data, last_update_date, created_at, updated_at`
+
+type groupRepository struct {
+	pool *pgxpool.Pool
+}
+
+// GetByNumber возвращает последнюю обновленную группу с таким именем: индекс по имени неуникальный, как в MongoDB
+func (r *groupRepository) GetByNumber(ctx context.Context, groupNumber string) (*models.StoredGroup, error) {
+	return r.getOne(ctx, `SELECT `+groupColumns+` FROM groups WHERE name = $1 ORDER BY updated_at DESC LIMIT 1`, groupNumber)
+}
+
+func (r *groupRepository) GetByID(ctx context.Context, id int) (*models.StoredGroup, error) {
+	return r.getOne(ctx, `SELECT `+groupColumns+` FROM groups WHERE bsuir_id = $1`, id)
+}
+
+// GetByNumbers, как и GetByNumber, из групп с одинаковым именем берет последнюю обновленную
+func (r *groupRepository) GetByNumbers(ctx context.Context, groupNumbers []string) ([]models.StoredGroup, error) {
+	return r.getMany(ctx, `SELECT DISTINCT ON (name) `+groupColumns+` FROM groups WHERE name = ANY($1) ORDER BY name, updated_at DESC`, groupNumbers)
+}
+
+func (r *groupRepository) GetAll(ctx context.Context) ([]models.StoredGroup, error) {
+	return r.getMany(ctx, `SELECT `+groupColumns+` FROM groups ORDER BY bsuir_id`)
+}
+
+func (r *groupRepository) getMany(ctx context.Context, query string, args ...any) ([]models.StoredGroup, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []models.StoredGroup
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+	return groups, rows.Err()
+}
+
+func (r *groupRepository) Count(ctx context.Context) (count int64, err error) {
+	err = r.pool.QueryRow(ctx, `SELECT count(*) FROM groups`).Scan(&count)
+	return count, err
+}
+
+func (r *groupRepository) Save(ctx context.Context, group *models.StoredGroup) error {
+	group.ID = newID(group.ID)
+	_, err := r.pool.Exec(ctx, `INSERT INTO groups (`+groupColumns+`, name) VALUES ($1, $2, $3, $4, $5, $6, $7)`, groupArgs(group)...)
+	return dbError(err)
+}
+
+// SaveMany, как неупорядоченная вставка в MongoDB, сохраняет все группы без конфликтов
+// и возвращает ErrDuplicate, если конфликты были
+func (r *groupRepository) SaveMany(ctx context.Context, groups []models.StoredGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for i := range groups {
+		groups[i].ID = newID(groups[i].ID)
+		batch.Queue(`INSERT INTO groups (`+groupColumns+`, name) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT DO NOTHING`, groupArgs(&groups[i])...)
+	}
+	return execMany(ctx, r.pool, batch)
+}
+
+// Update создает или заменяет группу с тем же BSUIRID, сохраняя исходные ID и CreatedAt
+func (r *groupRepository) Update(ctx context.Context, group *models.StoredGroup) error {
+	stored := *group
+	stored.ID = primitive.NewObjectID()
+
+	_, err := r.pool.Exec(ctx, `INSERT INTO groups (`+groupColumns+`, name) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (bsuir_id) DO UPDATE SET
+			name = EXCLUDED.name,
+			group_data = EXCLUDED.group_data,
+			last_update_date = EXCLUDED.last_update_date,
+			updated_at = EXCLUDED.updated_at`,
+		groupArgs(&stored)...)
+	return dbError(err)
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM groups WHERE bsuir_id = $1`, id)
+	return err
+}
+
+func (r *groupRepository) getOne(ctx context.Context, query string, args ...any) (*models.StoredGroup, error) {
+	group, err := scanGroup(r.pool.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return group, err
+}
+
+func groupArgs(group *models.StoredGroup) []any {
+	return []any{
+		group.ID.Hex(), group.BSUIRID, group.GroupData, group.LastUpdateDate,
+		group.CreatedAt, group.UpdatedAt, group.GroupData.Name,
+	}
+}
+
+func scanGroup(row pgx.Row) (*models.StoredGroup, error) {
+	var (
+		group models.StoredGroup
+		id    string
+	)
+	if err := row.Scan(&id, &group.BSUIRID, &group.GroupData, &group.LastUpdateDate, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return nil, err
+	}
+	group.ID = parseID(id)
+	return &group, nil
+}
@@ -0,0 +1,53 @@
+-- Начальная схема: те же ограничения уникальности, что у индексов MongoDB
+
+CREATE TABLE schedules (
+    id               CHAR(24)    PRIMARY KEY,
+    group_number     TEXT        UNIQUE,
+    employee_url_id  TEXT        UNIQUE,
+    schedule_data    JSONB       NOT NULL,
+    last_update_date TEXT        NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL,
+    CHECK (group_number IS NOT NULL OR employee_url_id IS NOT NULL)
+);
+
+CREATE TABLE groups (
+    bsuir_id         INTEGER     PRIMARY KEY,
+    id               CHAR(24)    NOT NULL UNIQUE,
+    name             TEXT        NOT NULL,
+    group_data       JSONB       NOT NULL,
+    last_update_date TEXT        NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX groups_name_idx ON groups (name);
+
+CREATE TABLE employees (
+    bsuir_id         INTEGER     PRIMARY KEY,
+    id               CHAR(24)    NOT NULL UNIQUE,
+    url_id           TEXT        NOT NULL UNIQUE,
+    employee_data    JSONB       NOT NULL,
+    last_update_date TEXT        NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE favorite_groups (
+    id           CHAR(24)    PRIMARY KEY,
+    user_id      TEXT        NOT NULL,
+    group_number TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, group_number)
+);
+
+CREATE TABLE api_keys (
+    id         CHAR(24)    PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    prefix     TEXT        NOT NULL UNIQUE,
+    key_hash   TEXT        NOT NULL UNIQUE,
+    role       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
@@ -0,0 +1,17 @@
+-- Избранное с типами: группы, преподаватели, аудитории. Старые избранные группы переносятся как тип group.
+CREATE TABLE favorites (
+    id         CHAR(24)    PRIMARY KEY,
+    user_id    TEXT        NOT NULL,
+    type       TEXT        NOT NULL,
+    key        TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, type, key)
+);
+
+CREATE INDEX favorites_user_created_idx ON favorites (user_id, created_at);
+
+INSERT INTO favorites (id, user_id, type, key, created_at, updated_at)
+SELECT id, user_id, 'group', group_number, created_at, updated_at FROM favorite_groups;
+
+DROP TABLE favorite_groups;
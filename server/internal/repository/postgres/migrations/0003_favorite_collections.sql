@@ -0,0 +1,20 @@
+-- Коллекции избранного, подписи, цвета, закрепление и ручной порядок элементов
+CREATE TABLE favorite_collections (
+    id         CHAR(24)    PRIMARY KEY,
+    user_id    TEXT        NOT NULL,
+    name       TEXT        NOT NULL,
+    color      TEXT        NOT NULL DEFAULT '',
+    position   BIGINT      NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+ALTER TABLE favorites
+    ADD COLUMN collection_id TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN label         TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN color         TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN pinned        BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN position      BIGINT  NOT NULL DEFAULT 0;
+
+CREATE INDEX favorites_user_collection_idx ON favorites (user_id, collection_id);
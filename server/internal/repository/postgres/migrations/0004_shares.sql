@@ -0,0 +1,15 @@
+-- Публичные ссылки на избранное и ленту; токен хранится только хэшем
+CREATE TABLE shares (
+    id            CHAR(24)    PRIMARY KEY,
+    user_id       TEXT        NOT NULL,
+    kind          TEXT        NOT NULL,
+    title         TEXT        NOT NULL DEFAULT '',
+    collection_id TEXT        NOT NULL DEFAULT '',
+    prefix        TEXT        NOT NULL,
+    token_hash    TEXT        NOT NULL UNIQUE,
+    created_at    TIMESTAMPTZ NOT NULL,
+    expires_at    TIMESTAMPTZ,
+    revoked_at    TIMESTAMPTZ
+);
+
+CREATE INDEX shares_user_created_idx ON shares (user_id, created_at);
@@ -0,0 +1,15 @@
+-- Личные события пользователей; recurrence — правило RRULE, пустое у разовых событий
+CREATE TABLE events (
+    id          CHAR(24)    PRIMARY KEY,
+    user_id     TEXT        NOT NULL,
+    title       TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    location    TEXT        NOT NULL DEFAULT '',
+    start_at    TIMESTAMPTZ NOT NULL,
+    end_at      TIMESTAMPTZ NOT NULL,
+    recurrence  TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX events_user_start_idx ON events (user_id, start_at);
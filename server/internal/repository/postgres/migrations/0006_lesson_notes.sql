@@ -0,0 +1,21 @@
+-- Заметки и домашние задания пользователей к занятиям; занятие задается группой, предметом, типом, датой и временем
+CREATE TABLE lesson_notes (
+    id           CHAR(24)    PRIMARY KEY,
+    user_id      TEXT        NOT NULL,
+    kind         TEXT        NOT NULL,
+    group_number TEXT        NOT NULL,
+    subject      TEXT        NOT NULL,
+    lesson_type  TEXT        NOT NULL DEFAULT '',
+    lesson_date  TEXT        NOT NULL,
+    lesson_start TEXT        NOT NULL,
+    text         TEXT        NOT NULL DEFAULT '',
+    attachments  JSONB       NOT NULL DEFAULT '[]',
+    due_at       TIMESTAMPTZ,
+    done         BOOLEAN     NOT NULL DEFAULT FALSE,
+    done_at      TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX lesson_notes_user_lesson_idx ON lesson_notes (user_id, lesson_date, lesson_start);
+CREATE INDEX lesson_notes_user_due_idx ON lesson_notes (user_id, due_at) WHERE kind = 'homework';
@@ -0,0 +1,21 @@
+-- Личные правки расписания: скрыть серию занятий или поменять ей аудиторию и время.
+-- Пустые lesson_type, weekday, lesson_start и нулевой subgroup подходят к любому занятию предмета.
+CREATE TABLE lesson_overrides (
+    id            CHAR(24)    PRIMARY KEY,
+    user_id       TEXT        NOT NULL,
+    schedule_type TEXT        NOT NULL,
+    key           TEXT        NOT NULL,
+    subject       TEXT        NOT NULL,
+    lesson_type   TEXT        NOT NULL DEFAULT '',
+    weekday       TEXT        NOT NULL DEFAULT '',
+    lesson_start  TEXT        NOT NULL DEFAULT '',
+    subgroup      INTEGER     NOT NULL DEFAULT 0,
+    hidden        BOOLEAN     NOT NULL DEFAULT FALSE,
+    auditories    JSONB       NOT NULL DEFAULT '[]',
+    start_time    TEXT        NOT NULL DEFAULT '',
+    end_time      TEXT        NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL,
+    updated_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX lesson_overrides_user_schedule_idx ON lesson_overrides (user_id, schedule_type, key, created_at);
@@ -0,0 +1,22 @@
+-- Подписки на напоминания о занятиях (одна на пользователя) и отметки об отправленных напоминаниях.
+-- Пустые quiet_from и quiet_to — тихих часов нет.
+CREATE TABLE reminder_subscriptions (
+    id           CHAR(24)    PRIMARY KEY,
+    user_id      TEXT        NOT NULL UNIQUE,
+    channel      TEXT        NOT NULL,
+    target       TEXT        NOT NULL,
+    lead_minutes INTEGER     NOT NULL,
+    quiet_from   TEXT        NOT NULL DEFAULT '',
+    quiet_to     TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE sent_reminders (
+    user_id TEXT        NOT NULL,
+    key     TEXT        NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX sent_reminders_sent_at_idx ON sent_reminders (sent_at);
@@ -0,0 +1,2 @@
+-- Ключи роли user действуют от имени пользователя; у ключей admin user_id пустой
+ALTER TABLE api_keys ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
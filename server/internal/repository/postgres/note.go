@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+const noteColumns = `id, user_id, kind, group_number, subject, lesson_type, lesson_date, lesson_start,
+	text, attachments, due_at, done, done_at, created_at, updated_at`
+
+type noteRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *noteRepository) GetByUser(ctx context.Context, userID string, filter repository.NoteFilter) ([]models.LessonNote, error) {
+	where, args := []string{"user_id = $1"}, []any{userID}
+	if filter.GroupNumber != "" {
+		args = append(args, filter.GroupNumber)
+		where = append(where, fmt.Sprintf("group_number = $%d", len(args)))
+	}
+	if filter.Date != "" {
+		args = append(args, filter.Date)
+		where = append(where, fmt.Sprintf("lesson_date = $%d", len(args)))
+	}
+	return r.query(ctx, `SELECT `+noteColumns+` FROM lesson_notes WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY lesson_date, lesson_start, created_at, id`, args...)
+}
+
+func (r *noteRepository) GetHomework(ctx context.Context, userID string, filter repository.HomeworkFilter) ([]models.LessonNote, error) {
+	where, args := []string{"user_id = $1", "kind = $2"}, []any{userID, models.NoteKindHomework}
+	if !filter.DueBefore.IsZero() {
+		args = append(args, filter.DueBefore)
+		where = append(where, fmt.Sprintf("due_at < $%d", len(args)))
+	}
+	if !filter.IncludeDone {
+		where = append(where, "NOT done")
+	}
+	return r.query(ctx, `SELECT `+noteColumns+` FROM lesson_notes WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY due_at NULLS FIRST, created_at, id`, args...)
+}
+
+func (r *noteRepository) query(ctx context.Context, sql string, args ...any) ([]models.LessonNote, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []models.LessonNote{}
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		notes = append(notes, *note)
+	}
+	return notes, rows.Err()
+}
+
+func (r *noteRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonNote, error) {
+	note, err := scanNote(r.pool.QueryRow(ctx, `SELECT `+noteColumns+` FROM lesson_notes WHERE id = $1 AND user_id = $2`, id.Hex(), userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return note, err
+}
+
+func (r *noteRepository) Create(ctx context.Context, note *models.LessonNote) error {
+	note.ID = newID(note.ID)
+	lesson := note.Lesson
+	_, err := r.pool.Exec(ctx, `INSERT INTO lesson_notes (`+noteColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		note.ID.Hex(), note.UserID, note.Kind, lesson.GroupNumber, lesson.Subject, lesson.LessonType, lesson.Date, lesson.Start,
+		note.Text, attachments(note.Attachments), note.DueAt, note.Done, note.DoneAt, note.CreatedAt, note.UpdatedAt)
+	return dbError(err)
+}
+
+func (r *noteRepository) Update(ctx context.Context, note *models.LessonNote) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE lesson_notes
+		SET text = $3, attachments = $4, due_at = $5, done = $6, done_at = $7, updated_at = $8
+		WHERE id = $1 AND user_id = $2`,
+		note.ID.Hex(), note.UserID, note.Text, attachments(note.Attachments), note.DueAt, note.Done, note.DoneAt, note.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *noteRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM lesson_notes WHERE id = $1 AND user_id = $2`, id.Hex(), userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// attachments пишет пустой список как [], а не как JSON null
+func attachments(list []models.Attachment) []models.Attachment {
+	if list == nil {
+		return []models.Attachment{}
+	}
+	return list
+}
+
+func scanNote(row pgx.Row) (*models.LessonNote, error) {
+	var (
+		note models.LessonNote
+		id   string
+	)
+	lesson := &note.Lesson
+	if err := row.Scan(&id, &note.UserID, &note.Kind, &lesson.GroupNumber, &lesson.Subject, &lesson.LessonType, &lesson.Date, &lesson.Start,
+		&note.Text, &note.Attachments, &note.DueAt, &note.Done, &note.DoneAt, &note.CreatedAt, &note.UpdatedAt); err != nil {
+		return nil, err
+	}
+	note.ID = parseID(id)
+	if len(note.Attachments) == 0 {
+		note.Attachments = nil
+	}
+	return &note, nil
+}
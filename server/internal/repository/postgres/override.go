@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+)
+
+const overrideColumns = `id, user_id, schedule_type, key, subject, lesson_type, weekday, lesson_start, subgroup,
+	hidden, auditories, start_time, end_time, created_at, updated_at`
+
+type overrideRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *overrideRepository) GetByUser(ctx context.Context, userID string) ([]models.LessonOverride, error) {
+	return r.query(ctx, `SELECT `+overrideColumns+` FROM lesson_overrides WHERE user_id = $1 ORDER BY created_at, id`, userID)
+}
+
+func (r *overrideRepository) GetBySchedule(ctx context.Context, userID, scheduleType, key string) ([]models.LessonOverride, error) {
+	return r.query(ctx, `SELECT `+overrideColumns+` FROM lesson_overrides
+		WHERE user_id = $1 AND schedule_type = $2 AND key = $3 ORDER BY created_at, id`, userID, scheduleType, key)
+}
+
+func (r *overrideRepository) query(ctx context.Context, sql string, args ...any) ([]models.LessonOverride, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := []models.LessonOverride{}
+	for rows.Next() {
+		override, err := scanOverride(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		overrides = append(overrides, *override)
+	}
+	return overrides, rows.Err()
+}
+
+func (r *overrideRepository) GetByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.LessonOverride, error) {
+	override, err := scanOverride(r.pool.QueryRow(ctx, `SELECT `+overrideColumns+` FROM lesson_overrides WHERE id = $1 AND user_id = $2`, id.Hex(), userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return override, err
+}
+
+func (r *overrideRepository) Create(ctx context.Context, override *models.LessonOverride) error {
+	override.ID = newID(override.ID)
+	series := override.Series
+	_, err := r.pool.Exec(ctx, `INSERT INTO lesson_overrides (`+overrideColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		override.ID.Hex(), override.UserID, override.ScheduleType, override.Key,
+		series.Subject, series.LessonType, series.Weekday, series.Start, series.Subgroup,
+		override.Hidden, auditories(override.Auditories), override.StartTime, override.EndTime, override.CreatedAt, override.UpdatedAt)
+	return dbError(err)
+}
+
+func (r *overrideRepository) Update(ctx context.Context, override *models.LessonOverride) (bool, error) {
+	series := override.Series
+	tag, err := r.pool.Exec(ctx, `UPDATE lesson_overrides
+		SET schedule_type = $3, key = $4, subject = $5, lesson_type = $6, weekday = $7, lesson_start = $8, subgroup = $9,
+			hidden = $10, auditories = $11, start_time = $12, end_time = $13, updated_at = $14
+		WHERE id = $1 AND user_id = $2`,
+		override.ID.Hex(), override.UserID, override.ScheduleType, override.Key,
+		series.Subject, series.LessonType, series.Weekday, series.Start, series.Subgroup,
+		override.Hidden, auditories(override.Auditories), override.StartTime, override.EndTime, override.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *overrideRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM lesson_overrides WHERE id = $1 AND user_id = $2`, id.Hex(), userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// auditories пишет пустой список как [], а не как JSON null
+func auditories(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+func scanOverride(row pgx.Row) (*models.LessonOverride, error) {
+	var (
+		override models.LessonOverride
+		id       string
+	)
+	series := &override.Series
+	if err := row.Scan(&id, &override.UserID, &override.ScheduleType, &override.Key,
+		&series.Subject, &series.LessonType, &series.Weekday, &series.Start, &series.Subgroup,
+		&override.Hidden, &override.Auditories, &override.StartTime, &override.EndTime, &override.CreatedAt, &override.UpdatedAt); err != nil {
+		return nil, err
+	}
+	override.ID = parseID(id)
+	if len(override.Auditories) == 0 {
+		override.Auditories = nil
+	}
+	return &override, nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"schedluer/internal/models"
+)
+
+const subscriptionColumns = `id, user_id, channel, target, lead_minutes, quiet_from, quiet_to, created_at, updated_at`
+
+type reminderRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *reminderRepository) GetSubscriptions(ctx context.Context) ([]models.ReminderSubscription, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+subscriptionColumns+` FROM reminder_subscriptions ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := []models.ReminderSubscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (r *reminderRepository) GetSubscription(ctx context.Context, userID string) (*models.ReminderSubscription, error) {
+	subscription, err := scanSubscription(r.pool.QueryRow(ctx, `SELECT `+subscriptionColumns+` FROM reminder_subscriptions WHERE user_id = $1`, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return subscription, err
+}
+
+func (r *reminderRepository) SaveSubscription(ctx context.Context, subscription *models.ReminderSubscription) error {
+	var quietFrom, quietTo string
+	if subscription.QuietHours != nil {
+		quietFrom, quietTo = subscription.QuietHours.From, subscription.QuietHours.To
+	}
+
+	var id string
+	err := r.pool.QueryRow(ctx, `INSERT INTO reminder_subscriptions (`+subscriptionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		SET channel = EXCLUDED.channel, target = EXCLUDED.target, lead_minutes = EXCLUDED.lead_minutes,
+			quiet_from = EXCLUDED.quiet_from, quiet_to = EXCLUDED.quiet_to, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`,
+		newID(subscription.ID).Hex(), subscription.UserID, subscription.Channel, subscription.Target, subscription.LeadMinutes,
+		quietFrom, quietTo, subscription.CreatedAt, subscription.UpdatedAt).Scan(&id, &subscription.CreatedAt)
+	if err != nil {
+		return dbError(err)
+	}
+	subscription.ID = parseID(id)
+	return nil
+}
+
+func (r *reminderRepository) DeleteSubscription(ctx context.Context, userID string) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM reminder_subscriptions WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *reminderRepository) MarkSent(ctx context.Context, userID, key string, sentAt time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `INSERT INTO sent_reminders (user_id, key, sent_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, key) DO NOTHING`, userID, key, sentAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *reminderRepository) UnmarkSent(ctx context.Context, userID, key string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM sent_reminders WHERE user_id = $1 AND key = $2`, userID, key)
+	return err
+}
+
+func (r *reminderRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM sent_reminders WHERE sent_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanSubscription(row pgx.Row) (*models.ReminderSubscription, error) {
+	var (
+		subscription       models.ReminderSubscription
+		id                 string
+		quietFrom, quietTo string
+	)
+	if err := row.Scan(&id, &subscription.UserID, &subscription.Channel, &subscription.Target, &subscription.LeadMinutes,
+		&quietFrom, &quietTo, &subscription.CreatedAt, &subscription.UpdatedAt); err != nil {
+		return nil, err
+	}
+	subscription.ID = parseID(id)
+	if quietFrom != "" || quietTo != "" {
+		subscription.QuietHours = &models.QuietHours{From: quietFrom, To: quietTo}
+	}
+	return &subscription, nil
+}
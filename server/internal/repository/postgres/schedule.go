@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"schedluer/internal/models"
+)
+
+const scheduleColumns = `id, group_number, employee_url_id, schedule_data, last_update_date, created_at, updated_at`
+
+type scheduleRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *scheduleRepository) GetByGroupNumber(ctx context.Context, groupNumber string) (*models.StoredSchedule, error) {
+	return r.getOne(ctx, `SELECT `+scheduleColumns+` FROM schedules WHERE group_number = $1`, groupNumber)
+}
+
+func (r *scheduleRepository) GetByEmployeeURLID(ctx context.Context, urlID string) (*models.StoredSchedule, error) {
+	return r.getOne(ctx, `SELECT `+scheduleColumns+` FROM schedules WHERE employee_url_id = $1`, urlID)
+}
+
+func (r *scheduleRepository) Save(ctx context.Context, schedule *models.StoredSchedule) error {
+	schedule.ID = newID(schedule.ID)
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+
+	_, err := r.pool.Exec(ctx, `INSERT INTO schedules (`+scheduleColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		schedule.ID.Hex(), nullable(schedule.GroupNumber), nullable(schedule.EmployeeURLID),
+		schedule.ScheduleData, schedule.LastUpdateDate, schedule.CreatedAt, schedule.UpdatedAt)
+	return dbError(err)
+}
+
+// Update создает или заменяет расписание группы либо преподавателя
+func (r *scheduleRepository) Update(ctx context.Context, schedule *models.StoredSchedule) error {
+	schedule.UpdatedAt = time.Now()
+
+	conflict := "group_number"
+	if schedule.GroupNumber == "" {
+		conflict = "employee_url_id"
+	}
+
+	_, err := r.pool.Exec(ctx, `INSERT INTO schedules (`+scheduleColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (`+conflict+`) DO UPDATE SET
+			schedule_data = EXCLUDED.schedule_data,
+			last_update_date = EXCLUDED.last_update_date,
+			updated_at = EXCLUDED.updated_at`,
+		newID(schedule.ID).Hex(), nullable(schedule.GroupNumber), nullable(schedule.EmployeeURLID),
+		schedule.ScheduleData, schedule.LastUpdateDate, schedule.UpdatedAt)
+	return dbError(err)
+}
+
+func (r *scheduleRepository) Delete(ctx context.Context, groupNumber string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM schedules WHERE group_number = $1`, groupNumber)
+	return err
+}
+
+func (r *scheduleRepository) DeleteByEmployeeURLID(ctx context.Context, urlID string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM schedules WHERE employee_url_id = $1`, urlID)
+	return err
+}
+
+func (r *scheduleRepository) getOne(ctx context.Context, query string, args ...any) (*models.StoredSchedule, error) {
+	var (
+		schedule      models.StoredSchedule
+		id            string
+		groupNumber   *string
+		employeeURLID *string
+	)
+	err := r.pool.QueryRow(ctx, query, args...).Scan(
+		&id, &groupNumber, &employeeURLID, &schedule.ScheduleData,
+		&schedule.LastUpdateDate, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.ID = parseID(id)
+	if groupNumber != nil {
+		schedule.GroupNumber = *groupNumber
+	}
+	if employeeURLID != nil {
+		schedule.EmployeeURLID = *employeeURLID
+	}
+	return &schedule, nil
+}
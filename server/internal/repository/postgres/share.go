@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+)
+
+const shareColumns = `id, user_id, kind, title, collection_id, prefix, token_hash, created_at, expires_at, revoked_at`
+
+type shareRepository struct {
+	pool *pgxpool.Pool
+}
+
+func (r *shareRepository) GetByHash(ctx context.Context, tokenHash string) (*models.Share, error) {
+	share, err := scanShare(r.pool.QueryRow(ctx, `SELECT `+shareColumns+` FROM shares WHERE token_hash = $1`, tokenHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return share, err
+}
+
+func (r *shareRepository) GetByUser(ctx context.Context, userID string) ([]models.Share, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+shareColumns+` FROM shares WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []models.Share{}
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		shares = append(shares, *share)
+	}
+	return shares, rows.Err()
+}
+
+func (r *shareRepository) Create(ctx context.Context, share *models.Share) error {
+	share.ID = newID(share.ID)
+	_, err := r.pool.Exec(ctx, `INSERT INTO shares (`+shareColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		share.ID.Hex(), share.UserID, share.Kind, share.Title, share.CollectionID, share.Prefix, share.TokenHash,
+		share.CreatedAt, share.ExpiresAt, share.RevokedAt)
+	return dbError(err)
+}
+
+// Revoke отзывает ссылку; false, если ее нет или она уже отозвана
+func (r *shareRepository) Revoke(ctx context.Context, userID string, id primitive.ObjectID, revokedAt time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE shares SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		id.Hex(), userID, revokedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanShare(row pgx.Row) (*models.Share, error) {
+	var (
+		share models.Share
+		id    string
+	)
+	if err := row.Scan(&id, &share.UserID, &share.Kind, &share.Title, &share.CollectionID, &share.Prefix, &share.TokenHash,
+		&share.CreatedAt, &share.ExpiresAt, &share.RevokedAt); err != nil {
+		return nil, err
+	}
+	share.ID = parseID(id)
+	return &share, nil
+}
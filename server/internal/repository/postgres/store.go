@@ -0,0 +1,175 @@
+// Package postgres — хранилище в PostgreSQL. Данные из API БГУИРа лежат в JSONB,
+// ограничения уникальности повторяют индексы MongoDB; схема ведется миграциями из migrations/.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/logging"
+	"schedluer/internal/repository"
+)
+
+// uniqueViolation — код ошибки PostgreSQL при нарушении UNIQUE
+const uniqueViolation = "23505"
+
+type Config struct {
+	DSN string
+	// Timeout ограничивает подключение, первый ping и применение миграций
+	Timeout  time.Duration
+	MaxConns int32
+}
+
+type store struct {
+	pool *pgxpool.Pool
+
+	schedules repository.ScheduleRepository
+	groups    repository.GroupRepository
+	employees repository.EmployeeRepository
+	favorites repository.FavoriteRepository
+	apiKeys   repository.APIKeyRepository
+	shares    repository.ShareRepository
+	events    repository.EventRepository
+	notes     repository.NoteRepository
+	overrides repository.OverrideRepository
+	reminders repository.ReminderRepository
+}
+
+// Open подключается к PostgreSQL и применяет недостающие миграции
+func Open(cfg Config, logger *logrus.Logger) (repository.Store, error) {
+	logger.WithField("dsn", logging.RedactURI(cfg.DSN)).Info("Connecting to PostgreSQL")
+
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PostgreSQL DSN: %w", err)
+	}
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
+	}
+
+	if err := Migrate(ctx, pool, logger); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	logger.Info("Successfully connected to PostgreSQL")
+
+	return &store{
+		pool:      pool,
+		schedules: &scheduleRepository{pool: pool},
+		groups:    &groupRepository{pool: pool},
+		employees: &employeeRepository{pool: pool},
+		favorites: &favoriteRepository{pool: pool},
+		apiKeys:   &apiKeyRepository{pool: pool},
+		shares:    &shareRepository{pool: pool},
+		events:    &eventRepository{pool: pool},
+		notes:     &noteRepository{pool: pool},
+		overrides: &overrideRepository{pool: pool},
+		reminders: &reminderRepository{pool: pool},
+	}, nil
+}
+
+func (s *store) Schedules() repository.ScheduleRepository { return s.schedules }
+func (s *store) Groups() repository.GroupRepository       { return s.groups }
+func (s *store) Employees() repository.EmployeeRepository { return s.employees }
+func (s *store) Favorites() repository.FavoriteRepository { return s.favorites }
+func (s *store) APIKeys() repository.APIKeyRepository     { return s.apiKeys }
+func (s *store) Shares() repository.ShareRepository       { return s.shares }
+func (s *store) Events() repository.EventRepository       { return s.events }
+func (s *store) Notes() repository.NoteRepository         { return s.notes }
+func (s *store) Overrides() repository.OverrideRepository { return s.overrides }
+func (s *store) Reminders() repository.ReminderRepository { return s.reminders }
+
+func (s *store) Driver() string { return "postgres" }
+
+func (s *store) Health(ctx context.Context) error {
+	return s.pool.Ping(ctx)
+}
+
+func (s *store) Close(ctx context.Context) error {
+	s.pool.Close()
+	return nil
+}
+
+// dbError приводит нарушение уникальности к ErrDuplicate, сохраняя исходное сообщение
+func dbError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %v", repository.ErrDuplicate, err)
+	}
+	return err
+}
+
+// newID возвращает ID записи или создает новый, если он не задан
+func newID(id primitive.ObjectID) primitive.ObjectID {
+	if id.IsZero() {
+		return primitive.NewObjectID()
+	}
+	return id
+}
+
+func parseID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(strings.TrimSpace(hex))
+	return id
+}
+
+// nullable превращает пустую строку в NULL, чтобы UNIQUE не срабатывал на отсутствующих ключах
+func nullable(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// execMany выполняет пакет вставок с ON CONFLICT DO NOTHING и сообщает ErrDuplicate,
+// если часть строк не вставлена из-за конфликта
+func execMany(ctx context.Context, pool *pgxpool.Pool, batch *pgx.Batch) error {
+	results := pool.SendBatch(ctx, batch)
+
+	skipped := 0
+	for range batch.Len() {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return dbError(err)
+		}
+		if tag.RowsAffected() == 0 {
+			skipped++
+		}
+	}
+	if err := results.Close(); err != nil {
+		return err
+	}
+
+	if skipped > 0 {
+		return fmt.Errorf("%w: %d of %d rows already exist", repository.ErrDuplicate, skipped, batch.Len())
+	}
+	return nil
+}
+
+// likePattern экранирует спецсимволы LIKE и ищет подстроку
+func likePattern(query string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(query) + "%"
+}
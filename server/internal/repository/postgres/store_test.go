@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/repository"
+	"schedluer/internal/repository/repotest"
+)
+
+// Набор прогоняется против настоящего PostgreSQL, только если задан SCHEDLUER_TEST_POSTGRES_DSN.
+// База должна быть отдельной: перед каждым тестом все таблицы очищаются.
+func TestStoreConformance(t *testing.T) {
+	dsn := os.Getenv("SCHEDLUER_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("SCHEDLUER_TEST_POSTGRES_DSN is not set")
+	}
+
+	repotest.Run(t, func(t *testing.T) repository.Store {
+		opened, err := Open(Config{DSN: dsn, Timeout: 10 * time.Second, MaxConns: 4}, logrus.StandardLogger())
+		if err != nil {
+			t.Fatalf("failed to open PostgreSQL store: %v", err)
+		}
+		t.Cleanup(func() { _ = opened.Close(context.Background()) })
+
+		_, err = opened.(*store).pool.Exec(context.Background(),
+			`TRUNCATE schedules, groups, employees, favorites, favorite_collections, api_keys, shares, events, lesson_notes, lesson_overrides, reminder_subscriptions, sent_reminders`)
+		if err != nil {
+			t.Fatalf("failed to clean tables: %v", err)
+		}
+		return opened
+	})
+}
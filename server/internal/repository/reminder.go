@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"schedluer/internal/models"
+)
+
+// ReminderRepository хранит подписки на напоминания и отметки об уже отправленных напоминаниях.
+// Отметка ставится до отправки, поэтому несколько экземпляров сервиса не отправят одно напоминание дважды.
+type ReminderRepository interface {
+	// GetSubscriptions возвращает все подписки по user_id — для планировщика
+	GetSubscriptions(ctx context.Context) ([]models.ReminderSubscription, error)
+	GetSubscription(ctx context.Context, userID string) (*models.ReminderSubscription, error)
+	// SaveSubscription создает или заменяет подписку пользователя; ID и CreatedAt берутся из сохраненной
+	SaveSubscription(ctx context.Context, subscription *models.ReminderSubscription) error
+	DeleteSubscription(ctx context.Context, userID string) (bool, error)
+
+	// MarkSent отмечает напоминание key отправленным; false, если отметка уже есть
+	MarkSent(ctx context.Context, userID, key string, sentAt time.Time) (bool, error)
+	// UnmarkSent снимает отметку, если отправить не удалось, — напоминание уйдет на следующем проходе
+	UnmarkSent(ctx context.Context, userID, key string) error
+	// DeleteSentBefore удаляет отметки, поставленные раньше before, и возвращает их число
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type sentReminder struct {
+	UserID string    `bson:"user_id"`
+	Key    string    `bson:"key"`
+	SentAt time.Time `bson:"sent_at"`
+}
+
+type reminderRepository struct {
+	subscriptions *mongo.Collection
+	sent          *mongo.Collection
+}
+
+func NewReminderRepository(db *mongo.Database) ReminderRepository {
+	subscriptions := db.Collection("reminder_subscriptions")
+	_, _ = subscriptions.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
+	sent := db.Collection("sent_reminders")
+	_, _ = sent.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "sent_at", Value: 1}},
+		},
+	})
+
+	return &reminderRepository{
+		subscriptions: subscriptions,
+		sent:          sent,
+	}
+}
+
+func (r *reminderRepository) GetSubscriptions(ctx context.Context) ([]models.ReminderSubscription, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "user_id", Value: 1}})
+	cursor, err := r.subscriptions.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := []models.ReminderSubscription{}
+	if err := cursor.All(ctx, &subscriptions); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *reminderRepository) GetSubscription(ctx context.Context, userID string) (*models.ReminderSubscription, error) {
+	var subscription models.ReminderSubscription
+	err := r.subscriptions.FindOne(ctx, bson.M{"user_id": userID}).Decode(&subscription)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *reminderRepository) SaveSubscription(ctx context.Context, subscription *models.ReminderSubscription) error {
+	if subscription.ID.IsZero() {
+		subscription.ID = primitive.NewObjectID()
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"channel":      subscription.Channel,
+			"target":       subscription.Target,
+			"lead_minutes": subscription.LeadMinutes,
+			"quiet_hours":  subscription.QuietHours,
+			"updated_at":   subscription.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        subscription.ID,
+			"created_at": subscription.CreatedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var saved models.ReminderSubscription
+	err := r.subscriptions.FindOneAndUpdate(ctx, bson.M{"user_id": subscription.UserID}, update, opts).Decode(&saved)
+	if err != nil {
+		return mongoError(err)
+	}
+	subscription.ID, subscription.CreatedAt = saved.ID, saved.CreatedAt
+	return nil
+}
+
+func (r *reminderRepository) DeleteSubscription(ctx context.Context, userID string) (bool, error) {
+	result, err := r.subscriptions.DeleteOne(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *reminderRepository) MarkSent(ctx context.Context, userID, key string, sentAt time.Time) (bool, error) {
+	_, err := r.sent.InsertOne(ctx, sentReminder{UserID: userID, Key: key, SentAt: sentAt})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *reminderRepository) UnmarkSent(ctx context.Context, userID, key string) error {
+	_, err := r.sent.DeleteOne(ctx, bson.M{"user_id": userID, "key": key})
+	return err
+}
+
+func (r *reminderRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.sent.DeleteMany(ctx, bson.M{"sent_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,101 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// APIKeyRepository проверяет API-ключи: префикс и хэш уникальны, отзыв — один раз
+func APIKeyRepository(t *testing.T, newRepo func(t *testing.T) repository.APIKeyRepository) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		key, err := repo.GetByHash(ctx, "missing")
+		mustNoError(t, err, "GetByHash missing")
+		if key != nil {
+			t.Errorf("GetByHash: expected nil, got %+v", key)
+		}
+
+		created := newAPIKey("sch_user0001", "hash-1", baseTime)
+		created.Role, created.UserID = models.RoleUser, "u_1"
+		mustNoError(t, repo.Create(ctx, created), "Create")
+		if created.ID.IsZero() {
+			t.Fatal("Create did not assign an ID")
+		}
+		mustDuplicate(t, repo.Create(ctx, newAPIKey("sch_user0001", "hash-2", baseTime)), "Create with a taken prefix")
+		mustDuplicate(t, repo.Create(ctx, newAPIKey("sch_user0002", "hash-1", baseTime)), "Create with a taken hash")
+
+		stored, err := repo.GetByHash(ctx, "hash-1")
+		mustNoError(t, err, "GetByHash")
+		if stored == nil || stored.ID != created.ID || stored.Name != "key sch_user0001" || stored.Prefix != "sch_user0001" ||
+			stored.KeyHash != "hash-1" || stored.Role != models.RoleUser || stored.UserID != "u_1" ||
+			!sameTime(stored.CreatedAt, baseTime) || stored.RevokedAt != nil {
+			t.Fatalf("GetByHash returned %+v", stored)
+		}
+		if key, _ := repo.GetByHash(ctx, "hash-2"); key != nil {
+			t.Errorf("rejected duplicate was stored: %+v", key)
+		}
+	})
+
+	t.Run("ListAndRevoke", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		keys, err := repo.GetAll(ctx)
+		mustNoError(t, err, "GetAll empty")
+		if keys == nil || len(keys) != 0 {
+			t.Errorf("GetAll without keys must return an empty slice, got %#v", keys)
+		}
+
+		second := newAPIKey("sch_admin002", "hash-2", baseTime.Add(time.Hour))
+		first := newAPIKey("sch_admin001", "hash-1", baseTime)
+		mustNoError(t, repo.Create(ctx, second), "Create")
+		mustNoError(t, repo.Create(ctx, first), "Create")
+
+		keys, err = repo.GetAll(ctx)
+		mustNoError(t, err, "GetAll")
+		if len(keys) != 2 || keys[0].ID != first.ID || keys[1].ID != second.ID {
+			t.Fatalf("GetAll must return keys in creation order, got %+v", keys)
+		}
+
+		revoked, err := repo.Revoke(ctx, "sch_missing", baseTime)
+		mustNoError(t, err, "Revoke missing")
+		if revoked {
+			t.Error("Revoke of a missing key reported success")
+		}
+
+		revokedAt := baseTime.Add(2 * time.Hour)
+		revoked, err = repo.Revoke(ctx, first.Prefix, revokedAt)
+		mustNoError(t, err, "Revoke")
+		if !revoked {
+			t.Fatal("Revoke reported nothing revoked")
+		}
+		revoked, err = repo.Revoke(ctx, first.Prefix, revokedAt.Add(time.Hour))
+		mustNoError(t, err, "Revoke again")
+		if revoked {
+			t.Error("second Revoke of the same key reported success")
+		}
+
+		stored, err := repo.GetByHash(ctx, first.KeyHash)
+		mustNoError(t, err, "GetByHash revoked")
+		if stored == nil || !stored.IsRevoked() || !sameTime(*stored.RevokedAt, revokedAt) {
+			t.Errorf("revoked key must keep the first revocation time, got %+v", stored)
+		}
+		keys, _ = repo.GetAll(ctx)
+		if len(keys) != 2 || !keys[0].IsRevoked() || keys[1].IsRevoked() {
+			t.Errorf("GetAll must list revoked keys too, got %+v", keys)
+		}
+	})
+}
+
+func newAPIKey(prefix, keyHash string, createdAt time.Time) *models.APIKey {
+	return &models.APIKey{
+		Name:      "key " + prefix,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Role:      models.RoleAdmin,
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,158 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// EmployeeRepository проверяет справочник преподавателей: уникальны и BSUIRID, и URL ID
+func EmployeeRepository(t *testing.T, newRepo func(t *testing.T) repository.EmployeeRepository) {
+	t.Run("NotFound", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		employee, err := repo.GetByURLID(ctx, "missing-e")
+		mustNoError(t, err, "GetByURLID")
+		if employee != nil {
+			t.Fatalf("GetByURLID: expected nil, got %+v", employee)
+		}
+
+		employee, err = repo.GetByID(ctx, 42)
+		mustNoError(t, err, "GetByID")
+		if employee != nil {
+			t.Fatalf("GetByID: expected nil, got %+v", employee)
+		}
+	})
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, employee(1, "ivanov-i-i")), "Save")
+
+		stored, err := repo.GetByURLID(ctx, "ivanov-i-i")
+		mustNoError(t, err, "GetByURLID")
+		if stored == nil {
+			t.Fatal("GetByURLID: saved employee not found")
+		}
+		if stored.ID.IsZero() || stored.BSUIRID != 1 || stored.EmployeeData.LastName != "Иванов" {
+			t.Errorf("employee not preserved: %+v", stored)
+		}
+		if len(stored.EmployeeData.AcademicDept) != 1 || stored.EmployeeData.AcademicDept[0] != "ЭВМ" {
+			t.Errorf("academic departments not preserved: %v", stored.EmployeeData.AcademicDept)
+		}
+
+		byID, err := repo.GetByID(ctx, 1)
+		mustNoError(t, err, "GetByID")
+		if byID == nil || byID.URLID != "ivanov-i-i" {
+			t.Errorf("GetByID = %+v", byID)
+		}
+	})
+
+	t.Run("Uniqueness", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, employee(1, "ivanov-i-i")), "Save")
+		mustDuplicate(t, repo.Save(ctx, employee(1, "petrov-p-p")), "Save same BSUIRID")
+		mustDuplicate(t, repo.Save(ctx, employee(2, "ivanov-i-i")), "Save same URL ID")
+		mustDuplicate(t, repo.Update(ctx, employee(2, "ivanov-i-i")), "Update to a taken URL ID")
+
+		if count, _ := repo.Count(ctx); count != 1 {
+			t.Errorf("rejected writes must not be stored, Count = %d", count)
+		}
+	})
+
+	t.Run("GetByURLIDs", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.SaveMany(ctx, []models.StoredEmployee{*employee(1, "ivanov-i-i"), *employee(2, "petrov-p-p")}), "SaveMany")
+		employees, err := repo.GetByURLIDs(ctx, []string{"petrov-p-p", "missing-e"})
+		mustNoError(t, err, "GetByURLIDs")
+		if len(employees) != 1 || employees[0].URLID != "petrov-p-p" || employees[0].BSUIRID != 2 {
+			t.Errorf("GetByURLIDs returned %+v, want petrov-p-p", employees)
+		}
+	})
+
+	t.Run("SaveMany", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.SaveMany(ctx, nil), "SaveMany with no employees")
+		mustNoError(t, repo.Save(ctx, employee(2, "petrov-p-p")), "Save")
+
+		err := repo.SaveMany(ctx, []models.StoredEmployee{
+			*employee(1, "ivanov-i-i"), *employee(2, "petrov-p-p"), *employee(3, "sidorov-s-s"),
+		})
+		mustDuplicate(t, err, "SaveMany with a conflict")
+
+		employees, err := repo.GetAll(ctx)
+		mustNoError(t, err, "GetAll")
+		if len(employees) != 3 {
+			t.Fatalf("GetAll returned %d employees, want 3", len(employees))
+		}
+	})
+
+	t.Run("UpdateUpserts", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Update(ctx, employee(1, "ivanov-i-i")), "Update missing employee")
+		original, err := repo.GetByID(ctx, 1)
+		mustNoError(t, err, "GetByID")
+		if original == nil || original.ID.IsZero() {
+			t.Fatalf("Update must insert a missing employee with an ID, got %+v", original)
+		}
+
+		// URL ID преподавателя может смениться, старый при этом освобождается
+		changed := employee(1, "ivanov-i-i-2")
+		changed.EmployeeData.Rank = "доцент"
+		changed.CreatedAt = baseTime.Add(24 * time.Hour)
+		changed.UpdatedAt = baseTime.Add(24 * time.Hour)
+		mustNoError(t, repo.Update(ctx, changed), "Update existing employee")
+
+		updated, err := repo.GetByURLID(ctx, "ivanov-i-i-2")
+		mustNoError(t, err, "GetByURLID")
+		if updated == nil || updated.EmployeeData.Rank != "доцент" {
+			t.Fatalf("Update did not replace employee: %+v", updated)
+		}
+		if updated.ID != original.ID {
+			t.Errorf("Update changed ID: %v -> %v", original.ID, updated.ID)
+		}
+		if !sameTime(updated.CreatedAt, original.CreatedAt) {
+			t.Errorf("Update changed CreatedAt: %v -> %v", original.CreatedAt, updated.CreatedAt)
+		}
+		if stale, _ := repo.GetByURLID(ctx, "ivanov-i-i"); stale != nil {
+			t.Errorf("old URL ID still resolves after Update: %+v", stale)
+		}
+		mustNoError(t, repo.Save(ctx, employee(2, "ivanov-i-i")), "Save with a released URL ID")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, employee(1, "ivanov-i-i")), "Save")
+		mustNoError(t, repo.Delete(ctx, 1), "Delete")
+		mustNoError(t, repo.Delete(ctx, 1), "Delete missing")
+
+		if stored, _ := repo.GetByURLID(ctx, "ivanov-i-i"); stored != nil {
+			t.Error("deleted employee is still returned")
+		}
+		mustNoError(t, repo.Save(ctx, employee(1, "ivanov-i-i")), "Save after Delete")
+	})
+}
+
+func employee(id int, urlID string) *models.StoredEmployee {
+	return &models.StoredEmployee{
+		BSUIRID: id,
+		URLID:   urlID,
+		EmployeeData: models.EmployeeListItem{
+			ID:           id,
+			URLID:        urlID,
+			FirstName:    "Иван",
+			LastName:     "Иванов",
+			AcademicDept: []string{"ЭВМ"},
+		},
+		LastUpdateDate: "01.09.2024",
+		CreatedAt:      baseTime,
+		UpdatedAt:      baseTime,
+	}
+}
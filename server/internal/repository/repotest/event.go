@@ -0,0 +1,117 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// EventRepository проверяет личные события: порядок по началу и доступ только владельца
+func EventRepository(t *testing.T, newRepo func(t *testing.T) repository.EventRepository) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		later := newEvent("user-1", "Кружок", baseTime.Add(48*time.Hour))
+		later.Recurrence = "FREQ=WEEKLY;BYDAY=TU,TH"
+		first := newEvent("user-1", "Консультация", baseTime)
+		first.Location, first.Description = "505-5 к.", "Взять зачетку"
+		mustNoError(t, repo.Create(ctx, later), "Create")
+		mustNoError(t, repo.Create(ctx, first), "Create")
+		mustNoError(t, repo.Create(ctx, newEvent("user-2", "Чужое", baseTime)), "Create for another user")
+		if first.ID.IsZero() {
+			t.Fatal("Create did not assign an ID")
+		}
+
+		events, err := repo.GetByUser(ctx, "user-1")
+		mustNoError(t, err, "GetByUser")
+		if len(events) != 2 || events[0].ID != first.ID || events[1].ID != later.ID {
+			t.Fatalf("GetByUser must return the user's events by start, got %+v", events)
+		}
+		if events[1].Recurrence != "FREQ=WEEKLY;BYDAY=TU,TH" || !sameTime(events[1].End, later.End) {
+			t.Errorf("GetByUser lost fields: %+v", events[1])
+		}
+		if events, _ := repo.GetByUser(ctx, "user-3"); events == nil || len(events) != 0 {
+			t.Errorf("GetByUser without events must return an empty slice, got %#v", events)
+		}
+
+		stored, err := repo.GetByID(ctx, "user-1", first.ID)
+		mustNoError(t, err, "GetByID")
+		if stored == nil || stored.Title != "Консультация" || stored.Location != "505-5 к." || stored.Description != "Взять зачетку" ||
+			!sameTime(stored.Start, baseTime) || !sameTime(stored.CreatedAt, baseTime) {
+			t.Errorf("GetByID returned %+v", stored)
+		}
+		for _, lookup := range []struct {
+			user string
+			id   primitive.ObjectID
+		}{{"user-2", first.ID}, {"user-1", primitive.NewObjectID()}} {
+			if event, err := repo.GetByID(ctx, lookup.user, lookup.id); err != nil || event != nil {
+				t.Errorf("GetByID(%s, %s): expected nil, got %+v, %v", lookup.user, lookup.id.Hex(), event, err)
+			}
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		event := newEvent("user-1", "Консультация", baseTime)
+		mustNoError(t, repo.Create(ctx, event), "Create")
+
+		changed := *event
+		changed.Title, changed.Recurrence = "Подготовка", "FREQ=DAILY;COUNT=3"
+		changed.Start, changed.End = baseTime.Add(time.Hour), baseTime.Add(2*time.Hour)
+		changed.CreatedAt, changed.UpdatedAt = baseTime.Add(time.Hour), baseTime.Add(time.Hour)
+
+		stranger := changed
+		stranger.UserID = "user-2"
+		updated, err := repo.Update(ctx, &stranger)
+		mustNoError(t, err, "Update by another user")
+		if updated {
+			t.Error("Update allowed another user to change the event")
+		}
+		updated, err = repo.Update(ctx, &changed)
+		mustNoError(t, err, "Update")
+		if !updated {
+			t.Fatal("Update did not find the event")
+		}
+
+		stored, err := repo.GetByID(ctx, "user-1", event.ID)
+		mustNoError(t, err, "GetByID")
+		if stored == nil || stored.Title != "Подготовка" || stored.Recurrence != "FREQ=DAILY;COUNT=3" ||
+			!sameTime(stored.Start, baseTime.Add(time.Hour)) || !sameTime(stored.UpdatedAt, baseTime.Add(time.Hour)) {
+			t.Fatalf("Update was not applied: %+v", stored)
+		}
+		if !sameTime(stored.CreatedAt, baseTime) {
+			t.Errorf("Update must keep CreatedAt, got %v", stored.CreatedAt)
+		}
+
+		if deleted, _ := repo.Delete(ctx, "user-2", event.ID); deleted {
+			t.Error("Delete allowed another user to delete the event")
+		}
+		deleted, err := repo.Delete(ctx, "user-1", event.ID)
+		mustNoError(t, err, "Delete")
+		if !deleted {
+			t.Error("Delete did not find the event")
+		}
+		if deleted, _ := repo.Delete(ctx, "user-1", event.ID); deleted {
+			t.Error("second Delete must report false")
+		}
+		if events, _ := repo.GetByUser(ctx, "user-1"); len(events) != 0 {
+			t.Errorf("deleted event is still listed: %+v", events)
+		}
+	})
+}
+
+func newEvent(userID, title string, start time.Time) *models.Event {
+	return &models.Event{
+		UserID:    userID,
+		Title:     title,
+		Start:     start,
+		End:       start.Add(90 * time.Minute),
+		CreatedAt: baseTime,
+		UpdatedAt: baseTime,
+	}
+}
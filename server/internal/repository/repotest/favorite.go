@@ -0,0 +1,389 @@
+package repotest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// FavoriteRepository проверяет избранное: тройка (пользователь, тип, ключ) уникальна,
+// избранное разных пользователей и разных типов не пересекается, поиск — подстрока без учета регистра
+func FavoriteRepository(t *testing.T, newRepo func(t *testing.T) repository.FavoriteRepository) {
+	t.Run("Empty", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		favorites, err := repo.GetAll(ctx, "user-1", "")
+		mustNoError(t, err, "GetAll")
+		if favorites == nil || len(favorites) != 0 {
+			t.Errorf("GetAll for a user without favorites must return an empty slice, got %#v", favorites)
+		}
+
+		favorite, err := repo.Get(ctx, "user-1", models.FavoriteTypeGroup, "221701")
+		mustNoError(t, err, "Get")
+		if favorite != nil {
+			t.Errorf("Get: expected nil, got %+v", favorite)
+		}
+
+		found, err := repo.IsFavorite(ctx, "user-1", models.FavoriteTypeGroup, "221701")
+		mustNoError(t, err, "IsFavorite")
+		if found {
+			t.Error("IsFavorite is true for an empty repository")
+		}
+	})
+
+	t.Run("AddIsIdempotent", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", "221701", baseTime)), "Add")
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", "221701", baseTime.Add(time.Hour))), "Add again")
+
+		favorites, err := repo.GetAll(ctx, "user-1", "")
+		mustNoError(t, err, "GetAll")
+		if len(favorites) != 1 {
+			t.Fatalf("repeated Add must not duplicate, got %d favorites", len(favorites))
+		}
+
+		stored, err := repo.Get(ctx, "user-1", models.FavoriteTypeGroup, "221701")
+		mustNoError(t, err, "Get")
+		if stored == nil {
+			t.Fatal("Get: added favorite not found")
+		}
+		if stored.ID.IsZero() || stored.Type != models.FavoriteTypeGroup || stored.Key != "221701" || stored.UserID != "user-1" {
+			t.Errorf("Get returned %+v", stored)
+		}
+		if !sameTime(stored.CreatedAt, baseTime) {
+			t.Errorf("repeated Add changed CreatedAt: %v", stored.CreatedAt)
+		}
+		if !sameTime(stored.UpdatedAt, baseTime.Add(time.Hour)) {
+			t.Errorf("repeated Add must refresh UpdatedAt, got %v", stored.UpdatedAt)
+		}
+	})
+
+	t.Run("UsersAreIsolated", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+		keys := favoriteKeys(t)
+
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", "221701", baseTime)), "Add")
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-2", "221701", baseTime)), "Add for another user")
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-2", "221702", baseTime)), "Add for another user")
+
+		if got := keys(repo.GetAll(ctx, "user-1", "")); fmt.Sprint(got) != "[group:221701]" {
+			t.Errorf("GetAll(user-1) = %v", got)
+		}
+		if got := keys(repo.GetAll(ctx, "user-2", "")); fmt.Sprint(got) != "[group:221701 group:221702]" {
+			t.Errorf("GetAll(user-2) = %v", got)
+		}
+
+		mustNoError(t, repo.Delete(ctx, "user-1", models.FavoriteTypeGroup, "221701"), "Delete")
+		mustNoError(t, repo.Delete(ctx, "user-1", models.FavoriteTypeGroup, "221701"), "Delete missing")
+
+		if found, _ := repo.IsFavorite(ctx, "user-1", models.FavoriteTypeGroup, "221701"); found {
+			t.Error("deleted favorite is still reported")
+		}
+		if found, _ := repo.IsFavorite(ctx, "user-2", models.FavoriteTypeGroup, "221701"); !found {
+			t.Error("Delete removed another user's favorite")
+		}
+	})
+
+	t.Run("TypesAreIsolated", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+		keys := favoriteKeys(t)
+
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", "221701", baseTime)), "Add group")
+		mustNoError(t, repo.Add(ctx, newFavorite("user-1", models.FavoriteTypeEmployee, "i-ivanov", baseTime.Add(time.Minute))), "Add employee")
+		mustNoError(t, repo.Add(ctx, newFavorite("user-1", models.FavoriteTypeAuditory, "101-5 к.", baseTime.Add(2*time.Minute))), "Add auditory")
+		// Тот же ключ у другого типа — отдельная запись
+		mustNoError(t, repo.Add(ctx, newFavorite("user-1", models.FavoriteTypeEmployee, "221701", baseTime.Add(3*time.Minute))), "Add employee with a group key")
+
+		if got := keys(repo.GetAll(ctx, "user-1", "")); fmt.Sprint(got) != "[auditory:101-5 к. employee:221701 employee:i-ivanov group:221701]" {
+			t.Errorf("GetAll(all types) = %v", got)
+		}
+		if got := keys(repo.GetAll(ctx, "user-1", models.FavoriteTypeEmployee)); fmt.Sprint(got) != "[employee:221701 employee:i-ivanov]" {
+			t.Errorf("GetAll(employee) = %v", got)
+		}
+
+		all, err := repo.GetAll(ctx, "user-1", "")
+		mustNoError(t, err, "GetAll")
+		if len(all) != 4 || all[0].Key != "221701" || all[0].Type != models.FavoriteTypeGroup || all[2].Key != "101-5 к." {
+			t.Errorf("GetAll must return favorites in the order they were added, got %+v", all)
+		}
+
+		mustNoError(t, repo.Delete(ctx, "user-1", models.FavoriteTypeEmployee, "221701"), "Delete employee")
+		if found, _ := repo.IsFavorite(ctx, "user-1", models.FavoriteTypeGroup, "221701"); !found {
+			t.Error("Delete of an employee removed the group with the same key")
+		}
+		if got := keys(repo.Search(ctx, "user-1", models.FavoriteTypeGroup, "2217")); fmt.Sprint(got) != "[group:221701]" {
+			t.Errorf("Search(group) = %v", got)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+		keys := favoriteKeys(t)
+
+		for _, number := range []string{"221701", "221702", "321701", "ПОИТ-1"} {
+			mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", number, baseTime)), "Add")
+		}
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-2", "221703", baseTime)), "Add for another user")
+
+		cases := []struct {
+			query string
+			want  string
+		}{
+			{"2217", "[group:221701 group:221702]"},
+			{"701", "[group:221701 group:321701]"},
+			{"поит", "[group:ПОИТ-1]"},
+			{"2217.", "[]"},
+			{"999", "[]"},
+		}
+		for _, tc := range cases {
+			if got := keys(repo.Search(ctx, "user-1", "", tc.query)); fmt.Sprint(got) != tc.want {
+				t.Errorf("Search(%q) = %v, want %s", tc.query, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("SearchLimit", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		for i := range 105 {
+			mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", fmt.Sprintf("2%05d", i), baseTime)), "Add")
+		}
+
+		favorites, err := repo.Search(ctx, "user-1", "", "2")
+		mustNoError(t, err, "Search")
+		if len(favorites) != 100 {
+			t.Errorf("Search returned %d favorites, want the limit of 100", len(favorites))
+		}
+	})
+
+	t.Run("UpdateDetails", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		details := favoriteGroup("user-1", "221701", baseTime)
+		details.Label, details.Color, details.Pinned, details.CollectionID = "Моя группа", "#FF8800", true, "c1"
+		found, err := repo.UpdateDetails(ctx, details)
+		mustNoError(t, err, "UpdateDetails missing")
+		if found {
+			t.Error("UpdateDetails reported a missing favorite as found")
+		}
+
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", "221701", baseTime)), "Add")
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", "221702", baseTime.Add(-time.Hour))), "Add")
+		details.Position = 7
+		details.UpdatedAt = baseTime.Add(time.Hour)
+		found, err = repo.UpdateDetails(ctx, details)
+		mustNoError(t, err, "UpdateDetails")
+		if !found {
+			t.Fatal("UpdateDetails did not find the favorite")
+		}
+
+		// Повторное добавление не сбрасывает оформление
+		mustNoError(t, repo.Add(ctx, favoriteGroup("user-1", "221701", baseTime.Add(2*time.Hour))), "Add again")
+
+		stored, err := repo.Get(ctx, "user-1", models.FavoriteTypeGroup, "221701")
+		mustNoError(t, err, "Get")
+		if stored.Label != "Моя группа" || stored.Color != "#FF8800" || !stored.Pinned || stored.CollectionID != "c1" || stored.Position != 7 {
+			t.Errorf("details were not stored: %+v", stored)
+		}
+
+		// Закрепленный элемент идет первым, хотя добавлен позже
+		if got := orderedKeys(t)(repo.GetAll(ctx, "user-1", "")); fmt.Sprint(got) != "[221701 221702]" {
+			t.Errorf("pinned favorite must come first, got %v", got)
+		}
+	})
+
+	t.Run("Reorder", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+		ordered := orderedKeys(t)
+
+		ids := map[string]primitive.ObjectID{}
+		for i, number := range []string{"221701", "221702", "221703"} {
+			favorite := favoriteGroup("user-1", number, baseTime.Add(time.Duration(i)*time.Minute))
+			favorite.Position = int64(i + 10)
+			mustNoError(t, repo.Add(ctx, favorite), "Add")
+			stored, err := repo.Get(ctx, "user-1", models.FavoriteTypeGroup, number)
+			mustNoError(t, err, "Get")
+			ids[number] = stored.ID
+		}
+		// Элемент другой коллекции в перестановку вне коллекций не входит
+		inCollection := favoriteGroup("user-1", "221704", baseTime)
+		mustNoError(t, repo.Add(ctx, inCollection), "Add")
+		inCollection.CollectionID = "c1"
+		_, err := repo.UpdateDetails(ctx, inCollection)
+		mustNoError(t, err, "UpdateDetails")
+
+		// Позиции становятся 0, 1, 2: элемент коллекции с позицией 0 и более ранним CreatedAt идет первым
+		mustNoError(t, repo.Reorder(ctx, "user-1", "", []primitive.ObjectID{ids["221703"], ids["221701"], ids["221702"]}), "Reorder")
+		if got := ordered(repo.GetAll(ctx, "user-1", "")); fmt.Sprint(got) != "[221704 221703 221701 221702]" {
+			t.Errorf("GetAll after Reorder = %v", got)
+		}
+
+		for name, invalid := range map[string][]primitive.ObjectID{
+			"missing":   {ids["221701"], ids["221702"]},
+			"duplicate": {ids["221701"], ids["221701"], ids["221702"]},
+			"foreign":   {ids["221701"], ids["221702"], primitive.NewObjectID()},
+		} {
+			if err := repo.Reorder(ctx, "user-1", "", invalid); !errors.Is(err, repository.ErrReorderMismatch) {
+				t.Errorf("Reorder with %s ids: expected ErrReorderMismatch, got %v", name, err)
+			}
+		}
+		if got := ordered(repo.GetAll(ctx, "user-1", "")); fmt.Sprint(got) != "[221704 221703 221701 221702]" {
+			t.Errorf("rejected Reorder changed the order: %v", got)
+		}
+		if err := repo.Reorder(ctx, "user-2", "", []primitive.ObjectID{ids["221701"]}); !errors.Is(err, repository.ErrReorderMismatch) {
+			t.Errorf("Reorder of another user's favorites: expected ErrReorderMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Collections", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		collections, err := repo.GetCollections(ctx, "user-1")
+		mustNoError(t, err, "GetCollections")
+		if collections == nil || len(collections) != 0 {
+			t.Errorf("GetCollections without collections must return an empty slice, got %#v", collections)
+		}
+
+		groups := newCollection("user-1", "Группы", 1)
+		teachers := newCollection("user-1", "Преподаватели", 2)
+		mustNoError(t, repo.CreateCollection(ctx, groups), "CreateCollection")
+		mustNoError(t, repo.CreateCollection(ctx, teachers), "CreateCollection")
+		mustNoError(t, repo.CreateCollection(ctx, newCollection("user-2", "Группы", 1)), "CreateCollection for another user")
+		mustDuplicate(t, repo.CreateCollection(ctx, newCollection("user-1", "Группы", 3)), "CreateCollection with a taken name")
+		if groups.ID.IsZero() {
+			t.Fatal("CreateCollection did not assign an ID")
+		}
+
+		if got := collectionNames(t)(repo.GetCollections(ctx, "user-1")); fmt.Sprint(got) != "[Группы Преподаватели]" {
+			t.Errorf("GetCollections = %v", got)
+		}
+		if collection, err := repo.GetCollection(ctx, "user-2", groups.ID); err != nil || collection != nil {
+			t.Errorf("GetCollection of another user's collection = %+v, %v", collection, err)
+		}
+
+		teachers.Name = "Группы"
+		_, err = repo.UpdateCollection(ctx, teachers)
+		mustDuplicate(t, err, "UpdateCollection with a taken name")
+		teachers.Name, teachers.Color = "Кафедра", "#00AA00"
+		found, err := repo.UpdateCollection(ctx, teachers)
+		mustNoError(t, err, "UpdateCollection")
+		if !found {
+			t.Error("UpdateCollection did not find the collection")
+		}
+		stored, err := repo.GetCollection(ctx, "user-1", teachers.ID)
+		mustNoError(t, err, "GetCollection")
+		if stored == nil || stored.Name != "Кафедра" || stored.Color != "#00AA00" || stored.Position != 2 {
+			t.Errorf("GetCollection after update = %+v", stored)
+		}
+
+		mustNoError(t, repo.ReorderCollections(ctx, "user-1", []primitive.ObjectID{teachers.ID, groups.ID}), "ReorderCollections")
+		if got := collectionNames(t)(repo.GetCollections(ctx, "user-1")); fmt.Sprint(got) != "[Кафедра Группы]" {
+			t.Errorf("GetCollections after reorder = %v", got)
+		}
+		if err := repo.ReorderCollections(ctx, "user-1", []primitive.ObjectID{groups.ID}); !errors.Is(err, repository.ErrReorderMismatch) {
+			t.Errorf("ReorderCollections with a missing id: expected ErrReorderMismatch, got %v", err)
+		}
+
+		// Удаление коллекции оставляет ее элементы в избранном вне коллекций
+		favorite := favoriteGroup("user-1", "221701", baseTime)
+		mustNoError(t, repo.Add(ctx, favorite), "Add")
+		favorite.CollectionID = groups.ID.Hex()
+		_, err = repo.UpdateDetails(ctx, favorite)
+		mustNoError(t, err, "UpdateDetails")
+
+		found, err = repo.DeleteCollection(ctx, "user-2", groups.ID)
+		mustNoError(t, err, "DeleteCollection of another user")
+		if found {
+			t.Error("DeleteCollection removed another user's collection")
+		}
+		found, err = repo.DeleteCollection(ctx, "user-1", groups.ID)
+		mustNoError(t, err, "DeleteCollection")
+		if !found {
+			t.Error("DeleteCollection did not find the collection")
+		}
+		if collection, _ := repo.GetCollection(ctx, "user-1", groups.ID); collection != nil {
+			t.Error("deleted collection is still returned")
+		}
+		unassigned, err := repo.Get(ctx, "user-1", models.FavoriteTypeGroup, "221701")
+		mustNoError(t, err, "Get")
+		if unassigned == nil || unassigned.CollectionID != "" {
+			t.Errorf("favorite of a deleted collection must stay unassigned, got %+v", unassigned)
+		}
+	})
+}
+
+func newCollection(userID, name string, position int64) *models.FavoriteCollection {
+	return &models.FavoriteCollection{
+		UserID:    userID,
+		Name:      name,
+		Position:  position,
+		CreatedAt: baseTime,
+		UpdatedAt: baseTime,
+	}
+}
+
+func newFavorite(userID, favoriteType, key string, at time.Time) *models.Favorite {
+	return &models.Favorite{
+		UserID:    userID,
+		Type:      favoriteType,
+		Key:       key,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+}
+
+func favoriteGroup(userID, groupNumber string, at time.Time) *models.Favorite {
+	return newFavorite(userID, models.FavoriteTypeGroup, groupNumber, at)
+}
+
+// favoriteKeys возвращает функцию, которая превращает результат GetAll или Search
+// в отсортированные пары "тип:ключ": порядок выдачи здесь не проверяется
+func favoriteKeys(t *testing.T) func([]models.Favorite, error) []string {
+	return func(favorites []models.Favorite, err error) []string {
+		t.Helper()
+		mustNoError(t, err, "list favorites")
+
+		keys := make([]string, 0, len(favorites))
+		for _, favorite := range favorites {
+			keys = append(keys, favorite.Type+":"+favorite.Key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+}
+
+// orderedKeys — ключи избранного в порядке выдачи
+func orderedKeys(t *testing.T) func([]models.Favorite, error) []string {
+	return func(favorites []models.Favorite, err error) []string {
+		t.Helper()
+		mustNoError(t, err, "list favorites")
+
+		keys := make([]string, 0, len(favorites))
+		for _, favorite := range favorites {
+			keys = append(keys, favorite.Key)
+		}
+		return keys
+	}
+}
+
+// collectionNames — имена коллекций в порядке выдачи
+func collectionNames(t *testing.T) func([]models.FavoriteCollection, error) []string {
+	return func(collections []models.FavoriteCollection, err error) []string {
+		t.Helper()
+		mustNoError(t, err, "list collections")
+
+		names := make([]string, 0, len(collections))
+		for _, collection := range collections {
+			names = append(names, collection.Name)
+		}
+		return names
+	}
+}
@@ -0,0 +1,184 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// GroupRepository проверяет справочник групп: уникальность по BSUIRID и upsert в Update
+func GroupRepository(t *testing.T, newRepo func(t *testing.T) repository.GroupRepository) {
+	t.Run("NotFound", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		group, err := repo.GetByNumber(ctx, "000000")
+		mustNoError(t, err, "GetByNumber")
+		if group != nil {
+			t.Fatalf("GetByNumber: expected nil, got %+v", group)
+		}
+
+		group, err = repo.GetByID(ctx, 42)
+		mustNoError(t, err, "GetByID")
+		if group != nil {
+			t.Fatalf("GetByID: expected nil, got %+v", group)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		groups, err := repo.GetAll(ctx)
+		mustNoError(t, err, "GetAll")
+		if len(groups) != 0 {
+			t.Errorf("GetAll on empty repository returned %d groups", len(groups))
+		}
+
+		count, err := repo.Count(ctx)
+		mustNoError(t, err, "Count")
+		if count != 0 {
+			t.Errorf("Count on empty repository = %d", count)
+		}
+	})
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, group(1, "221701")), "Save")
+
+		byNumber, err := repo.GetByNumber(ctx, "221701")
+		mustNoError(t, err, "GetByNumber")
+		byID, err := repo.GetByID(ctx, 1)
+		mustNoError(t, err, "GetByID")
+
+		for name, stored := range map[string]*models.StoredGroup{"GetByNumber": byNumber, "GetByID": byID} {
+			if stored == nil {
+				t.Fatalf("%s: saved group not found", name)
+			}
+			if stored.ID.IsZero() || stored.BSUIRID != 1 || stored.GroupData.Name != "221701" || stored.GroupData.Course != 2 {
+				t.Errorf("%s: group not preserved: %+v", name, stored)
+			}
+			if !sameTime(stored.CreatedAt, baseTime) {
+				t.Errorf("%s: CreatedAt = %v, want %v", name, stored.CreatedAt, baseTime)
+			}
+		}
+	})
+
+	t.Run("GetByNumbers", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		groups, err := repo.GetByNumbers(ctx, nil)
+		mustNoError(t, err, "GetByNumbers with no numbers")
+		if len(groups) != 0 {
+			t.Errorf("GetByNumbers with no numbers returned %+v", groups)
+		}
+
+		mustNoError(t, repo.SaveMany(ctx, []models.StoredGroup{*group(1, "221701"), *group(2, "221702"), *group(3, "221703")}), "SaveMany")
+		groups, err = repo.GetByNumbers(ctx, []string{"221703", "000000", "221701"})
+		mustNoError(t, err, "GetByNumbers")
+		found := make(map[string]bool)
+		for _, stored := range groups {
+			found[stored.GroupData.Name] = true
+		}
+		if len(groups) != 2 || !found["221701"] || !found["221703"] {
+			t.Errorf("GetByNumbers returned %+v, want 221701 and 221703", groups)
+		}
+	})
+
+	t.Run("SaveDuplicate", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, group(1, "221701")), "Save")
+		mustDuplicate(t, repo.Save(ctx, group(1, "221702")), "Save same BSUIRID")
+	})
+
+	t.Run("SaveMany", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.SaveMany(ctx, nil), "SaveMany with no groups")
+		mustNoError(t, repo.Save(ctx, group(2, "221702")), "Save")
+
+		// Как неупорядоченная вставка: конфликт не мешает сохранить остальные группы
+		err := repo.SaveMany(ctx, []models.StoredGroup{*group(1, "221701"), *group(2, "221702"), *group(3, "221703")})
+		mustDuplicate(t, err, "SaveMany with a conflict")
+
+		count, err := repo.Count(ctx)
+		mustNoError(t, err, "Count")
+		if count != 3 {
+			t.Errorf("Count = %d, want 3", count)
+		}
+
+		groups, err := repo.GetAll(ctx)
+		mustNoError(t, err, "GetAll")
+		if len(groups) != 3 {
+			t.Fatalf("GetAll returned %d groups, want 3", len(groups))
+		}
+	})
+
+	t.Run("UpdateUpserts", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Update(ctx, group(1, "221701")), "Update missing group")
+		original, err := repo.GetByID(ctx, 1)
+		mustNoError(t, err, "GetByID")
+		if original == nil || original.ID.IsZero() {
+			t.Fatalf("Update must insert a missing group with an ID, got %+v", original)
+		}
+
+		renamed := group(1, "221799")
+		renamed.CreatedAt = baseTime.Add(24 * time.Hour)
+		renamed.UpdatedAt = baseTime.Add(24 * time.Hour)
+		mustNoError(t, repo.Update(ctx, renamed), "Update existing group")
+
+		updated, err := repo.GetByID(ctx, 1)
+		mustNoError(t, err, "GetByID")
+		if updated.GroupData.Name != "221799" {
+			t.Errorf("Update did not replace group data: %+v", updated.GroupData)
+		}
+		if updated.ID != original.ID {
+			t.Errorf("Update changed ID: %v -> %v", original.ID, updated.ID)
+		}
+		if !sameTime(updated.CreatedAt, original.CreatedAt) {
+			t.Errorf("Update changed CreatedAt: %v -> %v", original.CreatedAt, updated.CreatedAt)
+		}
+		if !sameTime(updated.UpdatedAt, renamed.UpdatedAt) {
+			t.Errorf("UpdatedAt = %v, want %v", updated.UpdatedAt, renamed.UpdatedAt)
+		}
+
+		if stored, _ := repo.GetByNumber(ctx, "221799"); stored == nil || stored.BSUIRID != 1 {
+			t.Errorf("GetByNumber does not find the group by its new name: %+v", stored)
+		}
+		if count, _ := repo.Count(ctx); count != 1 {
+			t.Errorf("Update of an existing group must not insert, Count = %d", count)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.SaveMany(ctx, []models.StoredGroup{*group(1, "221701"), *group(2, "221702")}), "SaveMany")
+		mustNoError(t, repo.Delete(ctx, 1), "Delete")
+		mustNoError(t, repo.Delete(ctx, 1), "Delete missing")
+
+		if stored, _ := repo.GetByID(ctx, 1); stored != nil {
+			t.Error("deleted group is still returned by ID")
+		}
+		if stored, _ := repo.GetByNumber(ctx, "221701"); stored != nil {
+			t.Error("deleted group is still returned by number")
+		}
+		if stored, _ := repo.GetByID(ctx, 2); stored == nil {
+			t.Error("Delete removed another group")
+		}
+	})
+}
+
+func group(id int, name string) *models.StoredGroup {
+	return &models.StoredGroup{
+		BSUIRID:        id,
+		GroupData:      models.StudentGroupListItem{ID: id, Name: name, Course: 2, FacultyName: "ФКСиС"},
+		LastUpdateDate: "01.09.2024",
+		CreatedAt:      baseTime,
+		UpdatedAt:      baseTime,
+	}
+}
@@ -0,0 +1,153 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// NoteRepository проверяет заметки к занятиям: фильтры, порядок по занятию и по сроку, доступ только владельца
+func NoteRepository(t *testing.T, newRepo func(t *testing.T) repository.NoteRepository) {
+	t.Run("ListByLesson", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		late := newNote("user-1", models.NoteKindNote, lessonRef("221701", "2026-09-07", "13:25"), baseTime)
+		early := newNote("user-1", models.NoteKindNote, lessonRef("221701", "2026-09-07", "09:00"), baseTime.Add(time.Hour))
+		otherDay := newNote("user-1", models.NoteKindHomework, lessonRef("221702", "2026-09-08", "09:00"), baseTime)
+		otherDay.Attachments = []models.Attachment{{Name: "lab1.pdf", URL: "https://example.com/lab1.pdf", ContentType: "application/pdf", Size: 1024}}
+		for _, note := range []*models.LessonNote{late, otherDay, early, newNote("user-2", models.NoteKindNote, lessonRef("221701", "2026-09-07", "09:00"), baseTime)} {
+			mustNoError(t, repo.Create(ctx, note), "Create")
+		}
+		if early.ID.IsZero() {
+			t.Fatal("Create did not assign an ID")
+		}
+
+		notes, err := repo.GetByUser(ctx, "user-1", repository.NoteFilter{})
+		mustNoError(t, err, "GetByUser")
+		if len(notes) != 3 || notes[0].ID != early.ID || notes[1].ID != late.ID || notes[2].ID != otherDay.ID {
+			t.Fatalf("GetByUser must order by lesson date and start, got %+v", notes)
+		}
+		if attachments := notes[2].Attachments; len(attachments) != 1 || attachments[0] != otherDay.Attachments[0] {
+			t.Errorf("GetByUser lost attachments: %+v", notes[2])
+		}
+		if notes[2].Lesson != otherDay.Lesson || notes[0].Attachments != nil {
+			t.Errorf("GetByUser returned %+v and %+v", notes[2].Lesson, notes[0])
+		}
+
+		notes, err = repo.GetByUser(ctx, "user-1", repository.NoteFilter{GroupNumber: "221701", Date: "2026-09-07"})
+		mustNoError(t, err, "GetByUser with filter")
+		if len(notes) != 2 || notes[0].ID != early.ID {
+			t.Errorf("GetByUser must filter by group and date, got %+v", notes)
+		}
+		if notes, _ := repo.GetByUser(ctx, "user-3", repository.NoteFilter{}); notes == nil || len(notes) != 0 {
+			t.Errorf("GetByUser without notes must return an empty slice, got %#v", notes)
+		}
+	})
+
+	t.Run("Homework", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		lesson := lessonRef("221701", "2026-09-07", "09:00")
+		soon := newHomework("user-1", lesson, baseTime.Add(24*time.Hour))
+		later := newHomework("user-1", lesson, baseTime.Add(72*time.Hour))
+		done := newHomework("user-1", lesson, baseTime.Add(12*time.Hour))
+		doneAt := baseTime.Add(time.Hour)
+		done.Done, done.DoneAt = true, &doneAt
+		for _, note := range []*models.LessonNote{later, done, soon, newNote("user-1", models.NoteKindNote, lesson, baseTime)} {
+			mustNoError(t, repo.Create(ctx, note), "Create")
+		}
+
+		homework, err := repo.GetHomework(ctx, "user-1", repository.HomeworkFilter{})
+		mustNoError(t, err, "GetHomework")
+		if len(homework) != 2 || homework[0].ID != soon.ID || homework[1].ID != later.ID {
+			t.Errorf("GetHomework must return open homework by due date, got %+v", homework)
+		}
+
+		homework, err = repo.GetHomework(ctx, "user-1", repository.HomeworkFilter{DueBefore: baseTime.Add(48 * time.Hour), IncludeDone: true})
+		mustNoError(t, err, "GetHomework with filter")
+		if len(homework) != 2 || homework[0].ID != done.ID || homework[1].ID != soon.ID {
+			t.Fatalf("GetHomework must filter by due date and include done, got %+v", homework)
+		}
+		if !homework[0].Done || homework[0].DoneAt == nil || !sameTime(*homework[0].DoneAt, doneAt) || !sameTime(*homework[1].DueAt, *soon.DueAt) {
+			t.Errorf("GetHomework lost completion state: %+v", homework[0])
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		note := newHomework("user-1", lessonRef("221701", "2026-09-07", "09:00"), baseTime.Add(24*time.Hour))
+		mustNoError(t, repo.Create(ctx, note), "Create")
+
+		changed := *note
+		doneAt, dueAt := baseTime.Add(2*time.Hour), baseTime.Add(48*time.Hour)
+		changed.Text, changed.DueAt, changed.Done, changed.DoneAt = "Сдать отчет", &dueAt, true, &doneAt
+		changed.Attachments = []models.Attachment{{Name: "report.docx", URL: "https://example.com/report.docx"}}
+		changed.Lesson.Subject, changed.UpdatedAt = "Другой", baseTime.Add(2*time.Hour)
+
+		stranger := changed
+		stranger.UserID = "user-2"
+		if updated, err := repo.Update(ctx, &stranger); err != nil || updated {
+			t.Errorf("Update by another user = %v, %v", updated, err)
+		}
+		updated, err := repo.Update(ctx, &changed)
+		mustNoError(t, err, "Update")
+		if !updated {
+			t.Fatal("Update did not find the note")
+		}
+
+		stored, err := repo.GetByID(ctx, "user-1", note.ID)
+		mustNoError(t, err, "GetByID")
+		if stored == nil || stored.Text != "Сдать отчет" || !stored.Done || stored.DoneAt == nil || !sameTime(*stored.DueAt, dueAt) ||
+			len(stored.Attachments) != 1 || !sameTime(stored.UpdatedAt, baseTime.Add(2*time.Hour)) {
+			t.Fatalf("Update was not applied: %+v", stored)
+		}
+		if stored.Lesson.Subject != "ООП" || !sameTime(stored.CreatedAt, baseTime) {
+			t.Errorf("Update must not change the lesson or CreatedAt: %+v", stored)
+		}
+
+		changed.Done, changed.DoneAt, changed.Attachments = false, nil, nil
+		mustNoError(t, func() error { _, err := repo.Update(ctx, &changed); return err }(), "Update")
+		if stored, _ := repo.GetByID(ctx, "user-1", note.ID); stored == nil || stored.Done || stored.DoneAt != nil || len(stored.Attachments) != 0 {
+			t.Errorf("Update must clear completion and attachments: %+v", stored)
+		}
+
+		if stored, _ := repo.GetByID(ctx, "user-2", note.ID); stored != nil {
+			t.Errorf("GetByID returned another user's note: %+v", stored)
+		}
+		if deleted, _ := repo.Delete(ctx, "user-2", note.ID); deleted {
+			t.Error("Delete allowed another user to delete the note")
+		}
+		deleted, err := repo.Delete(ctx, "user-1", note.ID)
+		mustNoError(t, err, "Delete")
+		if !deleted {
+			t.Error("Delete did not find the note")
+		}
+		if stored, _ := repo.GetByID(ctx, "user-1", note.ID); stored != nil {
+			t.Errorf("deleted note is still stored: %+v", stored)
+		}
+	})
+}
+
+func lessonRef(group, date, start string) models.LessonRef {
+	return models.LessonRef{GroupNumber: group, Subject: "ООП", LessonType: "ЛР", Date: date, Start: start}
+}
+
+func newNote(userID, kind string, lesson models.LessonRef, at time.Time) *models.LessonNote {
+	return &models.LessonNote{
+		UserID:    userID,
+		Kind:      kind,
+		Lesson:    lesson,
+		Text:      "Лабораторная 1",
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+}
+
+func newHomework(userID string, lesson models.LessonRef, due time.Time) *models.LessonNote {
+	note := newNote(userID, models.NoteKindHomework, lesson, baseTime)
+	note.DueAt = &due
+	return note
+}
@@ -0,0 +1,117 @@
+package repotest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// OverrideRepository проверяет личные правки расписания: порядок создания, выборку по расписанию и доступ только владельца
+func OverrideRepository(t *testing.T, newRepo func(t *testing.T) repository.OverrideRepository) {
+	t.Run("CreateAndList", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		later := newOverride("user-1", models.FavoriteTypeGroup, "221701", "ООП", baseTime.Add(time.Hour))
+		later.Auditories, later.StartTime, later.EndTime = []string{"505-5 к.", "506-5 к."}, "10:35", "12:10"
+		first := newOverride("user-1", models.FavoriteTypeGroup, "221701", "Факультатив", baseTime)
+		first.Series = models.LessonSeries{Subject: "Факультатив", LessonType: "ЛК", Weekday: "Среда", Start: "18:45", Subgroup: 2}
+		first.Hidden = true
+		employee := newOverride("user-1", models.FavoriteTypeEmployee, "i-ivanov", "ООП", baseTime.Add(2*time.Hour))
+		for _, override := range []*models.LessonOverride{later, first, employee, newOverride("user-2", models.FavoriteTypeGroup, "221701", "ООП", baseTime)} {
+			mustNoError(t, repo.Create(ctx, override), "Create")
+		}
+		if first.ID.IsZero() {
+			t.Fatal("Create did not assign an ID")
+		}
+
+		overrides, err := repo.GetByUser(ctx, "user-1")
+		mustNoError(t, err, "GetByUser")
+		if len(overrides) != 3 || overrides[0].ID != first.ID || overrides[1].ID != later.ID || overrides[2].ID != employee.ID {
+			t.Fatalf("GetByUser must order by creation, got %+v", overrides)
+		}
+		if overrides[0].Series != first.Series || !overrides[0].Hidden || overrides[0].Auditories != nil {
+			t.Errorf("GetByUser lost fields: %+v", overrides[0])
+		}
+		if !slices.Equal(overrides[1].Auditories, later.Auditories) || overrides[1].StartTime != "10:35" || overrides[1].EndTime != "12:10" {
+			t.Errorf("GetByUser lost the override values: %+v", overrides[1])
+		}
+		if overrides, _ := repo.GetByUser(ctx, "user-3"); overrides == nil || len(overrides) != 0 {
+			t.Errorf("GetByUser without overrides must return an empty slice, got %#v", overrides)
+		}
+
+		overrides, err = repo.GetBySchedule(ctx, "user-1", models.FavoriteTypeGroup, "221701")
+		mustNoError(t, err, "GetBySchedule")
+		if len(overrides) != 2 || overrides[0].ID != first.ID || overrides[1].ID != later.ID {
+			t.Errorf("GetBySchedule must return only the group's overrides, got %+v", overrides)
+		}
+		if overrides, _ := repo.GetBySchedule(ctx, "user-1", models.FavoriteTypeGroup, "221702"); overrides == nil || len(overrides) != 0 {
+			t.Errorf("GetBySchedule without overrides must return an empty slice, got %#v", overrides)
+		}
+
+		for _, lookup := range []struct {
+			user string
+			id   primitive.ObjectID
+		}{{"user-2", first.ID}, {"user-1", primitive.NewObjectID()}} {
+			if override, err := repo.GetByID(ctx, lookup.user, lookup.id); err != nil || override != nil {
+				t.Errorf("GetByID(%s, %s): expected nil, got %+v, %v", lookup.user, lookup.id.Hex(), override, err)
+			}
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		override := newOverride("user-1", models.FavoriteTypeGroup, "221701", "ООП", baseTime)
+		override.Auditories = []string{"505-5 к."}
+		mustNoError(t, repo.Create(ctx, override), "Create")
+
+		changed := *override
+		changed.Series = models.LessonSeries{Subject: "ВМ", LessonType: "ПЗ"}
+		changed.Hidden, changed.Auditories = true, nil
+		changed.CreatedAt, changed.UpdatedAt = baseTime.Add(time.Hour), baseTime.Add(time.Hour)
+		updated, err := repo.Update(ctx, &changed)
+		mustNoError(t, err, "Update")
+		if !updated {
+			t.Fatal("Update must find the override")
+		}
+		stored, err := repo.GetByID(ctx, "user-1", override.ID)
+		mustNoError(t, err, "GetByID")
+		if stored == nil || stored.Series != changed.Series || !stored.Hidden || stored.Auditories != nil ||
+			!sameTime(stored.CreatedAt, baseTime) || !sameTime(stored.UpdatedAt, baseTime.Add(time.Hour)) {
+			t.Errorf("Update must replace the override and keep created_at, got %+v", stored)
+		}
+
+		foreign := changed
+		foreign.UserID = "user-2"
+		if updated, err := repo.Update(ctx, &foreign); err != nil || updated {
+			t.Errorf("Update of another user's override = %v, %v", updated, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-2", override.ID); err != nil || deleted {
+			t.Errorf("Delete of another user's override = %v, %v", deleted, err)
+		}
+		deleted, err := repo.Delete(ctx, "user-1", override.ID)
+		mustNoError(t, err, "Delete")
+		if !deleted {
+			t.Error("Delete must report the removed override")
+		}
+		if deleted, _ := repo.Delete(ctx, "user-1", override.ID); deleted {
+			t.Error("second Delete must report false")
+		}
+	})
+}
+
+func newOverride(userID, scheduleType, key, subject string, at time.Time) *models.LessonOverride {
+	return &models.LessonOverride{
+		UserID:       userID,
+		ScheduleType: scheduleType,
+		Key:          key,
+		Series:       models.LessonSeries{Subject: subject},
+		CreatedAt:    at,
+		UpdatedAt:    at,
+	}
+}
@@ -0,0 +1,167 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// ReminderRepository проверяет подписки на напоминания (одна на пользователя, замена сохраняет ID и CreatedAt)
+// и отметки об отправке, по которым планировщик не шлет напоминание дважды
+func ReminderRepository(t *testing.T, newRepo func(t *testing.T) repository.ReminderRepository) {
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		subscription := newSubscription("user-1", baseTime)
+		subscription.QuietHours = &models.QuietHours{From: "23:00", To: "07:00"}
+		mustNoError(t, repo.SaveSubscription(ctx, subscription), "SaveSubscription")
+		if subscription.ID.IsZero() {
+			t.Fatal("SaveSubscription did not assign an ID")
+		}
+		mustNoError(t, repo.SaveSubscription(ctx, newSubscription("user-0", baseTime)), "SaveSubscription")
+
+		stored, err := repo.GetSubscription(ctx, "user-1")
+		mustNoError(t, err, "GetSubscription")
+		if stored == nil || stored.ID != subscription.ID || stored.Channel != models.ReminderChannelWebhook ||
+			stored.Target != subscription.Target || stored.LeadMinutes != 15 {
+			t.Fatalf("GetSubscription = %+v, want %+v", stored, subscription)
+		}
+		if stored.QuietHours == nil || *stored.QuietHours != *subscription.QuietHours {
+			t.Errorf("GetSubscription lost quiet hours: %+v", stored.QuietHours)
+		}
+		if !sameTime(stored.CreatedAt, baseTime) {
+			t.Errorf("CreatedAt = %v, want %v", stored.CreatedAt, baseTime)
+		}
+
+		missing, err := repo.GetSubscription(ctx, "user-2")
+		mustNoError(t, err, "GetSubscription missing")
+		if missing != nil {
+			t.Errorf("GetSubscription for a user without a subscription must return nil, got %+v", missing)
+		}
+
+		subscriptions, err := repo.GetSubscriptions(ctx)
+		mustNoError(t, err, "GetSubscriptions")
+		if len(subscriptions) != 2 || subscriptions[0].UserID != "user-0" || subscriptions[1].UserID != "user-1" {
+			t.Errorf("GetSubscriptions must order by user, got %+v", subscriptions)
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		original := newSubscription("user-1", baseTime)
+		original.QuietHours = &models.QuietHours{From: "23:00", To: "07:00"}
+		mustNoError(t, repo.SaveSubscription(ctx, original), "SaveSubscription")
+
+		replacement := newSubscription("user-1", baseTime.Add(time.Hour))
+		replacement.Channel, replacement.Target, replacement.LeadMinutes = models.ReminderChannelEmail, "student@example.com", 30
+		mustNoError(t, repo.SaveSubscription(ctx, replacement), "SaveSubscription replace")
+		if replacement.ID != original.ID || !sameTime(replacement.CreatedAt, baseTime) {
+			t.Errorf("SaveSubscription must keep ID and CreatedAt, got %v %v", replacement.ID, replacement.CreatedAt)
+		}
+
+		stored, err := repo.GetSubscription(ctx, "user-1")
+		mustNoError(t, err, "GetSubscription")
+		if stored == nil || stored.Channel != models.ReminderChannelEmail || stored.Target != "student@example.com" || stored.LeadMinutes != 30 {
+			t.Fatalf("GetSubscription after replace = %+v", stored)
+		}
+		if stored.QuietHours != nil {
+			t.Errorf("replacement without quiet hours must clear them, got %+v", stored.QuietHours)
+		}
+		if !sameTime(stored.UpdatedAt, baseTime.Add(time.Hour)) {
+			t.Errorf("UpdatedAt = %v, want %v", stored.UpdatedAt, baseTime.Add(time.Hour))
+		}
+
+		subscriptions, err := repo.GetSubscriptions(ctx)
+		mustNoError(t, err, "GetSubscriptions")
+		if len(subscriptions) != 1 {
+			t.Errorf("replace must not add a second subscription, got %+v", subscriptions)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.SaveSubscription(ctx, newSubscription("user-1", baseTime)), "SaveSubscription")
+
+		deleted, err := repo.DeleteSubscription(ctx, "user-2")
+		mustNoError(t, err, "DeleteSubscription another user")
+		if deleted {
+			t.Error("DeleteSubscription must not report deleting a missing subscription")
+		}
+		deleted, err = repo.DeleteSubscription(ctx, "user-1")
+		mustNoError(t, err, "DeleteSubscription")
+		if !deleted {
+			t.Error("DeleteSubscription did not report the deletion")
+		}
+		if stored, _ := repo.GetSubscription(ctx, "user-1"); stored != nil {
+			t.Errorf("subscription still exists after delete: %+v", stored)
+		}
+	})
+
+	t.Run("MarkSent", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		marked, err := repo.MarkSent(ctx, "user-1", "lesson-1", baseTime)
+		mustNoError(t, err, "MarkSent")
+		if !marked {
+			t.Fatal("first MarkSent must mark the reminder")
+		}
+		marked, err = repo.MarkSent(ctx, "user-1", "lesson-1", baseTime.Add(time.Minute))
+		mustNoError(t, err, "MarkSent again")
+		if marked {
+			t.Error("second MarkSent must report the reminder as already sent")
+		}
+		marked, err = repo.MarkSent(ctx, "user-2", "lesson-1", baseTime)
+		mustNoError(t, err, "MarkSent another user")
+		if !marked {
+			t.Error("marks of different users must not collide")
+		}
+
+		mustNoError(t, repo.UnmarkSent(ctx, "user-1", "lesson-1"), "UnmarkSent")
+		mustNoError(t, repo.UnmarkSent(ctx, "user-1", "missing"), "UnmarkSent missing")
+		marked, err = repo.MarkSent(ctx, "user-1", "lesson-1", baseTime)
+		mustNoError(t, err, "MarkSent after unmark")
+		if !marked {
+			t.Error("MarkSent after UnmarkSent must mark the reminder again")
+		}
+	})
+
+	t.Run("DeleteSentBefore", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		for key, sentAt := range map[string]time.Time{
+			"old-1": baseTime,
+			"old-2": baseTime.Add(time.Hour),
+			"new":   baseTime.Add(3 * time.Hour),
+		} {
+			_, err := repo.MarkSent(ctx, "user-1", key, sentAt)
+			mustNoError(t, err, "MarkSent")
+		}
+
+		deleted, err := repo.DeleteSentBefore(ctx, baseTime.Add(2*time.Hour))
+		mustNoError(t, err, "DeleteSentBefore")
+		if deleted != 2 {
+			t.Errorf("DeleteSentBefore deleted %d marks, want 2", deleted)
+		}
+		if marked, _ := repo.MarkSent(ctx, "user-1", "old-1", baseTime); !marked {
+			t.Error("expired mark must be deleted")
+		}
+		if marked, _ := repo.MarkSent(ctx, "user-1", "new", baseTime); marked {
+			t.Error("fresh mark must be kept")
+		}
+	})
+}
+
+func newSubscription(userID string, at time.Time) *models.ReminderSubscription {
+	return &models.ReminderSubscription{
+		UserID:      userID,
+		Channel:     models.ReminderChannelWebhook,
+		Target:      "https://example.com/hooks/" + userID,
+		LeadMinutes: 15,
+		CreatedAt:   at,
+		UpdatedAt:   at,
+	}
+}
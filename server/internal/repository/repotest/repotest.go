@@ -0,0 +1,80 @@
+// Package repotest — общий набор тестов на соответствие контракту репозиториев.
+// Его проходит каждый драйвер хранилища (mongodb, bolt, postgres, memory), чтобы поведение
+// сервиса не зависело от выбранного storage.driver: upsert сохраняет ID и CreatedAt,
+// отсутствующая запись — это nil, nil, нарушение уникальности — repository.ErrDuplicate.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"schedluer/internal/repository"
+)
+
+// OpenStore открывает пустое хранилище для одного теста; закрывать его должна сама функция через t.Cleanup
+type OpenStore func(t *testing.T) repository.Store
+
+// Run прогоняет наборы для всех репозиториев хранилища, открывая чистое хранилище на каждый тест
+func Run(t *testing.T, open OpenStore) {
+	t.Run("Schedules", func(t *testing.T) {
+		ScheduleRepository(t, func(t *testing.T) repository.ScheduleRepository { return open(t).Schedules() })
+	})
+	t.Run("Groups", func(t *testing.T) {
+		GroupRepository(t, func(t *testing.T) repository.GroupRepository { return open(t).Groups() })
+	})
+	t.Run("Employees", func(t *testing.T) {
+		EmployeeRepository(t, func(t *testing.T) repository.EmployeeRepository { return open(t).Employees() })
+	})
+	t.Run("Favorites", func(t *testing.T) {
+		FavoriteRepository(t, func(t *testing.T) repository.FavoriteRepository { return open(t).Favorites() })
+	})
+	t.Run("APIKeys", func(t *testing.T) {
+		APIKeyRepository(t, func(t *testing.T) repository.APIKeyRepository { return open(t).APIKeys() })
+	})
+	t.Run("Shares", func(t *testing.T) {
+		ShareRepository(t, func(t *testing.T) repository.ShareRepository { return open(t).Shares() })
+	})
+	t.Run("Events", func(t *testing.T) {
+		EventRepository(t, func(t *testing.T) repository.EventRepository { return open(t).Events() })
+	})
+	t.Run("Notes", func(t *testing.T) {
+		NoteRepository(t, func(t *testing.T) repository.NoteRepository { return open(t).Notes() })
+	})
+	t.Run("Overrides", func(t *testing.T) {
+		OverrideRepository(t, func(t *testing.T) repository.OverrideRepository { return open(t).Overrides() })
+	})
+	t.Run("Reminders", func(t *testing.T) {
+		ReminderRepository(t, func(t *testing.T) repository.ReminderRepository { return open(t).Reminders() })
+	})
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func mustNoError(t *testing.T, err error, action string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", action, err)
+	}
+}
+
+func mustDuplicate(t *testing.T, err error, action string) {
+	t.Helper()
+	if !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("%s: expected ErrDuplicate, got %v", action, err)
+	}
+}
+
+// sameTime сравнивает время с точностью до миллисекунды: MongoDB хранит только миллисекунды
+func sameTime(a, b time.Time) bool {
+	diff := a.Sub(b)
+	return diff < time.Millisecond && diff > -time.Millisecond
+}
+
+// baseTime — фиксированный момент без наносекунд, чтобы сравнения не зависели от точности драйвера
+var baseTime = time.Date(2024, time.September, 2, 8, 0, 0, 0, time.UTC)
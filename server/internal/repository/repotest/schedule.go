@@ -0,0 +1,176 @@
+package repotest
+
+import (
+	"testing"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// ScheduleRepository проверяет кэш расписаний групп и преподавателей
+func ScheduleRepository(t *testing.T, newRepo func(t *testing.T) repository.ScheduleRepository) {
+	t.Run("NotFound", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		schedule, err := repo.GetByGroupNumber(ctx, "000000")
+		mustNoError(t, err, "GetByGroupNumber")
+		if schedule != nil {
+			t.Fatalf("GetByGroupNumber: expected nil for missing group, got %+v", schedule)
+		}
+
+		schedule, err = repo.GetByEmployeeURLID(ctx, "missing-e")
+		mustNoError(t, err, "GetByEmployeeURLID")
+		if schedule != nil {
+			t.Fatalf("GetByEmployeeURLID: expected nil for missing employee, got %+v", schedule)
+		}
+	})
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, groupSchedule("221701", "01.09.2024")), "Save")
+
+		stored, err := repo.GetByGroupNumber(ctx, "221701")
+		mustNoError(t, err, "GetByGroupNumber")
+		if stored == nil {
+			t.Fatal("GetByGroupNumber: saved schedule not found")
+		}
+		if stored.ID.IsZero() {
+			t.Error("stored schedule has no ID")
+		}
+		if stored.ScheduleData.StartDate != "01.09.2024" || stored.LastUpdateDate != "01.09.2024" {
+			t.Errorf("schedule data not preserved: %+v", stored)
+		}
+		if stored.CreatedAt.IsZero() || stored.UpdatedAt.IsZero() {
+			t.Errorf("Save must set timestamps, got created_at=%v updated_at=%v", stored.CreatedAt, stored.UpdatedAt)
+		}
+	})
+
+	t.Run("SaveDuplicate", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, groupSchedule("221701", "01.09.2024")), "Save")
+		mustDuplicate(t, repo.Save(ctx, groupSchedule("221701", "02.09.2024")), "Save same group")
+
+		mustNoError(t, repo.Save(ctx, employeeSchedule("ivanov-i-i", "01.09.2024")), "Save employee")
+		mustDuplicate(t, repo.Save(ctx, employeeSchedule("ivanov-i-i", "02.09.2024")), "Save same employee")
+	})
+
+	t.Run("EmployeeSchedulesDoNotCollide", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		// У расписаний преподавателей нет номера группы: пустой ключ не должен считаться дубликатом
+		mustNoError(t, repo.Save(ctx, employeeSchedule("ivanov-i-i", "01.09.2024")), "Save first employee")
+		mustNoError(t, repo.Save(ctx, employeeSchedule("petrov-p-p", "01.09.2024")), "Save second employee")
+		mustNoError(t, repo.Update(ctx, employeeSchedule("sidorov-s-s", "01.09.2024")), "Update third employee")
+
+		for _, urlID := range []string{"ivanov-i-i", "petrov-p-p", "sidorov-s-s"} {
+			stored, err := repo.GetByEmployeeURLID(ctx, urlID)
+			mustNoError(t, err, "GetByEmployeeURLID")
+			if stored == nil || stored.EmployeeURLID != urlID {
+				t.Errorf("GetByEmployeeURLID(%q) = %+v", urlID, stored)
+			}
+		}
+	})
+
+	t.Run("UpdateInserts", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Update(ctx, groupSchedule("221701", "01.09.2024")), "Update")
+		mustNoError(t, repo.Update(ctx, employeeSchedule("ivanov-i-i", "01.09.2024")), "Update employee")
+
+		group, err := repo.GetByGroupNumber(ctx, "221701")
+		mustNoError(t, err, "GetByGroupNumber")
+		if group == nil || group.GroupNumber != "221701" || group.ScheduleData.StartDate != "01.09.2024" {
+			t.Fatalf("Update must create a missing group schedule findable by number, got %+v", group)
+		}
+
+		employee, err := repo.GetByEmployeeURLID(ctx, "ivanov-i-i")
+		mustNoError(t, err, "GetByEmployeeURLID")
+		if employee == nil || employee.EmployeeURLID != "ivanov-i-i" {
+			t.Fatalf("Update must create a missing employee schedule findable by URL ID, got %+v", employee)
+		}
+	})
+
+	t.Run("UpdateReplaces", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, groupSchedule("221701", "01.09.2024")), "Save")
+		original, err := repo.GetByGroupNumber(ctx, "221701")
+		mustNoError(t, err, "GetByGroupNumber")
+
+		mustNoError(t, repo.Update(ctx, groupSchedule("221701", "08.09.2024")), "Update")
+
+		updated, err := repo.GetByGroupNumber(ctx, "221701")
+		mustNoError(t, err, "GetByGroupNumber")
+		if updated.ScheduleData.StartDate != "08.09.2024" || updated.LastUpdateDate != "08.09.2024" {
+			t.Errorf("Update did not replace data: %+v", updated)
+		}
+		if updated.ID != original.ID {
+			t.Errorf("Update changed ID: %v -> %v", original.ID, updated.ID)
+		}
+		if !sameTime(updated.CreatedAt, original.CreatedAt) {
+			t.Errorf("Update changed CreatedAt: %v -> %v", original.CreatedAt, updated.CreatedAt)
+		}
+		if updated.UpdatedAt.Before(original.UpdatedAt) {
+			t.Errorf("Update moved UpdatedAt back: %v -> %v", original.UpdatedAt, updated.UpdatedAt)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		mustNoError(t, repo.Save(ctx, groupSchedule("221701", "01.09.2024")), "Save")
+		mustNoError(t, repo.Save(ctx, groupSchedule("221702", "01.09.2024")), "Save")
+		mustNoError(t, repo.Save(ctx, employeeSchedule("ivanov-i-i", "01.09.2024")), "Save employee")
+
+		mustNoError(t, repo.Delete(ctx, "221701"), "Delete")
+		mustNoError(t, repo.Delete(ctx, "221701"), "Delete missing")
+		mustNoError(t, repo.DeleteByEmployeeURLID(ctx, "ivanov-i-i"), "DeleteByEmployeeURLID")
+		mustNoError(t, repo.DeleteByEmployeeURLID(ctx, "ivanov-i-i"), "DeleteByEmployeeURLID missing")
+
+		if stored, _ := repo.GetByGroupNumber(ctx, "221701"); stored != nil {
+			t.Error("deleted group schedule is still returned")
+		}
+		if stored, _ := repo.GetByEmployeeURLID(ctx, "ivanov-i-i"); stored != nil {
+			t.Error("deleted employee schedule is still returned")
+		}
+		if stored, _ := repo.GetByGroupNumber(ctx, "221702"); stored == nil {
+			t.Error("Delete removed another group's schedule")
+		}
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		schedule := groupSchedule("221701", "01.09.2024")
+		mustNoError(t, repo.Save(ctx, schedule), "Save")
+		schedule.ScheduleData.StartDate = "changed after save"
+
+		stored, err := repo.GetByGroupNumber(ctx, "221701")
+		mustNoError(t, err, "GetByGroupNumber")
+		stored.ScheduleData.StartDate = "changed after get"
+
+		again, err := repo.GetByGroupNumber(ctx, "221701")
+		mustNoError(t, err, "GetByGroupNumber")
+		if again.ScheduleData.StartDate != "01.09.2024" {
+			t.Errorf("stored schedule shares memory with callers: start date %q", again.ScheduleData.StartDate)
+		}
+	})
+}
+
+func groupSchedule(groupNumber, date string) *models.StoredSchedule {
+	return &models.StoredSchedule{
+		GroupNumber:    groupNumber,
+		ScheduleData:   models.ScheduleResponse{StartDate: date},
+		LastUpdateDate: date,
+	}
+}
+
+func employeeSchedule(urlID, date string) *models.StoredSchedule {
+	return &models.StoredSchedule{
+		EmployeeURLID:  urlID,
+		ScheduleData:   models.ScheduleResponse{StartDate: date},
+		LastUpdateDate: date,
+	}
+}
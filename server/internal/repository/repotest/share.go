@@ -0,0 +1,90 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+// ShareRepository проверяет публичные ссылки: хэш токена уникален, отзыв — только владельцем и один раз
+func ShareRepository(t *testing.T, newRepo func(t *testing.T) repository.ShareRepository) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		share, err := repo.GetByHash(ctx, "missing")
+		mustNoError(t, err, "GetByHash missing")
+		if share != nil {
+			t.Errorf("GetByHash: expected nil, got %+v", share)
+		}
+
+		expires := baseTime.Add(24 * time.Hour)
+		created := newShare("user-1", "hash-1", baseTime)
+		created.CollectionID, created.ExpiresAt = "c1", &expires
+		mustNoError(t, repo.Create(ctx, created), "Create")
+		if created.ID.IsZero() {
+			t.Fatal("Create did not assign an ID")
+		}
+		mustDuplicate(t, repo.Create(ctx, newShare("user-2", "hash-1", baseTime)), "Create with a taken token hash")
+
+		stored, err := repo.GetByHash(ctx, "hash-1")
+		mustNoError(t, err, "GetByHash")
+		if stored == nil || stored.ID != created.ID || stored.UserID != "user-1" || stored.TokenHash != "hash-1" ||
+			stored.Kind != models.ShareKindTimetable || stored.CollectionID != "c1" || stored.Prefix != "hash" {
+			t.Fatalf("GetByHash returned %+v", stored)
+		}
+		if stored.ExpiresAt == nil || !sameTime(*stored.ExpiresAt, expires) || stored.RevokedAt != nil {
+			t.Errorf("GetByHash lost expiry: %+v", stored)
+		}
+	})
+
+	t.Run("ListAndRevoke", func(t *testing.T) {
+		repo, ctx := newRepo(t), testContext(t)
+
+		second := newShare("user-1", "hash-2", baseTime.Add(time.Hour))
+		first := newShare("user-1", "hash-1", baseTime)
+		mustNoError(t, repo.Create(ctx, second), "Create")
+		mustNoError(t, repo.Create(ctx, first), "Create")
+		mustNoError(t, repo.Create(ctx, newShare("user-2", "hash-3", baseTime)), "Create for another user")
+
+		shares, err := repo.GetByUser(ctx, "user-1")
+		mustNoError(t, err, "GetByUser")
+		if len(shares) != 2 || shares[0].ID != first.ID || shares[1].ID != second.ID {
+			t.Errorf("GetByUser must return the user's shares in creation order, got %+v", shares)
+		}
+		if shares, _ := repo.GetByUser(ctx, "user-3"); shares == nil || len(shares) != 0 {
+			t.Errorf("GetByUser without shares must return an empty slice, got %#v", shares)
+		}
+
+		revoked, err := repo.Revoke(ctx, "user-2", first.ID, baseTime)
+		mustNoError(t, err, "Revoke by another user")
+		if revoked {
+			t.Error("Revoke allowed another user to revoke the share")
+		}
+		revoked, err = repo.Revoke(ctx, "user-1", first.ID, baseTime.Add(2*time.Hour))
+		mustNoError(t, err, "Revoke")
+		if !revoked {
+			t.Error("Revoke did not find the share")
+		}
+		if revoked, _ := repo.Revoke(ctx, "user-1", first.ID, baseTime.Add(3*time.Hour)); revoked {
+			t.Error("Revoke of a revoked share must report false")
+		}
+
+		stored, err := repo.GetByHash(ctx, "hash-1")
+		mustNoError(t, err, "GetByHash")
+		if stored == nil || stored.RevokedAt == nil || !sameTime(*stored.RevokedAt, baseTime.Add(2*time.Hour)) {
+			t.Errorf("revoked share must keep the first revocation time, got %+v", stored)
+		}
+	})
+}
+
+func newShare(userID, tokenHash string, at time.Time) *models.Share {
+	return &models.Share{
+		UserID:    userID,
+		Kind:      models.ShareKindTimetable,
+		Prefix:    tokenHash[:4],
+		TokenHash: tokenHash,
+		CreatedAt: at,
+	}
+}
@@ -75,30 +75,38 @@ func (r *scheduleRepository) Save(ctx context.Context, schedule *models.StoredSc
 	schedule.UpdatedAt = time.Now()
 
 	_, err := r.collection.InsertOne(ctx, schedule)
-	return err
+	return mongoError(err)
 }
 
+// Update создает или заменяет расписание группы либо преподавателя. Ищем по тому ключу,
+// который задан, и записываем его в документ, иначе вставленное upsert'ом расписание не найти.
 func (r *scheduleRepository) Update(ctx context.Context, schedule *models.StoredSchedule) error {
 	schedule.UpdatedAt = time.Now()
 
-	filter := bson.M{
-		"$or": []bson.M{
-			{"group_number": schedule.GroupNumber},
-			{"employee_url_id": schedule.EmployeeURLID},
-		},
+	filter := bson.M{"group_number": schedule.GroupNumber}
+	if schedule.GroupNumber == "" {
+		filter = bson.M{"employee_url_id": schedule.EmployeeURLID}
+	}
+
+	set := bson.M{
+		"schedule_data":    schedule.ScheduleData,
+		"last_update_date": schedule.LastUpdateDate,
+		"updated_at":       schedule.UpdatedAt,
+	}
+	for key, value := range filter {
+		set[key] = value
 	}
 
 	update := bson.M{
-		"$set": bson.M{
-			"schedule_data":    schedule.ScheduleData,
-			"last_update_date": schedule.LastUpdateDate,
-			"updated_at":       schedule.UpdatedAt,
+		"$set": set,
+		"$setOnInsert": bson.M{
+			"created_at": schedule.UpdatedAt,
 		},
 	}
 
 	opts := options.UpdateOne().SetUpsert(true)
 	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	return mongoError(err)
 }
 
 func (r *scheduleRepository) Delete(ctx context.Context, groupNumber string) error {
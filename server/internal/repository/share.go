@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"schedluer/internal/models"
+)
+
+// ShareRepository хранит публичные ссылки. Отозванные ссылки остаются в списке владельца.
+type ShareRepository interface {
+	GetByHash(ctx context.Context, tokenHash string) (*models.Share, error)
+	// GetByUser возвращает ссылки пользователя в порядке создания
+	GetByUser(ctx context.Context, userID string) ([]models.Share, error)
+	Create(ctx context.Context, share *models.Share) error
+	// Revoke отзывает ссылку пользователя; false, если ее нет или она уже отозвана
+	Revoke(ctx context.Context, userID string, id primitive.ObjectID, revokedAt time.Time) (bool, error)
+}
+
+type shareRepository struct {
+	collection *mongo.Collection
+}
+
+func NewShareRepository(db *mongo.Database) ShareRepository {
+	collection := db.Collection("shares")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &shareRepository{
+		collection: collection,
+	}
+}
+
+func (r *shareRepository) GetByHash(ctx context.Context, tokenHash string) (*models.Share, error) {
+	var share models.Share
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&share)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *shareRepository) GetByUser(ctx context.Context, userID string) ([]models.Share, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []models.Share{}
+	if err := cursor.All(ctx, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (r *shareRepository) Create(ctx context.Context, share *models.Share) error {
+	if share.ID.IsZero() {
+		share.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, share)
+	return mongoError(err)
+}
+
+func (r *shareRepository) Revoke(ctx context.Context, userID string, id primitive.ObjectID, revokedAt time.Time) (bool, error) {
+	filter := bson.M{"_id": id, "user_id": userID, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": revokedAt}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
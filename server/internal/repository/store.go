@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ErrDuplicate возвращается при нарушении уникальности (номер группы, URL ID, ключ избранного и т.д.)
+var ErrDuplicate = errors.New("duplicate key")
+
+// ErrReorderMismatch возвращается, если список для перестановки не совпадает с сохраненными элементами:
+// кто-то параллельно добавил или удалил элемент, и клиенту нужно перечитать список
+var ErrReorderMismatch = errors.New("reorder list does not match stored items")
+
+// Store — хранилище, выбранное в конфиге (storage.driver): репозитории поверх одного подключения
+type Store interface {
+	Schedules() ScheduleRepository
+	Groups() GroupRepository
+	Employees() EmployeeRepository
+	Favorites() FavoriteRepository
+	APIKeys() APIKeyRepository
+	Shares() ShareRepository
+	Events() EventRepository
+	Notes() NoteRepository
+	Overrides() OverrideRepository
+	Reminders() ReminderRepository
+
+	// Driver — имя драйвера для логов и /readyz
+	Driver() string
+	// Health проверяет, что хранилище доступно
+	Health(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Migrator реализуют хранилища с версионированными миграциями. Миграции применяются при открытии
+// хранилища, а список нужен schedluer-admin migrate, чтобы показать состояние схемы.
+type Migrator interface {
+	Migrations(ctx context.Context) ([]MigrationStatus, error)
+}
+
+// MigrationStatus — миграция и время ее применения; AppliedAt == nil, пока она не применена
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// mongoError приводит ошибку дубликата ключа к ErrDuplicate, сохраняя исходное сообщение
+func mongoError(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %v", ErrDuplicate, err)
+	}
+	return err
+}
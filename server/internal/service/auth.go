@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+)
+
+const (
+	apiKeyPrefix = "sch_"
+	// userIDPrefix отличает сгенерированные ID пользователей от прежних user_id вроде default
+	userIDPrefix    = "u_"
+	maxUserIDLength = 100
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+type AuthService interface {
+	// CreateAPIKey выпускает ключ роли admin или user; ключ user без userID заводит нового пользователя со случайным ID
+	CreateAPIKey(ctx context.Context, name string, role string, userID string) (string, *models.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, prefix string) error
+	Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error)
+}
+
+type authService struct {
+	apiKeyRepo repository.APIKeyRepository
+	logger     *logrus.Logger
+}
+
+func NewAuthService(apiKeyRepo repository.APIKeyRepository, logger *logrus.Logger) AuthService {
+	return &authService{
+		apiKeyRepo: apiKeyRepo,
+		logger:     logger,
+	}
+}
+
+// CreateAPIKey выпускает новый ключ. Открытое значение возвращается только здесь,
+// в базе хранится лишь его SHA-256 хэш.
+func (s *authService) CreateAPIKey(ctx context.Context, name string, role string, userID string) (string, *models.APIKey, error) {
+	if name == "" {
+		return "", nil, fmt.Errorf("api key name is required")
+	}
+	switch role {
+	case models.RoleAdmin:
+		if userID != "" {
+			return "", nil, fmt.Errorf("admin keys are not bound to a user")
+		}
+	case models.RoleUser:
+		if len(userID) > maxUserIDLength {
+			return "", nil, fmt.Errorf("user id must be at most %d characters", maxUserIDLength)
+		}
+		if userID == "" {
+			id := make([]byte, 12)
+			if _, err := rand.Read(id); err != nil {
+				return "", nil, fmt.Errorf("failed to generate user id: %w", err)
+			}
+			userID = userIDPrefix + hex.EncodeToString(id)
+		}
+	default:
+		return "", nil, fmt.Errorf("unknown role: %s", role)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	rawKey := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &models.APIKey{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		Prefix:    rawKey[:len(apiKeyPrefix)+8],
+		KeyHash:   hashAPIKey(rawKey),
+		Role:      role,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return "", nil, fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	return rawKey, key, nil
+}
+
+func (s *authService) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.apiKeyRepo.GetAll(ctx)
+}
+
+func (s *authService) RevokeAPIKey(ctx context.Context, prefix string) error {
+	revoked, err := s.apiKeyRepo.Revoke(ctx, prefix, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *authService) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	if rawKey == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if key == nil || key.IsRevoked() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return key, nil
+}
+
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"schedluer/internal/logging"
+	"schedluer/internal/metrics"
+)
+
+// observeCache отмечает результат обращения к кэшу в метриках, в текущем span'е и в логе запроса
+func observeCache(ctx context.Context, span trace.Span, service string, result string) {
+	metrics.ObserveCache(service, result)
+	span.SetAttributes(attribute.String("cache.result", result))
+	logging.AddFields(ctx, logrus.Fields{"cache": result})
+}
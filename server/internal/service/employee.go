@@ -7,10 +7,15 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
 
+	"schedluer/internal/logging"
+	"schedluer/internal/metrics"
 	"schedluer/internal/models"
 	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
 	"schedluer/pkg/bsuir"
+	"schedluer/pkg/worker"
 )
 
 type EmployeeService interface {
@@ -20,45 +25,59 @@ type EmployeeService interface {
 }
 
 type employeeService struct {
-	bsuirClient  *bsuir.Client
+	source       bsuir.ScheduleSource
 	employeeRepo repository.EmployeeRepository
+	tasks        *worker.Group
 	logger       *logrus.Logger
 }
 
 func NewEmployeeService(
-	bsuirClient *bsuir.Client,
+	source bsuir.ScheduleSource,
 	employeeRepo repository.EmployeeRepository,
+	tasks *worker.Group,
 	logger *logrus.Logger,
 ) EmployeeService {
 	return &employeeService{
-		bsuirClient:  bsuirClient,
+		source:       source,
 		employeeRepo: employeeRepo,
+		tasks:        tasks,
 		logger:       logger,
 	}
 }
 
-func (s *employeeService) GetAllEmployees(ctx context.Context, useCache bool) ([]models.EmployeeListItem, error) {
+func (s *employeeService) GetAllEmployees(ctx context.Context, useCache bool) (_ []models.EmployeeListItem, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.GetAllEmployees", attribute.Bool("cache.use", useCache))
+	defer tracing.End(span, &err)
+
 	if useCache {
 		stored, err := s.employeeRepo.GetAll(ctx)
 		if err != nil {
-			s.logger.Warnf("Failed to get employees from cache: %v", err)
+			logging.FromContext(ctx, s.logger).WithError(err).Warn("Failed to get employees from cache")
 		} else if len(stored) > 0 {
 			result := make([]models.EmployeeListItem, len(stored))
 			for i, e := range stored {
 				result[i] = e.EmployeeData
 			}
+			observeCache(ctx, span, "employees", metrics.CacheHit)
 			return result, nil
 		}
+		observeCache(ctx, span, "employees", metrics.CacheMiss)
+	} else {
+		observeCache(ctx, span, "employees", metrics.CacheBypass)
+		// Справочник в памяти тоже пропускаем, иначе useCache=false отдал бы его до истечения TTL
+		ctx = bsuir.WithoutCache(ctx)
 	}
 
-	employees, err := s.bsuirClient.GetAllEmployees()
+	employees, err := s.source.GetAllEmployees(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get employees from BSUIR API: %w", err)
 	}
 
-	// Сохраняем в фоне с отдельным контекстом
-	go func() {
-		saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	// Сохраняем в фоне, не задерживая ответ; при остановке сервиса запись будет дождана.
+	// Логгер берем из запроса, чтобы ошибки записи связывались с породившим их request_id.
+	log := logging.FromContext(ctx, s.logger).WithField("task", "cache-employees")
+	_ = s.tasks.Go("cache-employees", func(taskCtx context.Context) {
+		saveCtx, cancel := context.WithTimeout(taskCtx, 5*time.Minute)
 		defer cancel()
 
 		for _, e := range employees {
@@ -72,15 +91,20 @@ func (s *employeeService) GetAllEmployees(ctx context.Context, useCache bool) ([
 				UpdatedAt:      time.Now(),
 			}
 			if err := s.employeeRepo.Update(saveCtx, &stored); err != nil {
-				s.logger.Warnf("Failed to save employee %d to cache: %v", e.ID, err)
+				log.WithError(err).WithField("bsuir_id", e.ID).Warn("Failed to save employee to cache")
 			}
 		}
-	}()
+	})
 
 	return employees, nil
 }
 
-func (s *employeeService) GetEmployeeByURLID(ctx context.Context, urlID string) (*models.EmployeeListItem, error) {
+func (s *employeeService) GetEmployeeByURLID(ctx context.Context, urlID string) (_ *models.EmployeeListItem, err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.GetEmployeeByURLID", attribute.String("employee.url_id", urlID))
+	defer tracing.End(span, &err)
+
+	logging.AddFields(ctx, logrus.Fields{"employee_url_id": urlID})
+
 	stored, err := s.employeeRepo.GetByURLID(ctx, urlID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get employee: %w", err)
@@ -103,8 +127,14 @@ func (s *employeeService) GetEmployeeByURLID(ctx context.Context, urlID string)
 	return nil, fmt.Errorf("employee not found: %s", urlID)
 }
 
-func (s *employeeService) RefreshEmployees(ctx context.Context) error {
-	employees, err := s.bsuirClient.GetAllEmployees()
+func (s *employeeService) RefreshEmployees(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "EmployeeService.RefreshEmployees")
+	defer tracing.End(span, &err)
+
+	// Массовое обновление идет мимо кэша справочников и уступает очередь к API БГУИРа пользовательским запросам
+	ctx = bsuir.WithPriority(bsuir.WithoutCache(ctx), bsuir.PriorityBackground)
+
+	employees, err := s.source.GetAllEmployees(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get employees from BSUIR API: %w", err)
 	}
@@ -128,7 +158,7 @@ func (s *employeeService) RefreshEmployees(ctx context.Context) error {
 
 	for _, e := range stored {
 		if err := s.employeeRepo.Update(saveCtx, &e); err != nil {
-			s.logger.Warnf("Failed to update employee %d: %v", e.BSUIRID, err)
+			logging.FromContext(ctx, s.logger).WithError(err).WithField("bsuir_id", e.BSUIRID).Warn("Failed to update employee")
 		}
 	}
 
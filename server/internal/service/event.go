@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/converter"
+	"schedluer/pkg/recurrence"
+)
+
+var (
+	// ErrInvalidEvent — пустое название, конец раньше начала или правило повторения не разбирается
+	ErrInvalidEvent = errors.New("invalid event")
+	// ErrEventNotFound — события нет или оно принадлежит другому пользователю
+	ErrEventNotFound = errors.New("event not found")
+)
+
+const (
+	maxEventTitleLength       = 200
+	maxEventLocationLength    = 200
+	maxEventDescriptionLength = 2000
+	// maxEventDuration — дольше суток событие в ленте по датам не показать
+	maxEventDuration = 24 * time.Hour
+)
+
+// EventService ведет личные события пользователя. Повторения разворачиваются при сборке ленты,
+// в хранилище лежит одно событие с правилом.
+type EventService interface {
+	ListEvents(ctx context.Context, userID string) ([]models.Event, error)
+	GetEvent(ctx context.Context, userID string, id string) (*models.Event, error)
+	CreateEvent(ctx context.Context, userID string, request models.EventRequest) (*models.Event, error)
+	UpdateEvent(ctx context.Context, userID string, id string, request models.EventRequest) (*models.Event, error)
+	DeleteEvent(ctx context.Context, userID string, id string) error
+}
+
+type eventService struct {
+	eventRepo repository.EventRepository
+	logger    *logrus.Logger
+}
+
+func NewEventService(eventRepo repository.EventRepository, logger *logrus.Logger) EventService {
+	return &eventService{
+		eventRepo: eventRepo,
+		logger:    logger,
+	}
+}
+
+func (s *eventService) ListEvents(ctx context.Context, userID string) (_ []models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.ListEvents", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	return s.eventRepo.GetByUser(ctx, userID)
+}
+
+func (s *eventService) GetEvent(ctx context.Context, userID string, id string) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEvent", attribute.String("user.id", userID), attribute.String("event.id", id))
+	defer tracing.End(span, &err)
+
+	return s.event(ctx, userID, id)
+}
+
+func (s *eventService) CreateEvent(ctx context.Context, userID string, request models.EventRequest) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.CreateEvent", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	now := time.Now()
+	event := &models.Event{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyEventRequest(event, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+	return event, nil
+}
+
+func (s *eventService) UpdateEvent(ctx context.Context, userID string, id string, request models.EventRequest) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.UpdateEvent", attribute.String("user.id", userID), attribute.String("event.id", id))
+	defer tracing.End(span, &err)
+
+	event, err := s.event(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyEventRequest(event, request); err != nil {
+		return nil, err
+	}
+	event.UpdatedAt = time.Now()
+
+	found, err := s.eventRepo.Update(ctx, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, id)
+	}
+	return event, nil
+}
+
+func (s *eventService) DeleteEvent(ctx context.Context, userID string, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "EventService.DeleteEvent", attribute.String("user.id", userID), attribute.String("event.id", id))
+	defer tracing.End(span, &err)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrEventNotFound, id)
+	}
+	deleted, err := s.eventRepo.Delete(ctx, userID, objectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrEventNotFound, id)
+	}
+	return nil
+}
+
+func (s *eventService) event(ctx context.Context, userID, id string) (*models.Event, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, id)
+	}
+	event, err := s.eventRepo.GetByID(ctx, userID, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, id)
+	}
+	return event, nil
+}
+
+// applyEventRequest проверяет запрос и переносит его в событие; правило повторения сохраняется
+// в каноническом виде recurrence.Rule.String
+func applyEventRequest(event *models.Event, request models.EventRequest) error {
+	title := strings.TrimSpace(request.Title)
+	if title == "" || utf8.RuneCountInString(title) > maxEventTitleLength {
+		return fmt.Errorf("%w: title must be 1 to %d characters", ErrInvalidEvent, maxEventTitleLength)
+	}
+	location := strings.TrimSpace(request.Location)
+	if utf8.RuneCountInString(location) > maxEventLocationLength {
+		return fmt.Errorf("%w: location is longer than %d characters", ErrInvalidEvent, maxEventLocationLength)
+	}
+	description := strings.TrimSpace(request.Description)
+	if utf8.RuneCountInString(description) > maxEventDescriptionLength {
+		return fmt.Errorf("%w: description is longer than %d characters", ErrInvalidEvent, maxEventDescriptionLength)
+	}
+
+	if request.Start.IsZero() || request.End.IsZero() {
+		return fmt.Errorf("%w: start and end are required", ErrInvalidEvent)
+	}
+	if !request.End.After(request.Start) || request.End.Sub(request.Start) > maxEventDuration {
+		return fmt.Errorf("%w: end must be after start and at most %s later", ErrInvalidEvent, maxEventDuration)
+	}
+
+	rule := ""
+	if strings.TrimSpace(request.Recurrence) != "" {
+		parsed, err := recurrence.Parse(request.Recurrence)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		rule = parsed.String()
+	}
+
+	event.Title = title
+	event.Location = location
+	event.Description = description
+	event.Start = request.Start
+	event.End = request.End
+	event.Recurrence = rule
+	return nil
+}
+
+// eventOccurrences возвращает начала повторений события с from по to включительно (берутся только даты).
+// Повторения считаются по часам Минска, как и занятия.
+func eventOccurrences(event models.Event, from, to time.Time) []time.Time {
+	start := event.Start.In(converter.Minsk)
+	from = dayStart(from)
+	to = dayStart(to).AddDate(0, 0, 1)
+
+	if event.Recurrence == "" {
+		if start.Before(from) || !start.Before(to) {
+			return nil
+		}
+		return []time.Time{start}
+	}
+
+	// Правило проверено при сохранении; если его все же не разобрать, показываем только первое повторение
+	rule, err := recurrence.Parse(event.Recurrence)
+	if err != nil {
+		rule = recurrence.Rule{Frequency: recurrence.Daily, Count: 1}
+	}
+	return rule.Between(start, from, to)
+}
+
+func dayStart(t time.Time) time.Time {
+	t = t.In(converter.Minsk)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, converter.Minsk)
+}
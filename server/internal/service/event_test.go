@@ -0,0 +1,43 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/pkg/converter"
+)
+
+func TestEventOccurrences(t *testing.T) {
+	start := time.Date(2026, 9, 8, 18, 0, 0, 0, converter.Minsk)
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, converter.Minsk)
+	}
+	at := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 18, 0, 0, 0, converter.Minsk)
+	}
+
+	tests := []struct {
+		name       string
+		recurrence string
+		from, to   time.Time
+		want       []time.Time
+	}{
+		{"one-off inside range", "", day(9, 7), day(9, 13), []time.Time{start}},
+		{"one-off on the last day", "", day(9, 1), day(9, 8), []time.Time{start}},
+		{"one-off before range", "", day(9, 9), day(9, 30), nil},
+		{"one-off after range", "", day(9, 1), day(9, 7), nil},
+		{"weekly inside range", "FREQ=WEEKLY;COUNT=5", day(9, 14), day(9, 27), []time.Time{at(9, 15), at(9, 22)}},
+		{"COUNT counts from the first start", "FREQ=WEEKLY;COUNT=5", day(9, 28), day(10, 31), []time.Time{at(9, 29), at(10, 6)}},
+		{"after the last repetition", "FREQ=WEEKLY;COUNT=5", day(10, 7), day(10, 31), nil},
+		{"unparsable rule shows the first start", "FREQ=SOMETIMES", day(9, 1), day(10, 31), []time.Time{start}},
+	}
+	for _, tt := range tests {
+		event := models.Event{Start: start.UTC(), End: start.Add(90 * time.Minute).UTC(), Recurrence: tt.recurrence}
+		got := eventOccurrences(event, tt.from, tt.to)
+		if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+			t.Errorf("%s: eventOccurrences = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,16 +2,47 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
 
+	"schedluer/internal/logging"
 	"schedluer/internal/models"
 	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/bsuir"
 )
 
+var (
+	// ErrUnknownFavoriteType — тип избранного не из models.FavoriteTypes
+	ErrUnknownFavoriteType = errors.New("unknown favorite type")
+	// ErrInvalidFavorite — неверные подпись, цвет, имя коллекции или ID в перестановке
+	ErrInvalidFavorite    = errors.New("invalid favorite details")
+	ErrFavoriteNotFound   = errors.New("favorite not found")
+	ErrCollectionNotFound = errors.New("favorite collection not found")
+	// ErrUnknownFavoriteKey — группы, преподавателя или аудитории с таким ключом нет в справочниках БГУИРа
+	ErrUnknownFavoriteKey = errors.New("unknown favorite key")
+)
+
+const (
+	// maxLabelLength ограничивает подписи элементов и имена коллекций, в символах
+	maxLabelLength = 64
+	// maxFavoriteKeyLength ограничивает ключ избранного: номер группы, URL ID или аудиторию с корпусом
+	maxFavoriteKeyLength = 100
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// FavoriteService — избранное пользователя. Методы без Item работают только с группами
+// и отдают старый формат models.FavoriteGroup для существующих клиентов.
 type FavoriteService interface {
 	GetAllFavorites(ctx context.Context, userID string) ([]models.FavoriteGroup, error)
 	SearchFavorites(ctx context.Context, userID string, query string) ([]models.FavoriteGroup, error)
@@ -19,57 +50,511 @@ type FavoriteService interface {
 	RemoveFavorite(ctx context.Context, userID string, groupNumber string) error
 	IsFavorite(ctx context.Context, userID string, groupNumber string) (bool, error)
 	GetFavoriteGroupNumbers(ctx context.Context, userID string) ([]string, error)
+
+	// ListFavoriteItems возвращает избранное с данными для отображения; favoriteType "" — все типы
+	ListFavoriteItems(ctx context.Context, userID string, favoriteType string) ([]models.FavoriteItem, error)
+	AddFavoriteItem(ctx context.Context, userID string, favoriteType string, key string) (*models.Favorite, error)
+	RemoveFavoriteItem(ctx context.Context, userID string, favoriteType string, key string) error
+	IsFavoriteItem(ctx context.Context, userID string, favoriteType string, key string) (bool, error)
+	// UpdateFavoriteItem меняет коллекцию, подпись, цвет и закрепление. При переносе в другую
+	// коллекцию элемент встает в ее конец.
+	UpdateFavoriteItem(ctx context.Context, userID string, favoriteType string, key string, details models.FavoriteDetails) (*models.Favorite, error)
+	// ReorderFavoriteItems задает порядок всех элементов коллекции; неполный список — repository.ErrReorderMismatch
+	ReorderFavoriteItems(ctx context.Context, userID string, order models.FavoriteOrder) error
+
+	ListCollections(ctx context.Context, userID string) ([]models.FavoriteCollection, error)
+	CreateCollection(ctx context.Context, userID string, details models.FavoriteCollectionDetails) (*models.FavoriteCollection, error)
+	UpdateCollection(ctx context.Context, userID string, id string, details models.FavoriteCollectionDetails) (*models.FavoriteCollection, error)
+	DeleteCollection(ctx context.Context, userID string, id string) error
+	ReorderCollections(ctx context.Context, userID string, ids []string) error
 }
 
 type favoriteService struct {
 	favoriteRepo repository.FavoriteRepository
+	groupRepo    repository.GroupRepository
+	employeeRepo repository.EmployeeRepository
+	source       bsuir.ScheduleSource
 	logger       *logrus.Logger
 }
 
-func NewFavoriteService(favoriteRepo repository.FavoriteRepository, logger *logrus.Logger) FavoriteService {
+func NewFavoriteService(favoriteRepo repository.FavoriteRepository, groupRepo repository.GroupRepository, employeeRepo repository.EmployeeRepository, source bsuir.ScheduleSource, logger *logrus.Logger) FavoriteService {
 	return &favoriteService{
 		favoriteRepo: favoriteRepo,
+		groupRepo:    groupRepo,
+		employeeRepo: employeeRepo,
+		source:       source,
 		logger:       logger,
 	}
 }
 
-func (s *favoriteService) GetAllFavorites(ctx context.Context, userID string) ([]models.FavoriteGroup, error) {
-	return s.favoriteRepo.GetAll(ctx, userID)
+func (s *favoriteService) GetAllFavorites(ctx context.Context, userID string) (_ []models.FavoriteGroup, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.GetAllFavorites", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	return favoriteGroups(s.favoriteRepo.GetAll(ctx, userID, models.FavoriteTypeGroup))
 }
 
-func (s *favoriteService) SearchFavorites(ctx context.Context, userID string, query string) ([]models.FavoriteGroup, error) {
-	return s.favoriteRepo.Search(ctx, userID, query)
+func (s *favoriteService) SearchFavorites(ctx context.Context, userID string, query string) (_ []models.FavoriteGroup, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.SearchFavorites", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	return favoriteGroups(s.favoriteRepo.Search(ctx, userID, models.FavoriteTypeGroup, query))
 }
 
-func (s *favoriteService) AddFavorite(ctx context.Context, userID string, groupNumber string) error {
-	favorite := &models.FavoriteGroup{
-		ID:          primitive.NewObjectID(),
-		GroupNumber: groupNumber,
-		UserID:      userID,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	return s.favoriteRepo.Add(ctx, favorite)
+func (s *favoriteService) AddFavorite(ctx context.Context, userID string, groupNumber string) (err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.AddFavorite", attribute.String("user.id", userID), attribute.String("group.number", groupNumber))
+	defer tracing.End(span, &err)
+
+	_, err = s.add(ctx, userID, models.FavoriteTypeGroup, groupNumber)
+	return err
 }
 
-func (s *favoriteService) RemoveFavorite(ctx context.Context, userID string, groupNumber string) error {
-	return s.favoriteRepo.Delete(ctx, userID, groupNumber)
+func (s *favoriteService) RemoveFavorite(ctx context.Context, userID string, groupNumber string) (err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.RemoveFavorite", attribute.String("user.id", userID), attribute.String("group.number", groupNumber))
+	defer tracing.End(span, &err)
+
+	return s.favoriteRepo.Delete(ctx, userID, models.FavoriteTypeGroup, groupNumber)
 }
 
-func (s *favoriteService) IsFavorite(ctx context.Context, userID string, groupNumber string) (bool, error) {
-	return s.favoriteRepo.IsFavorite(ctx, userID, groupNumber)
+func (s *favoriteService) IsFavorite(ctx context.Context, userID string, groupNumber string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.IsFavorite", attribute.String("user.id", userID), attribute.String("group.number", groupNumber))
+	defer tracing.End(span, &err)
+
+	return s.favoriteRepo.IsFavorite(ctx, userID, models.FavoriteTypeGroup, groupNumber)
 }
 
-func (s *favoriteService) GetFavoriteGroupNumbers(ctx context.Context, userID string) ([]string, error) {
-	favorites, err := s.favoriteRepo.GetAll(ctx, userID)
+func (s *favoriteService) GetFavoriteGroupNumbers(ctx context.Context, userID string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.GetFavoriteGroupNumbers", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	favorites, err := s.favoriteRepo.GetAll(ctx, userID, models.FavoriteTypeGroup)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get favorites: %w", err)
 	}
 
 	groupNumbers := make([]string, len(favorites))
 	for i, fav := range favorites {
-		groupNumbers[i] = fav.GroupNumber
+		groupNumbers[i] = fav.Key
 	}
 
 	return groupNumbers, nil
 }
+
+func (s *favoriteService) ListFavoriteItems(ctx context.Context, userID string, favoriteType string) (_ []models.FavoriteItem, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.ListFavoriteItems", attribute.String("user.id", userID), attribute.String("favorite.type", favoriteType))
+	defer tracing.End(span, &err)
+
+	if favoriteType != "" && !models.IsFavoriteType(favoriteType) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFavoriteType, favoriteType)
+	}
+
+	favorites, err := s.favoriteRepo.GetAll(ctx, userID, favoriteType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+
+	// Подробности читаются одним запросом на тип, а не по запросу на элемент
+	var groupNumbers, urlIDs []string
+	hasAuditories := false
+	for _, favorite := range favorites {
+		switch favorite.Type {
+		case models.FavoriteTypeGroup:
+			groupNumbers = append(groupNumbers, favorite.Key)
+		case models.FavoriteTypeEmployee:
+			urlIDs = append(urlIDs, favorite.Key)
+		case models.FavoriteTypeAuditory:
+			hasAuditories = true
+		}
+	}
+
+	groups, err := s.groupRepo.GetByNumbers(ctx, groupNumbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorite groups: %w", err)
+	}
+	groupsByNumber := make(map[string]*models.StoredGroup, len(groups))
+	for i := range groups {
+		groupsByNumber[groups[i].GroupData.Name] = &groups[i]
+	}
+	employees, err := s.employeeRepo.GetByURLIDs(ctx, urlIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorite employees: %w", err)
+	}
+	employeesByURLID := make(map[string]*models.StoredEmployee, len(employees))
+	for i := range employees {
+		employeesByURLID[employees[i].URLID] = &employees[i]
+	}
+	var auditories map[string]*models.Auditory
+	if hasAuditories {
+		auditories = s.auditoriesByKey(ctx)
+	}
+
+	items := make([]models.FavoriteItem, len(favorites))
+	for i, favorite := range favorites {
+		item := models.FavoriteItem{Favorite: favorite, Title: favorite.Key}
+
+		switch favorite.Type {
+		case models.FavoriteTypeGroup:
+			if group := groupsByNumber[favorite.Key]; group != nil {
+				item.Group = &group.GroupData
+			}
+		case models.FavoriteTypeEmployee:
+			if employee := employeesByURLID[favorite.Key]; employee != nil {
+				item.Employee = &employee.EmployeeData
+				if employee.EmployeeData.FIO != "" {
+					item.Title = employee.EmployeeData.FIO
+				}
+			}
+		case models.FavoriteTypeAuditory:
+			item.Auditory = auditories[favorite.Key]
+		}
+
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+// auditoriesByKey — аудитории по ключу избранного. Аудитории не кэшируются в хранилище,
+// поэтому берутся из источника; без них список избранного все равно отдается, только без подробностей.
+func (s *favoriteService) auditoriesByKey(ctx context.Context) map[string]*models.Auditory {
+	result := make(map[string]*models.Auditory)
+
+	auditories, err := s.source.GetAllAuditories(ctx)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Warn("Failed to get auditories for favorites")
+		return result
+	}
+	for i := range auditories {
+		result[models.AuditoryKey(auditories[i])] = &auditories[i]
+	}
+	return result
+}
+
+func (s *favoriteService) AddFavoriteItem(ctx context.Context, userID string, favoriteType string, key string) (_ *models.Favorite, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.AddFavoriteItem", attribute.String("user.id", userID), attribute.String("favorite.type", favoriteType), attribute.String("favorite.key", key))
+	defer tracing.End(span, &err)
+
+	return s.add(ctx, userID, favoriteType, key)
+}
+
+func (s *favoriteService) RemoveFavoriteItem(ctx context.Context, userID string, favoriteType string, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.RemoveFavoriteItem", attribute.String("user.id", userID), attribute.String("favorite.type", favoriteType), attribute.String("favorite.key", key))
+	defer tracing.End(span, &err)
+
+	if !models.IsFavoriteType(favoriteType) {
+		return fmt.Errorf("%w: %q", ErrUnknownFavoriteType, favoriteType)
+	}
+	return s.favoriteRepo.Delete(ctx, userID, favoriteType, key)
+}
+
+func (s *favoriteService) IsFavoriteItem(ctx context.Context, userID string, favoriteType string, key string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.IsFavoriteItem", attribute.String("user.id", userID), attribute.String("favorite.type", favoriteType), attribute.String("favorite.key", key))
+	defer tracing.End(span, &err)
+
+	if !models.IsFavoriteType(favoriteType) {
+		return false, fmt.Errorf("%w: %q", ErrUnknownFavoriteType, favoriteType)
+	}
+	return s.favoriteRepo.IsFavorite(ctx, userID, favoriteType, key)
+}
+
+func (s *favoriteService) add(ctx context.Context, userID, favoriteType, key string) (*models.Favorite, error) {
+	if !models.IsFavoriteType(favoriteType) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFavoriteType, favoriteType)
+	}
+	key = strings.TrimSpace(key)
+	if key == "" || utf8.RuneCountInString(key) > maxFavoriteKeyLength {
+		return nil, fmt.Errorf("%w: key must be 1 to %d characters", ErrInvalidFavorite, maxFavoriteKeyLength)
+	}
+	if err := s.checkKey(ctx, favoriteType, key); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	favorite := &models.Favorite{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Type:      favoriteType,
+		Key:       key,
+		Position:  now.UnixMilli(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.favoriteRepo.Add(ctx, favorite); err != nil {
+		return nil, err
+	}
+	// Повторное добавление сохраняет исходные ID и CreatedAt, поэтому отдаем запись из хранилища
+	return s.favoriteRepo.Get(ctx, userID, favoriteType, key)
+}
+
+// checkKey сверяет ключ со справочниками: сначала с сохраненными группами и преподавателями, затем
+// со списками из API. Недоступный API не мешает добавить элемент, отказ — только если ключа нет в списке.
+func (s *favoriteService) checkKey(ctx context.Context, favoriteType, key string) error {
+	log := logging.FromContext(ctx, s.logger).WithFields(logrus.Fields{"type": favoriteType, "key": key})
+
+	var known bool
+	switch favoriteType {
+	case models.FavoriteTypeGroup:
+		group, err := s.groupRepo.GetByNumber(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get group %s: %w", key, err)
+		}
+		if group != nil {
+			return nil
+		}
+		groups, err := s.source.GetAllGroups(ctx)
+		if err != nil {
+			return skipKeyCheck(ctx, log, err, "groups")
+		}
+		known = slices.ContainsFunc(groups, func(group models.StudentGroupListItem) bool { return group.Name == key })
+	case models.FavoriteTypeEmployee:
+		employee, err := s.employeeRepo.GetByURLID(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get employee %s: %w", key, err)
+		}
+		if employee != nil {
+			return nil
+		}
+		employees, err := s.source.GetAllEmployees(ctx)
+		if err != nil {
+			return skipKeyCheck(ctx, log, err, "employees")
+		}
+		known = slices.ContainsFunc(employees, func(employee models.EmployeeListItem) bool { return employee.URLID == key })
+	case models.FavoriteTypeAuditory:
+		auditories, err := s.source.GetAllAuditories(ctx)
+		if err != nil {
+			return skipKeyCheck(ctx, log, err, "auditories")
+		}
+		known = slices.ContainsFunc(auditories, func(auditory models.Auditory) bool { return models.AuditoryKey(auditory) == key })
+	}
+	if !known {
+		return fmt.Errorf("%w: %s %q", ErrUnknownFavoriteKey, favoriteType, key)
+	}
+	return nil
+}
+
+func skipKeyCheck(ctx context.Context, log *logrus.Entry, err error, what string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	log.WithError(err).Warnf("Failed to get %s, adding favorite without key check", what)
+	return nil
+}
+
+func (s *favoriteService) UpdateFavoriteItem(ctx context.Context, userID string, favoriteType string, key string, details models.FavoriteDetails) (_ *models.Favorite, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.UpdateFavoriteItem", attribute.String("user.id", userID), attribute.String("favorite.type", favoriteType), attribute.String("favorite.key", key))
+	defer tracing.End(span, &err)
+
+	if !models.IsFavoriteType(favoriteType) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFavoriteType, favoriteType)
+	}
+	favorite, err := s.favoriteRepo.Get(ctx, userID, favoriteType, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorite: %w", err)
+	}
+	if favorite == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrFavoriteNotFound, favoriteType, key)
+	}
+
+	now := time.Now()
+	if details.CollectionID != nil && *details.CollectionID != favorite.CollectionID {
+		if *details.CollectionID != "" {
+			if _, err := s.collection(ctx, userID, *details.CollectionID); err != nil {
+				return nil, err
+			}
+		}
+		favorite.CollectionID = *details.CollectionID
+		favorite.Position = now.UnixMilli()
+	}
+	if details.Label != nil {
+		label := strings.TrimSpace(*details.Label)
+		if utf8.RuneCountInString(label) > maxLabelLength {
+			return nil, fmt.Errorf("%w: label is longer than %d characters", ErrInvalidFavorite, maxLabelLength)
+		}
+		favorite.Label = label
+	}
+	if details.Color != nil {
+		if err := validateColor(*details.Color); err != nil {
+			return nil, err
+		}
+		favorite.Color = strings.ToUpper(*details.Color)
+	}
+	if details.Pinned != nil {
+		favorite.Pinned = *details.Pinned
+	}
+	favorite.UpdatedAt = now
+
+	found, err := s.favoriteRepo.UpdateDetails(ctx, favorite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update favorite: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s %s", ErrFavoriteNotFound, favoriteType, key)
+	}
+	return favorite, nil
+}
+
+func (s *favoriteService) ReorderFavoriteItems(ctx context.Context, userID string, order models.FavoriteOrder) (err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.ReorderFavoriteItems", attribute.String("user.id", userID), attribute.String("favorite.collection_id", order.CollectionID), attribute.Int("favorite.count", len(order.IDs)))
+	defer tracing.End(span, &err)
+
+	ids, err := parseIDs(order.IDs)
+	if err != nil {
+		return err
+	}
+	if order.CollectionID != "" {
+		if _, err := s.collection(ctx, userID, order.CollectionID); err != nil {
+			return err
+		}
+	}
+	return s.favoriteRepo.Reorder(ctx, userID, order.CollectionID, ids)
+}
+
+func (s *favoriteService) ListCollections(ctx context.Context, userID string) (_ []models.FavoriteCollection, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.ListCollections", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	return s.favoriteRepo.GetCollections(ctx, userID)
+}
+
+// CreateCollection добавляет коллекцию в конец списка; repository.ErrDuplicate — имя уже занято
+func (s *favoriteService) CreateCollection(ctx context.Context, userID string, details models.FavoriteCollectionDetails) (_ *models.FavoriteCollection, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.CreateCollection", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	if details.Name == nil {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidFavorite)
+	}
+	now := time.Now()
+	collection := &models.FavoriteCollection{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Position:  now.UnixMilli(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyCollectionDetails(collection, details); err != nil {
+		return nil, err
+	}
+
+	if err := s.favoriteRepo.CreateCollection(ctx, collection); err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	return collection, nil
+}
+
+func (s *favoriteService) UpdateCollection(ctx context.Context, userID string, id string, details models.FavoriteCollectionDetails) (_ *models.FavoriteCollection, err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.UpdateCollection", attribute.String("user.id", userID), attribute.String("favorite.collection_id", id))
+	defer tracing.End(span, &err)
+
+	collection, err := s.collection(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCollectionDetails(collection, details); err != nil {
+		return nil, err
+	}
+	collection.UpdatedAt = time.Now()
+
+	found, err := s.favoriteRepo.UpdateCollection(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update collection: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, id)
+	}
+	return collection, nil
+}
+
+// DeleteCollection удаляет коллекцию; ее элементы остаются в избранном вне коллекций
+func (s *favoriteService) DeleteCollection(ctx context.Context, userID string, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.DeleteCollection", attribute.String("user.id", userID), attribute.String("favorite.collection_id", id))
+	defer tracing.End(span, &err)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCollectionNotFound, id)
+	}
+	found, err := s.favoriteRepo.DeleteCollection(ctx, userID, objectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrCollectionNotFound, id)
+	}
+	return nil
+}
+
+func (s *favoriteService) ReorderCollections(ctx context.Context, userID string, ids []string) (err error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.ReorderCollections", attribute.String("user.id", userID), attribute.Int("favorite.count", len(ids)))
+	defer tracing.End(span, &err)
+
+	objectIDs, err := parseIDs(ids)
+	if err != nil {
+		return err
+	}
+	return s.favoriteRepo.ReorderCollections(ctx, userID, objectIDs)
+}
+
+// collection возвращает коллекцию пользователя или ErrCollectionNotFound
+func (s *favoriteService) collection(ctx context.Context, userID, id string) (*models.FavoriteCollection, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, id)
+	}
+	collection, err := s.favoriteRepo.GetCollection(ctx, userID, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, id)
+	}
+	return collection, nil
+}
+
+func applyCollectionDetails(collection *models.FavoriteCollection, details models.FavoriteCollectionDetails) error {
+	if details.Name != nil {
+		name := strings.TrimSpace(*details.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxLabelLength {
+			return fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidFavorite, maxLabelLength)
+		}
+		collection.Name = name
+	}
+	if details.Color != nil {
+		if err := validateColor(*details.Color); err != nil {
+			return err
+		}
+		collection.Color = strings.ToUpper(*details.Color)
+	}
+	return nil
+}
+
+// validateColor допускает пустой цвет (сбросить) и #RRGGBB
+func validateColor(color string) error {
+	if color != "" && !colorPattern.MatchString(color) {
+		return fmt.Errorf("%w: color %q is not #RRGGBB", ErrInvalidFavorite, color)
+	}
+	return nil
+}
+
+func parseIDs(ids []string) ([]primitive.ObjectID, error) {
+	result := make([]primitive.ObjectID, len(ids))
+	for i, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: id %q", ErrInvalidFavorite, id)
+		}
+		result[i] = objectID
+	}
+	return result, nil
+}
+
+func favoriteGroups(favorites []models.Favorite, err error) ([]models.FavoriteGroup, error) {
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]models.FavoriteGroup, len(favorites))
+	for i, favorite := range favorites {
+		groups[i] = models.FavoriteGroupFrom(favorite)
+	}
+	return groups, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/repository/memory"
+	"schedluer/pkg/bsuir"
+)
+
+// listSource отдает справочники из памяти; err — ответ API при недоступности
+type listSource struct {
+	bsuir.ScheduleSource
+	groups     []models.StudentGroupListItem
+	employees  []models.EmployeeListItem
+	auditories []models.Auditory
+	err        error
+}
+
+func (s *listSource) GetAllGroups(ctx context.Context) ([]models.StudentGroupListItem, error) {
+	return s.groups, s.err
+}
+
+func (s *listSource) GetAllEmployees(ctx context.Context) ([]models.EmployeeListItem, error) {
+	return s.employees, s.err
+}
+
+func (s *listSource) GetAllAuditories(ctx context.Context) ([]models.Auditory, error) {
+	return s.auditories, s.err
+}
+
+// countingGroups и countingEmployees считают запросы к справочникам
+type countingGroups struct {
+	repository.GroupRepository
+	single, batch int
+}
+
+func (r *countingGroups) GetByNumber(ctx context.Context, groupNumber string) (*models.StoredGroup, error) {
+	r.single++
+	return r.GroupRepository.GetByNumber(ctx, groupNumber)
+}
+
+func (r *countingGroups) GetByNumbers(ctx context.Context, groupNumbers []string) ([]models.StoredGroup, error) {
+	r.batch++
+	return r.GroupRepository.GetByNumbers(ctx, groupNumbers)
+}
+
+type countingEmployees struct {
+	repository.EmployeeRepository
+	single, batch int
+}
+
+func (r *countingEmployees) GetByURLID(ctx context.Context, urlID string) (*models.StoredEmployee, error) {
+	r.single++
+	return r.EmployeeRepository.GetByURLID(ctx, urlID)
+}
+
+func (r *countingEmployees) GetByURLIDs(ctx context.Context, urlIDs []string) ([]models.StoredEmployee, error) {
+	r.batch++
+	return r.EmployeeRepository.GetByURLIDs(ctx, urlIDs)
+}
+
+func newFavoriteFixture(t *testing.T, source *listSource) (*favoriteService, *countingGroups, *countingEmployees) {
+	t.Helper()
+	ctx := context.Background()
+	groups := &countingGroups{GroupRepository: memory.NewGroupRepository()}
+	employees := &countingEmployees{EmployeeRepository: memory.NewEmployeeRepository()}
+	stored := []models.StoredGroup{
+		{BSUIRID: 1, GroupData: models.StudentGroupListItem{Name: "221701", Course: 2}},
+		{BSUIRID: 2, GroupData: models.StudentGroupListItem{Name: "221702", Course: 2}},
+	}
+	if err := groups.SaveMany(ctx, stored); err != nil {
+		t.Fatalf("failed to save groups: %v", err)
+	}
+	if err := employees.Save(ctx, &models.StoredEmployee{BSUIRID: 1, URLID: "i-ivanov", EmployeeData: models.EmployeeListItem{URLID: "i-ivanov", FIO: "Иванов И. И."}}); err != nil {
+		t.Fatalf("failed to save employee: %v", err)
+	}
+	service := NewFavoriteService(memory.NewFavoriteRepository(), groups, employees, source, testLogger()).(*favoriteService)
+	return service, groups, employees
+}
+
+func TestListFavoriteItemsBatchesLookups(t *testing.T) {
+	ctx := context.Background()
+	source := &listSource{
+		groups:     []models.StudentGroupListItem{{Name: "221703"}},
+		auditories: []models.Auditory{{Name: "101", BuildingNumber: models.BuildingNumber{Name: "5 к."}}},
+	}
+	service, groups, employees := newFavoriteFixture(t, source)
+	for _, favorite := range []struct{ kind, key string }{
+		{models.FavoriteTypeGroup, "221701"},
+		{models.FavoriteTypeGroup, "221702"},
+		{models.FavoriteTypeGroup, "221703"},
+		{models.FavoriteTypeEmployee, "i-ivanov"},
+		{models.FavoriteTypeAuditory, "101-5 к."},
+	} {
+		if _, err := service.AddFavoriteItem(ctx, "user-1", favorite.kind, favorite.key); err != nil {
+			t.Fatalf("AddFavoriteItem(%s, %s): %v", favorite.kind, favorite.key, err)
+		}
+	}
+
+	groups.single, groups.batch, employees.single, employees.batch = 0, 0, 0, 0
+	items, err := service.ListFavoriteItems(ctx, "user-1", "")
+	if err != nil {
+		t.Fatalf("ListFavoriteItems: %v", err)
+	}
+	if groups.single != 0 || groups.batch != 1 || employees.single != 0 || employees.batch != 1 {
+		t.Errorf("lookups: groups %d single, %d batch; employees %d single, %d batch; want one batch per type",
+			groups.single, groups.batch, employees.single, employees.batch)
+	}
+	if len(items) != 5 {
+		t.Fatalf("items = %+v, want 5", items)
+	}
+	if items[0].Group == nil || items[0].Group.Name != "221701" || items[1].Group == nil || items[1].Group.Name != "221702" {
+		t.Errorf("stored groups have no metadata: %+v, %+v", items[0], items[1])
+	}
+	if items[2].Group != nil {
+		t.Errorf("group missing from storage must come without metadata: %+v", items[2])
+	}
+	if items[3].Employee == nil || items[3].Title != "Иванов И. И." {
+		t.Errorf("employee favorite = %+v", items[3])
+	}
+	if items[4].Auditory == nil {
+		t.Errorf("auditory favorite = %+v", items[4])
+	}
+}
+
+func TestAddFavoriteItemChecksKey(t *testing.T) {
+	source := &listSource{
+		groups:     []models.StudentGroupListItem{{Name: "221703"}},
+		employees:  []models.EmployeeListItem{{URLID: "m-petrova"}},
+		auditories: []models.Auditory{{Name: "101", BuildingNumber: models.BuildingNumber{Name: "5 к."}}},
+	}
+	tests := []struct {
+		name      string
+		kind, key string
+		sourceErr error
+		wantErr   error
+	}{
+		{"stored group", models.FavoriteTypeGroup, "221701", nil, nil},
+		{"group known only to the API", models.FavoriteTypeGroup, "221703", nil, nil},
+		{"unknown group", models.FavoriteTypeGroup, "999999", nil, ErrUnknownFavoriteKey},
+		{"stored employee", models.FavoriteTypeEmployee, "i-ivanov", nil, nil},
+		{"employee known only to the API", models.FavoriteTypeEmployee, "m-petrova", nil, nil},
+		{"unknown employee", models.FavoriteTypeEmployee, "nobody", nil, ErrUnknownFavoriteKey},
+		{"auditory", models.FavoriteTypeAuditory, "101-5 к.", nil, nil},
+		{"unknown auditory", models.FavoriteTypeAuditory, "999-9 к.", nil, ErrUnknownFavoriteKey},
+		{"API unavailable", models.FavoriteTypeGroup, "999999", errors.New("bsuir is down"), nil},
+		{"empty key", models.FavoriteTypeGroup, "  ", nil, ErrInvalidFavorite},
+		{"key too long", models.FavoriteTypeAuditory, strings.Repeat("к", maxFavoriteKeyLength+1), nil, ErrInvalidFavorite},
+		{"unknown type", "building", "1", nil, ErrUnknownFavoriteType},
+	}
+	for _, tt := range tests {
+		source.err = tt.sourceErr
+		service, _, _ := newFavoriteFixture(t, source)
+		_, err := service.AddFavoriteItem(context.Background(), "user-1", tt.kind, tt.key)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: AddFavoriteItem(%s, %q) = %v, want %v", tt.name, tt.kind, tt.key, err, tt.wantErr)
+		}
+	}
+}
@@ -7,10 +7,15 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
 
+	"schedluer/internal/logging"
+	"schedluer/internal/metrics"
 	"schedluer/internal/models"
 	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
 	"schedluer/pkg/bsuir"
+	"schedluer/pkg/worker"
 )
 
 type GroupService interface {
@@ -20,41 +25,54 @@ type GroupService interface {
 }
 
 type groupService struct {
-	bsuirClient *bsuir.Client
-	groupRepo   repository.GroupRepository
-	logger      *logrus.Logger
+	source    bsuir.ScheduleSource
+	groupRepo repository.GroupRepository
+	tasks     *worker.Group
+	logger    *logrus.Logger
 }
 
-func NewGroupService(bsuirClient *bsuir.Client, groupRepo repository.GroupRepository, logger *logrus.Logger) GroupService {
+func NewGroupService(source bsuir.ScheduleSource, groupRepo repository.GroupRepository, tasks *worker.Group, logger *logrus.Logger) GroupService {
 	return &groupService{
-		bsuirClient: bsuirClient,
-		groupRepo:   groupRepo,
-		logger:      logger,
+		source:    source,
+		groupRepo: groupRepo,
+		tasks:     tasks,
+		logger:    logger,
 	}
 }
 
-func (s *groupService) GetAllGroups(ctx context.Context, useCache bool) ([]models.StudentGroupListItem, error) {
+func (s *groupService) GetAllGroups(ctx context.Context, useCache bool) (_ []models.StudentGroupListItem, err error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetAllGroups", attribute.Bool("cache.use", useCache))
+	defer tracing.End(span, &err)
+
 	if useCache {
 		stored, err := s.groupRepo.GetAll(ctx)
 		if err != nil {
-			s.logger.Warnf("Failed to get groups from cache: %v", err)
+			logging.FromContext(ctx, s.logger).WithError(err).Warn("Failed to get groups from cache")
 		} else if len(stored) > 0 {
 			result := make([]models.StudentGroupListItem, len(stored))
 			for i, g := range stored {
 				result[i] = g.GroupData
 			}
+			observeCache(ctx, span, "groups", metrics.CacheHit)
 			return result, nil
 		}
+		observeCache(ctx, span, "groups", metrics.CacheMiss)
+	} else {
+		observeCache(ctx, span, "groups", metrics.CacheBypass)
+		// Справочник в памяти тоже пропускаем, иначе useCache=false отдал бы его до истечения TTL
+		ctx = bsuir.WithoutCache(ctx)
 	}
 
-	groups, err := s.bsuirClient.GetAllGroups()
+	groups, err := s.source.GetAllGroups(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get groups from BSUIR API: %w", err)
 	}
 
-	// Сохраняем в фоне с отдельным контекстом
-	go func() {
-		saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	// Сохраняем в фоне, не задерживая ответ; при остановке сервиса запись будет дождана.
+	// Логгер берем из запроса, чтобы ошибки записи связывались с породившим их request_id.
+	log := logging.FromContext(ctx, s.logger).WithField("task", "cache-groups")
+	_ = s.tasks.Go("cache-groups", func(taskCtx context.Context) {
+		saveCtx, cancel := context.WithTimeout(taskCtx, 5*time.Minute)
 		defer cancel()
 
 		for _, g := range groups {
@@ -67,15 +85,20 @@ func (s *groupService) GetAllGroups(ctx context.Context, useCache bool) ([]model
 				UpdatedAt:      time.Now(),
 			}
 			if err := s.groupRepo.Update(saveCtx, &stored); err != nil {
-				s.logger.Warnf("Failed to save group %d to cache: %v", g.ID, err)
+				log.WithError(err).WithField("bsuir_id", g.ID).Warn("Failed to save group to cache")
 			}
 		}
-	}()
+	})
 
 	return groups, nil
 }
 
-func (s *groupService) GetGroupByNumber(ctx context.Context, groupNumber string) (*models.StudentGroupListItem, error) {
+func (s *groupService) GetGroupByNumber(ctx context.Context, groupNumber string) (_ *models.StudentGroupListItem, err error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupByNumber", attribute.String("group.number", groupNumber))
+	defer tracing.End(span, &err)
+
+	logging.AddFields(ctx, logrus.Fields{"group_number": groupNumber})
+
 	stored, err := s.groupRepo.GetByNumber(ctx, groupNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group: %w", err)
@@ -98,8 +121,14 @@ func (s *groupService) GetGroupByNumber(ctx context.Context, groupNumber string)
 	return nil, fmt.Errorf("group not found: %s", groupNumber)
 }
 
-func (s *groupService) RefreshGroups(ctx context.Context) error {
-	groups, err := s.bsuirClient.GetAllGroups()
+func (s *groupService) RefreshGroups(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "GroupService.RefreshGroups")
+	defer tracing.End(span, &err)
+
+	// Массовое обновление идет мимо кэша справочников и уступает очередь к API БГУИРа пользовательским запросам
+	ctx = bsuir.WithPriority(bsuir.WithoutCache(ctx), bsuir.PriorityBackground)
+
+	groups, err := s.source.GetAllGroups(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get groups from BSUIR API: %w", err)
 	}
@@ -122,7 +151,7 @@ func (s *groupService) RefreshGroups(ctx context.Context) error {
 
 	for _, g := range stored {
 		if err := s.groupRepo.Update(saveCtx, &g); err != nil {
-			s.logger.Warnf("Failed to update group %d: %v", g.BSUIRID, err)
+			logging.FromContext(ctx, s.logger).WithError(err).WithField("bsuir_id", g.BSUIRID).Warn("Failed to update group")
 		}
 	}
 
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/worker"
+)
+
+const (
+	// Результат пробы API БГУИРа кэшируется, чтобы частые запросы /readyz не нагружали iis.bsuir.by
+	bsuirProbeTTL     = time.Minute
+	bsuirProbeTimeout = 5 * time.Second
+	// Фоновая задача, работающая дольше этого порога, считается зависшей
+	backgroundLagThreshold = 10 * time.Minute
+)
+
+type HealthService interface {
+	Readiness(ctx context.Context) models.HealthReport
+}
+
+type healthService struct {
+	store        repository.Store
+	source       bsuir.ScheduleSource
+	groupRepo    repository.GroupRepository
+	employeeRepo repository.EmployeeRepository
+	tasks        *worker.Group
+	logger       *logrus.Logger
+
+	// probes объединяет параллельные пробы API в одну; probeMu защищает только ее результат
+	probes     singleflight.Group
+	probeMu    sync.Mutex
+	lastProbe  models.HealthCheck
+	probeValid bool
+}
+
+func NewHealthService(
+	store repository.Store,
+	source bsuir.ScheduleSource,
+	groupRepo repository.GroupRepository,
+	employeeRepo repository.EmployeeRepository,
+	tasks *worker.Group,
+	logger *logrus.Logger,
+) HealthService {
+	return &healthService{
+		store:        store,
+		source:       source,
+		groupRepo:    groupRepo,
+		employeeRepo: employeeRepo,
+		tasks:        tasks,
+		logger:       logger,
+	}
+}
+
+func (s *healthService) Readiness(ctx context.Context) models.HealthReport {
+	checks := map[string]models.HealthCheck{
+		"storage":    s.checkStorage(ctx),
+		"bsuir_api":  s.checkBSUIR(ctx),
+		"cache":      s.checkCache(ctx),
+		"background": s.checkBackground(),
+	}
+
+	status := models.HealthOK
+	for _, check := range checks {
+		if check.Status == models.HealthOK {
+			continue
+		}
+		if check.Critical {
+			status = models.HealthUnavailable
+			break
+		}
+		status = models.HealthDegraded
+	}
+
+	return models.HealthReport{
+		Status:    status,
+		Checks:    checks,
+		CheckedAt: time.Now(),
+	}
+}
+
+func (s *healthService) checkStorage(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	check := models.HealthCheck{
+		Status:   models.HealthOK,
+		Critical: true,
+		Details:  map[string]any{"driver": s.store.Driver()},
+	}
+
+	if err := s.store.Health(ctx); err != nil {
+		// /readyz публичный: текст ошибки драйвера (адреса, имена баз) остается в логе
+		s.logger.WithError(err).Warn("Storage health check failed")
+		check.Status = models.HealthUnavailable
+		check.Error = "storage is unavailable"
+	}
+
+	check.LatencyMs = time.Since(start).Milliseconds()
+	check.CheckedAt = time.Now()
+	return check
+}
+
+// checkBSUIR проверяет доступность API дешевым запросом текущей недели.
+// Без API сервис продолжает отдавать кэш, поэтому проверка некритичная. Проба идет одна на все
+// запросы и не зависит от их отмены; пока она в пути, отдается прошлый результат, и только
+// самый первый запрос ждет пробу (не дольше, чем живет его ctx).
+func (s *healthService) checkBSUIR(ctx context.Context) models.HealthCheck {
+	s.probeMu.Lock()
+	last, valid := s.lastProbe, s.probeValid
+	s.probeMu.Unlock()
+
+	if valid && time.Since(last.CheckedAt) < bsuirProbeTTL {
+		return last
+	}
+
+	result := s.probes.DoChan("bsuir", func() (any, error) {
+		return s.probeBSUIR(context.WithoutCancel(ctx)), nil
+	})
+	if valid {
+		return last
+	}
+
+	select {
+	case r := <-result:
+		return r.Val.(models.HealthCheck)
+	case <-ctx.Done():
+		return models.HealthCheck{Status: models.HealthDegraded, Error: "BSUIR API check is in progress", CheckedAt: time.Now()}
+	}
+}
+
+func (s *healthService) probeBSUIR(ctx context.Context) models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, bsuirProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	check := models.HealthCheck{Status: models.HealthOK}
+
+	week, err := s.source.GetCurrentWeek(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("BSUIR API health check failed")
+		check.Status = models.HealthDegraded
+		check.Error = "BSUIR API is unavailable"
+	} else {
+		check.Details = map[string]any{"current_week": week}
+	}
+
+	check.LatencyMs = time.Since(start).Milliseconds()
+	check.CheckedAt = time.Now()
+
+	s.probeMu.Lock()
+	s.lastProbe = check
+	s.probeValid = true
+	s.probeMu.Unlock()
+	return check
+}
+
+func (s *healthService) checkCache(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	check := models.HealthCheck{Status: models.HealthOK}
+
+	groups, err := s.groupRepo.Count(ctx)
+	if err == nil {
+		var employees int64
+		employees, err = s.employeeRepo.Count(ctx)
+		check.Details = map[string]any{"groups": groups, "employees": employees}
+		if err == nil && (groups == 0 || employees == 0) {
+			check.Status = models.HealthDegraded
+			check.Error = "cache is not warmed up"
+		}
+	}
+	if err != nil {
+		s.logger.WithError(err).Warn("Cache health check failed")
+		check.Status = models.HealthDegraded
+		check.Error = "failed to count cached groups and employees"
+	}
+
+	check.LatencyMs = time.Since(start).Milliseconds()
+	check.CheckedAt = time.Now()
+	return check
+}
+
+func (s *healthService) checkBackground() models.HealthCheck {
+	stats := s.tasks.Stats()
+	check := models.HealthCheck{
+		Status: models.HealthOK,
+		Details: map[string]any{
+			"running":            stats.Running,
+			"oldest_task":        stats.OldestTask,
+			"oldest_age_seconds": int64(stats.OldestAge.Seconds()),
+		},
+		CheckedAt: time.Now(),
+	}
+
+	if stats.OldestAge > backgroundLagThreshold {
+		check.Status = models.HealthDegraded
+		check.Error = "background task " + stats.OldestTask + " is running longer than expected"
+	}
+
+	return check
+}
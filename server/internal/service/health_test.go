@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/pkg/bsuir"
+)
+
+// blockingWeekSource отвечает на GetCurrentWeek, только когда тест отпустит release
+type blockingWeekSource struct {
+	bsuir.ScheduleSource
+	release chan struct{}
+	err     error
+	calls   atomic.Int32
+}
+
+func (s *blockingWeekSource) GetCurrentWeek(ctx context.Context) (int, error) {
+	s.calls.Add(1)
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	return 3, s.err
+}
+
+func TestCheckBSUIRSingleProbe(t *testing.T) {
+	source := &blockingWeekSource{release: make(chan struct{})}
+	health := &healthService{source: source, logger: testLogger()}
+
+	// Первые запросы ждут пробу не дольше своего ctx, но проба одна и переживает их отмену
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			if check := health.checkBSUIR(ctx); check.Status != models.HealthDegraded {
+				t.Errorf("check while the first probe is in flight = %+v", check)
+			}
+		}()
+	}
+	wg.Wait()
+	close(source.release)
+	waitProbe(t, health, func(check models.HealthCheck) bool { return check.Status == models.HealthOK })
+	if calls := source.calls.Load(); calls != 1 {
+		t.Fatalf("GetCurrentWeek called %d times, want 1", calls)
+	}
+
+	// Устаревший результат отдается сразу, пока в фоне идет новая проба
+	source.release = make(chan struct{})
+	health.probeMu.Lock()
+	health.lastProbe.CheckedAt = time.Now().Add(-2 * bsuirProbeTTL)
+	health.probeMu.Unlock()
+
+	start := time.Now()
+	if check := health.checkBSUIR(context.Background()); check.Status != models.HealthOK {
+		t.Errorf("stale check = %+v", check)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("stale check waited for the probe: %s", elapsed)
+	}
+	close(source.release)
+	waitProbe(t, health, func(check models.HealthCheck) bool { return time.Since(check.CheckedAt) < bsuirProbeTTL })
+	if calls := source.calls.Load(); calls != 2 {
+		t.Errorf("GetCurrentWeek called %d times, want 2", calls)
+	}
+}
+
+func TestCheckBSUIRHidesUpstreamError(t *testing.T) {
+	source := &blockingWeekSource{release: make(chan struct{}), err: errors.New("dial tcp 10.0.0.7:443: connection refused")}
+	close(source.release)
+	health := &healthService{source: source, logger: testLogger()}
+
+	check := health.checkBSUIR(context.Background())
+	if check.Status != models.HealthDegraded || check.Error == "" || strings.Contains(check.Error, "10.0.0.7") {
+		t.Errorf("check with a failing API = %+v", check)
+	}
+}
+
+func waitProbe(t *testing.T, health *healthService, done func(models.HealthCheck) bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		health.probeMu.Lock()
+		check, valid := health.lastProbe, health.probeValid
+		health.probeMu.Unlock()
+		if valid && done(check) {
+			return
+		}
+	}
+	t.Fatal("probe did not finish")
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+
+	"schedluer/internal/logging"
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/converter"
+)
+
+var (
+	// ErrInvalidNote — неизвестный вид записи, пустой текст, неверное занятие, срок или вложение
+	ErrInvalidNote = errors.New("invalid note")
+	// ErrNoteNotFound — записи нет или она принадлежит другому пользователю
+	ErrNoteNotFound = errors.New("note not found")
+	// ErrLessonNotFound — в расписании группы нет такого занятия в указанную дату
+	ErrLessonNotFound = errors.New("lesson not found")
+)
+
+const (
+	maxNoteTextLength       = 4000
+	maxAttachments          = 10
+	maxAttachmentNameLength = 200
+	lessonTimeLayout        = "15:04"
+)
+
+// NoteService ведет заметки и домашние задания к занятиям. Занятие при создании сверяется
+// с расписанием группы; если расписание получить не удалось, запись сохраняется без проверки.
+type NoteService interface {
+	ListNotes(ctx context.Context, userID string, filter repository.NoteFilter) ([]models.LessonNote, error)
+	CreateNote(ctx context.Context, userID string, request models.NoteRequest) (*models.LessonNote, error)
+	UpdateNote(ctx context.Context, userID string, id string, details models.NoteDetails) (*models.LessonNote, error)
+	DeleteNote(ctx context.Context, userID string, id string) error
+	// ListHomework возвращает домашние задания по сроку сдачи
+	ListHomework(ctx context.Context, userID string, filter repository.HomeworkFilter) ([]models.LessonNote, error)
+}
+
+type noteService struct {
+	noteRepo        repository.NoteRepository
+	scheduleService ScheduleService
+	source          bsuir.ScheduleSource
+	logger          *logrus.Logger
+}
+
+func NewNoteService(noteRepo repository.NoteRepository, scheduleService ScheduleService, source bsuir.ScheduleSource, logger *logrus.Logger) NoteService {
+	return &noteService{
+		noteRepo:        noteRepo,
+		scheduleService: scheduleService,
+		source:          source,
+		logger:          logger,
+	}
+}
+
+func (s *noteService) ListNotes(ctx context.Context, userID string, filter repository.NoteFilter) (_ []models.LessonNote, err error) {
+	ctx, span := tracing.Start(ctx, "NoteService.ListNotes", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	if filter.Date != "" {
+		if _, err := time.Parse(time.DateOnly, filter.Date); err != nil {
+			return nil, fmt.Errorf("%w: date must be in YYYY-MM-DD format", ErrInvalidNote)
+		}
+	}
+	return s.noteRepo.GetByUser(ctx, userID, filter)
+}
+
+func (s *noteService) ListHomework(ctx context.Context, userID string, filter repository.HomeworkFilter) (_ []models.LessonNote, err error) {
+	ctx, span := tracing.Start(ctx, "NoteService.ListHomework", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	return s.noteRepo.GetHomework(ctx, userID, filter)
+}
+
+func (s *noteService) CreateNote(ctx context.Context, userID string, request models.NoteRequest) (_ *models.LessonNote, err error) {
+	ctx, span := tracing.Start(ctx, "NoteService.CreateNote", attribute.String("user.id", userID), attribute.String("note.kind", request.Kind))
+	defer tracing.End(span, &err)
+
+	if request.Kind != models.NoteKindNote && request.Kind != models.NoteKindHomework {
+		return nil, fmt.Errorf("%w: kind must be %s or %s", ErrInvalidNote, models.NoteKindNote, models.NoteKindHomework)
+	}
+	lesson, err := normalizeLesson(request.Lesson)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	note := &models.LessonNote{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Kind:      request.Kind,
+		Lesson:    lesson,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	details := models.NoteDetails{Text: &request.Text, Attachments: &request.Attachments, DueAt: request.DueAt}
+	if err := applyNoteDetails(note, details, now); err != nil {
+		return nil, err
+	}
+	if err := s.checkLesson(ctx, lesson); err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to create note: %w", err)
+	}
+	return note, nil
+}
+
+func (s *noteService) UpdateNote(ctx context.Context, userID string, id string, details models.NoteDetails) (_ *models.LessonNote, err error) {
+	ctx, span := tracing.Start(ctx, "NoteService.UpdateNote", attribute.String("user.id", userID), attribute.String("note.id", id))
+	defer tracing.End(span, &err)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+	}
+	note, err := s.noteRepo.GetByID(ctx, userID, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	if note == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+	}
+
+	now := time.Now()
+	if err := applyNoteDetails(note, details, now); err != nil {
+		return nil, err
+	}
+	note.UpdatedAt = now
+
+	found, err := s.noteRepo.Update(ctx, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update note: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+	}
+	return note, nil
+}
+
+func (s *noteService) DeleteNote(ctx context.Context, userID string, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "NoteService.DeleteNote", attribute.String("user.id", userID), attribute.String("note.id", id))
+	defer tracing.End(span, &err)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+	}
+	deleted, err := s.noteRepo.Delete(ctx, userID, objectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+	}
+	return nil
+}
+
+// checkLesson ищет занятие в расписании группы на указанную дату. Недоступное расписание
+// не мешает сохранить запись: отказ только если расписание есть, а занятия в нем нет.
+func (s *noteService) checkLesson(ctx context.Context, lesson models.LessonRef) error {
+	log := logging.FromContext(ctx, s.logger).WithFields(logrus.Fields{"group_number": lesson.GroupNumber, "date": lesson.Date})
+
+	schedule, err := s.scheduleService.GetGroupSchedule(ctx, lesson.GroupNumber, true)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.WithError(err).Warn("Failed to get schedule, saving note without lesson check")
+		return nil
+	}
+	calendar, err := weekCalendar(currentWeek(ctx, s.source, s.logger), schedule)
+	if err != nil {
+		log.WithError(err).Warn("Failed to count study weeks, saving note without lesson check")
+		return nil
+	}
+
+	date, _ := time.ParseInLocation(time.DateOnly, lesson.Date, converter.Minsk)
+	for _, occurrence := range converter.Expand(schedule, date, date, calendar) {
+		if lessonMatches(occurrence.Lesson, lesson) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s %s (%s) at %s %s", ErrLessonNotFound, lesson.GroupNumber, lesson.Subject, lesson.LessonType, lesson.Date, lesson.Start)
+}
+
+func lessonMatches(scheduled models.Schedule, lesson models.LessonRef) bool {
+	return scheduled.Subject == lesson.Subject &&
+		scheduled.LessonTypeAbbrev == lesson.LessonType &&
+		scheduled.StartLessonTime == lesson.Start
+}
+
+// normalizeLesson проверяет ссылку на занятие и приводит время к HH:MM, как в расписании
+func normalizeLesson(lesson models.LessonRef) (models.LessonRef, error) {
+	lesson.GroupNumber = strings.TrimSpace(lesson.GroupNumber)
+	lesson.Subject = strings.TrimSpace(lesson.Subject)
+	lesson.LessonType = strings.TrimSpace(lesson.LessonType)
+	if lesson.GroupNumber == "" || lesson.Subject == "" {
+		return lesson, fmt.Errorf("%w: lesson group_number and subject are required", ErrInvalidNote)
+	}
+	if _, err := time.Parse(time.DateOnly, lesson.Date); err != nil {
+		return lesson, fmt.Errorf("%w: lesson date must be in YYYY-MM-DD format", ErrInvalidNote)
+	}
+	start, err := time.Parse(lessonTimeLayout, strings.TrimSpace(lesson.Start))
+	if err != nil {
+		return lesson, fmt.Errorf("%w: lesson start must be in HH:MM format", ErrInvalidNote)
+	}
+	lesson.Start = start.Format(lessonTimeLayout)
+	return lesson, nil
+}
+
+// applyNoteDetails переносит изменения в запись. Срок и отметка о выполнении есть только у домашних заданий.
+func applyNoteDetails(note *models.LessonNote, details models.NoteDetails, now time.Time) error {
+	homework := note.Kind == models.NoteKindHomework
+
+	if details.Text != nil {
+		text := strings.TrimSpace(*details.Text)
+		if text == "" || utf8.RuneCountInString(text) > maxNoteTextLength {
+			return fmt.Errorf("%w: text must be 1 to %d characters", ErrInvalidNote, maxNoteTextLength)
+		}
+		note.Text = text
+	}
+
+	if details.Attachments != nil {
+		attachments, err := normalizeAttachments(*details.Attachments)
+		if err != nil {
+			return err
+		}
+		note.Attachments = attachments
+	}
+
+	if details.DueAt != nil {
+		if !homework {
+			return fmt.Errorf("%w: only homework has a due date", ErrInvalidNote)
+		}
+		dueAt := *details.DueAt
+		note.DueAt = &dueAt
+	}
+	if homework && note.DueAt == nil {
+		return fmt.Errorf("%w: homework needs due_at", ErrInvalidNote)
+	}
+
+	if details.Done != nil {
+		if !homework {
+			return fmt.Errorf("%w: only homework can be marked done", ErrInvalidNote)
+		}
+		switch {
+		case *details.Done && !note.Done:
+			note.Done, note.DoneAt = true, &now
+		case !*details.Done:
+			note.Done, note.DoneAt = false, nil
+		}
+	}
+	return nil
+}
+
+func normalizeAttachments(attachments []models.Attachment) ([]models.Attachment, error) {
+	if len(attachments) > maxAttachments {
+		return nil, fmt.Errorf("%w: at most %d attachments", ErrInvalidNote, maxAttachments)
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	result := make([]models.Attachment, len(attachments))
+	for i, attachment := range attachments {
+		attachment.Name = strings.TrimSpace(attachment.Name)
+		if attachment.Name == "" || utf8.RuneCountInString(attachment.Name) > maxAttachmentNameLength {
+			return nil, fmt.Errorf("%w: attachment name must be 1 to %d characters", ErrInvalidNote, maxAttachmentNameLength)
+		}
+		parsed, err := url.Parse(strings.TrimSpace(attachment.URL))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%w: attachment %q needs an http or https URL", ErrInvalidNote, attachment.Name)
+		}
+		if attachment.Size < 0 {
+			return nil, fmt.Errorf("%w: attachment %q has a negative size", ErrInvalidNote, attachment.Name)
+		}
+		attachment.URL = parsed.String()
+		result[i] = attachment
+	}
+	return result, nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/converter"
+)
+
+var (
+	// ErrInvalidOverride — неизвестное расписание, пустой предмет, неверные день недели или время,
+	// либо правка ничего не меняет
+	ErrInvalidOverride = errors.New("invalid override")
+	// ErrOverrideNotFound — правки нет или она принадлежит другому пользователю
+	ErrOverrideNotFound = errors.New("override not found")
+)
+
+const (
+	maxOverrideAuditories     = 10
+	maxOverrideAuditoryLength = 100
+	maxOverrideSubjectLength  = 200
+	maxOverrideSubgroup       = 2
+)
+
+// OverrideService ведет личные правки расписания и накладывает их на ScheduleResponse.
+// Правки не сверяются с текущим расписанием: серия, которой больше нет, просто ни на что не влияет.
+type OverrideService interface {
+	ListOverrides(ctx context.Context, userID string) ([]models.LessonOverride, error)
+	CreateOverride(ctx context.Context, userID string, request models.OverrideRequest) (*models.LessonOverride, error)
+	UpdateOverride(ctx context.Context, userID string, id string, request models.OverrideRequest) (*models.LessonOverride, error)
+	DeleteOverride(ctx context.Context, userID string, id string) error
+	// Personalize возвращает копию расписания с правками пользователя; исходное расписание не меняется
+	Personalize(ctx context.Context, userID, scheduleType, key string, schedule *models.ScheduleResponse) (*models.ScheduleResponse, error)
+}
+
+type overrideService struct {
+	overrideRepo repository.OverrideRepository
+	logger       *logrus.Logger
+}
+
+func NewOverrideService(overrideRepo repository.OverrideRepository, logger *logrus.Logger) OverrideService {
+	return &overrideService{
+		overrideRepo: overrideRepo,
+		logger:       logger,
+	}
+}
+
+func (s *overrideService) ListOverrides(ctx context.Context, userID string) (_ []models.LessonOverride, err error) {
+	ctx, span := tracing.Start(ctx, "OverrideService.ListOverrides", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	return s.overrideRepo.GetByUser(ctx, userID)
+}
+
+func (s *overrideService) CreateOverride(ctx context.Context, userID string, request models.OverrideRequest) (_ *models.LessonOverride, err error) {
+	ctx, span := tracing.Start(ctx, "OverrideService.CreateOverride", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	now := time.Now()
+	override := &models.LessonOverride{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyOverrideRequest(override, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.overrideRepo.Create(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to create override: %w", err)
+	}
+	return override, nil
+}
+
+func (s *overrideService) UpdateOverride(ctx context.Context, userID string, id string, request models.OverrideRequest) (_ *models.LessonOverride, err error) {
+	ctx, span := tracing.Start(ctx, "OverrideService.UpdateOverride", attribute.String("user.id", userID), attribute.String("override.id", id))
+	defer tracing.End(span, &err)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrOverrideNotFound, id)
+	}
+	override, err := s.overrideRepo.GetByID(ctx, userID, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get override: %w", err)
+	}
+	if override == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOverrideNotFound, id)
+	}
+	if err := applyOverrideRequest(override, request); err != nil {
+		return nil, err
+	}
+	override.UpdatedAt = time.Now()
+
+	found, err := s.overrideRepo.Update(ctx, override)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update override: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrOverrideNotFound, id)
+	}
+	return override, nil
+}
+
+func (s *overrideService) DeleteOverride(ctx context.Context, userID string, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "OverrideService.DeleteOverride", attribute.String("user.id", userID), attribute.String("override.id", id))
+	defer tracing.End(span, &err)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrOverrideNotFound, id)
+	}
+	deleted, err := s.overrideRepo.Delete(ctx, userID, objectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete override: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrOverrideNotFound, id)
+	}
+	return nil
+}
+
+func (s *overrideService) Personalize(ctx context.Context, userID, scheduleType, key string, schedule *models.ScheduleResponse) (_ *models.ScheduleResponse, err error) {
+	ctx, span := tracing.Start(ctx, "OverrideService.Personalize",
+		attribute.String("user.id", userID), attribute.String("schedule.type", scheduleType), attribute.String("schedule.key", key))
+	defer tracing.End(span, &err)
+
+	overrides, err := s.overrideRepo.GetBySchedule(ctx, userID, scheduleType, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overrides: %w", err)
+	}
+	return applyOverrides(schedule, overrides), nil
+}
+
+// applyOverrides накладывает правки на занятия по дням недели; экзамены не трогаются.
+// Серия сравнивается с занятием из расписания, а не с уже исправленным; при нескольких
+// подходящих правках более поздняя перекрывает поля более ранней, скрытие не отменяется.
+func applyOverrides(schedule *models.ScheduleResponse, overrides []models.LessonOverride) *models.ScheduleResponse {
+	if schedule == nil || len(overrides) == 0 {
+		return schedule
+	}
+
+	personalized := *schedule
+	personalized.Schedules = make(map[string][]models.Schedule, len(schedule.Schedules))
+	for weekday, lessons := range schedule.Schedules {
+		result := make([]models.Schedule, 0, len(lessons))
+		for _, lesson := range lessons {
+			hidden, changed := false, lesson
+			for _, override := range overrides {
+				if !seriesMatches(override.Series, weekday, lesson) {
+					continue
+				}
+				if override.Hidden {
+					hidden = true
+					continue
+				}
+				if override.Auditories != nil {
+					changed.Auditories = append([]string(nil), override.Auditories...)
+				}
+				if override.StartTime != "" {
+					changed.StartLessonTime = override.StartTime
+				}
+				if override.EndTime != "" {
+					changed.EndLessonTime = override.EndTime
+				}
+			}
+			if !hidden {
+				result = append(result, changed)
+			}
+		}
+		// После переноса времени занятия дня снова идут по началу
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].StartLessonTime < result[j].StartLessonTime
+		})
+		personalized.Schedules[weekday] = result
+	}
+	return &personalized
+}
+
+func seriesMatches(series models.LessonSeries, weekday string, lesson models.Schedule) bool {
+	return series.Subject == lesson.Subject &&
+		(series.LessonType == "" || series.LessonType == lesson.LessonTypeAbbrev) &&
+		(series.Weekday == "" || series.Weekday == weekday) &&
+		(series.Start == "" || series.Start == lesson.StartLessonTime) &&
+		(series.Subgroup == 0 || series.Subgroup == lesson.NumSubgroup)
+}
+
+// applyOverrideRequest проверяет запрос и переносит его в правку
+func applyOverrideRequest(override *models.LessonOverride, request models.OverrideRequest) error {
+	if request.ScheduleType != models.FavoriteTypeGroup && request.ScheduleType != models.FavoriteTypeEmployee {
+		return fmt.Errorf("%w: schedule_type must be %s or %s", ErrInvalidOverride, models.FavoriteTypeGroup, models.FavoriteTypeEmployee)
+	}
+	key := strings.TrimSpace(request.Key)
+	if key == "" {
+		return fmt.Errorf("%w: key is required", ErrInvalidOverride)
+	}
+
+	series := request.Series
+	series.Subject = strings.TrimSpace(series.Subject)
+	series.LessonType = strings.TrimSpace(series.LessonType)
+	series.Weekday = strings.TrimSpace(series.Weekday)
+	if series.Subject == "" || utf8.RuneCountInString(series.Subject) > maxOverrideSubjectLength {
+		return fmt.Errorf("%w: series subject must be 1 to %d characters", ErrInvalidOverride, maxOverrideSubjectLength)
+	}
+	if series.Weekday != "" && !isWeekday(series.Weekday) {
+		return fmt.Errorf("%w: unknown series weekday %q", ErrInvalidOverride, series.Weekday)
+	}
+	if series.Subgroup < 0 || series.Subgroup > maxOverrideSubgroup {
+		return fmt.Errorf("%w: series subgroup must be 0 to %d", ErrInvalidOverride, maxOverrideSubgroup)
+	}
+	var err error
+	if series.Start, err = optionalLessonTime(series.Start, "series start"); err != nil {
+		return err
+	}
+
+	startTime, err := optionalLessonTime(request.StartTime, "start_time")
+	if err != nil {
+		return err
+	}
+	endTime, err := optionalLessonTime(request.EndTime, "end_time")
+	if err != nil {
+		return err
+	}
+	if startTime != "" && endTime != "" && endTime <= startTime {
+		return fmt.Errorf("%w: end_time must be after start_time", ErrInvalidOverride)
+	}
+	auditories, err := normalizeAuditories(request.Auditories)
+	if err != nil {
+		return err
+	}
+
+	changes := auditories != nil || startTime != "" || endTime != ""
+	if request.Hidden == changes {
+		return fmt.Errorf("%w: set either hidden or at least one of auditories, start_time and end_time", ErrInvalidOverride)
+	}
+
+	override.ScheduleType = request.ScheduleType
+	override.Key = key
+	override.Series = series
+	override.Hidden = request.Hidden
+	override.Auditories = auditories
+	override.StartTime = startTime
+	override.EndTime = endTime
+	return nil
+}
+
+// optionalLessonTime приводит непустое время к HH:MM, как в расписании
+func optionalLessonTime(value, field string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	parsed, err := time.Parse(lessonTimeLayout, value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s must be in HH:MM format", ErrInvalidOverride, field)
+	}
+	return parsed.Format(lessonTimeLayout), nil
+}
+
+func normalizeAuditories(auditories []string) ([]string, error) {
+	if len(auditories) == 0 {
+		return nil, nil
+	}
+	if len(auditories) > maxOverrideAuditories {
+		return nil, fmt.Errorf("%w: at most %d auditories", ErrInvalidOverride, maxOverrideAuditories)
+	}
+	result := make([]string, len(auditories))
+	for i, auditory := range auditories {
+		auditory = strings.TrimSpace(auditory)
+		if auditory == "" || utf8.RuneCountInString(auditory) > maxOverrideAuditoryLength {
+			return nil, fmt.Errorf("%w: auditory must be 1 to %d characters", ErrInvalidOverride, maxOverrideAuditoryLength)
+		}
+		result[i] = auditory
+	}
+	return result, nil
+}
+
+func isWeekday(weekday string) bool {
+	for _, name := range converter.Weekdays {
+		if name == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"schedluer/internal/models"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	lesson := func(subject, lessonType, start string, subgroup int) models.Schedule {
+		return models.Schedule{
+			Subject: subject, LessonTypeAbbrev: lessonType, StartLessonTime: start, EndLessonTime: start + "+",
+			NumSubgroup: subgroup, Auditories: []string{"101-5 к."},
+		}
+	}
+	schedule := &models.ScheduleResponse{
+		Schedules: map[string][]models.Schedule{
+			"Понедельник": {lesson("ООП", "ЛК", "09:00", 0), lesson("ВМ", "ПЗ", "10:35", 0), lesson("ООП", "ЛР", "12:25", 1), lesson("ООП", "ЛР", "12:25", 2)},
+			"Среда":       {lesson("ООП", "ЛК", "09:00", 0)},
+		},
+		Exams: []models.Schedule{lesson("ООП", "Экзамен", "09:00", 0)},
+	}
+
+	tests := []struct {
+		name      string
+		overrides []models.LessonOverride
+		// want — предмет, тип, начало и аудитория занятий понедельника после правок
+		want          []string
+		wantWednesday int
+	}{
+		{"no overrides", nil,
+			[]string{"ООП ЛК 09:00 101-5 к.", "ВМ ПЗ 10:35 101-5 к.", "ООП ЛР 12:25 101-5 к.", "ООП ЛР 12:25 101-5 к."}, 1},
+		{"hide a series on every weekday", []models.LessonOverride{{Series: models.LessonSeries{Subject: "ООП", LessonType: "ЛК"}, Hidden: true}},
+			[]string{"ВМ ПЗ 10:35 101-5 к.", "ООП ЛР 12:25 101-5 к.", "ООП ЛР 12:25 101-5 к."}, 0},
+		{"move a lesson and keep the day sorted", []models.LessonOverride{{
+			Series:     models.LessonSeries{Subject: "ООП", LessonType: "ЛК", Weekday: "Понедельник", Start: "09:00"},
+			Auditories: []string{"202-5 к."}, StartTime: "13:50",
+		}}, []string{"ВМ ПЗ 10:35 101-5 к.", "ООП ЛР 12:25 101-5 к.", "ООП ЛР 12:25 101-5 к.", "ООП ЛК 13:50 202-5 к."}, 1},
+		{"lesson type does not match", []models.LessonOverride{{Series: models.LessonSeries{Subject: "ВМ", LessonType: "ЛК"}, Hidden: true}},
+			[]string{"ООП ЛК 09:00 101-5 к.", "ВМ ПЗ 10:35 101-5 к.", "ООП ЛР 12:25 101-5 к.", "ООП ЛР 12:25 101-5 к."}, 1},
+		{"one subgroup", []models.LessonOverride{{Series: models.LessonSeries{Subject: "ООП", LessonType: "ЛР", Subgroup: 2}, Hidden: true}},
+			[]string{"ООП ЛК 09:00 101-5 к.", "ВМ ПЗ 10:35 101-5 к.", "ООП ЛР 12:25 101-5 к."}, 1},
+		{"later override wins, hiding is not undone", []models.LessonOverride{
+			{Series: models.LessonSeries{Subject: "ВМ"}, Auditories: []string{"202-5 к."}},
+			{Series: models.LessonSeries{Subject: "ВМ"}, Auditories: []string{"303-5 к."}},
+			{Series: models.LessonSeries{Subject: "ООП", LessonType: "ЛР"}, Hidden: true},
+			{Series: models.LessonSeries{Subject: "ООП", LessonType: "ЛР"}, StartTime: "14:00"},
+		}, []string{"ООП ЛК 09:00 101-5 к.", "ВМ ПЗ 10:35 303-5 к."}, 1},
+	}
+	for _, tt := range tests {
+		got := applyOverrides(schedule, tt.overrides)
+		var monday []string
+		for _, l := range got.Schedules["Понедельник"] {
+			monday = append(monday, l.Subject+" "+l.LessonTypeAbbrev+" "+l.StartLessonTime+" "+l.Auditories[0])
+		}
+		if !slices.Equal(monday, tt.want) {
+			t.Errorf("%s: Monday = %q, want %q", tt.name, monday, tt.want)
+		}
+		if len(got.Schedules["Среда"]) != tt.wantWednesday {
+			t.Errorf("%s: Wednesday = %+v, want %d lessons", tt.name, got.Schedules["Среда"], tt.wantWednesday)
+		}
+		if len(got.Exams) != 1 {
+			t.Errorf("%s: exams must not be touched, got %+v", tt.name, got.Exams)
+		}
+	}
+
+	// Исходное расписание из кэша не меняется
+	if monday := schedule.Schedules["Понедельник"]; len(monday) != 4 || monday[0].Auditories[0] != "101-5 к." {
+		t.Errorf("applyOverrides modified the cached schedule: %+v", monday)
+	}
+}
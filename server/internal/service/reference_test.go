@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"schedluer/internal/config"
+	"schedluer/internal/repository/memory"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/bsuir/bsuirtest"
+	"schedluer/pkg/worker"
+)
+
+// TestReferenceCacheBypass — справочники в памяти не прячут свежий список от useCache=false и /refresh
+func TestReferenceCacheBypass(t *testing.T) {
+	ctx := context.Background()
+	fake := bsuirtest.NewServer(bsuirtest.DefaultFixtures())
+	defer fake.Close()
+
+	client := bsuir.NewClient(&config.BSUIRAPIConfig{BaseURL: fake.BaseURL(), Timeout: 5 * time.Second})
+	source := bsuir.NewCachingSource(client, time.Hour)
+	tasks := worker.NewGroup(testLogger())
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		_ = tasks.Shutdown(shutdownCtx)
+	}()
+
+	groupService := NewGroupService(source, memory.NewGroupRepository(), tasks, testLogger())
+	employeeService := NewEmployeeService(source, memory.NewEmployeeRepository(), tasks, testLogger())
+
+	tests := []struct {
+		endpoint string
+		get      func(useCache bool) error
+		refresh  func() error
+	}{
+		{
+			endpoint: "/student-groups",
+			get: func(useCache bool) error {
+				_, err := groupService.GetAllGroups(ctx, useCache)
+				return err
+			},
+			refresh: func() error { return groupService.RefreshGroups(ctx) },
+		},
+		{
+			endpoint: "/employees/all",
+			get: func(useCache bool) error {
+				_, err := employeeService.GetAllEmployees(ctx, useCache)
+				return err
+			},
+			refresh: func() error { return employeeService.RefreshEmployees(ctx) },
+		},
+	}
+	for _, tt := range tests {
+		calls := fake.Calls(tt.endpoint)
+		step := func(name string, err error, want int) {
+			t.Helper()
+			if err != nil {
+				t.Fatalf("%s %s: %v", tt.endpoint, name, err)
+			}
+			if got := fake.Calls(tt.endpoint) - calls; got != want {
+				t.Errorf("%s after %s requested %d times, want %d", tt.endpoint, name, got, want)
+			}
+		}
+
+		// Первый запрос идет в API и в фоне сохраняет список, второй отдается из хранилища
+		step("first read", tt.get(true), 1)
+		for deadline := time.Now().Add(5 * time.Second); tasks.Stats().Running > 0 && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+		}
+		step("cached read", tt.get(true), 1)
+
+		step("useCache=false", tt.get(false), 2)
+		step("second useCache=false", tt.get(false), 3)
+		step("refresh", tt.refresh(), 4)
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/notify"
+)
+
+var (
+	// ErrInvalidReminder — канал не настроен на сервере, неверный адрес получателя, время упреждения или тихие часы
+	ErrInvalidReminder = errors.New("invalid reminder")
+	// ErrReminderNotFound — пользователь не подписан на напоминания
+	ErrReminderNotFound = errors.New("reminder not found")
+)
+
+const (
+	// maxReminderLead — самое раннее напоминание, сутки до занятия
+	maxReminderLead    = 24 * 60
+	maxReminderTarget  = 500
+	telegramChatPrefix = "@"
+)
+
+// telegramUsername — публичный канал или группа: @ и 5–32 символа
+var telegramUsername = regexp.MustCompile(`^@[A-Za-z][A-Za-z0-9_]{4,31}$`)
+
+// ReminderService ведет подписку пользователя на напоминания о занятиях.
+// Сами напоминания отправляет ReminderScheduler.
+type ReminderService interface {
+	GetReminder(ctx context.Context, userID string) (*models.ReminderSubscription, error)
+	// SaveReminder создает или заменяет подписку пользователя
+	SaveReminder(ctx context.Context, userID string, request models.ReminderRequest) (*models.ReminderSubscription, error)
+	DeleteReminder(ctx context.Context, userID string) error
+}
+
+type reminderService struct {
+	reminderRepo repository.ReminderRepository
+	// senders — отправители каналов, настроенных на сервере; nil — уведомления выключены
+	senders map[string]notify.Sender
+	// channels — их каналы в постоянном порядке, для сообщений об ошибке
+	channels []string
+	logger   *logrus.Logger
+}
+
+func NewReminderService(reminderRepo repository.ReminderRepository, senders map[string]notify.Sender, logger *logrus.Logger) ReminderService {
+	var channels []string
+	for _, channel := range []string{models.ReminderChannelWebhook, models.ReminderChannelEmail, models.ReminderChannelTelegram} {
+		if _, ok := senders[channel]; ok {
+			channels = append(channels, channel)
+		}
+	}
+	return &reminderService{
+		reminderRepo: reminderRepo,
+		senders:      senders,
+		channels:     channels,
+		logger:       logger,
+	}
+}
+
+func (s *reminderService) GetReminder(ctx context.Context, userID string) (_ *models.ReminderSubscription, err error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.GetReminder", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	subscription, err := s.reminderRepo.GetSubscription(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrReminderNotFound
+	}
+	return subscription, nil
+}
+
+func (s *reminderService) SaveReminder(ctx context.Context, userID string, request models.ReminderRequest) (_ *models.ReminderSubscription, err error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.SaveReminder", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	now := time.Now()
+	subscription := &models.ReminderSubscription{
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyReminderRequest(ctx, subscription, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.reminderRepo.SaveSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to save reminder: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"channel":      subscription.Channel,
+		"lead_minutes": subscription.LeadMinutes,
+	}).Info("Reminder subscription saved")
+
+	return subscription, nil
+}
+
+func (s *reminderService) DeleteReminder(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReminderService.DeleteReminder", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	deleted, err := s.reminderRepo.DeleteSubscription(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+	if !deleted {
+		return ErrReminderNotFound
+	}
+	return nil
+}
+
+func (s *reminderService) applyReminderRequest(ctx context.Context, subscription *models.ReminderSubscription, request models.ReminderRequest) error {
+	channel := strings.TrimSpace(request.Channel)
+	sender, ok := s.senders[channel]
+	if !ok {
+		return fmt.Errorf("%w: channel %q is not configured, available: %s", ErrInvalidReminder, channel, strings.Join(s.channels, ", "))
+	}
+
+	target, err := reminderTarget(channel, strings.TrimSpace(request.Target))
+	if err != nil {
+		return err
+	}
+	// Отправитель проверяет то, что зависит от его настроек, — например, куда webhook вообще можно отправлять
+	if validator, ok := sender.(notify.TargetValidator); ok {
+		if err := validator.ValidateTarget(ctx, target); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReminder, err)
+		}
+	}
+
+	if request.LeadMinutes < 1 || request.LeadMinutes > maxReminderLead {
+		return fmt.Errorf("%w: lead_minutes must be between 1 and %d", ErrInvalidReminder, maxReminderLead)
+	}
+
+	var quiet *models.QuietHours
+	if request.QuietHours != nil {
+		from, err := reminderTime(request.QuietHours.From, "quiet_hours.from")
+		if err != nil {
+			return err
+		}
+		to, err := reminderTime(request.QuietHours.To, "quiet_hours.to")
+		if err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("%w: quiet_hours.from and quiet_hours.to must differ", ErrInvalidReminder)
+		}
+		quiet = &models.QuietHours{From: from, To: to}
+	}
+
+	subscription.Channel = channel
+	subscription.Target = target
+	subscription.LeadMinutes = request.LeadMinutes
+	subscription.QuietHours = quiet
+	return nil
+}
+
+// reminderTarget проверяет адрес получателя для канала и приводит его к каноничному виду
+func reminderTarget(channel, target string) (string, error) {
+	if target == "" {
+		return "", fmt.Errorf("%w: target is required", ErrInvalidReminder)
+	}
+	if len(target) > maxReminderTarget {
+		return "", fmt.Errorf("%w: target must be at most %d characters", ErrInvalidReminder, maxReminderTarget)
+	}
+
+	switch channel {
+	case models.ReminderChannelWebhook:
+		parsed, err := url.Parse(target)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return "", fmt.Errorf("%w: webhook target must be an http(s) URL", ErrInvalidReminder)
+		}
+	case models.ReminderChannelEmail:
+		address, err := mail.ParseAddress(target)
+		if err != nil {
+			return "", fmt.Errorf("%w: email target must be an email address", ErrInvalidReminder)
+		}
+		target = address.Address
+	case models.ReminderChannelTelegram:
+		if strings.HasPrefix(target, telegramChatPrefix) {
+			if !telegramUsername.MatchString(target) {
+				return "", fmt.Errorf("%w: telegram target must be a chat id or @username", ErrInvalidReminder)
+			}
+		} else if _, err := strconv.ParseInt(target, 10, 64); err != nil {
+			return "", fmt.Errorf("%w: telegram target must be a chat id or @username", ErrInvalidReminder)
+		}
+	}
+	return target, nil
+}
+
+func reminderTime(value, field string) (string, error) {
+	parsed, err := time.Parse(lessonTimeLayout, strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s must be in HH:MM format", ErrInvalidReminder, field)
+	}
+	return parsed.Format(lessonTimeLayout), nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"schedluer/internal/metrics"
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/converter"
+	"schedluer/pkg/notify"
+)
+
+// sentReminderRetention — сколько хранить отметки об отправке. Напоминают не раньше чем за сутки,
+// так что через двое суток занятие по отметке уже давно прошло.
+const sentReminderRetention = 48 * time.Hour
+
+// ReminderScheduler раз в interval проходит по подпискам и напоминает о занятиях из ленты пользователя
+// (/me/timetable), которые начнутся в ближайшие LeadMinutes минут. Напоминание отмечается в хранилище
+// до отправки, поэтому повторные проходы и другие экземпляры сервиса его не дублируют. В тихие часы
+// ничего не отправляется, и занятие, которое еще не началось, напомнится на первом проходе после них.
+//
+// Лента собирается заново для каждой подписки на каждом проходе, но только за сегодня (и завтра, если окно
+// переходит через полночь) и из расписаний в хранилище и кэша номера недели — к API БГУИРа проход ходит,
+// только когда расписание устарело. Проход линейен по числу подписок; если их станет много, ленты стоит
+// собирать один раз на набор избранного, а не на пользователя.
+type ReminderScheduler struct {
+	reminderRepo     repository.ReminderRepository
+	timetableService TimetableService
+	// senders — отправители по каналам подписки
+	senders map[string]notify.Sender
+	// interval меняется на лету через SetInterval; intervalChanged будит ожидание следующего прохода
+	interval        atomic.Int64
+	intervalChanged chan struct{}
+	now             func() time.Time
+	logger          *logrus.Logger
+}
+
+// NewReminderScheduler создает планировщик; now == nil — системные часы
+func NewReminderScheduler(reminderRepo repository.ReminderRepository, timetableService TimetableService, senders map[string]notify.Sender, interval time.Duration, now func() time.Time, logger *logrus.Logger) *ReminderScheduler {
+	if now == nil {
+		now = time.Now
+	}
+	s := &ReminderScheduler{
+		reminderRepo:     reminderRepo,
+		timetableService: timetableService,
+		senders:          senders,
+		intervalChanged:  make(chan struct{}, 1),
+		now:              now,
+		logger:           logger,
+	}
+	s.interval.Store(int64(interval))
+	return s
+}
+
+// Interval возвращает текущий интервал между проходами
+func (s *ReminderScheduler) Interval() time.Duration {
+	return time.Duration(s.interval.Load())
+}
+
+// SetInterval меняет интервал без перезапуска (по SIGHUP). Следующий проход будет через новый
+// интервал после смены, а не после предыдущего прохода.
+func (s *ReminderScheduler) SetInterval(interval time.Duration) {
+	if interval <= 0 || s.interval.Swap(int64(interval)) == int64(interval) {
+		return
+	}
+	select {
+	case s.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
+// Run отправляет напоминания сразу и затем каждые Interval, пока не отменен ctx
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	s.logger.WithField("interval", s.Interval().String()).Info("Reminder scheduler started")
+	for {
+		if err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Error("Failed to send reminders")
+		}
+		if !s.wait(ctx) {
+			s.logger.Info("Reminder scheduler stopped")
+			return
+		}
+	}
+}
+
+// wait ждет следующего прохода; false — ctx отменен
+func (s *ReminderScheduler) wait(ctx context.Context) bool {
+	timer := time.NewTimer(s.Interval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-s.intervalChanged:
+			s.logger.WithField("interval", s.Interval().String()).Info("Reminder interval changed")
+			timer.Reset(s.Interval())
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// Tick — один проход по всем подпискам. Ошибка одной подписки пишется в лог и не мешает остальным;
+// ошибкой прохода считается только недоступность списка подписок.
+func (s *ReminderScheduler) Tick(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "ReminderScheduler.Tick")
+	defer tracing.End(span, &err)
+
+	now := s.now()
+	subscriptions, err := s.reminderRepo.GetSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get reminder subscriptions: %w", err)
+	}
+
+	sent := 0
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		count, err := s.remind(ctx, subscription, now)
+		sent += count
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"user_id": subscription.UserID,
+				"channel": subscription.Channel,
+			}).Warn("Failed to send reminders to user")
+		}
+	}
+
+	if _, err := s.reminderRepo.DeleteSentBefore(ctx, now.Add(-sentReminderRetention)); err != nil {
+		s.logger.WithError(err).Warn("Failed to delete old sent reminders")
+	}
+
+	span.SetAttributes(attribute.Int("reminders.subscriptions", len(subscriptions)), attribute.Int("reminders.sent", sent))
+	if sent > 0 {
+		s.logger.WithFields(logrus.Fields{"subscriptions": len(subscriptions), "sent": sent}).Info("Reminders sent")
+	}
+	return nil
+}
+
+// remind отправляет напоминания одного пользователя и возвращает их число
+func (s *ReminderScheduler) remind(ctx context.Context, subscription models.ReminderSubscription, now time.Time) (int, error) {
+	sender, ok := s.senders[subscription.Channel]
+	if !ok {
+		return 0, fmt.Errorf("channel %q is not configured", subscription.Channel)
+	}
+	if inQuietHours(now, subscription.QuietHours) {
+		return 0, nil
+	}
+
+	lead := time.Duration(subscription.LeadMinutes) * time.Minute
+	timetable, err := s.timetableService.GetTimetable(ctx, subscription.UserID, dayStart(now), dayStart(now.Add(lead)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get timetable: %w", err)
+	}
+
+	var (
+		sent int
+		errs []error
+	)
+	for _, entry := range timetable.Entries {
+		// Личные события пользователь завел сам, напоминаем только о занятиях
+		if entry.Lesson == nil || !entry.Start.After(now) || entry.Start.After(now.Add(lead)) {
+			continue
+		}
+
+		key := reminderKey(entry)
+		marked, err := s.reminderRepo.MarkSent(ctx, subscription.UserID, key, now)
+		if err != nil {
+			return sent, fmt.Errorf("failed to mark reminder: %w", err)
+		}
+		if !marked {
+			continue
+		}
+
+		if err := sender.Send(ctx, reminderMessage(subscription, entry, now)); err != nil {
+			metrics.RemindersSent.WithLabelValues(subscription.Channel, "failed").Inc()
+			// Без отметки напоминание уйдет на следующем проходе, если занятие еще не начнется
+			if err := s.reminderRepo.UnmarkSent(ctx, subscription.UserID, key); err != nil {
+				s.logger.WithError(err).WithField("user_id", subscription.UserID).Warn("Failed to unmark reminder")
+			}
+			errs = append(errs, err)
+			continue
+		}
+		metrics.RemindersSent.WithLabelValues(subscription.Channel, "sent").Inc()
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// inQuietHours проверяет время по Минску; интервал, где From позже To, идет через полночь
+func inQuietHours(now time.Time, quiet *models.QuietHours) bool {
+	if quiet == nil {
+		return false
+	}
+	from, err := time.Parse(lessonTimeLayout, quiet.From)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse(lessonTimeLayout, quiet.To)
+	if err != nil {
+		return false
+	}
+
+	local := now.In(converter.Minsk)
+	minute := local.Hour()*60 + local.Minute()
+	start, end := from.Hour()*60+from.Minute(), to.Hour()*60+to.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// reminderKey — занятие в ленте; по нему отмечается отправленное напоминание
+func reminderKey(entry models.TimetableEntry) string {
+	lesson := entry.Lesson
+	return strings.Join([]string{
+		entry.Start.UTC().Format(time.RFC3339),
+		lesson.Subject,
+		lesson.LessonTypeAbbrev,
+		fmt.Sprint(lesson.NumSubgroup),
+	}, "|")
+}
+
+func reminderMessage(subscription models.ReminderSubscription, entry models.TimetableEntry, now time.Time) notify.Message {
+	lesson := entry.Lesson
+	title := lesson.Subject
+	if lesson.LessonTypeAbbrev != "" {
+		title += " (" + lesson.LessonTypeAbbrev + ")"
+	}
+
+	start, end := entry.Start.In(converter.Minsk), entry.End.In(converter.Minsk)
+	minutes := int(math.Ceil(entry.Start.Sub(now).Minutes()))
+
+	lines := []string{fmt.Sprintf("%s, %s, %s–%s", entry.Weekday, start.Format("02.01"), start.Format(lessonTimeLayout), end.Format(lessonTimeLayout))}
+	if len(lesson.Auditories) > 0 {
+		lines = append(lines, "Аудитория: "+strings.Join(lesson.Auditories, ", "))
+	}
+	if lesson.NumSubgroup > 0 {
+		lines = append(lines, fmt.Sprintf("Подгруппа %d", lesson.NumSubgroup))
+	}
+	for _, employee := range lesson.Employees {
+		lines = append(lines, strings.TrimSpace(employee.LastName+" "+employee.FirstName+" "+employee.MiddleName))
+	}
+
+	return notify.Message{
+		To:      subscription.Target,
+		Subject: fmt.Sprintf("Через %d мин: %s", minutes, title),
+		Text:    strings.Join(lines, "\n"),
+		Payload: entry,
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/repository/memory"
+	"schedluer/pkg/converter"
+	"schedluer/pkg/notify"
+)
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// fakeTimetable отдает одни и те же занятия любому пользователю и считает запросы
+type fakeTimetable struct {
+	entries []models.TimetableEntry
+	calls   int
+}
+
+func (f *fakeTimetable) GetTimetable(ctx context.Context, userID string, from, to time.Time) (*models.Timetable, error) {
+	f.calls++
+	return &models.Timetable{Entries: f.entries}, nil
+}
+
+func (f *fakeTimetable) BuildTimetable(ctx context.Context, favorites []models.Favorite, from, to time.Time) (*models.Timetable, error) {
+	return &models.Timetable{Entries: f.entries}, nil
+}
+
+func lessonEntry(start time.Time, subject, lessonType string) models.TimetableEntry {
+	return models.TimetableEntry{
+		Date:    start.Format("2006-01-02"),
+		Weekday: "Понедельник",
+		Start:   start,
+		End:     start.Add(80 * time.Minute),
+		Lesson:  &models.Schedule{Subject: subject, LessonTypeAbbrev: lessonType, Auditories: []string{"101-5 к."}},
+	}
+}
+
+// reminderFixture — планировщик на памяти с подменными часами и отправителем webhook
+type reminderFixture struct {
+	now       time.Time
+	timetable *fakeTimetable
+	sent      []notify.Message
+	failNext  int
+	scheduler *ReminderScheduler
+}
+
+func newReminderFixture(t *testing.T, subscription models.ReminderSubscription, entries ...models.TimetableEntry) *reminderFixture {
+	t.Helper()
+	f := &reminderFixture{timetable: &fakeTimetable{entries: entries}}
+	repo := memory.NewReminderRepository()
+	if err := repo.SaveSubscription(context.Background(), &subscription); err != nil {
+		t.Fatalf("SaveSubscription: %v", err)
+	}
+	sender := notify.SenderFunc(func(ctx context.Context, message notify.Message) error {
+		if f.failNext > 0 {
+			f.failNext--
+			return errors.New("webhook responded with status 503")
+		}
+		f.sent = append(f.sent, message)
+		return nil
+	})
+	f.scheduler = NewReminderScheduler(repo, f.timetable, map[string]notify.Sender{models.ReminderChannelWebhook: sender},
+		time.Minute, func() time.Time { return f.now }, testLogger())
+	return f
+}
+
+func (f *reminderFixture) tick(t *testing.T, now time.Time) {
+	t.Helper()
+	f.now = now
+	if err := f.scheduler.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick at %s: %v", now.Format("15:04"), err)
+	}
+}
+
+func minsk(hour, minute int) time.Time {
+	return time.Date(2026, 9, 7, hour, minute, 0, 0, converter.Minsk)
+}
+
+func TestReminderSchedulerLeadWindow(t *testing.T) {
+	subscription := models.ReminderSubscription{UserID: "u1", Channel: models.ReminderChannelWebhook, Target: "https://example.com/hook", LeadMinutes: 15}
+	f := newReminderFixture(t, subscription, lessonEntry(minsk(9, 0), "ООП", "ЛК"), lessonEntry(minsk(10, 35), "ООП", "ЛР"))
+
+	// 08:44 — до лекции 16 минут, окно в 15 минут ее еще не захватывает
+	f.tick(t, minsk(8, 44))
+	if len(f.sent) != 0 {
+		t.Fatalf("reminders before the lead window: %+v", f.sent)
+	}
+
+	// 08:45 — ровно за 15 минут: граница окна включается
+	f.tick(t, minsk(8, 45))
+	if len(f.sent) != 1 || f.sent[0].Subject != "Через 15 мин: ООП (ЛК)" || f.sent[0].To != subscription.Target {
+		t.Fatalf("reminders at the window edge = %+v", f.sent)
+	}
+
+	// Повторные проходы не дублируют напоминание
+	f.tick(t, minsk(8, 50))
+	f.tick(t, minsk(8, 59))
+	if len(f.sent) != 1 {
+		t.Fatalf("reminder was duplicated: %+v", f.sent)
+	}
+
+	// Начавшееся занятие не напоминается, даже если окно его еще покрывает
+	f.tick(t, minsk(10, 35))
+	if len(f.sent) != 1 {
+		t.Fatalf("reminder for a lesson that has started: %+v", f.sent)
+	}
+}
+
+func TestReminderSchedulerUnmarksFailedSend(t *testing.T) {
+	subscription := models.ReminderSubscription{UserID: "u1", Channel: models.ReminderChannelWebhook, Target: "https://example.com/hook", LeadMinutes: 15}
+	f := newReminderFixture(t, subscription, lessonEntry(minsk(9, 0), "ООП", "ЛК"))
+
+	// Ошибка отправки не роняет проход, а снимает отметку: следующий проход повторяет попытку
+	f.failNext = 1
+	f.tick(t, minsk(8, 50))
+	if len(f.sent) != 0 {
+		t.Fatalf("failed reminder was recorded as sent: %+v", f.sent)
+	}
+	f.tick(t, minsk(8, 51))
+	if len(f.sent) != 1 || f.sent[0].Subject != "Через 9 мин: ООП (ЛК)" {
+		t.Fatalf("failed reminder was not retried: %+v", f.sent)
+	}
+	f.tick(t, minsk(8, 52))
+	if len(f.sent) != 1 {
+		t.Fatalf("retried reminder was duplicated: %+v", f.sent)
+	}
+}
+
+func TestReminderSchedulerQuietHoursOverMidnight(t *testing.T) {
+	subscription := models.ReminderSubscription{
+		UserID: "u1", Channel: models.ReminderChannelWebhook, Target: "https://example.com/hook", LeadMinutes: 30,
+		QuietHours: &models.QuietHours{From: "23:00", To: "07:00"},
+	}
+	f := newReminderFixture(t, subscription, lessonEntry(minsk(7, 10), "Физкультура", "ПЗ"))
+
+	f.tick(t, minsk(6, 45))
+	f.tick(t, minsk(6, 59))
+	if len(f.sent) != 0 || f.timetable.calls != 0 {
+		t.Fatalf("reminders in quiet hours: %+v (timetable requested %d times)", f.sent, f.timetable.calls)
+	}
+
+	// Тихие часы кончились, занятие еще впереди — напоминание уходит сразу
+	f.tick(t, minsk(7, 0))
+	if len(f.sent) != 1 || f.sent[0].Subject != "Через 10 мин: Физкультура (ПЗ)" {
+		t.Fatalf("reminder after quiet hours = %+v", f.sent)
+	}
+}
+
+func TestReminderSchedulerSkipsUnconfiguredChannel(t *testing.T) {
+	subscription := models.ReminderSubscription{UserID: "u1", Channel: models.ReminderChannelTelegram, Target: "42", LeadMinutes: 15}
+	f := newReminderFixture(t, subscription, lessonEntry(minsk(9, 0), "ООП", "ЛК"))
+
+	// Канал выключили после подписки: проход не падает, а подписка ждет, пока канал вернут
+	f.tick(t, minsk(8, 50))
+	if len(f.sent) != 0 || f.timetable.calls != 0 {
+		t.Errorf("reminder sent through an unconfigured channel: %+v", f.sent)
+	}
+}
+
+// countingSubscriptions считает проходы планировщика по запросам списка подписок
+type countingSubscriptions struct {
+	repository.ReminderRepository
+	calls atomic.Int32
+}
+
+func (r *countingSubscriptions) GetSubscriptions(ctx context.Context) ([]models.ReminderSubscription, error) {
+	r.calls.Add(1)
+	return r.ReminderRepository.GetSubscriptions(ctx)
+}
+
+func TestReminderSchedulerSetInterval(t *testing.T) {
+	repo := &countingSubscriptions{ReminderRepository: memory.NewReminderRepository()}
+	scheduler := NewReminderScheduler(repo, &fakeTimetable{}, nil, time.Hour, nil, testLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitPasses := func(want int32) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if repo.calls.Load() >= want {
+				return
+			}
+		}
+		t.Fatalf("scheduler made %d passes, want at least %d", repo.calls.Load(), want)
+	}
+
+	// Первый проход сразу, второго по часовому интервалу не дождаться, пока интервал не сменят на лету
+	waitPasses(1)
+	scheduler.SetInterval(10 * time.Millisecond)
+	waitPasses(3)
+
+	scheduler.SetInterval(0)
+	if interval := scheduler.Interval(); interval != 10*time.Millisecond {
+		t.Errorf("interval after SetInterval(0) = %s, want the previous 10ms", interval)
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	night := &models.QuietHours{From: "23:00", To: "07:00"}
+	lunch := &models.QuietHours{From: "12:00", To: "12:20"}
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		quiet *models.QuietHours
+		want  bool
+	}{
+		{"no quiet hours", minsk(3, 0), nil, false},
+		{"before midnight", minsk(23, 30), night, true},
+		{"from is inclusive", minsk(23, 0), night, true},
+		{"after midnight", minsk(0, 15), night, true},
+		{"last quiet minute", minsk(6, 59), night, true},
+		{"to is exclusive", minsk(7, 0), night, false},
+		{"evening", minsk(22, 59), night, false},
+		{"daytime range", minsk(12, 10), lunch, true},
+		{"after daytime range", minsk(12, 20), lunch, false},
+		{"before daytime range", minsk(11, 59), lunch, false},
+		{"UTC is converted to Minsk", time.Date(2026, 9, 7, 21, 30, 0, 0, time.UTC), night, true},
+		{"invalid value", minsk(23, 30), &models.QuietHours{From: "late", To: "07:00"}, false},
+	}
+	for _, tt := range tests {
+		if got := inQuietHours(tt.now, tt.quiet); got != tt.want {
+			t.Errorf("%s: inQuietHours(%s) = %v, want %v", tt.name, tt.now.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestReminderKey(t *testing.T) {
+	lecture := lessonEntry(minsk(9, 0), "ООП", "ЛК")
+	sameInUTC := lecture
+	sameInUTC.Start = lecture.Start.UTC()
+	if reminderKey(lecture) != reminderKey(sameInUTC) {
+		t.Errorf("key depends on the time zone: %q != %q", reminderKey(lecture), reminderKey(sameInUTC))
+	}
+	if got := reminderKey(lecture); got != "2026-09-07T06:00:00Z|ООП|ЛК|0" {
+		t.Errorf("reminderKey = %q", got)
+	}
+
+	subgroup := lessonEntry(minsk(9, 0), "ООП", "ЛК")
+	subgroup.Lesson.NumSubgroup = 2
+	lab := lessonEntry(minsk(9, 0), "ООП", "ЛР")
+	nextWeek := lessonEntry(minsk(9, 0).AddDate(0, 0, 7), "ООП", "ЛК")
+	for _, other := range []models.TimetableEntry{subgroup, lab, nextWeek} {
+		if reminderKey(other) == reminderKey(lecture) {
+			t.Errorf("different lessons share the key %q", reminderKey(other))
+		}
+	}
+}
+
+func TestSaveReminderValidatesWebhook(t *testing.T) {
+	senders := map[string]notify.Sender{
+		models.ReminderChannelWebhook: notify.NewWebhookSender(notify.WebhookConfig{Timeout: time.Second}),
+	}
+	reminderService := NewReminderService(memory.NewReminderRepository(), senders, testLogger())
+	ctx := context.Background()
+
+	for _, target := range []string{
+		"http://8.8.8.8/hook",
+		"https://127.0.0.1/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://192.168.0.10/hook",
+		"not a url",
+	} {
+		request := models.ReminderRequest{Channel: models.ReminderChannelWebhook, Target: target, LeadMinutes: 15}
+		if _, err := reminderService.SaveReminder(ctx, "u1", request); !errors.Is(err, ErrInvalidReminder) {
+			t.Errorf("SaveReminder(%s): expected ErrInvalidReminder, got %v", target, err)
+		}
+	}
+
+	request := models.ReminderRequest{Channel: models.ReminderChannelEmail, Target: "student@example.com", LeadMinutes: 15}
+	if _, err := reminderService.SaveReminder(ctx, "u1", request); !errors.Is(err, ErrInvalidReminder) {
+		t.Errorf("unconfigured channel: expected ErrInvalidReminder, got %v", err)
+	}
+
+	request = models.ReminderRequest{Channel: models.ReminderChannelWebhook, Target: "https://8.8.8.8/hook", LeadMinutes: 15}
+	if _, err := reminderService.SaveReminder(ctx, "u1", request); err != nil {
+		t.Errorf("public https webhook: %v", err)
+	}
+}
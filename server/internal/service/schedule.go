@@ -7,9 +7,13 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
 
+	"schedluer/internal/logging"
+	"schedluer/internal/metrics"
 	"schedluer/internal/models"
 	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
 	"schedluer/pkg/bsuir"
 )
 
@@ -21,33 +25,42 @@ type ScheduleService interface {
 }
 
 type scheduleService struct {
-	bsuirClient  *bsuir.Client
+	source       bsuir.ScheduleSource
 	scheduleRepo repository.ScheduleRepository
 	logger       *logrus.Logger
 }
 
-func NewScheduleService(bsuirClient *bsuir.Client, scheduleRepo repository.ScheduleRepository, logger *logrus.Logger) ScheduleService {
+func NewScheduleService(source bsuir.ScheduleSource, scheduleRepo repository.ScheduleRepository, logger *logrus.Logger) ScheduleService {
 	return &scheduleService{
-		bsuirClient:  bsuirClient,
+		source:       source,
 		scheduleRepo: scheduleRepo,
 		logger:       logger,
 	}
 }
 
-func (s *scheduleService) GetGroupSchedule(ctx context.Context, groupNumber string, useCache bool) (*models.ScheduleResponse, error) {
+func (s *scheduleService) GetGroupSchedule(ctx context.Context, groupNumber string, useCache bool) (_ *models.ScheduleResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.GetGroupSchedule", attribute.String("group.number", groupNumber), attribute.Bool("cache.use", useCache))
+	defer tracing.End(span, &err)
+
+	logging.AddFields(ctx, logrus.Fields{"group_number": groupNumber})
+
 	if useCache {
 		stored, err := s.scheduleRepo.GetByGroupNumber(ctx, groupNumber)
 		if err != nil {
-			s.logger.Warnf("Failed to get schedule from cache: %v", err)
+			logging.FromContext(ctx, s.logger).WithError(err).Warn("Failed to get schedule from cache")
 		} else if stored != nil {
 			// Проверяем, не устарело ли расписание
 			// Можно добавить логику проверки даты обновления
+			observeCache(ctx, span, "group_schedule", metrics.CacheHit)
 			return &stored.ScheduleData, nil
 		}
+		observeCache(ctx, span, "group_schedule", metrics.CacheMiss)
+	} else {
+		observeCache(ctx, span, "group_schedule", metrics.CacheBypass)
 	}
 
 	// Получаем из API
-	schedule, err := s.bsuirClient.GetGroupSchedule(groupNumber)
+	schedule, err := s.source.GetGroupSchedule(ctx, groupNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schedule from BSUIR API: %w", err)
 	}
@@ -61,23 +74,32 @@ func (s *scheduleService) GetGroupSchedule(ctx context.Context, groupNumber stri
 	}
 
 	if err := s.scheduleRepo.Update(ctx, stored); err != nil {
-		s.logger.Warnf("Failed to save schedule to cache: %v", err)
+		logging.FromContext(ctx, s.logger).WithError(err).Warn("Failed to save schedule to cache")
 	}
 
 	return schedule, nil
 }
 
-func (s *scheduleService) GetEmployeeSchedule(ctx context.Context, urlID string, useCache bool) (*models.ScheduleResponse, error) {
+func (s *scheduleService) GetEmployeeSchedule(ctx context.Context, urlID string, useCache bool) (_ *models.ScheduleResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.GetEmployeeSchedule", attribute.String("employee.url_id", urlID), attribute.Bool("cache.use", useCache))
+	defer tracing.End(span, &err)
+
+	logging.AddFields(ctx, logrus.Fields{"employee_url_id": urlID})
+
 	if useCache {
 		stored, err := s.scheduleRepo.GetByEmployeeURLID(ctx, urlID)
 		if err != nil {
-			s.logger.Warnf("Failed to get schedule from cache: %v", err)
+			logging.FromContext(ctx, s.logger).WithError(err).Warn("Failed to get schedule from cache")
 		} else if stored != nil {
+			observeCache(ctx, span, "employee_schedule", metrics.CacheHit)
 			return &stored.ScheduleData, nil
 		}
+		observeCache(ctx, span, "employee_schedule", metrics.CacheMiss)
+	} else {
+		observeCache(ctx, span, "employee_schedule", metrics.CacheBypass)
 	}
 
-	schedule, err := s.bsuirClient.GetEmployeeSchedule(urlID)
+	schedule, err := s.source.GetEmployeeSchedule(ctx, urlID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schedule from BSUIR API: %w", err)
 	}
@@ -90,14 +112,20 @@ func (s *scheduleService) GetEmployeeSchedule(ctx context.Context, urlID string,
 	}
 
 	if err := s.scheduleRepo.Update(ctx, stored); err != nil {
-		s.logger.Warnf("Failed to save schedule to cache: %v", err)
+		logging.FromContext(ctx, s.logger).WithError(err).Warn("Failed to save schedule to cache")
 	}
 
 	return schedule, nil
 }
 
-func (s *scheduleService) RefreshGroupSchedule(ctx context.Context, groupNumber string) error {
-	schedule, err := s.bsuirClient.GetGroupSchedule(groupNumber)
+func (s *scheduleService) RefreshGroupSchedule(ctx context.Context, groupNumber string) (err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.RefreshGroupSchedule", attribute.String("group.number", groupNumber))
+	defer tracing.End(span, &err)
+
+	logging.AddFields(ctx, logrus.Fields{"group_number": groupNumber})
+	ctx = bsuir.WithPriority(ctx, bsuir.PriorityBackground)
+
+	schedule, err := s.source.GetGroupSchedule(ctx, groupNumber)
 	if err != nil {
 		return fmt.Errorf("failed to get schedule from BSUIR API: %w", err)
 	}
@@ -112,8 +140,14 @@ func (s *scheduleService) RefreshGroupSchedule(ctx context.Context, groupNumber
 	return s.scheduleRepo.Update(ctx, stored)
 }
 
-func (s *scheduleService) RefreshEmployeeSchedule(ctx context.Context, urlID string) error {
-	schedule, err := s.bsuirClient.GetEmployeeSchedule(urlID)
+func (s *scheduleService) RefreshEmployeeSchedule(ctx context.Context, urlID string) (err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.RefreshEmployeeSchedule", attribute.String("employee.url_id", urlID))
+	defer tracing.End(span, &err)
+
+	logging.AddFields(ctx, logrus.Fields{"employee_url_id": urlID})
+	ctx = bsuir.WithPriority(ctx, bsuir.PriorityBackground)
+
+	schedule, err := s.source.GetEmployeeSchedule(ctx, urlID)
 	if err != nil {
 		return fmt.Errorf("failed to get schedule from BSUIR API: %w", err)
 	}
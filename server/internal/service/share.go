@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+)
+
+const shareTokenPrefix = "shr_"
+
+var (
+	// ErrInvalidShare — неизвестный вид ссылки, слишком длинное название или срок в прошлом
+	ErrInvalidShare = errors.New("invalid share")
+	// ErrShareNotFound — ссылки нет, она отозвана или истекла; получателю это не различить
+	ErrShareNotFound = errors.New("share not found")
+)
+
+// ShareService выпускает публичные ссылки на избранное и ленту. Как и API-ключи, токен
+// показывается только при создании, а в хранилище лежит его SHA-256 хэш.
+type ShareService interface {
+	CreateShare(ctx context.Context, userID string, request models.ShareRequest) (*models.CreatedShare, error)
+	ListShares(ctx context.Context, userID string) ([]models.Share, error)
+	RevokeShare(ctx context.Context, userID string, id string) error
+
+	// GetShared возвращает содержимое ссылки: избранное или ленту за период from–to
+	GetShared(ctx context.Context, token string, from, to time.Time) (*models.SharedView, error)
+	// GetSharedTimetable возвращает ленту за период для ссылки любого вида — для выгрузки в календарь
+	GetSharedTimetable(ctx context.Context, token string, from, to time.Time) (*models.SharedView, error)
+}
+
+type shareService struct {
+	shareRepo        repository.ShareRepository
+	favoriteRepo     repository.FavoriteRepository
+	favoriteService  FavoriteService
+	timetableService TimetableService
+	logger           *logrus.Logger
+}
+
+func NewShareService(shareRepo repository.ShareRepository, favoriteRepo repository.FavoriteRepository, favoriteService FavoriteService, timetableService TimetableService, logger *logrus.Logger) ShareService {
+	return &shareService{
+		shareRepo:        shareRepo,
+		favoriteRepo:     favoriteRepo,
+		favoriteService:  favoriteService,
+		timetableService: timetableService,
+		logger:           logger,
+	}
+}
+
+func (s *shareService) CreateShare(ctx context.Context, userID string, request models.ShareRequest) (_ *models.CreatedShare, err error) {
+	ctx, span := tracing.Start(ctx, "ShareService.CreateShare", attribute.String("user.id", userID), attribute.String("share.kind", request.Kind))
+	defer tracing.End(span, &err)
+
+	if request.Kind != models.ShareKindFavorites && request.Kind != models.ShareKindTimetable {
+		return nil, fmt.Errorf("%w: kind must be %s or %s", ErrInvalidShare, models.ShareKindFavorites, models.ShareKindTimetable)
+	}
+	title := strings.TrimSpace(request.Title)
+	if utf8.RuneCountInString(title) > maxLabelLength {
+		return nil, fmt.Errorf("%w: title is longer than %d characters", ErrInvalidShare, maxLabelLength)
+	}
+	now := time.Now()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidShare)
+	}
+	if request.CollectionID != "" {
+		id, err := primitive.ObjectIDFromHex(request.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, request.CollectionID)
+		}
+		collection, err := s.favoriteRepo.GetCollection(ctx, userID, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get collection: %w", err)
+		}
+		if collection == nil {
+			return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, request.CollectionID)
+		}
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+	token := shareTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	share := models.Share{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		Kind:         request.Kind,
+		Title:        title,
+		CollectionID: request.CollectionID,
+		Prefix:       token[:len(shareTokenPrefix)+8],
+		TokenHash:    hashShareToken(token),
+		CreatedAt:    now,
+		ExpiresAt:    request.ExpiresAt,
+	}
+	if err := s.shareRepo.Create(ctx, &share); err != nil {
+		return nil, fmt.Errorf("failed to save share: %w", err)
+	}
+
+	return &models.CreatedShare{Share: share, Token: token}, nil
+}
+
+func (s *shareService) ListShares(ctx context.Context, userID string) (_ []models.Share, err error) {
+	ctx, span := tracing.Start(ctx, "ShareService.ListShares", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	return s.shareRepo.GetByUser(ctx, userID)
+}
+
+func (s *shareService) RevokeShare(ctx context.Context, userID string, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "ShareService.RevokeShare", attribute.String("user.id", userID), attribute.String("share.id", id))
+	defer tracing.End(span, &err)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrShareNotFound, id)
+	}
+	revoked, err := s.shareRepo.Revoke(ctx, userID, objectID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke share: %w", err)
+	}
+	if !revoked {
+		return fmt.Errorf("%w: %s", ErrShareNotFound, id)
+	}
+	return nil
+}
+
+func (s *shareService) GetShared(ctx context.Context, token string, from, to time.Time) (_ *models.SharedView, err error) {
+	ctx, span := tracing.Start(ctx, "ShareService.GetShared")
+	defer tracing.End(span, &err)
+
+	share, err := s.activeShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if share.Kind == models.ShareKindTimetable {
+		return s.timetable(ctx, share, from, to)
+	}
+
+	items, err := s.favoriteService.ListFavoriteItems(ctx, share.UserID, "")
+	if err != nil {
+		return nil, err
+	}
+	view := newSharedView(share)
+	view.Favorites = []models.FavoriteItem{}
+	for _, item := range items {
+		if share.CollectionID == "" || item.CollectionID == share.CollectionID {
+			// Получателю ни к чему ID владельца
+			item.UserID = ""
+			view.Favorites = append(view.Favorites, item)
+		}
+	}
+	return view, nil
+}
+
+func (s *shareService) GetSharedTimetable(ctx context.Context, token string, from, to time.Time) (_ *models.SharedView, err error) {
+	ctx, span := tracing.Start(ctx, "ShareService.GetSharedTimetable")
+	defer tracing.End(span, &err)
+
+	share, err := s.activeShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.timetable(ctx, share, from, to)
+}
+
+func (s *shareService) timetable(ctx context.Context, share *models.Share, from, to time.Time) (*models.SharedView, error) {
+	favorites, err := s.favoriteRepo.GetAll(ctx, share.UserID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+	if share.CollectionID != "" {
+		filtered := favorites[:0]
+		for _, favorite := range favorites {
+			if favorite.CollectionID == share.CollectionID {
+				filtered = append(filtered, favorite)
+			}
+		}
+		favorites = filtered
+	}
+
+	timetable, err := s.timetableService.BuildTimetable(ctx, favorites, from, to)
+	if err != nil {
+		return nil, err
+	}
+	view := newSharedView(share)
+	view.Timetable = timetable
+	return view, nil
+}
+
+// activeShare находит ссылку по токену; отозванная и истекшая ссылки не отличаются от несуществующей
+func (s *shareService) activeShare(ctx context.Context, token string) (*models.Share, error) {
+	if !strings.HasPrefix(token, shareTokenPrefix) {
+		return nil, ErrShareNotFound
+	}
+	share, err := s.shareRepo.GetByHash(ctx, hashShareToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share: %w", err)
+	}
+	if share == nil || !share.IsActive(time.Now()) {
+		return nil, ErrShareNotFound
+	}
+	return share, nil
+}
+
+func newSharedView(share *models.Share) *models.SharedView {
+	return &models.SharedView{
+		Kind:      share.Kind,
+		Title:     share.Title,
+		ExpiresAt: share.ExpiresAt,
+	}
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
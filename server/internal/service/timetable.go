@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"schedluer/internal/logging"
+	"schedluer/internal/models"
+	"schedluer/internal/repository"
+	"schedluer/internal/tracing"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/converter"
+)
+
+// MaxTimetableDays ограничивает период одной выборки ленты
+const MaxTimetableDays = 92
+
+// ErrInvalidRange — период ленты пустой или длиннее MaxTimetableDays
+var ErrInvalidRange = errors.New("invalid date range")
+
+// TimetableService собирает расписания избранных групп и преподавателей в одну ленту по датам.
+// Избранные аудитории в ленту не попадают: расписания аудиторий API не отдает.
+// Личные события пользователя добавляются в ленту GetTimetable и пересекаются с занятиями как обычные записи.
+type TimetableService interface {
+	GetTimetable(ctx context.Context, userID string, from, to time.Time) (*models.Timetable, error)
+	// BuildTimetable собирает ленту из переданного избранного, например одной коллекции, без личных событий
+	BuildTimetable(ctx context.Context, favorites []models.Favorite, from, to time.Time) (*models.Timetable, error)
+}
+
+type timetableService struct {
+	favoriteRepo    repository.FavoriteRepository
+	eventRepo       repository.EventRepository
+	scheduleService ScheduleService
+	source          bsuir.ScheduleSource
+	logger          *logrus.Logger
+}
+
+func NewTimetableService(favoriteRepo repository.FavoriteRepository, eventRepo repository.EventRepository, scheduleService ScheduleService, source bsuir.ScheduleSource, logger *logrus.Logger) TimetableService {
+	return &timetableService{
+		favoriteRepo:    favoriteRepo,
+		eventRepo:       eventRepo,
+		scheduleService: scheduleService,
+		source:          source,
+		logger:          logger,
+	}
+}
+
+func (s *timetableService) GetTimetable(ctx context.Context, userID string, from, to time.Time) (_ *models.Timetable, err error) {
+	ctx, span := tracing.Start(ctx, "TimetableService.GetTimetable", attribute.String("user.id", userID))
+	defer tracing.End(span, &err)
+
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	favorites, err := s.favoriteRepo.GetAll(ctx, userID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+	events, err := s.eventRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	return s.build(ctx, favorites, events, from, to)
+}
+
+func (s *timetableService) BuildTimetable(ctx context.Context, favorites []models.Favorite, from, to time.Time) (_ *models.Timetable, err error) {
+	ctx, span := tracing.Start(ctx, "TimetableService.BuildTimetable", attribute.Int("favorites", len(favorites)))
+	defer tracing.End(span, &err)
+
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+	return s.build(ctx, favorites, nil, from, to)
+}
+
+func validateRange(from, to time.Time) error {
+	if to.Before(from) || to.Sub(from) >= MaxTimetableDays*24*time.Hour {
+		return fmt.Errorf("%w: from must not be after to, at most %d days", ErrInvalidRange, MaxTimetableDays)
+	}
+	return nil
+}
+
+func (s *timetableService) build(ctx context.Context, favorites []models.Favorite, events []models.Event, from, to time.Time) (*models.Timetable, error) {
+	timetable := &models.Timetable{
+		From:    from.In(converter.Minsk).Format(time.DateOnly),
+		To:      to.In(converter.Minsk).Format(time.DateOnly),
+		Entries: []models.TimetableEntry{},
+	}
+	current := currentWeek(ctx, s.source, s.logger)
+
+	merged := newTimetableBuilder()
+	for _, favorite := range favorites {
+		source := models.TimetableSource{Type: favorite.Type, Key: favorite.Key}
+
+		var (
+			schedule *models.ScheduleResponse
+			err      error
+		)
+		switch favorite.Type {
+		case models.FavoriteTypeGroup:
+			schedule, err = s.scheduleService.GetGroupSchedule(ctx, favorite.Key, true)
+		case models.FavoriteTypeEmployee:
+			schedule, err = s.scheduleService.GetEmployeeSchedule(ctx, favorite.Key, true)
+		default:
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logging.FromContext(ctx, s.logger).WithError(err).WithFields(logrus.Fields{"type": favorite.Type, "key": favorite.Key}).Warn("Failed to get schedule for timetable")
+			timetable.Unavailable = append(timetable.Unavailable, source)
+			continue
+		}
+
+		calendar, err := weekCalendar(current, schedule)
+		if err != nil {
+			timetable.Unavailable = append(timetable.Unavailable, source)
+			continue
+		}
+
+		for _, occurrence := range converter.Expand(schedule, from, to, calendar) {
+			merged.add(occurrence, source)
+		}
+	}
+
+	for _, event := range events {
+		for _, start := range eventOccurrences(event, from, to) {
+			merged.addEvent(event, start)
+		}
+	}
+
+	timetable.Entries = merged.entries()
+	logging.AddFields(ctx, logrus.Fields{"favorites": len(favorites), "events": len(events), "entries": len(timetable.Entries)})
+	return timetable, nil
+}
+
+// currentWeek — календарь недель по текущей неделе из API; nil, если API ее не отдал:
+// тогда недели считаются от начала семестра каждого расписания
+func currentWeek(ctx context.Context, source bsuir.ScheduleSource, logger *logrus.Logger) *converter.WeekCalendar {
+	week, err := source.GetCurrentWeek(ctx)
+	if err != nil || week < 1 || week > converter.StudyWeeks {
+		logging.FromContext(ctx, logger).WithError(err).WithField("week", week).Warn("Current week is unavailable, counting weeks from semester start")
+		return nil
+	}
+	return &converter.WeekCalendar{Reference: time.Now(), Week: week}
+}
+
+// weekCalendar — календарь для расписания: текущая неделя из API, а без нее — от начала семестра
+func weekCalendar(current *converter.WeekCalendar, schedule *models.ScheduleResponse) (converter.WeekCalendar, error) {
+	if current != nil {
+		return *current, nil
+	}
+	return converter.SemesterCalendar(schedule.StartDate)
+}
+
+// timetableBuilder склеивает одинаковые занятия из разных расписаний и отмечает пересечения
+type timetableBuilder struct {
+	list  []models.TimetableEntry
+	index map[string]int
+}
+
+func newTimetableBuilder() *timetableBuilder {
+	return &timetableBuilder{index: make(map[string]int)}
+}
+
+func (b *timetableBuilder) add(occurrence converter.Occurrence, source models.TimetableSource) {
+	key := occurrenceKey(occurrence)
+	if i, ok := b.index[key]; ok {
+		// В расписании преподавателя одно занятие у нескольких групп может идти отдельными записями
+		if !slices.Contains(b.list[i].Sources, source) {
+			b.list[i].Sources = append(b.list[i].Sources, source)
+		}
+		return
+	}
+
+	lesson := occurrence.Lesson
+	b.index[key] = len(b.list)
+	b.list = append(b.list, models.TimetableEntry{
+		Date:    occurrence.Date.Format(time.DateOnly),
+		Weekday: occurrence.Weekday,
+		Start:   occurrence.Start,
+		End:     occurrence.End,
+		Exam:    occurrence.Exam,
+		Lesson:  &lesson,
+		Sources: []models.TimetableSource{source},
+	})
+}
+
+// addEvent добавляет повторение личного события, начинающееся в start
+func (b *timetableBuilder) addEvent(event models.Event, start time.Time) {
+	b.list = append(b.list, models.TimetableEntry{
+		Date:    start.Format(time.DateOnly),
+		Weekday: converter.Weekdays[start.Weekday()],
+		Start:   start,
+		End:     start.Add(event.End.Sub(event.Start)),
+		Event:   &event,
+		Sources: []models.TimetableSource{{Type: models.TimetableSourceEvent, Key: event.ID.Hex()}},
+	})
+}
+
+// entries возвращает ленту по времени начала; занятия пересекаются, если одно начинается раньше, чем кончается другое
+func (b *timetableBuilder) entries() []models.TimetableEntry {
+	entries := b.list
+	if entries == nil {
+		return []models.TimetableEntry{}
+	}
+	sortTimetable(entries)
+
+	for i := range entries {
+		for j := i + 1; j < len(entries) && entries[j].Start.Before(entries[i].End); j++ {
+			entries[i].Overlaps = append(entries[i].Overlaps, j)
+			entries[j].Overlaps = append(entries[j].Overlaps, i)
+		}
+	}
+	return entries
+}
+
+// occurrenceKey — одно и то же занятие в расписаниях разных групп и преподавателя
+func occurrenceKey(occurrence converter.Occurrence) string {
+	lesson := occurrence.Lesson
+	return strings.Join([]string{
+		occurrence.Start.Format(time.RFC3339),
+		occurrence.End.Format(time.RFC3339),
+		lesson.Subject,
+		lesson.LessonTypeAbbrev,
+		strings.Join(lesson.Auditories, ","),
+		fmt.Sprint(lesson.NumSubgroup),
+	}, "|")
+}
+
+func sortTimetable(entries []models.TimetableEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Start.Equal(entries[j].Start) {
+			return entries[i].Start.Before(entries[j].Start)
+		}
+		return entries[i].End.Before(entries[j].End)
+	})
+}
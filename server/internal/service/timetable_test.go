@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+	"schedluer/pkg/converter"
+)
+
+func TestValidateRange(t *testing.T) {
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, converter.Minsk)
+	tests := []struct {
+		name    string
+		to      time.Time
+		wantErr bool
+	}{
+		{"single day", from, false},
+		{"one week", from.AddDate(0, 0, 7), false},
+		{"last allowed day", from.Add((MaxTimetableDays - 1) * 24 * time.Hour), false},
+		{"too long", from.Add(MaxTimetableDays * 24 * time.Hour), true},
+		{"to before from", from.Add(-time.Second), true},
+	}
+	for _, tt := range tests {
+		err := validateRange(from, tt.to)
+		if tt.wantErr != errors.Is(err, ErrInvalidRange) {
+			t.Errorf("%s: validateRange(%s) = %v, want error %v", tt.name, tt.to.Format(time.DateOnly), err, tt.wantErr)
+		}
+	}
+}
+
+func TestTimetableBuilder(t *testing.T) {
+	group := models.TimetableSource{Type: models.FavoriteTypeGroup, Key: "221701"}
+	lecturer := models.TimetableSource{Type: models.FavoriteTypeEmployee, Key: "i-ivanov"}
+	occurrence := func(start time.Time, subject, lessonType string, subgroup int) converter.Occurrence {
+		return converter.Occurrence{
+			Date:    dayStart(start),
+			Weekday: "Понедельник",
+			Start:   start,
+			End:     start.Add(80 * time.Minute),
+			Lesson: models.Schedule{
+				Subject: subject, LessonTypeAbbrev: lessonType, NumSubgroup: subgroup, Auditories: []string{"101-5 к."},
+				StartLessonTime: start.Format("15:04"),
+			},
+		}
+	}
+
+	b := newTimetableBuilder()
+	// Лабораторные подгрупп идут одновременно, лекция пришла из двух расписаний и дважды от преподавателя
+	b.add(occurrence(minsk(10, 35), "ООП", "ЛР", 1), group)
+	b.add(occurrence(minsk(10, 35), "ООП", "ЛР", 2), group)
+	b.add(occurrence(minsk(9, 0), "ООП", "ЛК", 0), group)
+	b.add(occurrence(minsk(9, 0), "ООП", "ЛК", 0), lecturer)
+	b.add(occurrence(minsk(9, 0), "ООП", "ЛК", 0), lecturer)
+	b.addEvent(models.Event{Title: "Кружок", Start: minsk(11, 30), End: minsk(12, 0)}, minsk(11, 30))
+
+	entries := b.entries()
+	if len(entries) != 4 {
+		t.Fatalf("entries = %+v, want 4", entries)
+	}
+	if lecture := entries[0]; lecture.Lesson.LessonTypeAbbrev != "ЛК" || !slices.Equal(lecture.Sources, []models.TimetableSource{group, lecturer}) ||
+		len(lecture.Overlaps) != 0 {
+		t.Errorf("lecture must be merged from both schedules once: %+v", lecture)
+	}
+	wantOverlaps := [][]int{nil, {2, 3}, {1, 3}, {1, 2}}
+	for i, entry := range entries {
+		if !slices.Equal(entry.Overlaps, wantOverlaps[i]) {
+			t.Errorf("entry %d overlaps = %v, want %v", i, entry.Overlaps, wantOverlaps[i])
+		}
+	}
+	if event := entries[3]; event.Event == nil || event.End.Sub(event.Start) != 30*time.Minute || event.Sources[0].Type != models.TimetableSourceEvent {
+		t.Errorf("event entry = %+v", event)
+	}
+
+	if empty := newTimetableBuilder().entries(); empty == nil || len(empty) != 0 {
+		t.Errorf("empty timetable = %#v, want an empty slice", empty)
+	}
+}
@@ -0,0 +1,167 @@
+package bsuirtest
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"schedluer/internal/models"
+)
+
+//go:embed fixtures
+var defaultFixtures embed.FS
+
+// Fixtures — данные, которые отдает фейковый API. Ответы эндпоинтов лежат в файлах
+// в том же формате, что и у iis.bsuir.by, поэтому настоящий ответ можно сохранить как фикстуру:
+//
+//	student-groups.json, employees.json (employees/all), faculties.json, departments.json,
+//	specialities.json, auditories.json, current-week.json,
+//	schedule/group/<номер группы>.json, schedule/employee/<url-id>.json,
+//	announcements/employee/<url-id>.json, announcements/department/<id>.json,
+//	last-update-date.json — {"groups": {"221701": "01.09.2026"}, "employees": {"i-ivanov": "..."}}
+type Fixtures struct {
+	Groups       []models.StudentGroupListItem
+	Employees    []models.EmployeeListItem
+	Faculties    []models.Faculty
+	Departments  []models.Department
+	Specialities []models.Speciality
+	Auditories   []models.Auditory
+	CurrentWeek  int
+
+	GroupSchedules    map[string]models.ScheduleResponse // по номеру группы
+	EmployeeSchedules map[string]models.ScheduleResponse // по url-id преподавателя
+
+	GroupLastUpdate    map[string]string // по номеру группы
+	EmployeeLastUpdate map[string]string // по url-id преподавателя
+
+	EmployeeAnnouncements   map[string][]models.Announcement // по url-id
+	DepartmentAnnouncements map[int][]models.Announcement    // по id кафедры
+}
+
+// DefaultFixtures возвращает встроенный набор: три группы, два преподавателя и их расписания.
+// Каждый вызов возвращает новую копию, которую можно менять.
+func DefaultFixtures() *Fixtures {
+	fsys, err := fs.Sub(defaultFixtures, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+	fixtures, err := LoadFixtures(fsys)
+	if err != nil {
+		panic(fmt.Sprintf("bsuirtest: invalid embedded fixtures: %v", err))
+	}
+	return fixtures
+}
+
+// LoadFixtures читает фикстуры из каталога (например, os.DirFS). Отсутствующие файлы — пустые данные.
+func LoadFixtures(fsys fs.FS) (*Fixtures, error) {
+	f := &Fixtures{
+		GroupSchedules:          make(map[string]models.ScheduleResponse),
+		EmployeeSchedules:       make(map[string]models.ScheduleResponse),
+		GroupLastUpdate:         make(map[string]string),
+		EmployeeLastUpdate:      make(map[string]string),
+		EmployeeAnnouncements:   make(map[string][]models.Announcement),
+		DepartmentAnnouncements: make(map[int][]models.Announcement),
+	}
+
+	files := []struct {
+		name   string
+		target any
+	}{
+		{"student-groups.json", &f.Groups},
+		{"employees.json", &f.Employees},
+		{"faculties.json", &f.Faculties},
+		{"departments.json", &f.Departments},
+		{"specialities.json", &f.Specialities},
+		{"auditories.json", &f.Auditories},
+		{"current-week.json", &f.CurrentWeek},
+	}
+	for _, file := range files {
+		if err := readJSON(fsys, file.name, file.target); err != nil {
+			return nil, err
+		}
+	}
+
+	var lastUpdate struct {
+		Groups    map[string]string `json:"groups"`
+		Employees map[string]string `json:"employees"`
+	}
+	if err := readJSON(fsys, "last-update-date.json", &lastUpdate); err != nil {
+		return nil, err
+	}
+	for key, date := range lastUpdate.Groups {
+		f.GroupLastUpdate[key] = date
+	}
+	for key, date := range lastUpdate.Employees {
+		f.EmployeeLastUpdate[key] = date
+	}
+
+	err := errors.Join(
+		readDir(fsys, "schedule/group", func(name string, data models.ScheduleResponse) { f.GroupSchedules[name] = data }),
+		readDir(fsys, "schedule/employee", func(name string, data models.ScheduleResponse) { f.EmployeeSchedules[name] = data }),
+		readDir(fsys, "announcements/employee", func(name string, data []models.Announcement) { f.EmployeeAnnouncements[name] = data }),
+		readDir(fsys, "announcements/department", func(name string, data []models.Announcement) {
+			if id, err := strconv.Atoi(name); err == nil {
+				f.DepartmentAnnouncements[id] = data
+			}
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Clone возвращает глубокую копию фикстур
+func (f *Fixtures) Clone() *Fixtures {
+	data, err := json.Marshal(f)
+	if err != nil {
+		panic(err)
+	}
+	var clone Fixtures
+	if err := json.Unmarshal(data, &clone); err != nil {
+		panic(err)
+	}
+	return &clone
+}
+
+func readJSON(fsys fs.FS, name string, target any) error {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("fixture %s: %w", name, err)
+	}
+	return nil
+}
+
+// readDir читает все <ключ>.json из каталога
+func readDir[T any](fsys fs.FS, dir string, store func(key string, data T)) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		var data T
+		if err := readJSON(fsys, path.Join(dir, entry.Name()), &data); err != nil {
+			return err
+		}
+		store(strings.TrimSuffix(entry.Name(), ".json"), data)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+// Package bsuirtest — фейковый API БГУИРа (iis.bsuir.by) на фикстурах для тестов и работы без сети.
+// Сервер подключается к bsuir.NewClient через BSUIRAPIConfig.BaseURL и умеет добавлять задержку,
+// отвечать ошибками и менять данные между запросами.
+package bsuirtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"schedluer/internal/models"
+)
+
+// BasePath — префикс API, как у https://iis.bsuir.by/api/v1
+const BasePath = "/api/v1"
+
+// Fault — внедряемая ошибка. Endpoint — префикс пути без BasePath ("/schedule", "/employees/all");
+// пустой Endpoint срабатывает на любой запрос. Times — сколько раз ответить ошибкой, 0 — пока не снята.
+type Fault struct {
+	Endpoint string
+	Status   int
+	// Body отдается как есть; пустой Body — JSON с описанием ошибки.
+	// Невалидный JSON при Status 200 проверяет обработку поврежденных ответов.
+	Body  string
+	Times int
+}
+
+// Fake — http.Handler фейкового API. Все методы безопасны для конкурентного использования.
+type Fake struct {
+	mu        sync.Mutex
+	fixtures  *Fixtures
+	latency   time.Duration
+	faults    []*Fault
+	calls     map[string]int
+	onRequest func(endpoint string, call int, fixtures *Fixtures)
+	mux       *http.ServeMux
+}
+
+// New создает фейковый API поверх фикстур; сами фикстуры не изменяются
+func New(fixtures *Fixtures) *Fake {
+	f := &Fake{
+		fixtures: fixtures.Clone(),
+		calls:    make(map[string]int),
+		mux:      http.NewServeMux(),
+	}
+
+	f.mux.HandleFunc("GET "+BasePath+"/student-groups", f.list(func(d *Fixtures) any { return d.Groups }))
+	f.mux.HandleFunc("GET "+BasePath+"/employees/all", f.list(func(d *Fixtures) any { return d.Employees }))
+	f.mux.HandleFunc("GET "+BasePath+"/faculties", f.list(func(d *Fixtures) any { return d.Faculties }))
+	f.mux.HandleFunc("GET "+BasePath+"/departments", f.list(func(d *Fixtures) any { return d.Departments }))
+	f.mux.HandleFunc("GET "+BasePath+"/specialities", f.list(func(d *Fixtures) any { return d.Specialities }))
+	f.mux.HandleFunc("GET "+BasePath+"/auditories", f.list(func(d *Fixtures) any { return d.Auditories }))
+	f.mux.HandleFunc("GET "+BasePath+"/schedule/current-week", f.list(func(d *Fixtures) any { return d.CurrentWeek }))
+	f.mux.HandleFunc("GET "+BasePath+"/schedule", f.groupSchedule)
+	f.mux.HandleFunc("GET "+BasePath+"/employees/schedule/{urlId}", f.employeeSchedule)
+	f.mux.HandleFunc("GET "+BasePath+"/last-update-date/student-group", f.groupLastUpdate)
+	f.mux.HandleFunc("GET "+BasePath+"/last-update-date/employee", f.employeeLastUpdate)
+	f.mux.HandleFunc("GET "+BasePath+"/announcements/employees", f.employeeAnnouncements)
+	f.mux.HandleFunc("GET "+BasePath+"/announcements/departments", f.departmentAnnouncements)
+
+	return f
+}
+
+// SetLatency задает задержку перед каждым ответом
+func (f *Fake) SetLatency(latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latency = latency
+}
+
+// InjectFault добавляет ошибку; из нескольких подходящих срабатывает добавленная раньше
+func (f *Fake) InjectFault(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &fault)
+}
+
+// FailNext отвечает статусом status на следующие times запросов к endpoint
+func (f *Fake) FailNext(endpoint string, status, times int) {
+	f.InjectFault(Fault{Endpoint: endpoint, Status: status, Times: times})
+}
+
+func (f *Fake) ClearFaults() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = nil
+}
+
+// Update меняет данные, которые вернут следующие запросы
+func (f *Fake) Update(change func(fixtures *Fixtures)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	change(f.fixtures)
+}
+
+// OnRequest вызывается перед каждым ответом с номером обращения к этому пути (с 1)
+// и позволяет менять данные между запросами, например выдавать новое расписание на втором вызове
+func (f *Fake) OnRequest(hook func(endpoint string, call int, fixtures *Fixtures)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onRequest = hook
+}
+
+// Calls возвращает число запросов к путям с префиксом endpoint; "" — все запросы
+func (f *Fake) Calls(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	total := 0
+	for path, count := range f.calls {
+		if strings.HasPrefix(path, endpoint) {
+			total += count
+		}
+	}
+	return total
+}
+
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, BasePath)
+
+	f.mu.Lock()
+	f.calls[endpoint]++
+	call := f.calls[endpoint]
+	latency := f.latency
+	fault := f.takeFault(endpoint)
+	if f.onRequest != nil {
+		f.onRequest(endpoint, call, f.fixtures)
+	}
+	f.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault != nil {
+		if fault.Body == "" {
+			writeError(w, fault.Status, "injected fault")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(fault.Status)
+		_, _ = w.Write([]byte(fault.Body))
+		return
+	}
+
+	f.mux.ServeHTTP(w, r)
+}
+
+// takeFault находит подходящую ошибку и уменьшает ее счетчик; вызывается под f.mu
+func (f *Fake) takeFault(endpoint string) *Fault {
+	for i, fault := range f.faults {
+		if !strings.HasPrefix(endpoint, fault.Endpoint) {
+			continue
+		}
+		matched := *fault
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				f.faults = append(f.faults[:i], f.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// list отдает данные из фикстур целиком
+func (f *Fake) list(get func(*Fixtures) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		writeJSON(w, get(f.fixtures))
+	}
+}
+
+func (f *Fake) groupSchedule(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	schedule, ok := f.fixtures.GroupSchedules[r.URL.Query().Get("studentGroup")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "student group not found")
+		return
+	}
+	writeJSON(w, schedule)
+}
+
+func (f *Fake) employeeSchedule(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	schedule, ok := f.fixtures.EmployeeSchedules[r.PathValue("urlId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "employee not found")
+		return
+	}
+	writeJSON(w, schedule)
+}
+
+// groupLastUpdate ищет группу по groupNumber или по id из student-groups.json
+func (f *Fake) groupLastUpdate(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	groupNumber := r.URL.Query().Get("groupNumber")
+	if id, err := strconv.Atoi(r.URL.Query().Get("id")); err == nil {
+		for _, group := range f.fixtures.Groups {
+			if group.ID == id {
+				groupNumber = group.Name
+			}
+		}
+	}
+	f.writeLastUpdate(w, f.fixtures.GroupLastUpdate, groupNumber)
+}
+
+// employeeLastUpdate ищет преподавателя по url-id или по id из employees.json
+func (f *Fake) employeeLastUpdate(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	urlID := r.URL.Query().Get("url-id")
+	if id, err := strconv.Atoi(r.URL.Query().Get("id")); err == nil {
+		for _, employee := range f.fixtures.Employees {
+			if employee.ID == id {
+				urlID = employee.URLID
+			}
+		}
+	}
+	f.writeLastUpdate(w, f.fixtures.EmployeeLastUpdate, urlID)
+}
+
+func (f *Fake) writeLastUpdate(w http.ResponseWriter, dates map[string]string, key string) {
+	date, ok := dates[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "last update date not found")
+		return
+	}
+	writeJSON(w, models.LastUpdateDate{LastUpdateDate: date})
+}
+
+func (f *Fake) employeeAnnouncements(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	writeJSON(w, nonNil(f.fixtures.EmployeeAnnouncements[r.URL.Query().Get("url-id")]))
+}
+
+func (f *Fake) departmentAnnouncements(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+	writeJSON(w, nonNil(f.fixtures.DepartmentAnnouncements[id]))
+}
+
+func nonNil(announcements []models.Announcement) []models.Announcement {
+	if announcements == nil {
+		return []models.Announcement{}
+	}
+	return announcements
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// Server — запущенный на локальном порту фейковый API
+type Server struct {
+	*Fake
+	httpServer *httptest.Server
+}
+
+// NewServer запускает фейковый API; по окончании нужно вызвать Close
+func NewServer(fixtures *Fixtures) *Server {
+	fake := New(fixtures)
+	return &Server{Fake: fake, httpServer: httptest.NewServer(fake)}
+}
+
+// BaseURL — значение для BSUIRAPIConfig.BaseURL
+func (s *Server) BaseURL() string {
+	return s.httpServer.URL + BasePath
+}
+
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
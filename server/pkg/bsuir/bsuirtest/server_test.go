@@ -0,0 +1,186 @@
+package bsuirtest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"schedluer/internal/config"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/bsuir/bsuirtest"
+)
+
+func newClient(t *testing.T, timeout time.Duration) (*bsuirtest.Server, *bsuir.Client) {
+	t.Helper()
+
+	server := bsuirtest.NewServer(bsuirtest.DefaultFixtures())
+	t.Cleanup(server.Close)
+
+	client := bsuir.NewClient(&config.BSUIRAPIConfig{BaseURL: server.BaseURL(), Timeout: timeout})
+	return server, client
+}
+
+func TestClientAgainstFixtures(t *testing.T) {
+	_, client := newClient(t, 5*time.Second)
+	ctx := context.Background()
+
+	groups, err := client.GetAllGroups(ctx)
+	if err != nil || len(groups) != 3 || groups[0].Name != "221701" {
+		t.Fatalf("GetAllGroups = %v, %v", groups, err)
+	}
+
+	employees, err := client.GetAllEmployees(ctx)
+	if err != nil || len(employees) != 2 || employees[0].URLID != "i-ivanov" {
+		t.Fatalf("GetAllEmployees = %v, %v", employees, err)
+	}
+
+	schedule, err := client.GetGroupSchedule(ctx, "221701")
+	if err != nil {
+		t.Fatalf("GetGroupSchedule: %v", err)
+	}
+	if schedule.StudentGroupDto == nil || schedule.StudentGroupDto.Name != "221701" || len(schedule.Schedules["Понедельник"]) != 3 {
+		t.Errorf("unexpected group schedule: %+v", schedule)
+	}
+
+	schedule, err = client.GetEmployeeSchedule(ctx, "i-ivanov")
+	if err != nil || schedule.EmployeeDto == nil || schedule.EmployeeDto.URLID != "i-ivanov" {
+		t.Errorf("GetEmployeeSchedule = %+v, %v", schedule, err)
+	}
+
+	if _, err := client.GetGroupSchedule(ctx, "000000"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("missing group: expected a 404 error, got %v", err)
+	}
+
+	lastUpdate, err := client.GetGroupLastUpdateDate(ctx, "221701")
+	if err != nil || lastUpdate.LastUpdateDate != "01.09.2026" {
+		t.Errorf("GetGroupLastUpdateDate = %+v, %v", lastUpdate, err)
+	}
+	lastUpdate, err = client.GetGroupLastUpdateDateByID(ctx, 22110)
+	if err != nil || lastUpdate.LastUpdateDate != "28.08.2026" {
+		t.Errorf("GetGroupLastUpdateDateByID = %+v, %v", lastUpdate, err)
+	}
+	lastUpdate, err = client.GetEmployeeLastUpdateDateByID(ctx, 500102)
+	if err != nil || lastUpdate.LastUpdateDate != "30.08.2026" {
+		t.Errorf("GetEmployeeLastUpdateDateByID = %+v, %v", lastUpdate, err)
+	}
+
+	week, err := client.GetCurrentWeek(ctx)
+	if err != nil || week != 2 {
+		t.Errorf("GetCurrentWeek = %d, %v", week, err)
+	}
+
+	auditories, err := client.GetAllAuditories(ctx)
+	if err != nil || len(auditories) != 2 || auditories[0].Capacity == nil {
+		t.Errorf("GetAllAuditories = %+v, %v", auditories, err)
+	}
+
+	announcements, err := client.GetEmployeeAnnouncements(ctx, "i-ivanov")
+	if err != nil || len(announcements) != 1 {
+		t.Errorf("GetEmployeeAnnouncements = %+v, %v", announcements, err)
+	}
+	announcements, err = client.GetDepartmentAnnouncements(ctx, 1)
+	if err != nil || announcements == nil || len(announcements) != 0 {
+		t.Errorf("GetDepartmentAnnouncements for an unknown department = %#v, %v", announcements, err)
+	}
+}
+
+func TestFaultInjection(t *testing.T) {
+	server, client := newClient(t, 5*time.Second)
+	ctx := context.Background()
+
+	server.FailNext("/schedule", http.StatusServiceUnavailable, 2)
+
+	for range 2 {
+		if _, err := client.GetGroupSchedule(ctx, "221701"); err == nil || !strings.Contains(err.Error(), "503") {
+			t.Fatalf("expected an injected 503, got %v", err)
+		}
+	}
+	if _, err := client.GetAllGroups(ctx); err != nil {
+		t.Errorf("fault for /schedule must not affect other endpoints: %v", err)
+	}
+	if _, err := client.GetGroupSchedule(ctx, "221701"); err != nil {
+		t.Errorf("fault must expire after 2 requests: %v", err)
+	}
+
+	server.InjectFault(bsuirtest.Fault{Endpoint: "/student-groups", Status: http.StatusOK, Body: "{not json"})
+	for range 2 {
+		if _, err := client.GetAllGroups(ctx); err == nil || !strings.Contains(err.Error(), "unmarshal") {
+			t.Fatalf("expected a decode error, got %v", err)
+		}
+	}
+	server.ClearFaults()
+	if _, err := client.GetAllGroups(ctx); err != nil {
+		t.Errorf("ClearFaults did not remove the permanent fault: %v", err)
+	}
+
+	if got := server.Calls("/schedule"); got != 3 {
+		t.Errorf("Calls(/schedule) = %d, want 3", got)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	server, client := newClient(t, 50*time.Millisecond)
+	server.SetLatency(200 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := client.GetCurrentWeek(context.Background()); err == nil {
+		t.Fatal("expected the client timeout to fire")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("client waited %v despite a 50ms timeout", elapsed)
+	}
+
+	server.SetLatency(0)
+	if _, err := client.GetCurrentWeek(context.Background()); err != nil {
+		t.Errorf("GetCurrentWeek without latency: %v", err)
+	}
+}
+
+func TestChangingData(t *testing.T) {
+	server, client := newClient(t, 5*time.Second)
+	ctx := context.Background()
+
+	// Со второго запроса расписание группы меняется вместе с датой обновления
+	server.OnRequest(func(endpoint string, call int, fixtures *bsuirtest.Fixtures) {
+		if endpoint == "/schedule" && call == 2 {
+			schedule := fixtures.GroupSchedules["221701"]
+			schedule.Schedules["Понедельник"] = schedule.Schedules["Понедельник"][:1]
+			fixtures.GroupSchedules["221701"] = schedule
+			fixtures.GroupLastUpdate["221701"] = "20.10.2026"
+		}
+	})
+
+	first, err := client.GetGroupSchedule(ctx, "221701")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.GetGroupSchedule(ctx, "221701")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Schedules["Понедельник"]) != 3 || len(second.Schedules["Понедельник"]) != 1 {
+		t.Errorf("schedule did not change between calls: %d then %d lessons",
+			len(first.Schedules["Понедельник"]), len(second.Schedules["Понедельник"]))
+	}
+
+	server.Update(func(fixtures *bsuirtest.Fixtures) { fixtures.CurrentWeek = 3 })
+	if week, _ := client.GetCurrentWeek(ctx); week != 3 {
+		t.Errorf("GetCurrentWeek after Update = %d, want 3", week)
+	}
+
+	lastUpdate, err := client.GetGroupLastUpdateDate(ctx, "221701")
+	if err != nil || lastUpdate.LastUpdateDate != "20.10.2026" {
+		t.Errorf("GetGroupLastUpdateDate = %+v, %v", lastUpdate, err)
+	}
+}
+
+func TestDefaultFixturesAreIndependent(t *testing.T) {
+	first := bsuirtest.DefaultFixtures()
+	first.Groups[0].Name = "changed"
+
+	if second := bsuirtest.DefaultFixtures(); second.Groups[0].Name != "221701" {
+		t.Errorf("DefaultFixtures shares data between calls: %q", second.Groups[0].Name)
+	}
+}
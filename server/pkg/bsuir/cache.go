@@ -0,0 +1,126 @@
+package bsuir
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"schedluer/internal/models"
+)
+
+// CachingSource держит в памяти справочники и номер текущей недели, которые меняются редко,
+// а запрашиваются при каждом обновлении списков и каждой проверке готовности.
+// Расписания не кэшируются: для них есть хранилище с проверкой даты обновления.
+// Возвращаемые срезы общие для всех вызывающих, менять их нельзя.
+// Срок хранения меняется на лету через SetTTL; при нулевом сроке запросы идут прямо в источник.
+type CachingSource struct {
+	ScheduleSource
+
+	ttl atomic.Int64
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     any
+	fetchedAt time.Time
+}
+
+func NewCachingSource(source ScheduleSource, ttl time.Duration) *CachingSource {
+	c := &CachingSource{
+		ScheduleSource: source,
+		now:            time.Now,
+		entries:        make(map[string]cacheEntry),
+	}
+	c.SetTTL(ttl)
+	return c
+}
+
+// SetTTL меняет срок хранения без перезапуска (по SIGHUP). Новый срок сразу действует и на уже
+// закэшированные ответы; 0 выключает кэш.
+func (c *CachingSource) SetTTL(ttl time.Duration) {
+	c.ttl.Store(int64(max(ttl, 0)))
+}
+
+// TTL возвращает текущий срок хранения
+func (c *CachingSource) TTL() time.Duration {
+	return time.Duration(c.ttl.Load())
+}
+
+type noCacheKey struct{}
+
+// WithoutCache просит CachingSource сходить в источник мимо кэша и запомнить свежий ответ:
+// принудительное обновление и запрос с useCache=false не должны получать устаревший справочник.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypass
+}
+
+// Invalidate сбрасывает весь кэш
+func (c *CachingSource) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+}
+
+func (c *CachingSource) GetAllGroups(ctx context.Context) ([]models.StudentGroupListItem, error) {
+	return cached(ctx, c, "groups", func() ([]models.StudentGroupListItem, error) { return c.ScheduleSource.GetAllGroups(ctx) })
+}
+
+func (c *CachingSource) GetAllEmployees(ctx context.Context) ([]models.EmployeeListItem, error) {
+	return cached(ctx, c, "employees", func() ([]models.EmployeeListItem, error) { return c.ScheduleSource.GetAllEmployees(ctx) })
+}
+
+func (c *CachingSource) GetAllFaculties(ctx context.Context) ([]models.Faculty, error) {
+	return cached(ctx, c, "faculties", func() ([]models.Faculty, error) { return c.ScheduleSource.GetAllFaculties(ctx) })
+}
+
+func (c *CachingSource) GetAllDepartments(ctx context.Context) ([]models.Department, error) {
+	return cached(ctx, c, "departments", func() ([]models.Department, error) { return c.ScheduleSource.GetAllDepartments(ctx) })
+}
+
+func (c *CachingSource) GetAllSpecialities(ctx context.Context) ([]models.Speciality, error) {
+	return cached(ctx, c, "specialities", func() ([]models.Speciality, error) { return c.ScheduleSource.GetAllSpecialities(ctx) })
+}
+
+func (c *CachingSource) GetAllAuditories(ctx context.Context) ([]models.Auditory, error) {
+	return cached(ctx, c, "auditories", func() ([]models.Auditory, error) { return c.ScheduleSource.GetAllAuditories(ctx) })
+}
+
+func (c *CachingSource) GetCurrentWeek(ctx context.Context) (int, error) {
+	return cached(ctx, c, "current_week", func() (int, error) { return c.ScheduleSource.GetCurrentWeek(ctx) })
+}
+
+// cached возвращает значение из кэша или запрашивает его; ошибки не кэшируются.
+// Запрос идет без блокировки: параллельные промахи могут сходить в источник одновременно.
+func cached[T any](ctx context.Context, c *CachingSource, key string, fetch func() (T, error)) (T, error) {
+	ttl := c.TTL()
+	if ttl == 0 {
+		return fetch()
+	}
+	if !cacheBypassed(ctx) {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && c.now().Before(entry.fetchedAt.Add(ttl)) {
+			return entry.value.(T), nil
+		}
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{value: value, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return value, nil
+}
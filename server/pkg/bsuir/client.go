@@ -1,203 +1,265 @@
 package bsuir
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
 	"schedluer/internal/config"
+	"schedluer/internal/logging"
+	"schedluer/internal/metrics"
 	"schedluer/internal/models"
+	"schedluer/internal/tracing"
 )
 
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	limiter    *limiter
+	recorder   *recorder
 }
 
 func NewClient(cfg *config.BSUIRAPIConfig) *Client {
 	return &Client{
 		baseURL: cfg.BaseURL,
 		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.Timeout) * time.Second,
+			Timeout: cfg.Timeout,
 		},
+		limiter:  newLimiter(cfg.RequestsPerSecond, cfg.Burst, cfg.MaxConcurrent),
+		recorder: newRecorder(cfg),
 	}
 }
 
-func (c *Client) GetGroupSchedule(groupNumber string) (*models.ScheduleResponse, error) {
+func (c *Client) GetGroupSchedule(ctx context.Context, groupNumber string) (*models.ScheduleResponse, error) {
 	url := fmt.Sprintf("%s/schedule?studentGroup=%s", c.baseURL, groupNumber)
 
 	var response models.ScheduleResponse
-	if err := c.makeRequest(url, &response); err != nil {
+	if err := c.makeRequest(ctx, "GetGroupSchedule", url, fileFixture("schedule", "group", groupNumber), &response); err != nil {
 		return nil, fmt.Errorf("failed to get group schedule: %w", err)
 	}
 
 	return &response, nil
 }
 
-func (c *Client) GetEmployeeSchedule(urlID string) (*models.ScheduleResponse, error) {
+func (c *Client) GetEmployeeSchedule(ctx context.Context, urlID string) (*models.ScheduleResponse, error) {
 	url := fmt.Sprintf("%s/employees/schedule/%s", c.baseURL, urlID)
 
 	var response models.ScheduleResponse
-	if err := c.makeRequest(url, &response); err != nil {
+	if err := c.makeRequest(ctx, "GetEmployeeSchedule", url, fileFixture("schedule", "employee", urlID), &response); err != nil {
 		return nil, fmt.Errorf("failed to get employee schedule: %w", err)
 	}
 
 	return &response, nil
 }
 
-func (c *Client) GetAllGroups() ([]models.StudentGroupListItem, error) {
+func (c *Client) GetAllGroups(ctx context.Context) ([]models.StudentGroupListItem, error) {
 	url := fmt.Sprintf("%s/student-groups", c.baseURL)
 
 	var groups []models.StudentGroupListItem
-	if err := c.makeRequest(url, &groups); err != nil {
+	if err := c.makeRequest(ctx, "GetAllGroups", url, fileFixture("student-groups"), &groups); err != nil {
 		return nil, fmt.Errorf("failed to get all groups: %w", err)
 	}
 
 	return groups, nil
 }
 
-func (c *Client) GetAllEmployees() ([]models.EmployeeListItem, error) {
+func (c *Client) GetAllEmployees(ctx context.Context) ([]models.EmployeeListItem, error) {
 	url := fmt.Sprintf("%s/employees/all", c.baseURL)
 
 	var employees []models.EmployeeListItem
-	if err := c.makeRequest(url, &employees); err != nil {
+	if err := c.makeRequest(ctx, "GetAllEmployees", url, fileFixture("employees"), &employees); err != nil {
 		return nil, fmt.Errorf("failed to get all employees: %w", err)
 	}
 
 	return employees, nil
 }
 
-func (c *Client) GetAllFaculties() ([]models.Faculty, error) {
+func (c *Client) GetAllFaculties(ctx context.Context) ([]models.Faculty, error) {
 	url := fmt.Sprintf("%s/faculties", c.baseURL)
 
 	var faculties []models.Faculty
-	if err := c.makeRequest(url, &faculties); err != nil {
+	if err := c.makeRequest(ctx, "GetAllFaculties", url, fileFixture("faculties"), &faculties); err != nil {
 		return nil, fmt.Errorf("failed to get all faculties: %w", err)
 	}
 
 	return faculties, nil
 }
 
-func (c *Client) GetAllDepartments() ([]models.Department, error) {
+func (c *Client) GetAllDepartments(ctx context.Context) ([]models.Department, error) {
 	url := fmt.Sprintf("%s/departments", c.baseURL)
 
 	var departments []models.Department
-	if err := c.makeRequest(url, &departments); err != nil {
+	if err := c.makeRequest(ctx, "GetAllDepartments", url, fileFixture("departments"), &departments); err != nil {
 		return nil, fmt.Errorf("failed to get all departments: %w", err)
 	}
 
 	return departments, nil
 }
 
-func (c *Client) GetAllSpecialities() ([]models.Speciality, error) {
+func (c *Client) GetAllSpecialities(ctx context.Context) ([]models.Speciality, error) {
 	url := fmt.Sprintf("%s/specialities", c.baseURL)
 
 	var specialities []models.Speciality
-	if err := c.makeRequest(url, &specialities); err != nil {
+	if err := c.makeRequest(ctx, "GetAllSpecialities", url, fileFixture("specialities"), &specialities); err != nil {
 		return nil, fmt.Errorf("failed to get all specialities: %w", err)
 	}
 
 	return specialities, nil
 }
 
-func (c *Client) GetEmployeeAnnouncements(urlID string) ([]models.Announcement, error) {
+func (c *Client) GetEmployeeAnnouncements(ctx context.Context, urlID string) ([]models.Announcement, error) {
 	url := fmt.Sprintf("%s/announcements/employees?url-id=%s", c.baseURL, urlID)
 
 	var announcements []models.Announcement
-	if err := c.makeRequest(url, &announcements); err != nil {
+	if err := c.makeRequest(ctx, "GetEmployeeAnnouncements", url, fileFixture("announcements", "employee", urlID), &announcements); err != nil {
 		return nil, fmt.Errorf("failed to get employee announcements: %w", err)
 	}
 
 	return announcements, nil
 }
 
-func (c *Client) GetDepartmentAnnouncements(departmentID int) ([]models.Announcement, error) {
+func (c *Client) GetDepartmentAnnouncements(ctx context.Context, departmentID int) ([]models.Announcement, error) {
 	url := fmt.Sprintf("%s/announcements/departments?id=%d", c.baseURL, departmentID)
 
 	var announcements []models.Announcement
-	if err := c.makeRequest(url, &announcements); err != nil {
+	if err := c.makeRequest(ctx, "GetDepartmentAnnouncements", url, fileFixture("announcements", "department", strconv.Itoa(departmentID)), &announcements); err != nil {
 		return nil, fmt.Errorf("failed to get department announcements: %w", err)
 	}
 
 	return announcements, nil
 }
 
-func (c *Client) GetAllAuditories() ([]models.Auditory, error) {
+func (c *Client) GetAllAuditories(ctx context.Context) ([]models.Auditory, error) {
 	url := fmt.Sprintf("%s/auditories", c.baseURL)
 
 	var auditories []models.Auditory
-	if err := c.makeRequest(url, &auditories); err != nil {
+	if err := c.makeRequest(ctx, "GetAllAuditories", url, fileFixture("auditories"), &auditories); err != nil {
 		return nil, fmt.Errorf("failed to get all auditories: %w", err)
 	}
 
 	return auditories, nil
 }
 
-func (c *Client) GetGroupLastUpdateDate(groupNumber string) (*models.LastUpdateDate, error) {
+func (c *Client) GetGroupLastUpdateDate(ctx context.Context, groupNumber string) (*models.LastUpdateDate, error) {
 	url := fmt.Sprintf("%s/last-update-date/student-group?groupNumber=%s", c.baseURL, groupNumber)
 
 	var updateDate models.LastUpdateDate
-	if err := c.makeRequest(url, &updateDate); err != nil {
+	if err := c.makeRequest(ctx, "GetGroupLastUpdateDate", url, lastUpdateFixture("groups", groupNumber), &updateDate); err != nil {
 		return nil, fmt.Errorf("failed to get group last update date: %w", err)
 	}
 
 	return &updateDate, nil
 }
 
-func (c *Client) GetGroupLastUpdateDateByID(groupID int) (*models.LastUpdateDate, error) {
+func (c *Client) GetGroupLastUpdateDateByID(ctx context.Context, groupID int) (*models.LastUpdateDate, error) {
 	url := fmt.Sprintf("%s/last-update-date/student-group?id=%d", c.baseURL, groupID)
 
 	var updateDate models.LastUpdateDate
-	if err := c.makeRequest(url, &updateDate); err != nil {
+	if err := c.makeRequest(ctx, "GetGroupLastUpdateDateByID", url, lastUpdateByIDFixture("groups", groupID), &updateDate); err != nil {
 		return nil, fmt.Errorf("failed to get group last update date by ID: %w", err)
 	}
 
 	return &updateDate, nil
 }
 
-func (c *Client) GetEmployeeLastUpdateDate(urlID string) (*models.LastUpdateDate, error) {
+func (c *Client) GetEmployeeLastUpdateDate(ctx context.Context, urlID string) (*models.LastUpdateDate, error) {
 	url := fmt.Sprintf("%s/last-update-date/employee?url-id=%s", c.baseURL, urlID)
 
 	var updateDate models.LastUpdateDate
-	if err := c.makeRequest(url, &updateDate); err != nil {
+	if err := c.makeRequest(ctx, "GetEmployeeLastUpdateDate", url, lastUpdateFixture("employees", urlID), &updateDate); err != nil {
 		return nil, fmt.Errorf("failed to get employee last update date: %w", err)
 	}
 
 	return &updateDate, nil
 }
 
-func (c *Client) GetEmployeeLastUpdateDateByID(employeeID int) (*models.LastUpdateDate, error) {
+func (c *Client) GetEmployeeLastUpdateDateByID(ctx context.Context, employeeID int) (*models.LastUpdateDate, error) {
 	url := fmt.Sprintf("%s/last-update-date/employee?id=%d", c.baseURL, employeeID)
 
 	var updateDate models.LastUpdateDate
-	if err := c.makeRequest(url, &updateDate); err != nil {
+	if err := c.makeRequest(ctx, "GetEmployeeLastUpdateDateByID", url, lastUpdateByIDFixture("employees", employeeID), &updateDate); err != nil {
 		return nil, fmt.Errorf("failed to get employee last update date by ID: %w", err)
 	}
 
 	return &updateDate, nil
 }
 
-func (c *Client) GetCurrentWeek() (int, error) {
+func (c *Client) GetCurrentWeek(ctx context.Context) (int, error) {
 	url := fmt.Sprintf("%s/schedule/current-week", c.baseURL)
 
 	var week int
-	if err := c.makeRequest(url, &week); err != nil {
+	if err := c.makeRequest(ctx, "GetCurrentWeek", url, fileFixture("current-week"), &week); err != nil {
 		return 0, fmt.Errorf("failed to get current week: %w", err)
 	}
 
 	return week, nil
 }
 
-func (c *Client) makeRequest(url string, target interface{}) error {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (c *Client) makeRequest(ctx context.Context, method string, url string, fixture fixture, target interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "bsuir."+method, attribute.String("http.url", url))
+	defer tracing.End(span, &err)
+
+	start := time.Now()
+	code := "error"
+	defer func() {
+		latency := time.Since(start)
+		span.SetAttributes(attribute.String("http.status_code", code))
+		metrics.UpstreamRequests.WithLabelValues(method, code).Inc()
+		metrics.UpstreamRequestDuration.WithLabelValues(method).Observe(latency.Seconds())
+
+		// Статус и латентность upstream попадают и в access-лог запроса, который к нему привел
+		fields := logrus.Fields{
+			"upstream_method":     method,
+			"upstream_status":     code,
+			"upstream_latency_ms": latency.Milliseconds(),
+		}
+		logging.AddFields(ctx, fields)
+		logging.FromContext(ctx, logrus.StandardLogger()).WithFields(fields).Debug("BSUIR API request")
+	}()
+
+	// В режиме replay сеть не нужна: ответ берется из каталога фикстур
+	if c.recorder.replaying() {
+		body, err := c.recorder.load(fixture)
+		if err != nil {
+			code = "not_recorded"
+			return err
+		}
+		code = "replay"
+		if err := json.Unmarshal(body, target); err != nil {
+			code = "decode_error"
+			return fmt.Errorf("failed to unmarshal recorded response: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.limiter.acquire(ctx); err != nil {
+		code = "rate_limited"
+		return fmt.Errorf("failed to wait for upstream rate limit: %w", err)
+	}
+	defer c.limiter.release()
+	// Время в очереди лимитера — частая причина долгих ответов, поэтому фиксируем его отдельно
+	span.AddEvent("upstream slot acquired", trace.WithAttributes(
+		attribute.Int64("bsuir.queue_wait_ms", time.Since(start).Milliseconds()),
+	))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -210,6 +272,7 @@ func (c *Client) makeRequest(url string, target interface{}) error {
 		}
 	}(resp.Body)
 
+	code = strconv.Itoa(resp.StatusCode)
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
@@ -220,7 +283,14 @@ func (c *Client) makeRequest(url string, target interface{}) error {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if c.recorder.recording() {
+		if err := c.recorder.save(fixture, body); err != nil {
+			logging.FromContext(ctx, logrus.StandardLogger()).WithError(err).Warn("Failed to record BSUIR API response")
+		}
+	}
+
 	if err := json.Unmarshal(body, target); err != nil {
+		code = "decode_error"
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
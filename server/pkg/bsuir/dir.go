@@ -0,0 +1,100 @@
+package bsuir
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"schedluer/internal/config"
+	"schedluer/internal/models"
+)
+
+type dirSource struct {
+	fixtures *recorder
+}
+
+// NewDirSource отдает данные из каталога с дампом API в раскладке фикстур: записью режима record,
+// фикстурами bsuirtest или выгрузкой, собранной вручную. Сеть не используется.
+func NewDirSource(dir string) ScheduleSource {
+	return &dirSource{fixtures: &recorder{mode: config.BSUIRModeReplay, dir: dir}}
+}
+
+func (d *dirSource) GetGroupSchedule(ctx context.Context, groupNumber string) (*models.ScheduleResponse, error) {
+	return loadPtr[models.ScheduleResponse](d.fixtures, fileFixture("schedule", "group", groupNumber))
+}
+
+func (d *dirSource) GetEmployeeSchedule(ctx context.Context, urlID string) (*models.ScheduleResponse, error) {
+	return loadPtr[models.ScheduleResponse](d.fixtures, fileFixture("schedule", "employee", urlID))
+}
+
+func (d *dirSource) GetAllGroups(ctx context.Context) ([]models.StudentGroupListItem, error) {
+	return load[[]models.StudentGroupListItem](d.fixtures, fileFixture("student-groups"))
+}
+
+func (d *dirSource) GetAllEmployees(ctx context.Context) ([]models.EmployeeListItem, error) {
+	return load[[]models.EmployeeListItem](d.fixtures, fileFixture("employees"))
+}
+
+func (d *dirSource) GetAllFaculties(ctx context.Context) ([]models.Faculty, error) {
+	return load[[]models.Faculty](d.fixtures, fileFixture("faculties"))
+}
+
+func (d *dirSource) GetAllDepartments(ctx context.Context) ([]models.Department, error) {
+	return load[[]models.Department](d.fixtures, fileFixture("departments"))
+}
+
+func (d *dirSource) GetAllSpecialities(ctx context.Context) ([]models.Speciality, error) {
+	return load[[]models.Speciality](d.fixtures, fileFixture("specialities"))
+}
+
+func (d *dirSource) GetAllAuditories(ctx context.Context) ([]models.Auditory, error) {
+	return load[[]models.Auditory](d.fixtures, fileFixture("auditories"))
+}
+
+func (d *dirSource) GetEmployeeAnnouncements(ctx context.Context, urlID string) ([]models.Announcement, error) {
+	return load[[]models.Announcement](d.fixtures, fileFixture("announcements", "employee", urlID))
+}
+
+func (d *dirSource) GetDepartmentAnnouncements(ctx context.Context, departmentID int) ([]models.Announcement, error) {
+	return load[[]models.Announcement](d.fixtures, fileFixture("announcements", "department", strconv.Itoa(departmentID)))
+}
+
+func (d *dirSource) GetGroupLastUpdateDate(ctx context.Context, groupNumber string) (*models.LastUpdateDate, error) {
+	return loadPtr[models.LastUpdateDate](d.fixtures, lastUpdateFixture("groups", groupNumber))
+}
+
+func (d *dirSource) GetGroupLastUpdateDateByID(ctx context.Context, groupID int) (*models.LastUpdateDate, error) {
+	return loadPtr[models.LastUpdateDate](d.fixtures, lastUpdateByIDFixture("groups", groupID))
+}
+
+func (d *dirSource) GetEmployeeLastUpdateDate(ctx context.Context, urlID string) (*models.LastUpdateDate, error) {
+	return loadPtr[models.LastUpdateDate](d.fixtures, lastUpdateFixture("employees", urlID))
+}
+
+func (d *dirSource) GetEmployeeLastUpdateDateByID(ctx context.Context, employeeID int) (*models.LastUpdateDate, error) {
+	return loadPtr[models.LastUpdateDate](d.fixtures, lastUpdateByIDFixture("employees", employeeID))
+}
+
+func (d *dirSource) GetCurrentWeek(ctx context.Context) (int, error) {
+	return load[int](d.fixtures, fileFixture("current-week"))
+}
+
+func load[T any](fixtures *recorder, f fixture) (value T, err error) {
+	body, err := fixtures.load(f)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return value, fmt.Errorf("failed to unmarshal %s: %w", f.file, err)
+	}
+	return value, nil
+}
+
+func loadPtr[T any](fixtures *recorder, f fixture) (*T, error) {
+	value, err := load[T](fixtures, f)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
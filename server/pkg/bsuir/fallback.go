@@ -0,0 +1,132 @@
+package bsuir
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/logging"
+	"schedluer/internal/models"
+)
+
+type fallbackSource struct {
+	primary  ScheduleSource
+	fallback ScheduleSource
+	logger   *logrus.Logger
+}
+
+// NewFallbackSource обращается к fallback, когда primary вернул ошибку, — например,
+// к дампу из NewDirSource, пока API БГУИРа лежит во время сессии.
+// Отмена запроса клиентом ошибкой источника не считается.
+func NewFallbackSource(primary, fallback ScheduleSource, logger *logrus.Logger) ScheduleSource {
+	return &fallbackSource{primary: primary, fallback: fallback, logger: logger}
+}
+
+func (f *fallbackSource) GetGroupSchedule(ctx context.Context, groupNumber string) (*models.ScheduleResponse, error) {
+	return withFallback(ctx, f, "GetGroupSchedule", func(s ScheduleSource) (*models.ScheduleResponse, error) {
+		return s.GetGroupSchedule(ctx, groupNumber)
+	})
+}
+
+func (f *fallbackSource) GetEmployeeSchedule(ctx context.Context, urlID string) (*models.ScheduleResponse, error) {
+	return withFallback(ctx, f, "GetEmployeeSchedule", func(s ScheduleSource) (*models.ScheduleResponse, error) {
+		return s.GetEmployeeSchedule(ctx, urlID)
+	})
+}
+
+func (f *fallbackSource) GetAllGroups(ctx context.Context) ([]models.StudentGroupListItem, error) {
+	return withFallback(ctx, f, "GetAllGroups", func(s ScheduleSource) ([]models.StudentGroupListItem, error) {
+		return s.GetAllGroups(ctx)
+	})
+}
+
+func (f *fallbackSource) GetAllEmployees(ctx context.Context) ([]models.EmployeeListItem, error) {
+	return withFallback(ctx, f, "GetAllEmployees", func(s ScheduleSource) ([]models.EmployeeListItem, error) {
+		return s.GetAllEmployees(ctx)
+	})
+}
+
+func (f *fallbackSource) GetAllFaculties(ctx context.Context) ([]models.Faculty, error) {
+	return withFallback(ctx, f, "GetAllFaculties", func(s ScheduleSource) ([]models.Faculty, error) {
+		return s.GetAllFaculties(ctx)
+	})
+}
+
+func (f *fallbackSource) GetAllDepartments(ctx context.Context) ([]models.Department, error) {
+	return withFallback(ctx, f, "GetAllDepartments", func(s ScheduleSource) ([]models.Department, error) {
+		return s.GetAllDepartments(ctx)
+	})
+}
+
+func (f *fallbackSource) GetAllSpecialities(ctx context.Context) ([]models.Speciality, error) {
+	return withFallback(ctx, f, "GetAllSpecialities", func(s ScheduleSource) ([]models.Speciality, error) {
+		return s.GetAllSpecialities(ctx)
+	})
+}
+
+func (f *fallbackSource) GetAllAuditories(ctx context.Context) ([]models.Auditory, error) {
+	return withFallback(ctx, f, "GetAllAuditories", func(s ScheduleSource) ([]models.Auditory, error) {
+		return s.GetAllAuditories(ctx)
+	})
+}
+
+func (f *fallbackSource) GetEmployeeAnnouncements(ctx context.Context, urlID string) ([]models.Announcement, error) {
+	return withFallback(ctx, f, "GetEmployeeAnnouncements", func(s ScheduleSource) ([]models.Announcement, error) {
+		return s.GetEmployeeAnnouncements(ctx, urlID)
+	})
+}
+
+func (f *fallbackSource) GetDepartmentAnnouncements(ctx context.Context, departmentID int) ([]models.Announcement, error) {
+	return withFallback(ctx, f, "GetDepartmentAnnouncements", func(s ScheduleSource) ([]models.Announcement, error) {
+		return s.GetDepartmentAnnouncements(ctx, departmentID)
+	})
+}
+
+func (f *fallbackSource) GetGroupLastUpdateDate(ctx context.Context, groupNumber string) (*models.LastUpdateDate, error) {
+	return withFallback(ctx, f, "GetGroupLastUpdateDate", func(s ScheduleSource) (*models.LastUpdateDate, error) {
+		return s.GetGroupLastUpdateDate(ctx, groupNumber)
+	})
+}
+
+func (f *fallbackSource) GetGroupLastUpdateDateByID(ctx context.Context, groupID int) (*models.LastUpdateDate, error) {
+	return withFallback(ctx, f, "GetGroupLastUpdateDateByID", func(s ScheduleSource) (*models.LastUpdateDate, error) {
+		return s.GetGroupLastUpdateDateByID(ctx, groupID)
+	})
+}
+
+func (f *fallbackSource) GetEmployeeLastUpdateDate(ctx context.Context, urlID string) (*models.LastUpdateDate, error) {
+	return withFallback(ctx, f, "GetEmployeeLastUpdateDate", func(s ScheduleSource) (*models.LastUpdateDate, error) {
+		return s.GetEmployeeLastUpdateDate(ctx, urlID)
+	})
+}
+
+func (f *fallbackSource) GetEmployeeLastUpdateDateByID(ctx context.Context, employeeID int) (*models.LastUpdateDate, error) {
+	return withFallback(ctx, f, "GetEmployeeLastUpdateDateByID", func(s ScheduleSource) (*models.LastUpdateDate, error) {
+		return s.GetEmployeeLastUpdateDateByID(ctx, employeeID)
+	})
+}
+
+func (f *fallbackSource) GetCurrentWeek(ctx context.Context) (int, error) {
+	return withFallback(ctx, f, "GetCurrentWeek", func(s ScheduleSource) (int, error) {
+		return s.GetCurrentWeek(ctx)
+	})
+}
+
+func withFallback[T any](ctx context.Context, f *fallbackSource, method string, call func(ScheduleSource) (T, error)) (T, error) {
+	value, err := call(f.primary)
+	if err == nil || ctx.Err() != nil {
+		return value, err
+	}
+
+	log := logging.FromContext(ctx, f.logger).WithField("upstream_method", method)
+	fallbackValue, fallbackErr := call(f.fallback)
+	if fallbackErr != nil {
+		log.WithError(fallbackErr).Debug("Fallback schedule source failed too")
+		return value, fmt.Errorf("%w (fallback: %v)", err, fallbackErr)
+	}
+
+	log.WithError(err).Warn("Schedule source failed, served from fallback")
+	logging.AddFields(ctx, logrus.Fields{"fallback": true})
+	return fallbackValue, nil
+}
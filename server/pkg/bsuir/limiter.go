@@ -0,0 +1,173 @@
+package bsuir
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type Priority int
+
+const (
+	// PriorityInteractive — запросы, которых ждет пользователь. Используется по умолчанию.
+	PriorityInteractive Priority = iota
+	// PriorityBackground — массовые обновления, которые могут подождать.
+	PriorityBackground
+
+	priorityCount
+)
+
+type priorityKey struct{}
+
+// WithPriority помечает контекст приоритетом для запросов к API БГУИРа.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok && priority >= 0 && priority < priorityCount {
+		return priority
+	}
+	return PriorityInteractive
+}
+
+// limiter ограничивает общую нагрузку на iis.bsuir.by: не больше maxConcurrent запросов
+// одновременно и не чаще rps в секунду. Слот и токен скорости выдаются вместе одной очередью
+// в порядке приоритета, внутри одного приоритета — в порядке очереди: фоновый запрос не займет
+// токен, которого ждет пользовательский, даже когда число одновременных запросов не ограничено.
+type limiter struct {
+	mu            sync.Mutex
+	active        int
+	maxConcurrent int
+	queues        [priorityCount][]*waiter
+	rate          *rate.Limiter
+	// timer разбудит очередь, когда появится следующий токен; nil — не заведен
+	timer *time.Timer
+}
+
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+func newLimiter(rps float64, burst int, maxConcurrent int) *limiter {
+	l := &limiter{maxConcurrent: maxConcurrent}
+	if rps > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(rps), max(burst, 1))
+	}
+	return l
+}
+
+func (l *limiter) disabled() bool {
+	return l.maxConcurrent <= 0 && l.rate == nil
+}
+
+// acquire ждет свою очередь на слот и токен скорости. При успехе вызывающий обязан вызвать release.
+func (l *limiter) acquire(ctx context.Context) error {
+	if l.disabled() {
+		return nil
+	}
+
+	priority := priorityFromContext(ctx)
+	w := &waiter{ready: make(chan struct{})}
+
+	l.mu.Lock()
+	l.queues[priority] = append(l.queues[priority], w)
+	l.dispatch()
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if w.granted {
+			// Слот успели выдать одновременно с отменой — возвращаем его следующему; токен уже потрачен
+			l.active--
+			l.dispatch()
+		} else {
+			l.remove(priority, w)
+		}
+		return ctx.Err()
+	}
+}
+
+func (l *limiter) release() {
+	if l.disabled() {
+		return
+	}
+
+	l.mu.Lock()
+	l.active--
+	l.dispatch()
+	l.mu.Unlock()
+}
+
+// dispatch выдает слоты ожидающим, пока есть свободные слоты и токены. Если токена нет,
+// заводит таймер на момент, когда он появится. Вызывается под мьютексом.
+func (l *limiter) dispatch() {
+	for l.maxConcurrent <= 0 || l.active < l.maxConcurrent {
+		if l.empty() {
+			return
+		}
+		if l.rate != nil {
+			now := time.Now()
+			reservation := l.rate.ReserveN(now, 1)
+			if delay := reservation.DelayFrom(now); delay > 0 {
+				reservation.CancelAt(now)
+				l.wakeAfter(delay)
+				return
+			}
+		}
+
+		w := l.next()
+		w.granted = true
+		l.active++
+		close(w.ready)
+	}
+}
+
+func (l *limiter) wakeAfter(delay time.Duration) {
+	if l.timer != nil {
+		return
+	}
+	l.timer = time.AfterFunc(delay, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.timer = nil
+		l.dispatch()
+	})
+}
+
+func (l *limiter) empty() bool {
+	for _, queue := range l.queues {
+		if len(queue) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *limiter) next() *waiter {
+	for priority := range l.queues {
+		if queue := l.queues[priority]; len(queue) > 0 {
+			l.queues[priority] = queue[1:]
+			return queue[0]
+		}
+	}
+	return nil
+}
+
+func (l *limiter) remove(priority Priority, w *waiter) {
+	queue := l.queues[priority]
+	for i, queued := range queue {
+		if queued == w {
+			l.queues[priority] = append(queue[:i], queue[i+1:]...)
+			return
+		}
+	}
+}
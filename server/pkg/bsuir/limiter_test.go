@@ -0,0 +1,146 @@
+package bsuir
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitQueued ждет, пока в очереди лимитера окажется n запросов
+func waitQueued(t *testing.T, l *limiter, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		l.mu.Lock()
+		queued := 0
+		for _, queue := range l.queues {
+			queued += len(queue)
+		}
+		l.mu.Unlock()
+		if queued == n {
+			return
+		}
+	}
+	t.Fatalf("limiter queue did not reach %d waiters", n)
+}
+
+// acquireAsync встает в очередь с приоритетом и сообщает name, когда получит слот
+func acquireAsync(l *limiter, priority Priority, name string, order chan<- string) {
+	go func() {
+		if err := l.acquire(WithPriority(context.Background(), priority)); err == nil {
+			order <- name
+		}
+	}()
+}
+
+func TestLimiterPriorityOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		limiter *limiter
+	}{
+		// Занят единственный слот
+		{"concurrency", newLimiter(0, 0, 1)},
+		// Слоты не ограничены, но токен скорости уже потрачен
+		{"rate", newLimiter(20, 1, 0)},
+	}
+	for _, tt := range tests {
+		l := tt.limiter
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("%s: first acquire: %v", tt.name, err)
+		}
+
+		order := make(chan string, 3)
+		acquireAsync(l, PriorityBackground, "background-1", order)
+		waitQueued(t, l, 1)
+		acquireAsync(l, PriorityBackground, "background-2", order)
+		waitQueued(t, l, 2)
+		acquireAsync(l, PriorityInteractive, "interactive", order)
+		waitQueued(t, l, 3)
+
+		var got []string
+		for range 3 {
+			l.release()
+			select {
+			case name := <-order:
+				got = append(got, name)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s: waiter was not granted, got %v", tt.name, got)
+			}
+		}
+		l.release()
+
+		want := []string{"interactive", "background-1", "background-2"}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: grant order = %v, want %v", tt.name, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestLimiterCancelWhileQueued(t *testing.T) {
+	l := newLimiter(0, 0, 1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire with an expired context = %v", err)
+	}
+	waitQueued(t, l, 0)
+
+	// Отмененный запрос не держит слот: после release его сразу получает следующий
+	l.release()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.acquire(ctx); err != nil {
+		t.Fatalf("slot leaked after a cancelled waiter: %v", err)
+	}
+	l.release()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active != 0 {
+		t.Errorf("active = %d after all releases, want 0", l.active)
+	}
+}
+
+func TestLimiterReleaseFreesSlot(t *testing.T) {
+	l := newLimiter(0, 0, 2)
+	for range 2 {
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+	}
+
+	order := make(chan string, 1)
+	acquireAsync(l, PriorityInteractive, "third", order)
+	waitQueued(t, l, 1)
+	select {
+	case <-order:
+		t.Fatal("third request ran over max_concurrent")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	l.release()
+	select {
+	case <-order:
+	case <-time.After(5 * time.Second):
+		t.Fatal("release did not hand the slot to the waiter")
+	}
+	l.release()
+	l.release()
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	l := newLimiter(0, 0, 0)
+	for range 100 {
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("disabled limiter: %v", err)
+		}
+	}
+	l.release()
+}
@@ -0,0 +1,172 @@
+package bsuir
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"schedluer/internal/config"
+	"schedluer/internal/models"
+)
+
+// ErrNotRecorded — в режиме replay для запроса нет записанного ответа
+var ErrNotRecorded = errors.New("no recorded response")
+
+const lastUpdateFile = "last-update-date.json"
+
+// fixture — место ответа метода в каталоге фикстур. Раскладка совпадает с bsuirtest.LoadFixtures,
+// поэтому записанный каталог можно отдать и фейковому API (cmd/bsuir-fake -fixtures).
+type fixture struct {
+	file string
+	// section и key — для даты обновления, которые лежат вместе в last-update-date.json
+	section string
+	key     string
+}
+
+func fileFixture(parts ...string) fixture {
+	for _, part := range parts {
+		// Части пути приходят из запроса пользователя: не даем выйти за пределы каталога
+		if part == "" || part == "." || part == ".." || strings.ContainsAny(part, `/\`+"\x00") {
+			return fixture{}
+		}
+	}
+	return fixture{file: filepath.Join(parts...) + ".json"}
+}
+
+func lastUpdateFixture(section, key string) fixture {
+	return fixture{file: lastUpdateFile, section: section, key: key}
+}
+
+func lastUpdateByIDFixture(section string, id int) fixture {
+	return lastUpdateFixture(section, "id:"+strconv.Itoa(id))
+}
+
+// recorder сохраняет ответы API в каталог фикстур (record) или отдает их вместо API (replay)
+type recorder struct {
+	mode string
+	dir  string
+	// mu сериализует чтение-изменение-запись last-update-date.json
+	mu sync.Mutex
+}
+
+func newRecorder(cfg *config.BSUIRAPIConfig) *recorder {
+	if cfg.Mode != config.BSUIRModeRecord && cfg.Mode != config.BSUIRModeReplay {
+		return nil
+	}
+	return &recorder{mode: cfg.Mode, dir: cfg.FixturesDir}
+}
+
+func (r *recorder) replaying() bool {
+	return r != nil && r.mode == config.BSUIRModeReplay
+}
+
+func (r *recorder) recording() bool {
+	return r != nil && r.mode == config.BSUIRModeRecord
+}
+
+// load возвращает записанное тело ответа
+func (r *recorder) load(f fixture) ([]byte, error) {
+	if f.file == "" {
+		return nil, ErrNotRecorded
+	}
+
+	if f.section == "" {
+		body, err := os.ReadFile(filepath.Join(r.dir, f.file))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotRecorded, f.file)
+		}
+		return body, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dates, err := r.readLastUpdates()
+	if err != nil {
+		return nil, err
+	}
+	date, ok := dates[f.section][f.key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s %s", ErrNotRecorded, f.file, f.section, f.key)
+	}
+	return json.Marshal(models.LastUpdateDate{LastUpdateDate: date})
+}
+
+// save записывает тело ответа как есть, даже если его не удастся декодировать:
+// так ошибки разбора в models воспроизводятся по записи
+func (r *recorder) save(f fixture, body []byte) error {
+	if f.file == "" {
+		return nil
+	}
+
+	if f.section == "" {
+		return writeFileAtomic(filepath.Join(r.dir, f.file), body)
+	}
+
+	var date models.LastUpdateDate
+	if err := json.Unmarshal(body, &date); err != nil {
+		return fmt.Errorf("failed to decode last update date: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dates, err := r.readLastUpdates()
+	if err != nil {
+		return err
+	}
+	if dates[f.section] == nil {
+		dates[f.section] = make(map[string]string)
+	}
+	dates[f.section][f.key] = date.LastUpdateDate
+
+	data, err := json.MarshalIndent(dates, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(r.dir, lastUpdateFile), append(data, '\n'))
+}
+
+func (r *recorder) readLastUpdates() (map[string]map[string]string, error) {
+	dates := make(map[string]map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(r.dir, lastUpdateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return dates, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &dates); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", lastUpdateFile, err)
+	}
+	return dates, nil
+}
+
+// writeFileAtomic пишет во временный файл и переименовывает, чтобы параллельный replay не прочитал половину
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".record-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
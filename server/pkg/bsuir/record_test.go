@@ -0,0 +1,104 @@
+package bsuir_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"schedluer/internal/config"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/bsuir/bsuirtest"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	fake := bsuirtest.NewServer(bsuirtest.DefaultFixtures())
+	recording := bsuir.NewClient(&config.BSUIRAPIConfig{
+		BaseURL:     fake.BaseURL(),
+		Timeout:     5 * time.Second,
+		Mode:        config.BSUIRModeRecord,
+		FixturesDir: dir,
+	})
+
+	recorded, err := recording.GetGroupSchedule(ctx, "221701")
+	if err != nil {
+		t.Fatalf("GetGroupSchedule: %v", err)
+	}
+	if _, err := recording.GetAllGroups(ctx); err != nil {
+		t.Fatalf("GetAllGroups: %v", err)
+	}
+	if _, err := recording.GetGroupLastUpdateDate(ctx, "221701"); err != nil {
+		t.Fatalf("GetGroupLastUpdateDate: %v", err)
+	}
+	if _, err := recording.GetEmployeeLastUpdateDate(ctx, "i-ivanov"); err != nil {
+		t.Fatalf("GetEmployeeLastUpdateDate: %v", err)
+	}
+	if _, err := recording.GetCurrentWeek(ctx); err != nil {
+		t.Fatalf("GetCurrentWeek: %v", err)
+	}
+	// Путь из пользовательского ввода не должен выводить запись за пределы каталога
+	_, _ = recording.GetGroupSchedule(ctx, "..")
+
+	// Ответ, который не разбирается, тоже записывается, чтобы ошибку можно было воспроизвести
+	fake.InjectFault(bsuirtest.Fault{Endpoint: "/auditories", Status: http.StatusOK, Body: `[{"id": "not a number"}]`})
+	if _, err := recording.GetAllAuditories(ctx); err == nil {
+		t.Fatal("expected a decode error for the broken auditories response")
+	}
+	fake.Close()
+
+	replaying := bsuir.NewClient(&config.BSUIRAPIConfig{
+		BaseURL:     fake.BaseURL(),
+		Timeout:     time.Second,
+		Mode:        config.BSUIRModeReplay,
+		FixturesDir: dir,
+	})
+
+	replayed, err := replaying.GetGroupSchedule(ctx, "221701")
+	if err != nil {
+		t.Fatalf("replay GetGroupSchedule: %v", err)
+	}
+	if len(replayed.Schedules["Понедельник"]) != len(recorded.Schedules["Понедельник"]) || replayed.StudentGroupDto.Name != "221701" {
+		t.Errorf("replayed schedule differs from the recorded one: %+v", replayed)
+	}
+
+	date, err := replaying.GetEmployeeLastUpdateDate(ctx, "i-ivanov")
+	if err != nil || date.LastUpdateDate != "01.09.2026" {
+		t.Errorf("replay GetEmployeeLastUpdateDate = %+v, %v", date, err)
+	}
+	if week, err := replaying.GetCurrentWeek(ctx); err != nil || week != 2 {
+		t.Errorf("replay GetCurrentWeek = %d, %v", week, err)
+	}
+
+	if _, err := replaying.GetGroupSchedule(ctx, "221702"); !errors.Is(err, bsuir.ErrNotRecorded) {
+		t.Errorf("replay of an unrecorded group: expected ErrNotRecorded, got %v", err)
+	}
+	if _, err := replaying.GetGroupSchedule(ctx, ".."); !errors.Is(err, bsuir.ErrNotRecorded) {
+		t.Errorf("replay with an unsafe path: expected ErrNotRecorded, got %v", err)
+	}
+	if _, err := replaying.GetAllAuditories(ctx); err == nil || errors.Is(err, bsuir.ErrNotRecorded) {
+		t.Errorf("replay must reproduce the decode error, got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "schedule", "group.json")); err == nil {
+		t.Error("unsafe group number was recorded")
+	}
+
+	// Записанный каталог читается фейковым API; сломанный ответ фейк, как и клиент, не разберет
+	if err := os.Remove(filepath.Join(dir, "auditories.json")); err != nil {
+		t.Fatal(err)
+	}
+	fixtures, err := bsuirtest.LoadFixtures(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("recorded directory is not loadable as fixtures: %v", err)
+	}
+	if len(fixtures.Groups) != 3 || fixtures.GroupLastUpdate["221701"] != "01.09.2026" || fixtures.CurrentWeek != 2 {
+		t.Errorf("unexpected fixtures from the recording: %d groups, last update %q, week %d",
+			len(fixtures.Groups), fixtures.GroupLastUpdate["221701"], fixtures.CurrentWeek)
+	}
+}
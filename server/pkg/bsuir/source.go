@@ -0,0 +1,35 @@
+package bsuir
+
+import (
+	"context"
+
+	"schedluer/internal/models"
+)
+
+// ScheduleSource — откуда сервисы берут расписания и справочники БГУИРа.
+// Client ходит в API (в режимах record/replay — еще и в каталог фикстур), DirSource читает
+// статический дамп, CachingSource и FallbackSource оборачивают другие источники.
+// Источник в сервисах подменяется и фейком в тестах.
+type ScheduleSource interface {
+	GetGroupSchedule(ctx context.Context, groupNumber string) (*models.ScheduleResponse, error)
+	GetEmployeeSchedule(ctx context.Context, urlID string) (*models.ScheduleResponse, error)
+
+	GetAllGroups(ctx context.Context) ([]models.StudentGroupListItem, error)
+	GetAllEmployees(ctx context.Context) ([]models.EmployeeListItem, error)
+	GetAllFaculties(ctx context.Context) ([]models.Faculty, error)
+	GetAllDepartments(ctx context.Context) ([]models.Department, error)
+	GetAllSpecialities(ctx context.Context) ([]models.Speciality, error)
+	GetAllAuditories(ctx context.Context) ([]models.Auditory, error)
+
+	GetEmployeeAnnouncements(ctx context.Context, urlID string) ([]models.Announcement, error)
+	GetDepartmentAnnouncements(ctx context.Context, departmentID int) ([]models.Announcement, error)
+
+	GetGroupLastUpdateDate(ctx context.Context, groupNumber string) (*models.LastUpdateDate, error)
+	GetGroupLastUpdateDateByID(ctx context.Context, groupID int) (*models.LastUpdateDate, error)
+	GetEmployeeLastUpdateDate(ctx context.Context, urlID string) (*models.LastUpdateDate, error)
+	GetEmployeeLastUpdateDateByID(ctx context.Context, employeeID int) (*models.LastUpdateDate, error)
+
+	GetCurrentWeek(ctx context.Context) (int, error)
+}
+
+var _ ScheduleSource = (*Client)(nil)
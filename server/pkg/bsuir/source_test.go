@@ -0,0 +1,124 @@
+package bsuir_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/config"
+	"schedluer/pkg/bsuir"
+	"schedluer/pkg/bsuir/bsuirtest"
+)
+
+func TestDirSource(t *testing.T) {
+	ctx := context.Background()
+	source := bsuir.NewDirSource("bsuirtest/fixtures")
+
+	schedule, err := source.GetGroupSchedule(ctx, "221701")
+	if err != nil {
+		t.Fatalf("GetGroupSchedule: %v", err)
+	}
+	if len(schedule.Schedules["Понедельник"]) == 0 {
+		t.Fatal("dump schedule has no lessons")
+	}
+	if week, err := source.GetCurrentWeek(ctx); err != nil || week != 2 {
+		t.Fatalf("GetCurrentWeek = %d, %v; want 2", week, err)
+	}
+	if _, err := source.GetGroupLastUpdateDate(ctx, "221701"); err != nil {
+		t.Fatalf("GetGroupLastUpdateDate: %v", err)
+	}
+	if _, err := source.GetGroupSchedule(ctx, "999999"); err == nil {
+		t.Fatal("missing group must be an error")
+	}
+}
+
+func TestFallbackSource(t *testing.T) {
+	ctx := context.Background()
+	fake := bsuirtest.NewServer(bsuirtest.DefaultFixtures())
+	defer fake.Close()
+
+	client := bsuir.NewClient(&config.BSUIRAPIConfig{BaseURL: fake.BaseURL(), Timeout: 5 * time.Second})
+	source := bsuir.NewFallbackSource(client, bsuir.NewDirSource("bsuirtest/fixtures"), logrus.New())
+
+	fake.InjectFault(bsuirtest.Fault{Endpoint: "/schedule", Status: http.StatusServiceUnavailable, Times: 1})
+	schedule, err := source.GetGroupSchedule(ctx, "221701")
+	if err != nil {
+		t.Fatalf("fallback did not answer: %v", err)
+	}
+	if schedule.StudentGroupDto == nil || schedule.StudentGroupDto.Name != "221701" {
+		t.Fatalf("unexpected schedule from fallback: %+v", schedule.StudentGroupDto)
+	}
+
+	fake.InjectFault(bsuirtest.Fault{Endpoint: "/schedule", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := source.GetGroupSchedule(ctx, "999999"); err == nil {
+		t.Fatal("error expected when both sources fail")
+	}
+}
+
+func TestCachingSource(t *testing.T) {
+	ctx := context.Background()
+	fake := bsuirtest.NewServer(bsuirtest.DefaultFixtures())
+	defer fake.Close()
+
+	client := bsuir.NewClient(&config.BSUIRAPIConfig{BaseURL: fake.BaseURL(), Timeout: 5 * time.Second})
+	source := bsuir.NewCachingSource(client, time.Hour)
+
+	for range 3 {
+		if _, err := source.GetAllGroups(ctx); err != nil {
+			t.Fatalf("GetAllGroups: %v", err)
+		}
+	}
+	if calls := fake.Calls("/student-groups"); calls != 1 {
+		t.Fatalf("student-groups requested %d times, want 1", calls)
+	}
+
+	source.Invalidate()
+	if _, err := source.GetAllGroups(ctx); err != nil {
+		t.Fatalf("GetAllGroups: %v", err)
+	}
+	if calls := fake.Calls("/student-groups"); calls != 2 {
+		t.Fatalf("student-groups requested %d times after invalidate, want 2", calls)
+	}
+
+	// Запрос мимо кэша идет в источник и обновляет кэш для остальных
+	if _, err := source.GetAllGroups(bsuir.WithoutCache(ctx)); err != nil {
+		t.Fatalf("GetAllGroups without cache: %v", err)
+	}
+	if _, err := source.GetAllGroups(ctx); err != nil {
+		t.Fatalf("GetAllGroups: %v", err)
+	}
+	if calls := fake.Calls("/student-groups"); calls != 3 {
+		t.Fatalf("student-groups requested %d times after a bypass, want 3", calls)
+	}
+
+	// Нулевой срок выключает кэш, а возвращенный прежний срок снова отдает сохраненный ответ
+	source.SetTTL(0)
+	for range 2 {
+		if _, err := source.GetAllGroups(ctx); err != nil {
+			t.Fatalf("GetAllGroups: %v", err)
+		}
+	}
+	if calls := fake.Calls("/student-groups"); calls != 5 {
+		t.Fatalf("student-groups requested %d times with the cache off, want 5", calls)
+	}
+	source.SetTTL(time.Hour)
+	if _, err := source.GetAllGroups(ctx); err != nil {
+		t.Fatalf("GetAllGroups: %v", err)
+	}
+	if calls := fake.Calls("/student-groups"); calls != 5 {
+		t.Fatalf("student-groups requested %d times after the cache was turned back on, want 5", calls)
+	}
+
+	// Сокращенный срок сразу действует и на уже сохраненный ответ
+	source.SetTTL(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, err := source.GetAllGroups(ctx); err != nil {
+		t.Fatalf("GetAllGroups: %v", err)
+	}
+	if calls := fake.Calls("/student-groups"); calls != 6 {
+		t.Fatalf("student-groups requested %d times after the TTL was shortened, want 6", calls)
+	}
+}
@@ -1,3 +1,185 @@
+// Package converter разворачивает недельное расписание БГУИРа в занятия по датам.
 package converter
 
-// TODO: Реализовать конвертацию расписания в нужный формат
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"schedluer/internal/models"
+)
+
+// Minsk — часовой пояс расписания. Перехода на летнее время в Беларуси нет, поэтому смещение фиксированное.
+var Minsk = time.FixedZone("Europe/Minsk", 3*60*60)
+
+// StudyWeeks — длина цикла учебных недель: занятие идет по неделям из Schedule.WeekNumber (1–4)
+const StudyWeeks = 4
+
+const (
+	dateLayout = "02.01.2006"
+	timeLayout = "15:04"
+)
+
+// Weekdays — ключи ScheduleResponse.Schedules по дням недели
+var Weekdays = map[time.Weekday]string{
+	time.Monday:    "Понедельник",
+	time.Tuesday:   "Вторник",
+	time.Wednesday: "Среда",
+	time.Thursday:  "Четверг",
+	time.Friday:    "Пятница",
+	time.Saturday:  "Суббота",
+	time.Sunday:    "Воскресенье",
+}
+
+// Occurrence — занятие в конкретную дату
+type Occurrence struct {
+	Date    time.Time
+	Weekday string
+	Start   time.Time
+	End     time.Time
+	Exam    bool
+	Lesson  models.Schedule
+}
+
+// WeekCalendar знает, какая учебная неделя идет в заданную дату: по известной неделе Week
+// в дату Reference недели сдвигаются по кругу от 1 до StudyWeeks
+type WeekCalendar struct {
+	Reference time.Time
+	Week      int
+}
+
+// SemesterCalendar — календарь от начала семестра (ScheduleResponse.StartDate): первая неделя — неделя начала.
+// Нужен, когда номер текущей недели из API получить не удалось.
+func SemesterCalendar(startDate string) (WeekCalendar, error) {
+	start, err := ParseDate(startDate)
+	if err != nil {
+		return WeekCalendar{}, err
+	}
+	return WeekCalendar{Reference: start, Week: 1}, nil
+}
+
+// WeekNumber возвращает учебную неделю (1–StudyWeeks) для даты
+func (c WeekCalendar) WeekNumber(date time.Time) int {
+	weeks := int(monday(date).Sub(monday(c.Reference)).Hours()/24) / 7
+	week := (c.Week - 1 + weeks) % StudyWeeks
+	if week < 0 {
+		week += StudyWeeks
+	}
+	return week + 1
+}
+
+// Expand разворачивает расписание и экзамены в занятия с from по to включительно (берутся только даты),
+// отсортированные по времени начала. Занятия с DateLesson идут только в эту дату,
+// остальные — по дню недели, учебным неделям и границам StartLessonDate–EndLessonDate.
+func Expand(schedule *models.ScheduleResponse, from, to time.Time, calendar WeekCalendar) []Occurrence {
+	occurrences := []Occurrence{}
+	if schedule == nil {
+		return occurrences
+	}
+
+	from, to = day(from), day(to)
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		weekday := Weekdays[date.Weekday()]
+		week := calendar.WeekNumber(date)
+
+		for _, lesson := range schedule.Schedules[weekday] {
+			if occursOn(lesson, date, week) {
+				occurrences = appendOccurrence(occurrences, lesson, date, weekday, false)
+			}
+		}
+	}
+
+	for _, exam := range schedule.Exams {
+		date, err := ParseDate(exam.DateLesson)
+		if err != nil || date.Before(from) || date.After(to) {
+			continue
+		}
+		occurrences = appendOccurrence(occurrences, exam, date, Weekdays[date.Weekday()], true)
+	}
+
+	sortOccurrences(occurrences)
+	return occurrences
+}
+
+func occursOn(lesson models.Schedule, date time.Time, week int) bool {
+	if lesson.DateLesson != "" {
+		lessonDate, err := ParseDate(lesson.DateLesson)
+		return err == nil && lessonDate.Equal(date)
+	}
+
+	if start, err := ParseDate(lesson.StartLessonDate); err == nil && date.Before(start) {
+		return false
+	}
+	if end, err := ParseDate(lesson.EndLessonDate); err == nil && date.After(end) {
+		return false
+	}
+
+	if len(lesson.WeekNumber) == 0 {
+		return true
+	}
+	for _, number := range lesson.WeekNumber {
+		if number == week {
+			return true
+		}
+	}
+	return false
+}
+
+// appendOccurrence пропускает занятия с неразборчивым временем: поставить их в ленту некуда
+func appendOccurrence(occurrences []Occurrence, lesson models.Schedule, date time.Time, weekday string, exam bool) []Occurrence {
+	start, err := At(date, lesson.StartLessonTime)
+	if err != nil {
+		return occurrences
+	}
+	end, err := At(date, lesson.EndLessonTime)
+	if err != nil || end.Before(start) {
+		end = start
+	}
+	return append(occurrences, Occurrence{
+		Date:    date,
+		Weekday: weekday,
+		Start:   start,
+		End:     end,
+		Exam:    exam,
+		Lesson:  lesson,
+	})
+}
+
+// ParseDate разбирает дату в формате API (02.01.2006) в полночь по Минску
+func ParseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("empty date")
+	}
+	return time.ParseInLocation(dateLayout, value, Minsk)
+}
+
+// At возвращает момент clock ("09:00") в дату date по Минску
+func At(date time.Time, clock string) (time.Time, error) {
+	parsed, err := time.Parse(timeLayout, clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	date = day(date)
+	return time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, Minsk), nil
+}
+
+// day — полночь даты t по Минску
+func day(t time.Time) time.Time {
+	t = t.In(Minsk)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Minsk)
+}
+
+func monday(t time.Time) time.Time {
+	t = day(t)
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+func sortOccurrences(occurrences []Occurrence) {
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		if !occurrences[i].Start.Equal(occurrences[j].Start) {
+			return occurrences[i].Start.Before(occurrences[j].Start)
+		}
+		return occurrences[i].End.Before(occurrences[j].End)
+	})
+}
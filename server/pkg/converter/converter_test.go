@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"schedluer/internal/models"
+)
+
+func date(value string) time.Time {
+	parsed, err := time.ParseInLocation(time.DateOnly, value, Minsk)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestWeekNumber(t *testing.T) {
+	// 2026-09-01 — вторник первой недели
+	calendar := WeekCalendar{Reference: date("2026-09-01"), Week: 1}
+
+	cases := map[string]int{
+		"2026-08-31": 1, // понедельник той же недели
+		"2026-09-06": 1,
+		"2026-09-07": 2,
+		"2026-09-21": 4,
+		"2026-09-28": 1,
+		"2026-08-30": 4, // воскресенье предыдущей недели
+		"2026-08-17": 3,
+	}
+	for value, want := range cases {
+		if got := calendar.WeekNumber(date(value)); got != want {
+			t.Errorf("WeekNumber(%s) = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	schedule := &models.ScheduleResponse{
+		Schedules: map[string][]models.Schedule{
+			"Понедельник": {
+				{Subject: "ООП", WeekNumber: []int{1, 2, 3, 4}, StartLessonTime: "09:00", EndLessonTime: "10:20", StartLessonDate: "01.09.2026", EndLessonDate: "27.12.2026"},
+				{Subject: "ЛР", WeekNumber: []int{1, 3}, StartLessonTime: "10:35", EndLessonTime: "11:55"},
+			},
+			"Среда": {
+				{Subject: "Консультация", DateLesson: "16.09.2026", StartLessonTime: "15:00", EndLessonTime: "16:20"},
+			},
+		},
+		Exams: []models.Schedule{
+			{Subject: "Экзамен", DateLesson: "09.09.2026", StartLessonTime: "09:00", EndLessonTime: "12:00"},
+		},
+	}
+	calendar := WeekCalendar{Reference: date("2026-09-01"), Week: 1}
+
+	occurrences := Expand(schedule, date("2026-08-31"), date("2026-09-16"), calendar)
+
+	var got []string
+	for _, o := range occurrences {
+		got = append(got, o.Date.Format(time.DateOnly)+" "+o.Lesson.Subject)
+	}
+	want := []string{
+		// 31.08 — до StartLessonDate у ООП, но ЛР без границ идет
+		"2026-08-31 ЛР",
+		"2026-09-07 ООП",
+		"2026-09-09 Экзамен",
+		"2026-09-14 ООП",
+		"2026-09-14 ЛР",
+		"2026-09-16 Консультация",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expand = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expand = %v, want %v", got, want)
+		}
+	}
+
+	first := occurrences[0]
+	if first.Weekday != "Понедельник" || first.Start.Hour() != 10 || first.Start.Minute() != 35 || first.End.Sub(first.Start) != 80*time.Minute {
+		t.Errorf("unexpected occurrence %+v", first)
+	}
+	if !occurrences[2].Exam {
+		t.Error("exam is not marked")
+	}
+}
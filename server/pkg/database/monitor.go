@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"schedluer/internal/logging"
+	"schedluer/internal/metrics"
+	"schedluer/internal/tracing"
+)
+
+// commandMonitor измеряет длительность команд MongoDB по коллекциям и открывает для них span'ы.
+// Каждая коллекция принадлежит одному репозиторию, так что это и есть латентность репозиториев.
+type commandMonitor struct {
+	inflight sync.Map // RequestID -> *command
+}
+
+type command struct {
+	collection string
+	span       trace.Span
+}
+
+func newCommandMonitor() *event.CommandMonitor {
+	m := &commandMonitor{}
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *commandMonitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	// Для CRUD-команд имя коллекции — значение поля с именем самой команды: {"find": "groups", ...}
+	collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK()
+	if !ok {
+		return
+	}
+
+	_, span := tracing.Start(ctx, "mongodb."+evt.CommandName,
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.name", evt.DatabaseName),
+		attribute.String("db.mongodb.collection", collection),
+		attribute.String("db.operation", evt.CommandName),
+	)
+
+	m.inflight.Store(evt.RequestID, &command{collection: collection, span: span})
+}
+
+func (m *commandMonitor) succeeded(ctx context.Context, evt *event.CommandSucceededEvent) {
+	m.finish(ctx, evt.CommandFinishedEvent, nil)
+}
+
+func (m *commandMonitor) failed(ctx context.Context, evt *event.CommandFailedEvent) {
+	m.finish(ctx, evt.CommandFinishedEvent, evt.Failure)
+}
+
+func (m *commandMonitor) finish(ctx context.Context, evt event.CommandFinishedEvent, failure error) {
+	value, ok := m.inflight.LoadAndDelete(evt.RequestID)
+	if !ok {
+		return
+	}
+	cmd := value.(*command)
+
+	status := "ok"
+	if failure != nil {
+		status = "error"
+		cmd.span.RecordError(failure)
+		cmd.span.SetStatus(codes.Error, failure.Error())
+	}
+	cmd.span.End()
+
+	metrics.MongoOperationDuration.
+		WithLabelValues(cmd.collection, evt.CommandName, status).
+		Observe(evt.Duration.Seconds())
+
+	log := logging.FromContext(ctx, logrus.StandardLogger()).WithFields(logrus.Fields{
+		"collection": cmd.collection,
+		"command":    evt.CommandName,
+		"latency_ms": evt.Duration.Milliseconds(),
+	})
+	if failure != nil {
+		log.WithError(failure).Warn("MongoDB command failed")
+		return
+	}
+	log.Debug("MongoDB command")
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var objectIDType = reflect.TypeOf(primitive.ObjectID{})
+
+// Registry — BSON-кодеки клиента. Модели используют primitive.ObjectID из первой версии драйвера,
+// а драйвер v2 считает его обычным [12]byte и пишет в _id бинарные данные, из-за чего ObjectID,
+// созданные сервером при upsert, не читаются в модели. Кодек пишет и читает настоящий BSON ObjectID.
+func Registry() *bson.Registry {
+	registry := bson.NewRegistry()
+	registry.RegisterTypeEncoder(objectIDType, bson.ValueEncoderFunc(encodeObjectID))
+	registry.RegisterTypeDecoder(objectIDType, bson.ValueDecoderFunc(decodeObjectID))
+	return registry
+}
+
+func encodeObjectID(_ bson.EncodeContext, vw bson.ValueWriter, value reflect.Value) error {
+	if !value.IsValid() || value.Type() != objectIDType {
+		return bson.ValueEncoderError{Name: "ObjectIDEncodeValue", Types: []reflect.Type{objectIDType}, Received: value}
+	}
+	return vw.WriteObjectID(bson.ObjectID(value.Interface().(primitive.ObjectID)))
+}
+
+// decodeObjectID принимает только BSON ObjectID (и null как пустой ID). Идентификаторы, сохраненные
+// бинарными данными или массивом, — ошибка: их приводит к ObjectID миграция normalize_object_ids.
+func decodeObjectID(_ bson.DecodeContext, vr bson.ValueReader, value reflect.Value) error {
+	if !value.CanSet() || value.Type() != objectIDType {
+		return bson.ValueDecoderError{Name: "ObjectIDDecodeValue", Types: []reflect.Type{objectIDType}, Received: value}
+	}
+
+	var id primitive.ObjectID
+	switch vr.Type() {
+	case bson.TypeObjectID:
+		oid, err := vr.ReadObjectID()
+		if err != nil {
+			return err
+		}
+		id = primitive.ObjectID(oid)
+	case bson.TypeNull:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into an ObjectID: run the MongoDB migrations to normalize stored ids", vr.Type())
+	}
+
+	value.Set(reflect.ValueOf(id))
+	return nil
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type document struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty"`
+	Name string             `bson:"name"`
+}
+
+func encode(t *testing.T, value any) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder := bson.NewEncoder(bson.NewDocumentWriter(&buf))
+	encoder.SetRegistry(Registry())
+	if err := encoder.Encode(value); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decode(data []byte, value any) error {
+	decoder := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(data)))
+	decoder.SetRegistry(Registry())
+	return decoder.Decode(value)
+}
+
+func TestObjectIDRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	data := encode(t, document{ID: id, Name: "221701"})
+
+	if kind := bson.Raw(data).Lookup("_id").Type; kind != bson.TypeObjectID {
+		t.Fatalf("_id is encoded as %v, want objectID", kind)
+	}
+
+	var decoded document
+	if err := decode(data, &decoded); err != nil || decoded.ID != id {
+		t.Fatalf("decoded %+v, %v; want id %s", decoded, err, id.Hex())
+	}
+
+	// ID, созданный сервером, тоже читается
+	serverID := bson.NewObjectID()
+	if err := decode(encode(t, bson.M{"_id": serverID}), &decoded); err != nil || decoded.ID.Hex() != serverID.Hex() {
+		t.Errorf("server ObjectID decoded as %s, %v", decoded.ID.Hex(), err)
+	}
+
+	// Пустой ID не пишется, и сервер подставит свой
+	if data := encode(t, document{Name: "221701"}); bson.Raw(data).Lookup("_id").Type != 0 {
+		t.Errorf("zero ObjectID must be omitted: %v", bson.Raw(data))
+	}
+}
+
+func TestObjectIDDecodeIsStrict(t *testing.T) {
+	for name, id := range map[string]any{
+		"binary": bson.Binary{Data: make([]byte, 12)},
+		"array":  bson.A{1, 2, 3},
+		"string": "66f0c0c0c0c0c0c0c0c0c0c0",
+	} {
+		var decoded document
+		err := decode(encode(t, bson.M{"_id": id}), &decoded)
+		if err == nil || !strings.Contains(err.Error(), "migrations") {
+			t.Errorf("%s _id: expected an error pointing to migrations, got %v", name, err)
+		}
+	}
+}
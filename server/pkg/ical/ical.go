@@ -0,0 +1,116 @@
+// Package ical пишет календарь в формате iCalendar (RFC 5545) для подписки из Google Calendar,
+// Apple Calendar и Outlook. Поддерживается только то, что нужно ленте: события с началом и концом.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType — MIME-тип ответа с календарем
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	utcLayout = "20060102T150405Z"
+	// lineLimit — длина строки в октетах, после которой RFC 5545 требует перенос
+	lineLimit = 75
+)
+
+type Calendar struct {
+	// ProdID — идентификатор программы, создавшей календарь
+	ProdID string
+	// Name — название календаря в приложении (X-WR-CALNAME)
+	Name   string
+	Events []Event
+}
+
+type Event struct {
+	// UID должен быть стабильным между выгрузками, иначе календари дублируют события при обновлении
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+	// Stamp — время создания события (DTSTAMP)
+	Stamp time.Time
+}
+
+// Write пишет календарь в w. Время записывается в UTC, поэтому VTIMEZONE не нужен.
+func Write(w io.Writer, calendar Calendar) error {
+	out := &writer{w: bufio.NewWriter(w)}
+
+	out.line("BEGIN", "VCALENDAR")
+	out.line("VERSION", "2.0")
+	out.line("PRODID", calendar.ProdID)
+	out.line("CALSCALE", "GREGORIAN")
+	out.line("METHOD", "PUBLISH")
+	if calendar.Name != "" {
+		out.text("X-WR-CALNAME", calendar.Name)
+	}
+
+	for _, event := range calendar.Events {
+		out.line("BEGIN", "VEVENT")
+		out.text("UID", event.UID)
+		out.line("DTSTAMP", event.Stamp.UTC().Format(utcLayout))
+		out.line("DTSTART", event.Start.UTC().Format(utcLayout))
+		out.line("DTEND", event.End.UTC().Format(utcLayout))
+		out.text("SUMMARY", event.Summary)
+		if event.Location != "" {
+			out.text("LOCATION", event.Location)
+		}
+		if event.Description != "" {
+			out.text("DESCRIPTION", event.Description)
+		}
+		out.line("END", "VEVENT")
+	}
+
+	out.line("END", "VCALENDAR")
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// text пишет свойство с текстовым значением, экранируя спецсимволы
+func (w *writer) text(name, value string) {
+	w.line(name, escape(value))
+}
+
+// line пишет свойство, перенося длинные строки: продолжение начинается с пробела,
+// а многобайтовые символы не разрываются
+func (w *writer) line(name, value string) {
+	if w.err != nil {
+		return
+	}
+	content := name + ":" + value
+
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := utf8.RuneLen(r)
+		if width+size > lineLimit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, w.err = w.w.WriteString(b.String())
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
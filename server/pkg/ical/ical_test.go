@@ -0,0 +1,69 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2026, 9, 7, 9, 0, 0, 0, time.FixedZone("Europe/Minsk", 3*60*60))
+	calendar := Calendar{
+		ProdID: "-//schedluer//RU",
+		Name:   "Группа 221701",
+		Events: []Event{{
+			UID:         "lesson-1@schedluer",
+			Start:       start,
+			End:         start.Add(95 * time.Minute),
+			Summary:     "ООП (ЛК)",
+			Location:    "101-5 к., 102-5 к.",
+			Description: "Иванов И. И.\nподгруппа 1; поток",
+			Stamp:       start,
+		}},
+	}
+
+	var out strings.Builder
+	if err := Write(&out, calendar); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got := out.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Группа 221701\r\n",
+		"DTSTART:20260907T060000Z\r\n",
+		"DTEND:20260907T073500Z\r\n",
+		`LOCATION:101-5 к.\, 102-5 к.` + "\r\n",
+		`DESCRIPTION:Иванов И. И.\nподгруппа 1\; поток` + "\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar has no %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestLongLinesAreFolded(t *testing.T) {
+	var out strings.Builder
+	err := Write(&out, Calendar{ProdID: "-//schedluer//RU", Events: []Event{{
+		UID:     "long",
+		Summary: strings.Repeat("Программирование ", 10),
+	}}})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		if len(line) > lineLimit {
+			t.Errorf("line is %d octets long: %q", len(line), line)
+		}
+		if !strings.HasPrefix(line, " ") {
+			unfolded.WriteString("\n")
+		}
+		unfolded.WriteString(strings.TrimPrefix(line, " "))
+	}
+	if !strings.Contains(unfolded.String(), "SUMMARY:"+strings.Repeat("Программирование ", 10)) {
+		t.Errorf("folded summary does not unfold back:\n%s", out.String())
+	}
+}
@@ -0,0 +1,44 @@
+// Package notify отправляет короткие уведомления по разным каналам: webhook, почта через SMTP, Telegram.
+// Отправители не знают, о чем уведомление, — только куда и что отправить.
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// Message — одно уведомление. To зависит от канала: URL для webhook, адрес для почты, chat_id для Telegram.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	// Payload — структурированные данные для webhook; остальные каналы его не отправляют
+	Payload any
+}
+
+// Sender отправляет уведомление по одному каналу
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// TargetValidator — отправитель, который проверяет получателя заранее, при подписке
+type TargetValidator interface {
+	ValidateTarget(ctx context.Context, to string) error
+}
+
+// SenderFunc позволяет использовать функцию как Sender, например в тестах
+type SenderFunc func(ctx context.Context, message Message) error
+
+func (f SenderFunc) Send(ctx context.Context, message Message) error {
+	return f(ctx, message)
+}
+
+// withoutURL убирает адрес из ошибки HTTP-клиента: в адресе Telegram API лежит токен бота
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
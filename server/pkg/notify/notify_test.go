@@ -0,0 +1,192 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"schedluer/pkg/notify/notifytest"
+)
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestWebhookSender(t *testing.T) {
+	var received webhookPayload
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(WebhookConfig{Timeout: time.Second, AllowPrivate: true})
+	message := Message{To: server.URL + "/hook", Subject: "Через 15 мин: ООП", Text: "09:00, 101-5 к.", Payload: map[string]string{"subject": "ООП"}}
+	if err := sender.Send(testContext(t), message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if received.Subject != message.Subject || received.Text != message.Text || received.Payload == nil {
+		t.Errorf("webhook received %+v", received)
+	}
+
+	status = http.StatusInternalServerError
+	if err := sender.Send(testContext(t), message); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected an error for status 500, got %v", err)
+	}
+
+	// Редирект не выполняется: иначе публичный адрес мог бы переслать запрос внутрь сети
+	status = http.StatusFound
+	if err := sender.Send(testContext(t), message); err == nil || !strings.Contains(err.Error(), "302") {
+		t.Errorf("expected an error for a redirect, got %v", err)
+	}
+}
+
+func TestWebhookSenderValidateTarget(t *testing.T) {
+	sender := NewWebhookSender(WebhookConfig{Timeout: time.Second})
+
+	tests := []struct {
+		target string
+		want   error
+	}{
+		{"https://8.8.8.8/hook", nil},
+		{"https://[2001:4860:4860::8888]/hook", nil},
+		{"http://8.8.8.8/hook", ErrInsecureWebhook},
+		{"ftp://8.8.8.8/hook", ErrInsecureWebhook},
+		{"https://127.0.0.1/hook", ErrPrivateAddress},
+		{"https://[::1]:8443/hook", ErrPrivateAddress},
+		{"https://10.0.0.5/hook", ErrPrivateAddress},
+		{"https://172.16.0.1/hook", ErrPrivateAddress},
+		{"https://192.168.1.1/hook", ErrPrivateAddress},
+		{"https://169.254.169.254/latest/meta-data", ErrPrivateAddress},
+		{"https://[fe80::1]/hook", ErrPrivateAddress},
+		{"https://[fd00::1]/hook", ErrPrivateAddress},
+		{"https://[::ffff:127.0.0.1]/hook", ErrPrivateAddress},
+		{"https://0.0.0.0/hook", ErrPrivateAddress},
+		{"https://100.64.0.1/hook", ErrPrivateAddress},
+		{"https://224.0.0.1/hook", ErrPrivateAddress},
+	}
+	for _, tt := range tests {
+		err := sender.ValidateTarget(testContext(t), tt.target)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("ValidateTarget(%s) = %v, want %v", tt.target, err, tt.want)
+		}
+	}
+
+	if err := sender.ValidateTarget(testContext(t), "https:///hook"); err == nil {
+		t.Error("a URL without a host must be rejected")
+	}
+
+	allowed := NewWebhookSender(WebhookConfig{Timeout: time.Second, AllowPrivate: true})
+	for _, target := range []string{"http://127.0.0.1:8080/hook", "https://10.0.0.5/hook"} {
+		if err := allowed.ValidateTarget(testContext(t), target); err != nil {
+			t.Errorf("ValidateTarget(%s) with AllowPrivate = %v", target, err)
+		}
+	}
+}
+
+// TestWebhookSenderDialCheck — адрес проверяется и при соединении: подписка могла пройти проверку,
+// а DNS потом начать отвечать внутренним адресом
+func TestWebhookSenderDialCheck(t *testing.T) {
+	called := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(WebhookConfig{Timeout: time.Second})
+	err := sender.Send(testContext(t), Message{To: server.URL + "/hook", Subject: "Через 15 мин: ООП"})
+	if !errors.Is(err, ErrPrivateAddress) || called {
+		t.Errorf("Send to a loopback address = %v (called %v), want %v", err, called, ErrPrivateAddress)
+	}
+}
+
+func TestTelegramSender(t *testing.T) {
+	var path string
+	var received telegramMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if received.ChatID == "blocked" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"ok":false,"description":"Forbidden: bot was blocked by the user"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	sender := NewTelegramSender(server.URL+"/", "123:secret", time.Second)
+	if err := sender.Send(testContext(t), Message{To: "42", Subject: "Через 15 мин: ООП", Text: "09:00"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if path != "/bot123:secret/sendMessage" || received.ChatID != "42" || received.Text != "Через 15 мин: ООП\n09:00" {
+		t.Errorf("telegram received %s %+v", path, received)
+	}
+
+	err := sender.Send(testContext(t), Message{To: "blocked", Text: "09:00"})
+	if err == nil || !strings.Contains(err.Error(), "blocked by the user") {
+		t.Errorf("expected the Telegram description in the error, got %v", err)
+	}
+
+	server.Close()
+	err = sender.Send(testContext(t), Message{To: "42", Text: "09:00"})
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("network errors must not leak the bot token, got %v", err)
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	server, err := notifytest.NewSMTPServer()
+	if err != nil {
+		t.Fatalf("NewSMTPServer: %v", err)
+	}
+	defer server.Close()
+
+	sender := NewSMTPSender(SMTPConfig{Host: server.Host(), Port: server.Port(), From: "schedluer@example.com", Timeout: time.Second})
+	message := Message{To: "student@example.com", Subject: "Через 15 мин: ООП", Text: "ООП (ЛК)\n.\n09:00, 101-5 к."}
+	if err := sender.Send(testContext(t), message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	mails := server.Mails()
+	if len(mails) != 1 || mails[0].From != "schedluer@example.com" || len(mails[0].To) != 1 || mails[0].To[0] != "student@example.com" {
+		t.Fatalf("server received %+v", mails)
+	}
+	data := mails[0].Data
+	if !strings.Contains(data, "Subject: =?utf-8?q?") || !strings.Contains(data, "charset=utf-8") ||
+		!strings.HasSuffix(data, "\r\n\r\nООП (ЛК)\r\n.\r\n09:00, 101-5 к.\r\n") {
+		t.Errorf("unexpected message:\n%s", data)
+	}
+
+	// Перевод строки в адресе не дает дописать заголовки или лишних получателей
+	injected := message
+	injected.To = "student@example.com\r\nBcc: victim@example.com"
+	if err := sender.Send(testContext(t), injected); err == nil {
+		t.Error("expected an error for a recipient with CRLF")
+	}
+	if mails := server.Mails(); len(mails) != 1 {
+		t.Errorf("server received %d mails after an injection attempt, want 1", len(mails))
+	}
+
+	server.RejectRecipients("no such user")
+	if err := sender.Send(testContext(t), message); err == nil || !strings.Contains(err.Error(), "no such user") {
+		t.Errorf("expected a rejected recipient, got %v", err)
+	}
+
+	server.Close()
+	if err := sender.Send(testContext(t), message); err == nil || !strings.Contains(err.Error(), "failed to connect") {
+		t.Errorf("expected a connection error, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+// Package notifytest — локальные заменители каналов уведомлений для тестов: SMTP-сервер,
+// который принимает письма в память. Webhook и Telegram проверяются через httptest.
+package notifytest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Mail — принятое письмо. Data — заголовки и тело как есть, с \r\n.
+type Mail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// SMTPServer — минимальный SMTP-сервер на 127.0.0.1: HELO/EHLO, MAIL, RCPT, DATA, RSET, NOOP, QUIT.
+// STARTTLS и AUTH не предлагает, поэтому NewSMTPSender подключается к нему без шифрования.
+// Все методы безопасны для конкурентного использования.
+type SMTPServer struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	mails  []Mail
+	reject string
+}
+
+// NewSMTPServer запускает сервер на свободном порту; остановить — Close
+func NewSMTPServer() (*SMTPServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &SMTPServer{listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Host и Port — куда подключать notify.SMTPConfig
+func (s *SMTPServer) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+func (s *SMTPServer) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Mails возвращает копию принятых писем в порядке получения
+func (s *SMTPServer) Mails() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail(nil), s.mails...)
+}
+
+// RejectRecipients заставляет сервер отвечать 550 на RCPT TO, пока не передан пустой reason
+func (s *SMTPServer) RejectRecipients(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reject = reason
+}
+
+func (s *SMTPServer) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *SMTPServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) bool {
+		_, err := conn.Write([]byte(line + "\r\n"))
+		return err == nil
+	}
+
+	var mail Mail
+	if !reply("220 notifytest SMTP ready") {
+		return
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 notifytest")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			mail = Mail{From: address(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.mu.Lock()
+			reject := s.reject
+			s.mu.Unlock()
+			if reject != "" {
+				reply("550 " + reject)
+				continue
+			}
+			mail.To = append(mail.To, address(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case command == "DATA":
+			if !reply("354 End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, ok := readData(reader)
+			if !ok {
+				return
+			}
+			mail.Data = data
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			mail = Mail{}
+			reply("250 OK: queued")
+		case command == "RSET":
+			mail = Mail{}
+			reply("250 OK")
+		case command == "NOOP":
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// readData читает тело письма до строки из одной точки, снимая точку-экранирование
+func readData(reader *bufio.Reader) (string, bool) {
+	var b strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", false
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return b.String(), true
+		}
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+func address(value string) string {
+	value = strings.TrimSpace(value)
+	if end := strings.IndexByte(value, ' '); end >= 0 {
+		value = value[:end]
+	}
+	return strings.Trim(value, "<>")
+}
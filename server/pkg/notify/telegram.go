@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTelegramURL — адрес Bot API; в тестах его подменяют локальным сервером
+const DefaultTelegramURL = "https://api.telegram.org"
+
+// TelegramSender отправляет уведомление от бота в чат Message.To методом sendMessage.
+// Пользователь должен сам написать боту, иначе Telegram не даст отправить ему сообщение.
+type TelegramSender struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewTelegramSender(baseURL, token string, timeout time.Duration) *TelegramSender {
+	if baseURL == "" {
+		baseURL = DefaultTelegramURL
+	}
+	return &TelegramSender{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type telegramMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (s *TelegramSender) Send(ctx context.Context, message Message) error {
+	text := message.Text
+	if message.Subject != "" {
+		text = message.Subject + "\n" + message.Text
+	}
+	body, err := json.Marshal(telegramMessage{ChatID: message.To, Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram message: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/bot"+s.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid telegram API URL: %w", withoutURL(err))
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("telegram request failed: %w", withoutURL(err))
+	}
+	defer response.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(io.LimitReader(response.Body, 64<<10)).Decode(&result); err != nil {
+		return fmt.Errorf("telegram responded with status %d", response.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram rejected the message: %s", result.Description)
+	}
+	return nil
+}
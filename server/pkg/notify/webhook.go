@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInsecureWebhook — адрес webhook не https
+	ErrInsecureWebhook = errors.New("webhook URL must use https")
+	// ErrPrivateAddress — адрес webhook ведет в локальную сеть, на loopback или link-local
+	ErrPrivateAddress = errors.New("webhook host resolves to a non-public address")
+)
+
+// nonPublicPrefixes — диапазоны, которые netip не считает частными, но наружу из них ничего не ведет
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// WebhookConfig — настройки отправки webhook
+type WebhookConfig struct {
+	Timeout time.Duration
+	// AllowPrivate разрешает http и адреса локальной сети. Webhook уходит на URL, который задал пользователь,
+	// поэтому без этого флага сервер ходит только по https и только на публичные адреса.
+	AllowPrivate bool
+}
+
+// WebhookSender отправляет уведомление POST-запросом с JSON на адрес из Message.To.
+// Любой ответ, кроме 2xx, считается ошибкой, редиректы не выполняются.
+type WebhookSender struct {
+	client       *http.Client
+	resolver     *net.Resolver
+	allowPrivate bool
+}
+
+// webhookPayload — тело запроса webhook
+type webhookPayload struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	Payload any    `json:"payload,omitempty"`
+}
+
+func NewWebhookSender(cfg WebhookConfig) *WebhookSender {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivate {
+		// Адрес проверяется при каждом соединении уже после разрешения имени: проверка при сохранении
+		// подписки не спасает, если DNS позже начнет отвечать внутренним адресом
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, address)
+			}
+			if !PublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		// Без прокси: иначе проверялся бы адрес прокси, а не получателя
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: cfg.Timeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		resolver:     net.DefaultResolver,
+		allowPrivate: cfg.AllowPrivate,
+	}
+}
+
+// ValidateTarget проверяет адрес webhook при подписке: https и только публичные адреса хоста
+func (s *WebhookSender) ValidateTarget(ctx context.Context, to string) error {
+	target, err := s.parseTarget(to)
+	if err != nil || s.allowPrivate {
+		return err
+	}
+
+	addrs, err := s.resolver.LookupNetIP(ctx, "ip", target.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host %q", target.Hostname())
+	}
+	for _, addr := range addrs {
+		if !PublicAddress(addr) {
+			return fmt.Errorf("%w: %s", ErrPrivateAddress, addr)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookSender) Send(ctx context.Context, message Message) error {
+	if _, err := s.parseTarget(message.To); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(webhookPayload{Subject: message.Subject, Text: message.Text, Payload: message.Payload})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, message.To, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "schedluer")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+func (s *WebhookSender) parseTarget(to string) (*url.URL, error) {
+	target, err := url.Parse(to)
+	if err != nil || target.Hostname() == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", to)
+	}
+	if target.Scheme != "https" && (!s.allowPrivate || target.Scheme != "http") {
+		return nil, ErrInsecureWebhook
+	}
+	return target, nil
+}
+
+// PublicAddress сообщает, что адрес маршрутизируется в интернете: не loopback, не частная сеть,
+// не link-local (в том числе 169.254.169.254 облачных метаданных), не multicast и не служебные диапазоны
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
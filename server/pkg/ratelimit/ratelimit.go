@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// minIdleTTL — минимальное время, через которое неактивный клиент забывается.
+const minIdleTTL = 10 * time.Minute
+
+type Rule struct {
+	RPS   float64
+	Burst int
+}
+
+// Limiter — набор token bucket'ов, по одному на ключ клиента.
+type Limiter struct {
+	mu        sync.Mutex
+	rule      Rule
+	idleTTL   time.Duration
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func New(rule Rule) *Limiter {
+	return &Limiter{
+		rule:      rule,
+		idleTTL:   idleTTL(rule),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow списывает токен из корзины клиента. Если токенов нет, возвращает false
+// и время, через которое запрос будет разрешен.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.idleTTL {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(l.rule.RPS), l.rule.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, l.idleTTL
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+// SetRule меняет лимит на лету. Уже накопленные клиентами токены сохраняются,
+// но не больше нового burst.
+func (l *Limiter) SetRule(rule Rule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rule = rule
+	l.idleTTL = idleTTL(rule)
+	for _, b := range l.buckets {
+		b.limiter.SetLimit(rate.Limit(rule.RPS))
+		b.limiter.SetBurst(rule.Burst)
+	}
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > l.idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// idleTTL не меньше времени полного восстановления корзины: к моменту удаления
+// она заведомо полна, так что забывание клиента не меняет поведение лимитера.
+func idleTTL(rule Rule) time.Duration {
+	if rule.RPS <= 0 {
+		return minIdleTTL
+	}
+	refill := time.Duration(float64(rule.Burst) / rule.RPS * float64(time.Second))
+	return max(refill, minIdleTTL)
+}
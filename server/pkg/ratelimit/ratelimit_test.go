@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterBurstExhaustion(t *testing.T) {
+	limiter := New(Rule{RPS: 1, Burst: 3})
+
+	for i := range 3 {
+		if allowed, _ := limiter.Allow("ip:192.0.2.1"); !allowed {
+			t.Fatalf("request %d within the burst was rejected", i+1)
+		}
+	}
+	allowed, retryAfter := limiter.Allow("ip:192.0.2.1")
+	if allowed {
+		t.Fatal("request over the burst was allowed")
+	}
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("retryAfter = %s, want up to one token interval", retryAfter)
+	}
+
+	// Отказ не списывает токен: после паузы в один токен проходит ровно один запрос
+	time.Sleep(retryAfter + 20*time.Millisecond)
+	if allowed, _ := limiter.Allow("ip:192.0.2.1"); !allowed {
+		t.Error("request after the refill was rejected")
+	}
+	if allowed, _ := limiter.Allow("ip:192.0.2.1"); allowed {
+		t.Error("refill gave more than one token")
+	}
+}
+
+func TestLimiterKeysHaveSeparateBuckets(t *testing.T) {
+	limiter := New(Rule{RPS: 0.01, Burst: 1})
+
+	for _, key := range []string{"ip:192.0.2.1", "ip:192.0.2.2", "key:sch_abc123", "key:sch_def456"} {
+		if allowed, _ := limiter.Allow(key); !allowed {
+			t.Errorf("first request of %s was rejected", key)
+		}
+		if allowed, _ := limiter.Allow(key); allowed {
+			t.Errorf("second request of %s was allowed", key)
+		}
+	}
+}
+
+func TestLimiterSetRule(t *testing.T) {
+	limiter := New(Rule{RPS: 0.01, Burst: 1})
+	if allowed, _ := limiter.Allow("ip:192.0.2.1"); !allowed {
+		t.Fatal("first request was rejected")
+	}
+
+	// Новое правило действует и на уже известных клиентов
+	limiter.SetRule(Rule{RPS: 1000, Burst: 5})
+	time.Sleep(10 * time.Millisecond)
+	for i := range 5 {
+		if allowed, _ := limiter.Allow("ip:192.0.2.1"); !allowed {
+			t.Fatalf("request %d under the new rule was rejected", i+1)
+		}
+	}
+}
+
+func TestIdleTTL(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want time.Duration
+	}{
+		{Rule{RPS: 10, Burst: 40}, minIdleTTL},
+		{Rule{RPS: 1.0 / 60, Burst: 20}, 20 * time.Minute},
+		{Rule{RPS: 0, Burst: 1}, minIdleTTL},
+	}
+	for _, tt := range tests {
+		if got := idleTTL(tt.rule); got != tt.want {
+			t.Errorf("idleTTL(%+v) = %s, want %s", tt.rule, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Package recurrence разбирает правила повторения в синтаксисе RRULE (RFC 5545) и разворачивает их в даты.
+// Поддерживается то, что нужно личным событиям: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY для
+// недельных правил, COUNT и UNTIL в виде даты. Повторения сохраняют время на часах исходного события,
+// поэтому переход на летнее время не сдвигает кружок в 18:00 на 19:00.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// untilLayout — формат UNTIL: последняя дата повторений включительно
+const untilLayout = "20060102"
+
+// maxInterval ограничивает INTERVAL, чтобы правило не превращалось в разовое событие на десятилетия вперед
+const maxInterval = 366
+
+// ErrInvalidRule — правило не разбирается или использует то, что пакет не поддерживает
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule — разобранное правило. Нулевые Count и Until — повторять без конца.
+type Rule struct {
+	Frequency Frequency
+	Interval  int
+	// Weekdays — дни недели (BYDAY), только для Weekly; пусто — день недели первого повторения
+	Weekdays []time.Weekday
+	Count    int
+	// Until — последняя дата повторений; сравниваются только даты в часовом поясе события
+	Until time.Time
+}
+
+// Parse разбирает правило вида FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20261225.
+// Префикс RRULE: допускается, порядок частей не важен.
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("%w: rule is empty", ErrInvalidRule)
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, arg, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		arg = strings.ToUpper(strings.TrimSpace(arg))
+		if !ok || arg == "" {
+			return Rule{}, fmt.Errorf("%w: %q must look like NAME=VALUE", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return Rule{}, fmt.Errorf("%w: %s is given twice", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			rule.Frequency = Frequency(arg)
+			if rule.Frequency != Daily && rule.Frequency != Weekly && rule.Frequency != Monthly {
+				return Rule{}, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidRule)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(arg)
+			if err != nil || interval < 1 || interval > maxInterval {
+				return Rule{}, fmt.Errorf("%w: INTERVAL must be between 1 and %d", ErrInvalidRule, maxInterval)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(arg)
+			if err != nil || count < 1 {
+				return Rule{}, fmt.Errorf("%w: COUNT must be a positive number", ErrInvalidRule)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := time.Parse(untilLayout, arg)
+			if err != nil {
+				return Rule{}, fmt.Errorf("%w: UNTIL must be a date in YYYYMMDD format", ErrInvalidRule)
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, code := range strings.Split(arg, ",") {
+				weekday, ok := weekdayCodes[strings.TrimSpace(code)]
+				if !ok {
+					return Rule{}, fmt.Errorf("%w: unknown BYDAY value %q, expected MO..SU", ErrInvalidRule, code)
+				}
+				if !slices.Contains(rule.Weekdays, weekday) {
+					rule.Weekdays = append(rule.Weekdays, weekday)
+				}
+			}
+		default:
+			return Rule{}, fmt.Errorf("%w: %s is not supported", ErrInvalidRule, name)
+		}
+	}
+
+	switch {
+	case rule.Frequency == "":
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL must not be used together", ErrInvalidRule)
+	case len(rule.Weekdays) > 0 && rule.Frequency != Weekly:
+		return Rule{}, fmt.Errorf("%w: BYDAY is supported only with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	slices.SortFunc(rule.Weekdays, func(a, b time.Weekday) int { return mondayIndex(a) - mondayIndex(b) })
+	return rule, nil
+}
+
+// String возвращает правило в каноническом виде, который разбирает Parse
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		codes := make([]string, 0, len(r.Weekdays))
+		for _, weekday := range r.Weekdays {
+			codes = append(codes, weekdayCode(weekday))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format(untilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between возвращает начала повторений с from (включительно) до to (не включительно).
+// Первое повторение — start; COUNT считается от него, даже если оно раньше from.
+func (r Rule) Between(start, from, to time.Time) []time.Time {
+	starts := []time.Time{}
+	interval := max(r.Interval, 1)
+	count := 0
+
+	// emit учитывает очередное повторение; false — правило или период закончились
+	emit := func(occurrence time.Time) bool {
+		if !r.Until.IsZero() && dateAfter(occurrence, r.Until) {
+			return false
+		}
+		if !occurrence.Before(to) {
+			return false
+		}
+		count++
+		if !occurrence.Before(from) {
+			starts = append(starts, occurrence)
+		}
+		return r.Count == 0 || count < r.Count
+	}
+
+	switch r.Frequency {
+	case Daily:
+		for i := 0; ; i++ {
+			if !emit(start.AddDate(0, 0, i*interval)) {
+				return starts
+			}
+		}
+
+	case Weekly:
+		weekdays := r.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+		// Недели считаются с понедельника, как WKST=MO по умолчанию
+		monday := start.AddDate(0, 0, -mondayIndex(start.Weekday()))
+		for week := 0; ; week++ {
+			weekStart := monday.AddDate(0, 0, week*7*interval)
+			for _, weekday := range weekdays {
+				occurrence := weekStart.AddDate(0, 0, mondayIndex(weekday))
+				if occurrence.Before(start) {
+					continue
+				}
+				if !emit(occurrence) {
+					return starts
+				}
+			}
+		}
+
+	case Monthly:
+		// Месяцы без нужного числа (31-е в апреле) пропускаются, как в RFC 5545
+		for i := 0; ; i++ {
+			occurrence := time.Date(start.Year(), start.Month()+time.Month(i*interval), start.Day(),
+				start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+			if occurrence.Day() != start.Day() {
+				if occurrence.After(to) {
+					return starts
+				}
+				continue
+			}
+			if !emit(occurrence) {
+				return starts
+			}
+		}
+	}
+	return starts
+}
+
+// dateAfter сравнивает дату повторения в его часовом поясе с датой until
+func dateAfter(occurrence, until time.Time) bool {
+	date := time.Date(occurrence.Year(), occurrence.Month(), occurrence.Day(), 0, 0, 0, 0, time.UTC)
+	return date.After(until)
+}
+
+func mondayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func weekdayCode(weekday time.Weekday) string {
+	for code, day := range weekdayCodes {
+		if day == weekday {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var minsk = time.FixedZone("Europe/Minsk", 3*60*60)
+
+func dates(times []time.Time) []string {
+	result := make([]string, 0, len(times))
+	for _, t := range times {
+		result = append(result, t.Format("2006-01-02 15:04"))
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("RRULE:freq=weekly;byday=TH,TU;interval=2;until=20261225")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := rule.String(); got != "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20261225" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, value := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20261225",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;UNTIL=2026-12-25",
+		"FREQ=WEEKLY;FREQ=DAILY",
+		"FREQ=WEEKLY;BYSETPOS=1",
+	} {
+		if _, err := Parse(value); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q): expected ErrInvalidRule, got %v", value, err)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	// Четверг, 3 сентября 2026
+	start := time.Date(2026, 9, 3, 18, 0, 0, 0, minsk)
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, minsk)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, minsk)
+
+	tests := []struct {
+		rule string
+		from time.Time
+		want []string
+	}{
+		{
+			rule: "FREQ=DAILY;COUNT=3",
+			from: from,
+			want: []string{"2026-09-03 18:00", "2026-09-04 18:00", "2026-09-05 18:00"},
+		},
+		{
+			rule: "FREQ=WEEKLY;UNTIL=20260917",
+			from: from,
+			want: []string{"2026-09-03 18:00", "2026-09-10 18:00", "2026-09-17 18:00"},
+		},
+		{
+			// Вторник первой недели раньше начала и не считается
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=4",
+			from: from,
+			want: []string{"2026-09-03 18:00", "2026-09-15 18:00", "2026-09-17 18:00", "2026-09-29 18:00"},
+		},
+		{
+			// COUNT считается от первого повторения, а не от начала периода
+			rule: "FREQ=DAILY;COUNT=10",
+			from: time.Date(2026, 9, 10, 0, 0, 0, 0, minsk),
+			want: []string{"2026-09-10 18:00", "2026-09-11 18:00", "2026-09-12 18:00"},
+		},
+	}
+	for _, test := range tests {
+		rule, err := Parse(test.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", test.rule, err)
+		}
+		got := dates(rule.Between(start, test.from, to))
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.rule, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: got %v, want %v", test.rule, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestMonthlySkipsShortMonths(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;COUNT=3")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	start := time.Date(2026, 1, 31, 10, 0, 0, 0, minsk)
+	got := dates(rule.Between(start, start, start.AddDate(1, 0, 0)))
+	want := []string{"2026-01-31 10:00", "2026-03-31 10:00", "2026-05-31 10:00"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBetweenKeepsWallClock(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	rule, _ := Parse("FREQ=WEEKLY")
+	// Переход на зимнее время 25 октября 2026
+	start := time.Date(2026, 10, 22, 18, 0, 0, 0, warsaw)
+	got := rule.Between(start, start, start.AddDate(0, 0, 8))
+	if len(got) != 2 || got[1].Hour() != 18 {
+		t.Errorf("weekly event must stay at 18:00 after DST change, got %v", got)
+	}
+}
@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"schedluer/internal/metrics"
+)
+
+var ErrStopped = errors.New("worker group is stopped")
+
+// Group отслеживает фоновые задачи, которые должны пережить HTTP-запрос, но не процесс:
+// при остановке сервиса новые задачи не принимаются, а текущие дорабатывают до таймаута.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	stopped bool
+	nextID  uint64
+	running map[uint64]runningTask
+
+	logger *logrus.Logger
+}
+
+func NewGroup(logger *logrus.Logger) *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(map[uint64]runningTask),
+		logger:  logger,
+	}
+}
+
+type runningTask struct {
+	name      string
+	startedAt time.Time
+}
+
+// Stats — снимок выполняющихся задач для проверки готовности
+type Stats struct {
+	Running    int
+	OldestTask string
+	OldestAge  time.Duration
+}
+
+func (g *Group) Stats() Stats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := Stats{Running: len(g.running)}
+	for _, task := range g.running {
+		if age := time.Since(task.startedAt); age > stats.OldestAge {
+			stats.OldestAge = age
+			stats.OldestTask = task.name
+		}
+	}
+	return stats
+}
+
+// Go запускает задачу в отдельной горутине. Контекст задачи отменяется,
+// только если Shutdown не дождался ее завершения.
+func (g *Group) Go(name string, task func(ctx context.Context)) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		g.logger.WithField("task", name).Warn("Background task rejected: shutting down")
+		return ErrStopped
+	}
+
+	g.nextID++
+	id := g.nextID
+	start := time.Now()
+	g.running[id] = runningTask{name: name, startedAt: start}
+
+	g.wg.Add(1)
+	go func() {
+		status := "ok"
+		defer g.wg.Done()
+		defer func() {
+			g.mu.Lock()
+			delete(g.running, id)
+			g.mu.Unlock()
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				status = "panic"
+				g.logger.WithFields(logrus.Fields{"task": name, "panic": r}).Error("Background task panicked")
+			}
+			metrics.BackgroundTaskDuration.WithLabelValues(name, status).Observe(time.Since(start).Seconds())
+		}()
+
+		task(g.ctx)
+	}()
+
+	return nil
+}
+
+// Shutdown перестает принимать задачи и ждет завершения запущенных.
+// Если ctx истекает раньше, задачам отменяется контекст и возвращается ошибка ctx.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.stopped = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		g.cancel()
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		return ctx.Err()
+	}
+}
+
+// Wait ждет завершения всех задач, но не дольше ctx. После Shutdown с истекшим ctx
+// задачам уже отменен контекст, и Wait дает им время выйти, прежде чем закрыть то, чем они пользуются.
+func (g *Group) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
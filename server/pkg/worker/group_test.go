@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func testGroup() *Group {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewGroup(logger)
+}
+
+func TestGroupShutdownWaitsForTasks(t *testing.T) {
+	g := testGroup()
+	finished := make(chan struct{})
+	if err := g.Go("short", func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+		close(finished)
+	}); err != nil {
+		t.Fatalf("Go: %v", err)
+	}
+
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("Shutdown returned before the task finished")
+	}
+	if err := g.Go("late", func(ctx context.Context) {}); !errors.Is(err, ErrStopped) {
+		t.Errorf("Go after Shutdown = %v, want ErrStopped", err)
+	}
+}
+
+func TestGroupWaitAfterShutdownTimeout(t *testing.T) {
+	g := testGroup()
+	exited := make(chan struct{})
+	_ = g.Go("slow", func(ctx context.Context) {
+		<-ctx.Done()
+		// Задача еще что-то дописывает после отмены
+		time.Sleep(20 * time.Millisecond)
+		close(exited)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+
+	// Shutdown отменил контекст задачи, но не дождался ее — это делает Wait
+	waitCtx, cancelWait := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelWait()
+	if err := g.Wait(waitCtx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	select {
+	case <-exited:
+	default:
+		t.Error("Wait returned before the cancelled task exited")
+	}
+}
+
+func TestGroupWaitIsBounded(t *testing.T) {
+	g := testGroup()
+	release := make(chan struct{})
+	defer close(release)
+	_ = g.Go("stuck", func(ctx context.Context) { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_ = g.Shutdown(ctx)
+
+	waitCtx, cancelWait := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelWait()
+	if err := g.Wait(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait for a task that ignores cancellation = %v, want DeadlineExceeded", err)
+	}
+}